@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/codeneuss/lampcontrol/internal/application"
-	"github.com/codeneuss/lampcontrol/internal/infrastructure/bluetooth"
 	"github.com/spf13/cobra"
 )
 
@@ -18,25 +16,22 @@ var brightnessCmd = &cobra.Command{
 	Short: "Set brightness level",
 	Long:  `Set the brightness level of the LED lamp (0-255).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if deviceAddress == "" {
-			return fmt.Errorf("device address required (use --device or -d flag)")
+		if err := requireDevice(); err != nil {
+			return err
 		}
 
 		if brightnessLevel < 0 || brightnessLevel > 255 {
 			return fmt.Errorf("brightness must be between 0 and 255")
 		}
 
-		// Create BLE adapter
-		adapter, err := bluetooth.NewAdapter()
+		// Control the lamp locally or through a remote daemon
+		service, err := newLampClient()
 		if err != nil {
-			return fmt.Errorf("failed to initialize Bluetooth adapter: %w", err)
+			return err
 		}
+		defer service.Close()
 
-		// Create device service
-		service := application.NewDeviceService(adapter)
-		defer service.DisconnectAll()
-
-		fmt.Printf("Setting brightness to %d on device %s...\n", brightnessLevel, deviceAddress)
+		fmt.Printf("Setting brightness to %d on device %s...\n", brightnessLevel, deviceLabel())
 
 		// Set brightness
 		ctx := context.Background()
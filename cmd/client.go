@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/bluetooth"
+	"github.com/codeneuss/lampcontrol/pkg/lampcontrolv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// lampClient runs the lamp commands, on the local Bluetooth adapter or
+// through the gRPC API of a daemon started with `lamp web --grpc-port`
+type lampClient interface {
+	Scan(ctx context.Context, timeout time.Duration) ([]*domain.Device, error)
+	SetPower(ctx context.Context, address string, on bool) error
+	SetColor(ctx context.Context, address string, r, g, b uint8) error
+	SetBrightness(ctx context.Context, address string, level uint8) error
+	SetWhiteBalance(ctx context.Context, address string, warm, cold uint8) error
+	SetEffect(ctx context.Context, address string, effect, speed uint8) error
+	Close() error
+}
+
+// newLampClient connects to the daemon given with --remote, or to the local adapter
+func newLampClient() (lampClient, error) {
+	if remoteAddress != "" {
+		conn, err := grpc.NewClient(remoteAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %w", remoteAddress, err)
+		}
+		return &remoteClient{conn: conn, client: lampcontrolv1.NewLampControlClient(conn)}, nil
+	}
+
+	// Create BLE adapter
+	adapter, err := bluetooth.NewAdapter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Bluetooth adapter: %w", err)
+	}
+
+	// Create device service
+	return &localClient{application.NewDeviceService(adapter)}, nil
+}
+
+// requireDevice checks that a device is given. A daemon falls back to its selected device.
+func requireDevice() error {
+	if deviceAddress == "" && remoteAddress == "" {
+		return fmt.Errorf("device address required (use --device or -d flag)")
+	}
+	return nil
+}
+
+// deviceLabel names the device a command goes to
+func deviceLabel() string {
+	if deviceAddress == "" {
+		return "selected on " + remoteAddress
+	}
+	return deviceAddress
+}
+
+// localClient controls the lamps with the local Bluetooth adapter
+type localClient struct {
+	*application.DeviceService
+}
+
+// Close disconnects from all lamps
+func (c *localClient) Close() error {
+	return c.DisconnectAll()
+}
+
+// remoteClient controls the lamps of a daemon over gRPC
+type remoteClient struct {
+	conn   *grpc.ClientConn
+	client lampcontrolv1.LampControlClient
+}
+
+func (c *remoteClient) Scan(ctx context.Context, timeout time.Duration) ([]*domain.Device, error) {
+	seconds := uint32((timeout + time.Second - 1) / time.Second)
+	resp, err := c.client.Scan(ctx, &lampcontrolv1.ScanRequest{TimeoutSeconds: seconds})
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]*domain.Device, len(resp.Devices))
+	for i, device := range resp.Devices {
+		devices[i] = &domain.Device{
+			Address:   device.Address,
+			Name:      device.Name,
+			RSSI:      int16(device.Rssi),
+			Connected: device.Connected,
+			LastSeen:  device.LastSeen.AsTime(),
+		}
+	}
+	return devices, nil
+}
+
+func (c *remoteClient) SetPower(ctx context.Context, address string, on bool) error {
+	_, err := c.client.SetPower(ctx, &lampcontrolv1.SetPowerRequest{Address: address, On: on})
+	return err
+}
+
+func (c *remoteClient) SetColor(ctx context.Context, address string, r, g, b uint8) error {
+	color := &lampcontrolv1.Color{R: uint32(r), G: uint32(g), B: uint32(b)}
+	_, err := c.client.SetColor(ctx, &lampcontrolv1.SetColorRequest{Address: address, Color: color})
+	return err
+}
+
+func (c *remoteClient) SetBrightness(ctx context.Context, address string, level uint8) error {
+	_, err := c.client.SetBrightness(ctx, &lampcontrolv1.SetBrightnessRequest{Address: address, Level: uint32(level)})
+	return err
+}
+
+func (c *remoteClient) SetWhiteBalance(ctx context.Context, address string, warm, cold uint8) error {
+	_, err := c.client.SetWhiteBalance(ctx, &lampcontrolv1.SetWhiteBalanceRequest{Address: address, Warm: uint32(warm), Cold: uint32(cold)})
+	return err
+}
+
+func (c *remoteClient) SetEffect(ctx context.Context, address string, effect, speed uint8) error {
+	_, err := c.client.SetEffect(ctx, &lampcontrolv1.SetEffectRequest{Address: address, Effect: uint32(effect), Speed: uint32(speed)})
+	return err
+}
+
+// Close closes the connection to the daemon
+func (c *remoteClient) Close() error {
+	return c.conn.Close()
+}
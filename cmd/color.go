@@ -6,8 +6,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/codeneuss/lampcontrol/internal/application"
-	"github.com/codeneuss/lampcontrol/internal/infrastructure/bluetooth"
 	"github.com/spf13/cobra"
 )
 
@@ -20,8 +18,8 @@ var colorCmd = &cobra.Command{
 	Short: "Set RGB color",
 	Long:  `Set the RGB color of the LED lamp.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if deviceAddress == "" {
-			return fmt.Errorf("device address required (use --device or -d flag)")
+		if err := requireDevice(); err != nil {
+			return err
 		}
 
 		if rgbColor == "" {
@@ -49,17 +47,14 @@ var colorCmd = &cobra.Command{
 			return fmt.Errorf("invalid blue value: %w", err)
 		}
 
-		// Create BLE adapter
-		adapter, err := bluetooth.NewAdapter()
+		// Control the lamp locally or through a remote daemon
+		service, err := newLampClient()
 		if err != nil {
-			return fmt.Errorf("failed to initialize Bluetooth adapter: %w", err)
+			return err
 		}
+		defer service.Close()
 
-		// Create device service
-		service := application.NewDeviceService(adapter)
-		defer service.DisconnectAll()
-
-		fmt.Printf("Setting color to RGB(%d,%d,%d) on device %s...\n", r, g, b, deviceAddress)
+		fmt.Printf("Setting color to RGB(%d,%d,%d) on device %s...\n", r, g, b, deviceLabel())
 
 		// Set color
 		ctx := context.Background()
@@ -4,13 +4,17 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
 	"github.com/spf13/cobra"
 )
 
 var (
 	// Global flags
 	deviceAddress string
+	remoteAddress string
 	verbose       bool
+	logLevel      string
+	logFormat     string
 )
 
 var rootCmd = &cobra.Command{
@@ -19,12 +23,23 @@ var rootCmd = &cobra.Command{
 	Long: `A CLI tool and REST API for controlling duoCo StripX LED lamps
 using the ELK-BLEDOM protocol over Bluetooth Low Energy.`,
 	Version: "1.0.0",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// --verbose turns on debug logs unless a level was picked explicitly
+		level := logLevel
+		if verbose && !cmd.Flags().Changed("log-level") {
+			level = "debug"
+		}
+		return logging.Setup(os.Stderr, level, logFormat)
+	},
 }
 
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVarP(&deviceAddress, "device", "d", "", "Device MAC address")
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	rootCmd.PersistentFlags().StringVar(&remoteAddress, "remote", os.Getenv("LAMP_REMOTE"), "Control the lamps through the gRPC API of a daemon (host:port) instead of Bluetooth")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output, including debug logs with BLE frames")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logging.FormatText, "Log format (text, json)")
 
 	// Add subcommands
 	rootCmd.AddCommand(scanCmd)
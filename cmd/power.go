@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/codeneuss/lampcontrol/internal/application"
-	"github.com/codeneuss/lampcontrol/internal/infrastructure/bluetooth"
 	"github.com/spf13/cobra"
 )
 
@@ -15,8 +13,8 @@ var powerCmd = &cobra.Command{
 	Long:  `Turn the LED lamp on or off.`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if deviceAddress == "" {
-			return fmt.Errorf("device address required (use --device or -d flag)")
+		if err := requireDevice(); err != nil {
+			return err
 		}
 
 		state := args[0]
@@ -26,17 +24,14 @@ var powerCmd = &cobra.Command{
 
 		on := state == "on"
 
-		// Create BLE adapter
-		adapter, err := bluetooth.NewAdapter()
+		// Control the lamp locally or through a remote daemon
+		service, err := newLampClient()
 		if err != nil {
-			return fmt.Errorf("failed to initialize Bluetooth adapter: %w", err)
+			return err
 		}
+		defer service.Close()
 
-		// Create device service
-		service := application.NewDeviceService(adapter)
-		defer service.DisconnectAll()
-
-		fmt.Printf("Turning %s device %s...\n", state, deviceAddress)
+		fmt.Printf("Turning %s device %s...\n", state, deviceLabel())
 
 		// Set power
 		ctx := context.Background()
@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/codeneuss/lampcontrol/internal/application"
-	"github.com/codeneuss/lampcontrol/internal/infrastructure/bluetooth"
 	"github.com/spf13/cobra"
 )
 
@@ -19,14 +17,12 @@ var scanCmd = &cobra.Command{
 	Short: "Scan for ELK-BLEDOM devices",
 	Long:  `Scan for available ELK-BLEDOM LED devices in range.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Create BLE adapter
-		adapter, err := bluetooth.NewAdapter()
+		// Control the lamp locally or through a remote daemon
+		service, err := newLampClient()
 		if err != nil {
-			return fmt.Errorf("failed to initialize Bluetooth adapter: %w", err)
+			return err
 		}
-
-		// Create device service
-		service := application.NewDeviceService(adapter)
+		defer service.Close()
 
 		fmt.Printf("Scanning for devices (timeout: %v)...\n", scanTimeout)
 
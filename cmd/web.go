@@ -3,19 +3,29 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
+	"os"
 
 	"github.com/codeneuss/lampcontrol/internal/application"
 	"github.com/codeneuss/lampcontrol/internal/infrastructure/bluetooth"
 	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/twitch"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api/state"
+	"github.com/codeneuss/lampcontrol/internal/presentation/rpc"
+	"github.com/codeneuss/lampcontrol/pkg/lampcontrolv1"
+	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
 )
 
 var (
-	webPort int
-	webHost string
+	webPort     int
+	webHost     string
+	consoleChat bool
+	wledAPI     bool
+	grpcPort    int
 )
 
 var webCmd = &cobra.Command{
@@ -45,31 +55,216 @@ var webCmd = &cobra.Command{
 			return fmt.Errorf("failed to initialize twitch storage: %w", err)
 		}
 
+		// Create Twitch command history storage
+		historyStorage, err := storage.NewHistoryStorage()
+		if err != nil {
+			return fmt.Errorf("failed to initialize history storage: %w", err)
+		}
+
+		// Create loyalty points ledger storage
+		loyaltyStorage, err := storage.NewLoyaltyStorage()
+		if err != nil {
+			return fmt.Errorf("failed to initialize loyalty storage: %w", err)
+		}
+
+		// Create OBS storage
+		obsStorage, err := storage.NewOBSStorage()
+		if err != nil {
+			return fmt.Errorf("failed to initialize OBS storage: %w", err)
+		}
+
+		// Create alert storage
+		alertStorage, err := storage.NewAlertStorage()
+		if err != nil {
+			return fmt.Errorf("failed to initialize alert storage: %w", err)
+		}
+
+		// Create MQTT storage
+		mqttStorage, err := storage.NewMQTTStorage()
+		if err != nil {
+			return fmt.Errorf("failed to initialize MQTT storage: %w", err)
+		}
+
+		// Create Hue storage
+		hueStorage, err := storage.NewHueStorage()
+		if err != nil {
+			return fmt.Errorf("failed to initialize Hue storage: %w", err)
+		}
+
+		// Create DMX storage
+		dmxStorage, err := storage.NewDMXStorage()
+		if err != nil {
+			return fmt.Errorf("failed to initialize DMX storage: %w", err)
+		}
+
+		// Create OpenRGB storage
+		openRGBStorage, err := storage.NewOpenRGBStorage()
+		if err != nil {
+			return fmt.Errorf("failed to initialize OpenRGB storage: %w", err)
+		}
+
+		// Decide between OBS, viewers and alerts competing for the lamps
+		arbiter := application.NewLampArbiter(deviceService)
+		arbiter.Start()
+		defer arbiter.Stop()
+
 		// Create Twitch service
-		twitchService := application.NewTwitchService(deviceService, twitchStorage)
+		twitchService := application.NewTwitchService(deviceService, arbiter, twitchStorage, historyStorage)
+
+		// Pay viewers loyalty points and charge command costs
+		loyaltyService := application.NewLoyaltyService(loyaltyStorage, twitchStorage)
+		twitchService.SetPointsLedger(loyaltyService)
+		twitchService.SetPresenceCallback(loyaltyService.Observe)
+		loyaltyService.Start()
+		defer loyaltyService.Stop()
+
+		// Enable follower lookups when a Twitch client ID is configured
+		godotenv.Load()
+		if clientID := os.Getenv("TWITCH_CLIENT_ID"); clientID != "" {
+			twitchService.SetAPIClient(twitch.NewAPIClient(clientID, os.Getenv("TWITCH_CLIENT_SECRET")))
+		}
+
+		// Enable YouTube Live chats when an API key is configured
+		if apiKey := os.Getenv("YOUTUBE_API_KEY"); apiKey != "" {
+			twitchService.SetYouTubeAPIKey(apiKey)
+		}
+
+		if consoleChat {
+			twitchService.EnableConsoleChat()
+		}
 
 		// Create server state (with Twitch service)
 		serverState := state.NewServerState(deviceService, twitchService)
 
+		// Let the lamps follow OBS scene, stream and record events
+		obsService := application.NewOBSService(deviceService, arbiter, obsStorage)
+		serverState.SetOBSService(obsService)
+		defer obsService.Stop()
+
+		// Flash the lamps on follows, subs, raids and donations
+		alertService := application.NewAlertService(deviceService, arbiter, alertStorage)
+		serverState.SetAlertService(alertService)
+		defer alertService.Stop()
+
+		// Bridge the lamps to Home Assistant over MQTT
+		mqttService := application.NewMQTTService(deviceService, mqttStorage)
+		serverState.SetMQTTService(mqttService)
+		defer mqttService.Stop()
+
+		// Let Hue apps find and control the lamps as if they were Hue lights
+		hueService := application.NewHueService(deviceService, hueStorage)
+		serverState.SetHueService(hueService)
+		defer hueService.Stop()
+
+		// Drive the lamps from lighting consoles over Art-Net and sACN
+		dmxService := application.NewDMXService(deviceService, arbiter, dmxStorage)
+		serverState.SetDMXService(dmxService)
+		defer dmxService.Stop()
+
+		// Let OpenRGB and its effect plugins drive the lamps
+		openRGBService := application.NewOpenRGBService(deviceService, arbiter, openRGBStorage)
+		serverState.SetOpenRGBService(openRGBService)
+		defer openRGBService.Stop()
+
 		// Create and start server
-		server := api.NewServer(webHost, webPort, serverState, effectStorage, twitchStorage)
+		server := api.NewServer(webHost, webPort, serverState, api.Services{
+			EffectStorage:  effectStorage,
+			TwitchStorage:  twitchStorage,
+			LoyaltyService: loyaltyService,
+			OBSService:     obsService,
+			OBSStorage:     obsStorage,
+			AlertService:   alertService,
+			AlertStorage:   alertStorage,
+			MQTTService:    mqttService,
+			MQTTStorage:    mqttStorage,
+			HueService:     hueService,
+			HueStorage:     hueStorage,
+			DMXService:     dmxService,
+			DMXStorage:     dmxStorage,
+			OpenRGBService: openRGBService,
+			OpenRGBStorage: openRGBStorage,
+		}, wledAPI)
+
+		// Serve the gRPC API if enabled
+		if grpcPort > 0 {
+			listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", webHost, grpcPort))
+			if err != nil {
+				return fmt.Errorf("failed to listen for gRPC on port %d: %w", grpcPort, err)
+			}
+
+			grpcServer := grpc.NewServer()
+			lampcontrolv1.RegisterLampControlServer(grpcServer, rpc.NewServer(serverState, effectStorage))
+			defer grpcServer.GracefulStop()
+
+			go func() {
+				if err := grpcServer.Serve(listener); err != nil {
+					slog.Error("gRPC server error", "error", err)
+				}
+			}()
+			slog.Info("gRPC API enabled", "address", listener.Addr().String())
+		}
 
 		// Auto-start Twitch if enabled
 		twitchConfig := twitchStorage.Get()
-		if twitchConfig.Enabled {
+		if twitchConfig.Enabled || consoleChat {
 			if err := twitchService.Start(context.Background()); err != nil {
-				log.Printf("Failed to auto-start Twitch integration: %v", err)
+				slog.Error("Failed to auto-start Twitch integration", "error", err)
 			} else {
-				log.Printf("Twitch integration auto-started for channel: %s", twitchConfig.Channel)
+				slog.Info("Twitch integration auto-started", "channel", twitchConfig.Channel)
+			}
+		}
+
+		// Auto-start OBS if enabled
+		if obsStorage.Get().Enabled {
+			if err := obsService.Start(context.Background()); err != nil {
+				slog.Error("Failed to auto-start OBS integration", "error", err)
 			}
 		}
 
-		log.Printf("Starting LampControl Web Server")
-		log.Printf("  Host: %s", webHost)
-		log.Printf("  Port: %d", webPort)
-		log.Printf("  Web UI: http://%s:%d", webHost, webPort)
-		log.Printf("  API: http://%s:%d/api", webHost, webPort)
-		log.Printf("  WebSocket: ws://%s:%d/ws", webHost, webPort)
+		// Auto-start MQTT if enabled
+		if mqttStorage.Get().Enabled {
+			if err := mqttService.Start(context.Background()); err != nil {
+				slog.Error("Failed to auto-start MQTT bridge", "error", err)
+			}
+		}
+
+		// Auto-start the Hue bridge if enabled
+		if hueStorage.Get().Enabled {
+			if err := hueService.Start(context.Background()); err != nil {
+				slog.Error("Failed to auto-start Hue bridge", "error", err)
+			}
+		}
+
+		// Auto-start the DMX input if enabled
+		if dmxStorage.Get().Enabled {
+			if err := dmxService.Start(context.Background()); err != nil {
+				slog.Error("Failed to auto-start DMX input", "error", err)
+			}
+		}
+
+		// Auto-start the OpenRGB server if enabled
+		if openRGBStorage.Get().Enabled {
+			if err := openRGBService.Start(context.Background()); err != nil {
+				slog.Error("Failed to auto-start OpenRGB server", "error", err)
+			}
+		}
+
+		// Connect the enabled alert providers
+		if err := alertService.Start(context.Background()); err != nil {
+			slog.Error("Failed to start alert providers", "error", err)
+		}
+
+		base := fmt.Sprintf("%s:%d", webHost, webPort)
+		slog.Info("Starting LampControl web server",
+			"host", webHost,
+			"port", webPort,
+			"ui", "http://"+base,
+			"api", "http://"+base+"/api",
+			"websocket", "ws://"+base+"/ws",
+		)
+		if wledAPI {
+			slog.Info("WLED API enabled", "url", "http://"+base+"/json")
+		}
 
 		if err := server.Start(); err != nil {
 			return fmt.Errorf("server error: %w", err)
@@ -82,4 +277,7 @@ var webCmd = &cobra.Command{
 func init() {
 	webCmd.Flags().IntVarP(&webPort, "port", "p", 8080, "HTTP server port")
 	webCmd.Flags().StringVarP(&webHost, "host", "H", "localhost", "HTTP server host")
+	webCmd.Flags().BoolVar(&wledAPI, "wled", false, "Expose the lamps through the WLED JSON API at /json")
+	webCmd.Flags().IntVar(&grpcPort, "grpc-port", 0, "Serve the gRPC API on this port (0 disables it)")
+	webCmd.Flags().BoolVar(&consoleChat, "console-chat", false, "Read simulated chat messages like \"alice: !lamp red\" from the terminal")
 }
@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/codeneuss/lampcontrol/internal/application"
-	"github.com/codeneuss/lampcontrol/internal/infrastructure/bluetooth"
 	"github.com/spf13/cobra"
 )
 
@@ -19,8 +17,8 @@ var whiteCmd = &cobra.Command{
 	Short: "Set white balance",
 	Long:  `Set the white balance (warm/cold) of the LED lamp.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if deviceAddress == "" {
-			return fmt.Errorf("device address required (use --device or -d flag)")
+		if err := requireDevice(); err != nil {
+			return err
 		}
 
 		if warmLevel < 0 || warmLevel > 255 {
@@ -31,17 +29,14 @@ var whiteCmd = &cobra.Command{
 			return fmt.Errorf("cold level must be between 0 and 255")
 		}
 
-		// Create BLE adapter
-		adapter, err := bluetooth.NewAdapter()
+		// Control the lamp locally or through a remote daemon
+		service, err := newLampClient()
 		if err != nil {
-			return fmt.Errorf("failed to initialize Bluetooth adapter: %w", err)
+			return err
 		}
+		defer service.Close()
 
-		// Create device service
-		service := application.NewDeviceService(adapter)
-		defer service.DisconnectAll()
-
-		fmt.Printf("Setting white balance to warm=%d, cold=%d on device %s...\n", warmLevel, coldLevel, deviceAddress)
+		fmt.Printf("Setting white balance to warm=%d, cold=%d on device %s...\n", warmLevel, coldLevel, deviceLabel())
 
 		// Set white balance
 		ctx := context.Background()
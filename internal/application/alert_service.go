@@ -0,0 +1,202 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/alerts"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+)
+
+// AlertService reacts to follows, subs, gifted subs, raids and donations from
+// webhooks, alert provider sockets and Twitch chat with a temporary lamp effect.
+// Alert effects are leases above viewer effects: a running viewer effect resumes
+// when the alert ends, if it has time left.
+type AlertService struct {
+	DeviceRoute
+	arbiter *LampArbiter
+	storage *storage.AlertStorage
+	clients []*alerts.Client
+	mu      sync.RWMutex
+	log     *slog.Logger
+
+	// Callbacks
+	onAlert           func(alert *domain.Alert)
+	onStatusChange    func(status AlertStatus)
+	onLampChange      func(deviceAddr string)
+	getSelectedDevice func() (string, error)
+}
+
+// AlertStatus describes the alert provider connections
+type AlertStatus struct {
+	Providers map[string]domain.ConnectionStatus // Provider name -> connection, only enabled providers
+}
+
+// NewAlertService creates a new alert service
+func NewAlertService(deviceService *DeviceService, arbiter *LampArbiter, storage *storage.AlertStorage) *AlertService {
+	return &AlertService{
+		DeviceRoute: NewDeviceRoute(deviceService),
+		arbiter:     arbiter,
+		storage:     storage,
+		log:         logging.Source("alerts"),
+	}
+}
+
+// Start connects the enabled alert provider sockets. Webhooks don't need a start.
+func (s *AlertService) Start(ctx context.Context) error {
+	config := s.storage.Get()
+
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// Drop previous connections before replacing the clients
+	s.Stop()
+
+	var clients []*alerts.Client
+	if config.StreamElements.Enabled {
+		clients = append(clients, alerts.NewStreamElementsClient(config.StreamElements.Token))
+	}
+	if config.Streamlabs.Enabled {
+		clients = append(clients, alerts.NewStreamlabsClient(config.Streamlabs.Token))
+	}
+
+	for _, client := range clients {
+		client.SetAlertHandler(s.HandleAlert)
+		client.SetStateHandler(func(domain.ConnectionStatus) {
+			s.notifyStatus()
+		})
+	}
+
+	s.mu.Lock()
+	s.clients = clients
+	s.mu.Unlock()
+
+	for _, client := range clients {
+		if err := client.Connect(ctx); err != nil {
+			s.Stop()
+			return fmt.Errorf("failed to connect to %s: %w", client.Provider(), err)
+		}
+		s.log.Info("Started alerts", "provider", client.Provider())
+	}
+
+	return nil
+}
+
+// Stop disconnects the alert provider sockets
+func (s *AlertService) Stop() error {
+	s.mu.Lock()
+	clients := s.clients
+	s.clients = nil
+	s.mu.Unlock()
+
+	for _, client := range clients {
+		client.Disconnect()
+	}
+	return nil
+}
+
+// GetStatus returns the connection status of the enabled alert providers
+func (s *AlertService) GetStatus() AlertStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := AlertStatus{Providers: make(map[string]domain.ConnectionStatus)}
+	for _, client := range s.clients {
+		status.Providers[client.Provider()] = client.Status()
+	}
+	return status
+}
+
+// GetConfig returns the alert configuration
+func (s *AlertService) GetConfig() *domain.AlertConfig {
+	return s.storage.Get()
+}
+
+// HandleAlert announces an alert and plays the matching reaction on the lamp
+func (s *AlertService) HandleAlert(alert *domain.Alert) {
+	s.log.Info("Alert received", "type", alert.Type, "provider", alert.Source, "user", alert.User, "amount", alert.Amount)
+
+	if s.onAlert != nil {
+		s.onAlert(alert)
+	}
+
+	reaction := s.storage.Get().ReactionFor(alert)
+	if reaction == nil {
+		return
+	}
+
+	if err := s.play(reaction, alert); err != nil {
+		s.log.Error("Failed to play reaction", "type", alert.Type, "error", err)
+	}
+}
+
+// play applies a reaction, the arbiter restores the lamp when it ends.
+// A new alert during a running effect replaces it.
+func (s *AlertService) play(reaction *domain.AlertReaction, alert *domain.Alert) error {
+	if s.getSelectedDevice == nil {
+		return fmt.Errorf("no device selected")
+	}
+	deviceAddr, err := s.getSelectedDevice()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	lease := domain.Lease{
+		Source:     domain.LeaseSourceAlert,
+		Holder:     alert.User,
+		Priority:   domain.PriorityAlert,
+		Change:     reaction.Change(),
+		Blend:      reaction.Blend,
+		Opacity:    reaction.Opacity,
+		Fade:       reaction.Fade,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(reaction.DurationFor(alert.Amount)),
+	}
+	if err := s.arbiter.Acquire(context.Background(), deviceAddr, lease, s.devices); err != nil {
+		return err
+	}
+	s.notifyLampChange(deviceAddr)
+
+	return nil
+}
+
+// notifyStatus reports the current status to the status callback
+func (s *AlertService) notifyStatus() {
+	if s.onStatusChange != nil {
+		s.onStatusChange(s.GetStatus())
+	}
+}
+
+// notifyLampChange reports a lamp change to the lamp change callback
+func (s *AlertService) notifyLampChange(deviceAddr string) {
+	if s.onLampChange != nil {
+		s.onLampChange(deviceAddr)
+	}
+}
+
+// SetGetSelectedDeviceFunc sets the function returning the selected device
+func (s *AlertService) SetGetSelectedDeviceFunc(fn func() (string, error)) {
+	s.getSelectedDevice = fn
+}
+
+// SetAlertCallback sets the callback for received alerts
+func (s *AlertService) SetAlertCallback(callback func(alert *domain.Alert)) {
+	s.onAlert = callback
+}
+
+// SetStatusChangeCallback sets the callback for provider connection changes
+func (s *AlertService) SetStatusChangeCallback(callback func(status AlertStatus)) {
+	s.onStatusChange = callback
+}
+
+// SetLampChangeCallback sets the callback for lamp changes made by alert effects
+func (s *AlertService) SetLampChangeCallback(callback func(deviceAddr string)) {
+	s.onLampChange = callback
+}
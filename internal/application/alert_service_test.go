@@ -0,0 +1,123 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDeviceAddr = "AA:BB:CC:DD:EE:FF"
+
+// fakeDevices records the state the lamp would be in
+type fakeDevices struct {
+	mu    sync.Mutex
+	state domain.DeviceState
+}
+
+func (f *fakeDevices) apply(change domain.StateChange) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.state = change.Apply(f.state)
+	return nil
+}
+
+func (f *fakeDevices) SetPower(_ context.Context, _ string, on bool) error {
+	return f.apply(domain.StateChange{PowerOn: &on})
+}
+
+func (f *fakeDevices) SetColor(_ context.Context, _ string, r, g, b uint8) error {
+	return f.apply(domain.StateChange{RGB: &domain.RGB{R: r, G: g, B: b}})
+}
+
+func (f *fakeDevices) SetBrightness(_ context.Context, _ string, level uint8) error {
+	return f.apply(domain.StateChange{Brightness: &level})
+}
+
+func (f *fakeDevices) SetWhiteBalance(_ context.Context, _ string, warm, cold uint8) error {
+	return f.apply(domain.StateChange{WhiteBalance: &domain.WhiteBalance{Warm: warm, Cold: cold}})
+}
+
+func (f *fakeDevices) SetEffect(_ context.Context, _ string, effect, speed uint8) error {
+	effectInt := int(effect)
+	return f.apply(domain.StateChange{Effect: &effectInt, EffectSpeed: &speed})
+}
+
+func (f *fakeDevices) ApplyState(_ context.Context, _ string, state domain.DeviceState) error {
+	return f.apply(domain.ChangeTo(state))
+}
+
+// Color returns the RGB color of the lamp, or nil in white or effect mode
+func (f *fakeDevices) Color() *domain.RGB {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.state.RGB
+}
+
+// newTestLamp registers a connected lamp in warm white and returns an arbiter
+// sending to a fake controller
+func newTestLamp(t *testing.T) (*DeviceService, *LampArbiter, *fakeDevices) {
+	t.Helper()
+	return newTestLampIn(t, domain.DeviceState{PowerOn: true, Brightness: 200, WhiteBalance: &domain.WhiteBalance{Warm: 255}})
+}
+
+// newTestLampIn is newTestLamp with the lamp starting in state
+func newTestLampIn(t *testing.T, state domain.DeviceState) (*DeviceService, *LampArbiter, *fakeDevices) {
+	t.Helper()
+
+	device := domain.NewDevice(testDeviceAddr, "Desk", -50)
+	device.Connected = true
+	device.State = state
+
+	deviceService := NewDeviceService(nil)
+	deviceService.devices[testDeviceAddr] = device
+
+	devices := &fakeDevices{state: state}
+	arbiter := NewLampArbiter(deviceService)
+	arbiter.SetDeviceController(devices)
+	t.Cleanup(arbiter.Stop)
+
+	return deviceService, arbiter, devices
+}
+
+func TestOverlappingAlertsRestoreOnce(t *testing.T) {
+	deviceService, arbiter, devices := newTestLamp(t)
+
+	service := NewAlertService(deviceService, arbiter, nil)
+	service.SetDeviceController(devices)
+	service.SetGetSelectedDeviceFunc(func() (string, error) { return testDeviceAddr, nil })
+
+	red := &domain.RGB{R: 255}
+	green := &domain.RGB{G: 255}
+	blue := &domain.RGB{B: 255}
+
+	// A viewer effect is running when the alerts arrive
+	require.NoError(t, arbiter.Acquire(context.Background(), testDeviceAddr, domain.Lease{
+		Source:    domain.LeaseSourceViewer,
+		Priority:  domain.PriorityViewer,
+		Change:    domain.StateChange{RGB: blue},
+		ExpiresAt: time.Now().Add(400 * time.Millisecond),
+	}, devices))
+
+	require.NoError(t, service.play(&domain.AlertReaction{Type: domain.AlertFollow, Color: red, Duration: 100 * time.Millisecond}, &domain.Alert{User: "first"}))
+	assert.Equal(t, red, devices.Color())
+
+	// The second alert replaces the first and restores what was below both
+	require.NoError(t, service.play(&domain.AlertReaction{Type: domain.AlertFollow, Color: green, Duration: 150 * time.Millisecond}, &domain.Alert{User: "second"}))
+	assert.Equal(t, green, devices.Color())
+
+	assert.Eventually(t, func() bool {
+		color := devices.Color()
+		return color != nil && *color == *blue
+	}, time.Second, 5*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		devices.mu.Lock()
+		defer devices.mu.Unlock()
+		return devices.state.RGB == nil && devices.state.WhiteBalance != nil
+	}, time.Second, 5*time.Millisecond)
+	assert.Nil(t, arbiter.Owner(testDeviceAddr))
+}
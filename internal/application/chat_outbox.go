@@ -0,0 +1,219 @@
+package application
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+)
+
+// maxOutboxQueue caps the replies waiting for the rate limit, newer replies are dropped
+const maxOutboxQueue = 50
+
+// ReplyLimiter counts the messages a chat account sent within the rate window.
+// Twitch limits messages per account, so the chats written by one account share a limiter.
+type ReplyLimiter struct {
+	limit  func() int
+	window time.Duration
+	sent   []time.Time // Send times within the current window
+	mu     sync.Mutex
+}
+
+// NewReplyLimiter creates a limiter allowing limit() messages per window
+func NewReplyLimiter(limit func() int, window time.Duration) *ReplyLimiter {
+	return &ReplyLimiter{
+		limit:  limit,
+		window: window,
+		sent:   make([]time.Time, 0),
+	}
+}
+
+// take claims a message. If the limit is reached it returns false
+// and how long until the oldest message leaves the window.
+func (l *ReplyLimiter) take() (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	// Forget sends that left the window
+	kept := l.sent[:0]
+	for _, sentAt := range l.sent {
+		if now.Sub(sentAt) < l.window {
+			kept = append(kept, sentAt)
+		}
+	}
+	l.sent = kept
+
+	if len(l.sent) >= l.limit() {
+		return l.window - now.Sub(l.sent[0]), false
+	}
+
+	l.sent = append(l.sent, now)
+	return 0, true
+}
+
+// ChatOutbox rate limits the bot replies of a single chat.
+// Replies over the limit wait in a queue, queued cooldown replies collapse into one
+// and a viewer is reminded of a cooldown at most once per rate window.
+type ChatOutbox struct {
+	send    func(message string)
+	render  func(event domain.ReplyEvent, data domain.ReplyData) string
+	limiter *ReplyLimiter
+
+	queue        []*outboxReply       // Replies waiting for the rate limit
+	cooldownSent map[string]time.Time // Viewer -> last cooldown reply
+	timer        *time.Timer
+	closed       bool
+	mu           sync.Mutex
+	log          *slog.Logger
+}
+
+// outboxReply is a reply waiting to be rendered and sent
+type outboxReply struct {
+	event domain.ReplyEvent
+	data  domain.ReplyData
+}
+
+// NewChatOutbox creates an outbox sending through send.
+// Replies are rendered when they are sent, an empty rendering drops the reply.
+func NewChatOutbox(
+	send func(message string),
+	render func(event domain.ReplyEvent, data domain.ReplyData) string,
+	limiter *ReplyLimiter,
+) *ChatOutbox {
+	return &ChatOutbox{
+		send:         send,
+		render:       render,
+		limiter:      limiter,
+		queue:        make([]*outboxReply, 0),
+		cooldownSent: make(map[string]time.Time),
+		log:          logging.Source("twitch"),
+	}
+}
+
+// Enqueue sends a reply now or as soon as the rate limit allows
+func (o *ChatOutbox) Enqueue(event domain.ReplyEvent, data domain.ReplyData) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return
+	}
+
+	if event == domain.ReplyCooldown {
+		if !o.collapseCooldown(data) {
+			return
+		}
+	} else {
+		o.push(&outboxReply{event: event, data: data})
+	}
+
+	o.flush()
+}
+
+// collapseCooldown queues a cooldown reply. It returns false if the reply was
+// dropped or merged into a queued cooldown reply.
+func (o *ChatOutbox) collapseCooldown(data domain.ReplyData) bool {
+	user := strings.ToLower(data.User)
+	now := time.Now()
+
+	if last, exists := o.cooldownSent[user]; exists && now.Sub(last) < o.limiter.window {
+		return false // Already reminded
+	}
+	for name, last := range o.cooldownSent {
+		if now.Sub(last) >= o.limiter.window {
+			delete(o.cooldownSent, name)
+		}
+	}
+	o.cooldownSent[user] = now
+
+	for _, queued := range o.queue {
+		if queued.event != domain.ReplyCooldown && queued.event != domain.ReplyCooldownMany {
+			continue
+		}
+		if len(queued.data.Users) == 0 {
+			queued.data.Users = []string{queued.data.User}
+		}
+		queued.data.Users = append(queued.data.Users, data.User)
+		queued.event = domain.ReplyCooldownMany
+		return false
+	}
+
+	o.push(&outboxReply{event: domain.ReplyCooldown, data: data})
+	return true
+}
+
+// push appends a reply to the queue unless it is full
+func (o *ChatOutbox) push(reply *outboxReply) {
+	if len(o.queue) >= maxOutboxQueue {
+		o.log.Warn("Reply queue full, dropping reply", "event", reply.event)
+		return
+	}
+	o.queue = append(o.queue, reply)
+}
+
+// flush sends queued replies while the rate limit allows and schedules the rest.
+// Must be called with the lock held.
+func (o *ChatOutbox) flush() {
+	for len(o.queue) > 0 {
+		reply := o.queue[0]
+
+		message := o.render(reply.event, reply.data)
+		if message == "" {
+			o.queue = o.queue[1:]
+			continue
+		}
+
+		wait, ok := o.limiter.take()
+		if !ok {
+			o.retryAfter(wait)
+			return
+		}
+
+		o.queue = o.queue[1:]
+		o.send(message)
+	}
+}
+
+// retryAfter flushes the queue again once the limiter frees up.
+// Must be called with the lock held.
+func (o *ChatOutbox) retryAfter(wait time.Duration) {
+	if o.timer != nil {
+		return
+	}
+
+	o.timer = time.AfterFunc(wait, func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+
+		o.timer = nil
+		if !o.closed {
+			o.flush()
+		}
+	})
+}
+
+// Pending returns the number of replies waiting for the rate limit
+func (o *ChatOutbox) Pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return len(o.queue)
+}
+
+// Close drops all waiting replies
+func (o *ChatOutbox) Close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.closed = true
+	o.queue = nil
+	if o.timer != nil {
+		o.timer.Stop()
+		o.timer = nil
+	}
+}
@@ -0,0 +1,106 @@
+package application
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingOutbox creates an outbox that records rendered replies as "event:users"
+func recordingOutbox(limit int, window time.Duration) (*ChatOutbox, func() []string) {
+	var mu sync.Mutex
+	sent := make([]string, 0)
+
+	outbox := NewChatOutbox(
+		func(message string) {
+			mu.Lock()
+			defer mu.Unlock()
+			sent = append(sent, message)
+		},
+		func(event domain.ReplyEvent, data domain.ReplyData) string {
+			return string(event) + ":" + strings.TrimPrefix(data.Mention(), "@")
+		},
+		NewReplyLimiter(func() int { return limit }, window),
+	)
+
+	return outbox, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), sent...)
+	}
+}
+
+func TestChatOutboxRateLimit(t *testing.T) {
+	outbox, sent := recordingOutbox(2, 100*time.Millisecond)
+	defer outbox.Close()
+
+	outbox.Enqueue(domain.ReplySuccess, domain.ReplyData{User: "alice"})
+	outbox.Enqueue(domain.ReplySuccess, domain.ReplyData{User: "bob"})
+	outbox.Enqueue(domain.ReplySuccess, domain.ReplyData{User: "carol"})
+
+	assert.Equal(t, []string{"success:alice", "success:bob"}, sent())
+	assert.Equal(t, 1, outbox.Pending())
+
+	// The queued reply goes out once the window frees up
+	assert.Eventually(t, func() bool { return len(sent()) == 3 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "success:carol", sent()[2])
+}
+
+func TestChatOutboxCollapsesCooldowns(t *testing.T) {
+	outbox, sent := recordingOutbox(1, time.Hour)
+	defer outbox.Close()
+
+	outbox.Enqueue(domain.ReplySuccess, domain.ReplyData{User: "alice"})
+	outbox.Enqueue(domain.ReplyCooldown, domain.ReplyData{User: "bob"})
+	outbox.Enqueue(domain.ReplyCooldown, domain.ReplyData{User: "carol"})
+	outbox.Enqueue(domain.ReplyCooldown, domain.ReplyData{User: "Bob"}) // Already reminded
+
+	assert.Equal(t, []string{"success:alice"}, sent())
+	assert.Equal(t, 1, outbox.Pending())
+
+	outbox.mu.Lock()
+	queued := *outbox.queue[0]
+	outbox.mu.Unlock()
+	assert.Equal(t, domain.ReplyCooldownMany, queued.event)
+	assert.Equal(t, []string{"bob", "carol"}, queued.data.Users)
+
+	outbox.Close()
+	assert.Equal(t, 0, outbox.Pending())
+}
+
+func TestChatOutboxesShareLimiter(t *testing.T) {
+	var mu sync.Mutex
+	sent := make([]string, 0)
+	newOutbox := func(chat string, limiter *ReplyLimiter) *ChatOutbox {
+		return NewChatOutbox(
+			func(message string) {
+				mu.Lock()
+				defer mu.Unlock()
+				sent = append(sent, chat+":"+message)
+			},
+			func(event domain.ReplyEvent, data domain.ReplyData) string { return data.User },
+			limiter,
+		)
+	}
+
+	// Both chats are written by the same account
+	limiter := NewReplyLimiter(func() int { return 2 }, 100*time.Millisecond)
+	main := newOutbox("main", limiter)
+	defer main.Close()
+	costream := newOutbox("costream", limiter)
+	defer costream.Close()
+
+	main.Enqueue(domain.ReplySuccess, domain.ReplyData{User: "alice"})
+	costream.Enqueue(domain.ReplySuccess, domain.ReplyData{User: "bob"})
+	costream.Enqueue(domain.ReplySuccess, domain.ReplyData{User: "carol"})
+	assert.Equal(t, 1, costream.Pending())
+
+	assert.Eventually(t, func() bool { return costream.Pending() == 0 }, time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"main:alice", "costream:bob", "costream:carol"}, sent)
+}
@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/console"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/twitch"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/youtube"
+)
+
+// ChatSource is a chat the Twitch service reads lamp commands from.
+// Sources report commands and connection changes through their handlers
+// and keep reconnecting on their own until Disconnect is called.
+type ChatSource interface {
+	Connect(ctx context.Context) error
+	Disconnect() error
+	Status() domain.ConnectionStatus
+	SendMessage(message string)
+	SetMessageHandler(handler domain.ChatCommandHandler)
+	SetStateHandler(handler domain.ConnectionStateHandler)
+	SetPresenceHandler(handler domain.ChatPresenceHandler)
+}
+
+// chatConnection is a running chat source and the channel it serves
+type chatConnection struct {
+	channel domain.ChatChannel
+	source  ChatSource
+	outbox  *ChatOutbox // Rate limits the bot replies to the source
+}
+
+// newChatSource creates the source for a chat channel
+func (s *TwitchService) newChatSource(channel domain.ChatChannel, config *domain.TwitchConfig) (ChatSource, error) {
+	switch channel.Platform {
+	case domain.PlatformTwitch:
+		return twitch.NewIRCClient(config.BotUsername, config.AccessToken, channel.Channel), nil
+	case domain.PlatformYouTube:
+		if s.youtubeAPIKey == "" {
+			return nil, fmt.Errorf("YouTube API key is not configured")
+		}
+		return youtube.NewChatClient(s.youtubeAPIKey, channel.Channel), nil
+	case domain.PlatformConsole:
+		return console.NewChatSource(channel.Channel, console.StdinLines(), os.Stdout), nil
+	default:
+		return nil, fmt.Errorf("unsupported chat platform: %s", channel.Platform)
+	}
+}
@@ -36,12 +36,64 @@ func (m *CooldownManager) CheckUser(username string, cooldown time.Duration) (bo
 	return m.state.CheckUserCooldown(username, cooldown)
 }
 
+// CheckCommand checks the cooldown of a single command
+func (m *CooldownManager) CheckCommand(command string, cooldown time.Duration) (bool, time.Duration) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.state.CheckCommandCooldown(command, cooldown)
+}
+
 // RecordCommand records a command execution
-func (m *CooldownManager) RecordCommand(username string) {
+func (m *CooldownManager) RecordCommand(username, command string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.state.RecordCommand(username, command)
+}
+
+// RecordGlobal records a command that only affects the global cooldown
+func (m *CooldownManager) RecordGlobal() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.state.RecordCommand(username)
+	m.state.LastGlobalCommand = time.Now()
+}
+
+// CooldownStatus holds the running cooldowns of a device
+type CooldownStatus struct {
+	DeviceAddress string
+	Global        time.Duration
+	Commands      map[string]time.Duration // command -> remaining cooldown
+	Users         map[string]time.Duration // username -> remaining cooldown
+}
+
+// Status returns the remaining cooldowns under the given config, expired ones are left out
+func (m *CooldownManager) Status(config *domain.TwitchConfig) CooldownStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	status := CooldownStatus{
+		Commands: make(map[string]time.Duration),
+		Users:    make(map[string]time.Duration),
+	}
+
+	_, status.Global = m.state.CheckGlobalCooldown(config.GlobalCooldown)
+
+	for command := range m.state.CommandLastRun {
+		cooldown := config.ResolveCommand(command).Cooldown
+		if _, remaining := m.state.CheckCommandCooldown(command, cooldown); remaining > 0 {
+			status.Commands[command] = remaining
+		}
+	}
+
+	for username := range m.state.UserLastCommand {
+		if _, remaining := m.state.CheckUserCooldown(username, config.UserCooldown); remaining > 0 {
+			status.Users[username] = remaining
+		}
+	}
+
+	return status
 }
 
 // Reset resets all cooldowns
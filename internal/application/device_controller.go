@@ -0,0 +1,76 @@
+package application
+
+import (
+	"context"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// DeviceController changes the state of the lamps.
+// It is implemented by DeviceService and by the safety filter in front of it.
+type DeviceController interface {
+	SetPower(ctx context.Context, address string, on bool) error
+	SetColor(ctx context.Context, address string, r, g, b uint8) error
+	SetBrightness(ctx context.Context, address string, level uint8) error
+	SetWhiteBalance(ctx context.Context, address string, warm, cold uint8) error
+	SetEffect(ctx context.Context, address string, effect, speed uint8) error
+	ApplyState(ctx context.Context, address string, state domain.DeviceState) error
+}
+
+// DeviceRoute holds the controller a service sends its lamp changes to. Embedded
+// in every service that changes lamps, it starts at the DeviceService and is
+// switched to the safety filter by the server when Twitch is set up.
+type DeviceRoute struct {
+	devices DeviceController
+}
+
+// NewDeviceRoute creates a route to the given controller
+func NewDeviceRoute(devices DeviceController) DeviceRoute {
+	return DeviceRoute{devices: devices}
+}
+
+// Devices returns the controller lamp changes are sent to
+func (r *DeviceRoute) Devices() DeviceController {
+	return r.devices
+}
+
+// SetDeviceController changes the controller lamp changes are sent to
+func (r *DeviceRoute) SetDeviceController(devices DeviceController) {
+	r.devices = devices
+}
+
+// ChangeFunc sends a change made on behalf of the streamer to a lamp
+type ChangeFunc func(ctx context.Context, deviceAddr string, change domain.StateChange) error
+
+// ApplyChange sends the set fields of a change to the lamp
+func ApplyChange(ctx context.Context, devices DeviceController, deviceAddr string, change domain.StateChange) error {
+	if change.PowerOn != nil {
+		if err := devices.SetPower(ctx, deviceAddr, *change.PowerOn); err != nil {
+			return err
+		}
+		if !*change.PowerOn {
+			return nil
+		}
+	}
+
+	if change.Brightness != nil {
+		if err := devices.SetBrightness(ctx, deviceAddr, *change.Brightness); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case change.RGB != nil:
+		return devices.SetColor(ctx, deviceAddr, change.RGB.R, change.RGB.G, change.RGB.B)
+	case change.WhiteBalance != nil:
+		return devices.SetWhiteBalance(ctx, deviceAddr, change.WhiteBalance.Warm, change.WhiteBalance.Cold)
+	case change.Effect != nil:
+		speed := domain.DefaultEffectSpeed
+		if change.EffectSpeed != nil {
+			speed = *change.EffectSpeed
+		}
+		return devices.SetEffect(ctx, deviceAddr, uint8(*change.Effect), speed)
+	}
+
+	return nil
+}
@@ -2,13 +2,15 @@ package application
 
 import (
 	"context"
-	"encoding/hex"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/codeneuss/lampcontrol/internal/domain"
 	"github.com/codeneuss/lampcontrol/internal/infrastructure/bluetooth"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/metrics"
 	"github.com/codeneuss/lampcontrol/pkg/protocol"
 )
 
@@ -21,6 +23,11 @@ type DeviceService struct {
 	connectTimeout time.Duration
 	writeTimeout   time.Duration
 	retryAttempts  int
+	log            *slog.Logger
+
+	// Callbacks
+	onStateChange      func(address string, state domain.DeviceState)
+	onConnectionChange func(address string, connected bool)
 }
 
 // NewDeviceService creates a new device service
@@ -32,6 +39,7 @@ func NewDeviceService(adapter *bluetooth.Adapter) *DeviceService {
 		connectTimeout: 10 * time.Second,
 		writeTimeout:   5 * time.Second,
 		retryAttempts:  3,
+		log:            logging.Source("ble"),
 	}
 }
 
@@ -90,77 +98,102 @@ func (s *DeviceService) ListDevices() []*domain.Device {
 
 func (s *DeviceService) connect(ctx context.Context, address string) (*bluetooth.Connection, error) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
 	if conn, exists := s.connections[address]; exists {
+		s.mu.Unlock()
 		return conn, nil
 	}
 
 	conn, err := s.bleAdapter.Connect(ctx, address, s.connectTimeout)
 	if err != nil {
+		s.mu.Unlock()
 		return nil, err
 	}
 	s.connections[address] = conn
+	metrics.DeviceConnected.With(address).Set(1)
 
 	// === ELK-BLEDOM DISCOVERY ===
 	if dev, exists := s.devices[address]; exists {
 		dev.MarkConnected()
 	}
+	callback := s.onConnectionChange
+	s.mu.Unlock()
 
+	if callback != nil {
+		callback(address, true)
+	}
 	return conn, nil
 }
 
 // disconnect closes a connection to a device
 func (s *DeviceService) Disconnect(address string) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
 	conn, exists := s.connections[address]
 	if !exists {
+		s.mu.Unlock()
 		return nil // Already disconnected
 	}
 
 	if err := s.bleAdapter.Disconnect(conn); err != nil {
+		s.mu.Unlock()
 		return err
 	}
 
 	delete(s.connections, address)
+	metrics.DeviceConnected.With(address).Set(0)
 
 	// Update device status
 	if dev, exists := s.devices[address]; exists {
 		dev.MarkDisconnected()
 	}
+	callback := s.onConnectionChange
+	s.mu.Unlock()
 
+	if callback != nil {
+		callback(address, false)
+	}
 	return nil
 }
 
 func (s *DeviceService) writeCommand(ctx context.Context, address string, cmd protocol.Command) error {
 	var lastErr error
 
+	started := time.Now()
+	defer func() {
+		metrics.DeviceCommandDuration.With(cmd.Name()).Observe(time.Since(started).Seconds())
+	}()
+
 	for attempt := 0; attempt < s.retryAttempts; attempt++ {
-		// Connect + get Connection (nicht Device!)
+		if attempt > 0 {
+			metrics.DeviceCommandRetries.With(bluetooth.ErrorType(lastErr)).Inc()
+		}
+
+		// The connection holds the write characteristic
 		conn, err := s.connect(ctx, address)
 		if err != nil {
+			s.log.Debug("Connect attempt failed", "device", address, "attempt", attempt+1, "error", err)
 			lastErr = err
 			time.Sleep(500 * time.Millisecond)
 			continue
 		}
 
-		// Adapter.Write() verwendet die Connection.characteristic!
 		writeCtx, cancel := context.WithTimeout(ctx, s.writeTimeout)
 		err = s.bleAdapter.Write(writeCtx, conn, cmd.Bytes())
 		cancel()
 
 		if err == nil {
-			fmt.Println("✓ Command gesendet:", hex.EncodeToString(cmd.Bytes()))
+			s.log.Debug("Command sent", "device", address, "command", cmd.Name(), "attempts", attempt+1)
 			return nil
 		}
+		s.log.Debug("Command attempt failed", "device", address, "command", cmd.Name(), "attempt", attempt+1, "error", err)
 
 		lastErr = err
 		s.Disconnect(address)
 		time.Sleep(500 * time.Millisecond)
 	}
 
+	metrics.DeviceCommandFailures.With(bluetooth.ErrorType(lastErr)).Inc()
 	return fmt.Errorf("failed after %d attempts: %w", s.retryAttempts, lastErr)
 }
 
@@ -173,15 +206,7 @@ func (s *DeviceService) SetPower(ctx context.Context, address string, on bool) e
 	}
 
 	// Update local state
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if dev, exists := s.devices[address]; exists {
-		state := dev.State
-		state.PowerOn = on
-		state.LastUpdated = time.Now()
-		dev.UpdateState(state)
-	}
+	s.updateState(address, domain.StateChange{PowerOn: &on})
 
 	return nil
 }
@@ -195,18 +220,8 @@ func (s *DeviceService) SetColor(ctx context.Context, address string, r, g, b ui
 	}
 
 	// Update local state
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if dev, exists := s.devices[address]; exists {
-		state := dev.State
-		rgb, _ := domain.NewRGB(r, g, b)
-		state.RGB = &rgb
-		state.WhiteBalance = nil // Clear white balance when setting RGB
-		state.Effect = nil       // Clear effect when setting RGB
-		state.LastUpdated = time.Now()
-		dev.UpdateState(state)
-	}
+	rgb, _ := domain.NewRGB(r, g, b)
+	s.updateState(address, domain.StateChange{RGB: &rgb})
 
 	return nil
 }
@@ -220,15 +235,7 @@ func (s *DeviceService) SetBrightness(ctx context.Context, address string, level
 	}
 
 	// Update local state
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if dev, exists := s.devices[address]; exists {
-		state := dev.State
-		state.Brightness = level
-		state.LastUpdated = time.Now()
-		dev.UpdateState(state)
-	}
+	s.updateState(address, domain.StateChange{Brightness: &level})
 
 	return nil
 }
@@ -242,17 +249,8 @@ func (s *DeviceService) SetWhiteBalance(ctx context.Context, address string, war
 	}
 
 	// Update local state
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if dev, exists := s.devices[address]; exists {
-		state := dev.State
-		state.WhiteBalance = &domain.WhiteBalance{Warm: warm, Cold: cold}
-		state.RGB = nil    // Clear RGB when setting white balance
-		state.Effect = nil // Clear effect when setting white balance
-		state.LastUpdated = time.Now()
-		dev.UpdateState(state)
-	}
+	wb := domain.WhiteBalance{Warm: warm, Cold: cold}
+	s.updateState(address, domain.StateChange{WhiteBalance: &wb})
 
 	return nil
 }
@@ -266,16 +264,55 @@ func (s *DeviceService) SetEffect(ctx context.Context, address string, effect, s
 	}
 
 	// Update local state
+	effectInt := int(effect)
+	s.updateState(address, domain.StateChange{Effect: &effectInt, EffectSpeed: &speed})
+
+	return nil
+}
+
+// updateState applies a change to the assumed state of a device and reports the new state
+func (s *DeviceService) updateState(address string, change domain.StateChange) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	dev, exists := s.devices[address]
+	if !exists {
+		s.mu.Unlock()
+		return
+	}
+	dev.UpdateState(change.Apply(dev.State))
+	state := dev.State
+	callback := s.onStateChange
+	s.mu.Unlock()
 
-	if dev, exists := s.devices[address]; exists {
-		state := dev.State
-		effectInt := int(effect)
-		state.Effect = &effectInt
-		state.EffectSpeed = &speed
-		state.LastUpdated = time.Now()
-		dev.UpdateState(state)
+	if callback != nil {
+		callback(address, state)
+	}
+}
+
+// ApplyState restores a full device state: power, brightness and the active color mode
+func (s *DeviceService) ApplyState(ctx context.Context, address string, state domain.DeviceState) error {
+	if err := s.SetPower(ctx, address, state.PowerOn); err != nil {
+		return err
+	}
+
+	if !state.PowerOn {
+		return nil
+	}
+
+	if err := s.SetBrightness(ctx, address, state.Brightness); err != nil {
+		return err
+	}
+
+	switch {
+	case state.RGB != nil:
+		return s.SetColor(ctx, address, state.RGB.R, state.RGB.G, state.RGB.B)
+	case state.WhiteBalance != nil:
+		return s.SetWhiteBalance(ctx, address, state.WhiteBalance.Warm, state.WhiteBalance.Cold)
+	case state.Effect != nil:
+		speed := domain.DefaultEffectSpeed
+		if state.EffectSpeed != nil {
+			speed = *state.EffectSpeed
+		}
+		return s.SetEffect(ctx, address, uint8(*state.Effect), speed)
 	}
 
 	return nil
@@ -299,3 +336,19 @@ func (s *DeviceService) DisconnectAll() error {
 
 	return lastErr
 }
+
+// SetStateChangeCallback sets the callback for changes to the assumed state of a device
+func (s *DeviceService) SetStateChangeCallback(callback func(address string, state domain.DeviceState)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onStateChange = callback
+}
+
+// SetConnectionChangeCallback sets the callback for lamps connecting and disconnecting
+func (s *DeviceService) SetConnectionChangeCallback(callback func(address string, connected bool)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onConnectionChange = callback
+}
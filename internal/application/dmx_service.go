@@ -0,0 +1,388 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/dmx"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+)
+
+// dmxWatchInterval is how often the inputs are checked for loss
+const dmxWatchInterval = 250 * time.Millisecond
+
+// DMXService drives the lamps from lighting consoles such as QLC+ or xLights
+// over Art-Net and sACN. Each patched lamp gets a frame limiter, as DMX sends
+// up to 44 frames per second and BLE writes can't keep up. When a universe
+// stops sending the lamps hold their last look, or black out if configured.
+// The look is a lease above OBS automation, so viewer effects and alerts still
+// show on top of the console and the lamps return below it when the input stops.
+type DMXService struct {
+	DeviceRoute
+	deviceService *DeviceService
+	arbiter       *LampArbiter
+	storage       *storage.DMXStorage
+	conns         []net.PacketConn
+	serving       sync.WaitGroup
+	fixtures      map[int][]domain.DMXFixture       // universe -> patched lamps
+	limiters      map[string]*FrameLimiter          // deviceAddr -> limiter
+	universes     map[int]*domain.DMXUniverseStatus // universe -> input state
+	timeout       time.Duration
+	onLoss        domain.DMXLossBehavior
+	stopWatch     chan struct{}
+	lastErr       string
+	mu            sync.RWMutex
+	log           *slog.Logger
+
+	// Callbacks
+	onStatusChange func(status domain.DMXStatus)
+	onLampChange   func(deviceAddr string, change domain.StateChange)
+}
+
+// NewDMXService creates a new DMX service
+func NewDMXService(deviceService *DeviceService, arbiter *LampArbiter, storage *storage.DMXStorage) *DMXService {
+	return &DMXService{
+		DeviceRoute:   NewDeviceRoute(deviceService),
+		deviceService: deviceService,
+		arbiter:       arbiter,
+		storage:       storage,
+		log:           logging.Source("dmx"),
+	}
+}
+
+// Start listens for the enabled protocols on the patched universes
+func (s *DMXService) Start(ctx context.Context) error {
+	config := s.storage.Get()
+
+	if !config.Enabled {
+		return fmt.Errorf("DMX input is disabled")
+	}
+
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// Release the ports before listening again
+	s.Stop()
+
+	type listener struct {
+		conn  net.PacketConn
+		parse dmx.Parser
+	}
+	var listeners []listener
+	fail := func(err error) error {
+		for _, l := range listeners {
+			l.conn.Close()
+		}
+		s.setError(err.Error())
+		return err
+	}
+
+	if config.ArtNet {
+		conn, err := dmx.ListenArtNet(domain.DefaultArtNetPort)
+		if err != nil {
+			return fail(err)
+		}
+		listeners = append(listeners, listener{conn: conn, parse: dmx.ParseArtNet})
+	}
+
+	if config.SACN {
+		for _, universe := range config.Universes() {
+			conn, err := dmx.ListenSACN(universe, domain.DefaultSACNPort)
+			if err != nil {
+				return fail(err)
+			}
+
+			// Every socket on the port sees all universes, keep only its own
+			parse := func(packet []byte) (dmx.Frame, error) {
+				frame, err := dmx.ParseSACN(packet)
+				if err == nil && frame.Universe != universe {
+					return frame, dmx.ErrNotDMX
+				}
+				return frame, err
+			}
+			listeners = append(listeners, listener{conn: conn, parse: parse})
+		}
+	}
+
+	interval := time.Second / time.Duration(config.MaxRateOrDefault())
+	fixtures := make(map[int][]domain.DMXFixture)
+	limiters := make(map[string]*FrameLimiter)
+	universes := make(map[int]*domain.DMXUniverseStatus)
+	for _, fixture := range config.Fixtures {
+		fixtures[fixture.Universe] = append(fixtures[fixture.Universe], fixture)
+		limiters[fixture.DeviceAddress] = NewFrameLimiter(interval, s.sendTo(fixture.DeviceAddress))
+		universes[fixture.Universe] = &domain.DMXUniverseStatus{Universe: fixture.Universe}
+	}
+
+	stopWatch := make(chan struct{})
+
+	s.mu.Lock()
+	s.conns = make([]net.PacketConn, 0, len(listeners))
+	s.fixtures = fixtures
+	s.limiters = limiters
+	s.universes = universes
+	s.timeout = config.InputTimeout()
+	s.onLoss = config.OnInputLossOrDefault()
+	s.stopWatch = stopWatch
+	s.lastErr = ""
+	for _, l := range listeners {
+		s.conns = append(s.conns, l.conn)
+		s.serving.Add(1)
+		go func(conn net.PacketConn, parse dmx.Parser) {
+			defer s.serving.Done()
+			if err := dmx.Serve(conn, parse, s.handleFrame); err != nil {
+				s.log.Error("Input stopped", "error", err)
+				s.setError(err.Error())
+			}
+		}(l.conn, l.parse)
+	}
+	s.mu.Unlock()
+
+	go s.watch(stopWatch)
+
+	s.log.Info("Listening", "universes", config.Universes(), "artnet", config.ArtNet,
+		"sacn", config.SACN, "max_rate", config.MaxRateOrDefault())
+	s.notifyStatus()
+	return nil
+}
+
+// Stop closes the listeners. The lamps keep their last look.
+func (s *DMXService) Stop() error {
+	s.mu.Lock()
+	conns := s.conns
+	limiters := s.limiters
+	stopWatch := s.stopWatch
+	s.conns = nil
+	s.limiters = nil
+	s.fixtures = nil
+	s.universes = nil
+	s.stopWatch = nil
+	s.mu.Unlock()
+
+	if conns == nil {
+		return nil
+	}
+
+	close(stopWatch)
+	for _, conn := range conns {
+		conn.Close()
+	}
+	s.serving.Wait()
+
+	// The lamps return to the state below the console
+	for deviceAddr, limiter := range limiters {
+		limiter.Stop()
+		if err := s.arbiter.Release(context.Background(), deviceAddr, domain.LeaseSourceDMX); err != nil {
+			s.log.Error("Failed to restore device", "device", deviceAddr, "error", err)
+		}
+	}
+
+	s.notifyStatus()
+	return nil
+}
+
+// GetStatus returns the input state of the patched universes
+func (s *DMXService) GetStatus() domain.DMXStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := domain.DMXStatus{
+		Running:   s.conns != nil,
+		Universes: make([]domain.DMXUniverseStatus, 0, len(s.universes)),
+		Error:     s.lastErr,
+	}
+	for _, universe := range s.universes {
+		status.Universes = append(status.Universes, *universe)
+	}
+	sort.Slice(status.Universes, func(i, j int) bool {
+		return status.Universes[i].Universe < status.Universes[j].Universe
+	})
+	return status
+}
+
+// GetConfig returns the DMX configuration
+func (s *DMXService) GetConfig() *domain.DMXConfig {
+	return s.storage.Get()
+}
+
+// handleFrame passes a frame on to the limiters of the lamps patched to its universe
+func (s *DMXService) handleFrame(frame dmx.Frame) {
+	s.mu.Lock()
+	universe, patched := s.universes[frame.Universe]
+	if !patched {
+		s.mu.Unlock()
+		return
+	}
+
+	if frame.Terminated {
+		wasActive := universe.Active
+		universe.Active = false
+		s.mu.Unlock()
+
+		if wasActive {
+			s.inputLost(frame.Universe, "stream terminated")
+		}
+		return
+	}
+
+	wasActive := universe.Active
+	universe.Active = true
+	universe.Protocol = frame.Protocol
+	universe.Source = frame.Source
+	universe.LastFrame = time.Now()
+	universe.Frames++
+
+	fixtures := s.fixtures[frame.Universe]
+	limiters := make([]*FrameLimiter, len(fixtures))
+	for i, fixture := range fixtures {
+		limiters[i] = s.limiters[fixture.DeviceAddress]
+	}
+	s.mu.Unlock()
+
+	if !wasActive {
+		s.log.Info("Receiving universe", "universe", frame.Universe, "from", frame.Source, "protocol", frame.Protocol)
+
+		// Other sources may have changed the lamps in the meantime
+		for _, limiter := range limiters {
+			limiter.Forget()
+		}
+		s.notifyStatus()
+	}
+
+	for i, fixture := range fixtures {
+		limiters[i].Push(fixture.Change(frame.Data))
+	}
+}
+
+// watch marks universes that stopped sending as lost until stopped
+func (s *DMXService) watch(stop chan struct{}) {
+	ticker := time.NewTicker(dmxWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		var lost []int
+
+		s.mu.Lock()
+		for number, universe := range s.universes {
+			if universe.Active && now.Sub(universe.LastFrame) > s.timeout {
+				universe.Active = false
+				lost = append(lost, number)
+			}
+		}
+		s.mu.Unlock()
+
+		sort.Ints(lost)
+		for _, number := range lost {
+			s.inputLost(number, "no frames")
+		}
+	}
+}
+
+// inputLost holds the last look of the lamps on a universe, or blacks them out
+func (s *DMXService) inputLost(universe int, reason string) {
+	s.mu.RLock()
+	onLoss := s.onLoss
+	fixtures := s.fixtures[universe]
+	limiters := make([]*FrameLimiter, len(fixtures))
+	for i, fixture := range fixtures {
+		limiters[i] = s.limiters[fixture.DeviceAddress]
+	}
+	s.mu.RUnlock()
+
+	if onLoss == domain.DMXLossBlackout {
+		s.log.Warn("Input lost, blacking out", "universe", universe, "reason", reason)
+		off := false
+		for _, limiter := range limiters {
+			limiter.Push(domain.StateChange{PowerOn: &off})
+		}
+	} else {
+		s.log.Warn("Input lost, holding last look", "universe", universe, "reason", reason)
+	}
+
+	s.notifyStatus()
+}
+
+// sendTo returns the send function of the limiter of a lamp. A lamp that keeps
+// failing is only logged once, DMX would otherwise log every frame.
+func (s *DMXService) sendTo(deviceAddr string) func(change domain.StateChange) error {
+	failing := false // Only used by the limiter goroutine
+
+	return func(change domain.StateChange) error {
+		if err := s.hold(deviceAddr, change); err != nil {
+			if !failing {
+				s.log.Error("Failed to update device", "device", deviceAddr, "error", err)
+			}
+			failing = true
+			return err
+		}
+
+		failing = false
+		s.notifyLampChange(deviceAddr, change)
+		return nil
+	}
+}
+
+// hold lays a frame on the DMX lease of a lamp. It builds on the earlier frames,
+// so the lease keeps the whole look when only some channels changed.
+func (s *DMXService) hold(deviceAddr string, change domain.StateChange) error {
+	combined := change
+	if held := s.arbiter.Lease(deviceAddr, domain.LeaseSourceDMX); held != nil {
+		combined = held.Change.Then(change)
+	}
+
+	lease := domain.Lease{
+		Source:   domain.LeaseSourceDMX,
+		Holder:   "console",
+		Priority: domain.PriorityLive,
+		Change:   combined,
+	}
+	return s.arbiter.Acquire(context.Background(), deviceAddr, lease, s.devices)
+}
+
+// setError records why the input stopped
+func (s *DMXService) setError(message string) {
+	s.mu.Lock()
+	s.lastErr = message
+	s.mu.Unlock()
+
+	s.notifyStatus()
+}
+
+// notifyStatus reports the current status to the status callback
+func (s *DMXService) notifyStatus() {
+	if s.onStatusChange != nil {
+		s.onStatusChange(s.GetStatus())
+	}
+}
+
+// notifyLampChange reports a lamp change to the lamp change callback
+func (s *DMXService) notifyLampChange(deviceAddr string, change domain.StateChange) {
+	if s.onLampChange != nil {
+		s.onLampChange(deviceAddr, change)
+	}
+}
+
+// SetStatusChangeCallback sets the callback for input changes
+func (s *DMXService) SetStatusChangeCallback(callback func(status domain.DMXStatus)) {
+	s.onStatusChange = callback
+}
+
+// SetLampChangeCallback sets the callback for lamp changes made by a console
+func (s *DMXService) SetLampChangeCallback(callback func(deviceAddr string, change domain.StateChange)) {
+	s.onLampChange = callback
+}
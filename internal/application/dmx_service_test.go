@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDMXLookYieldsToViewers(t *testing.T) {
+	deviceService, arbiter, devices := newTestLamp(t)
+	service := NewDMXService(deviceService, arbiter, nil)
+	service.SetDeviceController(devices)
+	ctx := context.Background()
+
+	red := &domain.RGB{R: 255}
+	green := &domain.RGB{G: 255}
+	require.NoError(t, service.hold(testDeviceAddr, domain.StateChange{RGB: red}))
+	assert.Equal(t, red, devices.Color())
+
+	require.NoError(t, arbiter.Acquire(ctx, testDeviceAddr, domain.Lease{
+		Source:   domain.LeaseSourceViewer,
+		Priority: domain.PriorityViewer,
+		Change:   domain.StateChange{RGB: green},
+	}, devices))
+
+	// Console frames keep coming in below the viewer effect
+	dim := uint8(100)
+	require.NoError(t, service.hold(testDeviceAddr, domain.StateChange{Brightness: &dim}))
+	assert.Equal(t, green, devices.Color())
+
+	// The lamp returns to the whole look of the console
+	require.NoError(t, arbiter.Release(ctx, testDeviceAddr, domain.LeaseSourceViewer))
+	assert.Equal(t, red, devices.Color())
+	assert.Equal(t, domain.LeaseSourceDMX, arbiter.Owner(testDeviceAddr).Source)
+	assert.Equal(t, dim, *arbiter.Owner(testDeviceAddr).Change.Brightness)
+}
+
+func TestPausedLampRefusesConsole(t *testing.T) {
+	deviceService, arbiter, devices := newTestLamp(t)
+	service := NewDMXService(deviceService, arbiter, nil)
+	service.SetDeviceController(devices)
+	ctx := context.Background()
+
+	red := &domain.RGB{R: 255}
+	blue := &domain.RGB{B: 255}
+	require.NoError(t, service.hold(testDeviceAddr, domain.StateChange{RGB: red}))
+
+	// A panic drops the console look and keeps the next frames off the lamp
+	arbiter.Pause(testDeviceAddr)
+	require.NoError(t, arbiter.SetBase(ctx, testDeviceAddr, domain.StateChange{RGB: blue}, devices))
+	assert.ErrorIs(t, service.hold(testDeviceAddr, domain.StateChange{RGB: red}), domain.ErrLampPaused)
+	assert.Equal(t, blue, devices.Color())
+	assert.Nil(t, arbiter.Owner(testDeviceAddr))
+
+	arbiter.Resume()
+	require.NoError(t, service.hold(testDeviceAddr, domain.StateChange{RGB: red}))
+	assert.Equal(t, red, devices.Color())
+}
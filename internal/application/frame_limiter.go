@@ -0,0 +1,110 @@
+package application
+
+import (
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// FrameLimiter passes a stream of looks for one lamp on at a maximum rate.
+// Frames arriving while a write is in flight or the interval has not passed
+// replace each other, so only the latest look is sent. Only the fields that
+// differ from the last look sent reach the lamp, a console repeating the same
+// frame costs no BLE writes.
+type FrameLimiter struct {
+	send     func(change domain.StateChange) error
+	interval time.Duration
+
+	pending *domain.StateChange
+	sent    domain.StateChange // Last look sent to the lamp
+	wake    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	mu      sync.Mutex
+}
+
+// NewFrameLimiter creates a limiter sending at most one change per interval
+func NewFrameLimiter(interval time.Duration, send func(change domain.StateChange) error) *FrameLimiter {
+	l := &FrameLimiter{
+		send:     send,
+		interval: interval,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// Push queues a look, replacing a look still waiting
+func (l *FrameLimiter) Push(change domain.StateChange) {
+	l.mu.Lock()
+	l.pending = &change
+	l.mu.Unlock()
+
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Forget drops the memory of the last look, so the next look is sent in full
+// even where it matches, e.g. after something else changed the lamp
+func (l *FrameLimiter) Forget() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sent = domain.StateChange{}
+}
+
+// Stop ends the limiter, dropping a look still waiting
+func (l *FrameLimiter) Stop() {
+	close(l.stop)
+	<-l.done
+}
+
+// run sends the waiting looks until stopped
+func (l *FrameLimiter) run() {
+	defer close(l.done)
+
+	timer := time.NewTimer(0)
+	<-timer.C
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-l.wake:
+		}
+
+		l.mu.Lock()
+		look := l.pending
+		l.pending = nil
+		var delta domain.StateChange
+		if look != nil {
+			delta = look.Since(l.sent)
+		}
+		l.mu.Unlock()
+
+		if delta.IsZero() {
+			continue
+		}
+
+		started := time.Now()
+		if err := l.send(delta); err == nil {
+			l.mu.Lock()
+			l.sent = l.sent.Then(*look)
+			l.mu.Unlock()
+		}
+
+		// Hold off the next frame for the rest of the interval
+		timer.Reset(l.interval - time.Since(started))
+		select {
+		case <-l.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
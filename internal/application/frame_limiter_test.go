@@ -0,0 +1,65 @@
+package application
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameLimiter(t *testing.T) {
+	var mu sync.Mutex
+	sent := make([]domain.StateChange, 0)
+
+	limiter := NewFrameLimiter(50*time.Millisecond, func(change domain.StateChange) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, change)
+		return nil
+	})
+	defer limiter.Stop()
+
+	sentChanges := func() []domain.StateChange {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]domain.StateChange(nil), sent...)
+	}
+
+	on := true
+	full := uint8(255)
+	look := func(r uint8) domain.StateChange {
+		return domain.StateChange{PowerOn: &on, Brightness: &full, RGB: &domain.RGB{R: r}}
+	}
+
+	// A console streaming a fade at 44 fps for 100ms
+	for r := uint8(1); r <= 5; r++ {
+		limiter.Push(look(r))
+		time.Sleep(20 * time.Millisecond)
+	}
+	limiter.Push(look(200))
+
+	require.Eventually(t, func() bool {
+		changes := sentChanges()
+		return len(changes) > 0 && *changes[len(changes)-1].RGB == domain.RGB{R: 200}
+	}, time.Second, 5*time.Millisecond, "the latest look always reaches the lamp")
+
+	changes := sentChanges()
+	assert.LessOrEqual(t, len(changes), 4, "frames coalesced to the rate")
+	assert.Equal(t, look(1), changes[0], "first look sent in full")
+	assert.Equal(t, domain.StateChange{RGB: &domain.RGB{R: 200}}, changes[len(changes)-1], "unchanged fields skipped")
+
+	// Repeating the same frame sends nothing
+	count := len(changes)
+	limiter.Push(look(200))
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, sentChanges(), count)
+
+	// After Forget the look is sent in full again
+	limiter.Forget()
+	limiter.Push(look(200))
+	require.Eventually(t, func() bool { return len(sentChanges()) == count+1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, look(200), sentChanges()[count])
+}
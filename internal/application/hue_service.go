@@ -0,0 +1,299 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/hue"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+)
+
+// hueBridgeName is the bridge name Hue apps show
+const hueBridgeName = "LampControl"
+
+// HueService emulates a Hue bridge so Hue apps and integrations can control the
+// lamps. Apps find the bridge over SSDP and pair while the link button, pressed
+// in the web UI, is active. Commands go to the base layer below viewer effects
+// and alerts, like changes made by the streamer.
+type HueService struct {
+	deviceService *DeviceService
+	apply         ChangeFunc // Where commands are sent, straight to the lamp unless set
+	storage       *storage.HueStorage
+	mac           string
+	server        *http.Server
+	responder     *hue.Responder
+	port          int
+	linkUntil     time.Time
+	lastErr       string
+	mu            sync.RWMutex
+	configMu      sync.Mutex // Serializes read-modify-write of the stored config
+	log           *slog.Logger
+
+	// Callbacks
+	onStatusChange func(status domain.HueStatus)
+}
+
+// NewHueService creates a new Hue service
+func NewHueService(deviceService *DeviceService, storage *storage.HueStorage) *HueService {
+	return &HueService{
+		deviceService: deviceService,
+		apply: func(ctx context.Context, deviceAddr string, change domain.StateChange) error {
+			return ApplyChange(ctx, deviceService, deviceAddr, change)
+		},
+		storage: storage,
+		mac:     hueMAC(),
+		log:     logging.Source("hue"),
+	}
+}
+
+// hueMAC derives a stable MAC address from the host name, apps remember a bridge by it
+func hueMAC() string {
+	hostname, _ := os.Hostname()
+	h := fnv.New64a()
+	h.Write([]byte("lampcontrol-hue:" + hostname))
+	return fmt.Sprintf("%012x", h.Sum64()&0xffffffffffff)
+}
+
+// Start serves the bridge API and answers discovery
+func (s *HueService) Start(ctx context.Context) error {
+	config := s.storage.Get()
+
+	if !config.Enabled {
+		return fmt.Errorf("Hue bridge is disabled")
+	}
+
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// Free the port before listening again
+	s.Stop()
+
+	port := config.PortOrDefault()
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		err = fmt.Errorf("failed to listen on port %d: %w", port, err)
+		s.setError(err.Error())
+		return err
+	}
+
+	server := &http.Server{
+		Handler:           hue.NewBridge(s, hueBridgeName, s.mac),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.Error("Bridge stopped", "error", err)
+			s.setError(err.Error())
+		}
+	}()
+
+	// The bridge still works without discovery, apps can be given the address by hand
+	responder := hue.NewResponder(port, s.mac)
+	if err := responder.Start(); err != nil {
+		s.log.Warn("Discovery unavailable", "error", err)
+		responder = nil
+	}
+
+	s.mu.Lock()
+	s.server = server
+	s.responder = responder
+	s.port = port
+	s.lastErr = ""
+	s.mu.Unlock()
+
+	s.log.Info("Bridge listening", "bridge_id", hue.BridgeID(s.mac), "port", port)
+	s.notifyStatus()
+	return nil
+}
+
+// Stop shuts the bridge down
+func (s *HueService) Stop() error {
+	s.mu.Lock()
+	server := s.server
+	responder := s.responder
+	s.server = nil
+	s.responder = nil
+	s.mu.Unlock()
+
+	if responder != nil {
+		responder.Stop()
+	}
+	if server == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := server.Shutdown(ctx)
+
+	s.notifyStatus()
+	return err
+}
+
+// GetStatus returns the bridge status
+func (s *HueService) GetStatus() domain.HueStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := domain.HueStatus{
+		Running:   s.server != nil,
+		Port:      s.port,
+		Discovery: s.responder != nil,
+		Error:     s.lastErr,
+	}
+	if time.Now().Before(s.linkUntil) {
+		status.LinkUntil = s.linkUntil
+	}
+	return status
+}
+
+// GetConfig returns the Hue configuration
+func (s *HueService) GetConfig() *domain.HueConfig {
+	return s.storage.Get()
+}
+
+// PressLinkButton opens the pairing window for apps
+func (s *HueService) PressLinkButton() domain.HueStatus {
+	s.mu.Lock()
+	s.linkUntil = time.Now().Add(domain.HueLinkWindow)
+	s.mu.Unlock()
+
+	s.log.Info("Link button pressed, pairing open", "window", domain.HueLinkWindow)
+
+	// Report the window closing as well
+	time.AfterFunc(domain.HueLinkWindow, s.notifyStatus)
+	s.notifyStatus()
+	return s.GetStatus()
+}
+
+// CreateUser pairs an app while the link button is pressed
+func (s *HueService) CreateUser(deviceType string) (string, error) {
+	s.mu.RLock()
+	linking := time.Now().Before(s.linkUntil)
+	s.mu.RUnlock()
+
+	if !linking {
+		return "", hue.ErrLinkButtonNotPressed
+	}
+
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate username: %w", err)
+	}
+	username := hex.EncodeToString(buf)
+
+	s.configMu.Lock()
+	config := s.storage.Get().Clone()
+	config.Users = append(config.Users, domain.HueUser{
+		Username:   username,
+		DeviceType: deviceType,
+		CreatedAt:  time.Now(),
+	})
+	config.UpdatedAt = time.Now()
+	err := s.storage.Save(config)
+	s.configMu.Unlock()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to save paired app: %w", err)
+	}
+
+	s.log.Info("Paired app", "device_type", deviceType)
+	s.notifyStatus()
+	return username, nil
+}
+
+// DeleteUser unpairs an app
+func (s *HueService) DeleteUser(username string) error {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	config := s.storage.Get().Clone()
+	for i, user := range config.Users {
+		if user.Username == username {
+			config.Users = append(config.Users[:i], config.Users[i+1:]...)
+			config.UpdatedAt = time.Now()
+			return s.storage.Save(config)
+		}
+	}
+	return domain.ErrHueUserNotFound
+}
+
+// Authorized returns whether an app is paired
+func (s *HueService) Authorized(username string) bool {
+	return username != "" && s.storage.Get().HasUser(username)
+}
+
+// Lights returns the known lamps, giving new lamps a light ID that sticks across restarts
+func (s *HueService) Lights() []hue.Lamp {
+	devices := s.deviceService.ListDevices()
+	addresses := make([]string, len(devices))
+	for i, device := range devices {
+		addresses[i] = device.Address
+	}
+
+	s.configMu.Lock()
+	config := s.storage.Get().Clone()
+	if config.AssignLightIDs(addresses) {
+		config.UpdatedAt = time.Now()
+		if err := s.storage.Save(config); err != nil {
+			s.log.Error("Failed to save light IDs", "error", err)
+		}
+	}
+	s.configMu.Unlock()
+
+	lamps := make([]hue.Lamp, 0, len(devices))
+	for _, device := range devices {
+		lamps = append(lamps, hue.Lamp{ID: config.LightIDs[device.Address], Device: device})
+	}
+	hue.SortLamps(lamps)
+	return lamps
+}
+
+// SetLightState applies a light state change from an app
+func (s *HueService) SetLightState(ctx context.Context, deviceAddr string, change domain.StateChange) error {
+	if err := s.apply(ctx, deviceAddr, change); err != nil {
+		s.log.Error("Failed to apply command", "device", deviceAddr, "error", err)
+		return err
+	}
+	return nil
+}
+
+// setError records why the bridge is not running
+func (s *HueService) setError(message string) {
+	s.mu.Lock()
+	s.lastErr = message
+	s.mu.Unlock()
+
+	s.notifyStatus()
+}
+
+// notifyStatus reports the current status to the status callback
+func (s *HueService) notifyStatus() {
+	if s.onStatusChange != nil {
+		s.onStatusChange(s.GetStatus())
+	}
+}
+
+// SetStatusChangeCallback sets the callback for bridge status changes
+func (s *HueService) SetStatusChangeCallback(callback func(status domain.HueStatus)) {
+	s.onStatusChange = callback
+}
+
+// SetChangeFunc routes commands through another function, e.g. the server state
+// applying them to the base layer below viewer effects and alerts
+func (s *HueService) SetChangeFunc(apply ChangeFunc) {
+	s.apply = apply
+}
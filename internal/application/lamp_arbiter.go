@@ -0,0 +1,392 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+)
+
+// renderInterval is how often the render loop animates crossfades.
+// BLE writes take tens of milliseconds, so faster frames would only queue up.
+const renderInterval = 100 * time.Millisecond
+
+// LampArbiter decides which source controls a lamp. OBS, viewers and stream alerts
+// each hold a lease with a priority and an optional expiry. Leases are layers on top
+// of the base state, combined by their blend mode and opacity, so the highest opaque
+// lease owns the lamp while e.g. a dimmed alert flash mixes with the state below.
+// When a lease ends the lamp returns to the state of the remaining layers.
+//
+// The base scene is what the streamer set by hand, from the web UI, the API or an
+// integration. The overlay lease lies on top of it below OBS automation, e.g. a
+// dimmed evening tint; a scheduler would drive it once scheduling exists.
+type LampArbiter struct {
+	DeviceRoute
+	deviceService *DeviceService
+	stacks        map[string]*domain.LeaseStack // deviceAddr -> leases, only while a lease is held
+	frames        map[string]domain.DeviceState // deviceAddr -> last frame sent to the lamp
+	timers        map[string]*time.Timer        // deviceAddr -> next lease expiry
+	paused        map[string]bool               // deviceAddr -> leases refused until Resume
+	stop          chan struct{}                 // Stops the render loop, nil if not running
+	mu            sync.Mutex
+	renderMu      sync.Mutex // Keeps frames of a lamp in order
+	log           *slog.Logger
+
+	// Callbacks
+	onChange   func(deviceAddr string)
+	onLeaseEnd func(deviceAddr string, lease domain.Lease)
+}
+
+// DeviceLeases is the lease stack of a lamp
+type DeviceLeases struct {
+	DeviceAddress string
+	Stack         domain.LeaseStack
+}
+
+// NewLampArbiter creates a new lamp arbiter
+func NewLampArbiter(deviceService *DeviceService) *LampArbiter {
+	return &LampArbiter{
+		DeviceRoute:   NewDeviceRoute(deviceService),
+		deviceService: deviceService,
+		stacks:        make(map[string]*domain.LeaseStack),
+		frames:        make(map[string]domain.DeviceState),
+		timers:        make(map[string]*time.Timer),
+		paused:        make(map[string]bool),
+		log:           logging.Source("arbiter"),
+	}
+}
+
+// Start runs the render loop animating crossfades
+func (a *LampArbiter) Start() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.stop != nil {
+		return
+	}
+	a.stop = make(chan struct{})
+	go a.run(a.stop)
+}
+
+// Stop ends the render loop and the expiry timers. Held leases stay on the lamps.
+func (a *LampArbiter) Stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.stop != nil {
+		close(a.stop)
+		a.stop = nil
+	}
+	for deviceAddr, timer := range a.timers {
+		timer.Stop()
+		delete(a.timers, deviceAddr)
+	}
+}
+
+// run renders the lamps with a running crossfade until stopped
+func (a *LampArbiter) run(stop chan struct{}) {
+	ticker := time.NewTicker(renderInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			a.mu.Lock()
+			devices := make([]string, 0)
+			for deviceAddr, stack := range a.stacks {
+				// Includes the tick after a fade ends, so its final frame is sent
+				if stack.Fading(last, now) {
+					devices = append(devices, deviceAddr)
+				}
+			}
+			a.mu.Unlock()
+			last = now
+
+			for _, deviceAddr := range devices {
+				if err := a.render(context.Background(), deviceAddr, a.devices, true); err != nil {
+					a.log.Error("Failed to render frame", "device", deviceAddr, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// Acquire grants a lease, replacing the previous lease of its source, and sends
+// the resulting frame. A lease below an opaque one only shows once the higher
+// leases end. The frame is sent through the given controller so each source keeps
+// its own safety limits. If it fails the previous lease is kept.
+func (a *LampArbiter) Acquire(ctx context.Context, deviceAddr string, lease domain.Lease, devices DeviceController) error {
+	if lease.AcquiredAt.IsZero() {
+		lease.AcquiredAt = time.Now()
+	}
+
+	a.mu.Lock()
+	if a.paused[deviceAddr] {
+		a.mu.Unlock()
+		return domain.ErrLampPaused
+	}
+
+	stack, exists := a.stacks[deviceAddr]
+	if !exists {
+		// The lamp state without any lease becomes the base
+		device, err := a.deviceService.GetDevice(deviceAddr)
+		if err != nil {
+			a.mu.Unlock()
+			return err
+		}
+		stack = domain.NewLeaseStack(device.State)
+		a.stacks[deviceAddr] = stack
+		a.frames[deviceAddr] = device.State
+	}
+
+	var previous *domain.Lease
+	if held := stack.Get(lease.Source); held != nil {
+		copied := *held
+		previous = &copied
+	}
+
+	stack.Put(lease)
+	a.schedule(deviceAddr)
+	a.mu.Unlock()
+
+	if err := a.render(ctx, deviceAddr, devices, false); err != nil {
+		a.mu.Lock()
+		if stack, exists := a.stacks[deviceAddr]; exists {
+			if previous != nil {
+				stack.Put(*previous)
+			} else {
+				stack.Remove(lease.Source)
+			}
+			a.cleanup(deviceAddr)
+		}
+		a.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// Release ends the lease of a source and restores the state below it
+func (a *LampArbiter) Release(ctx context.Context, deviceAddr, source string) error {
+	a.mu.Lock()
+	stack, exists := a.stacks[deviceAddr]
+	if !exists || stack.Get(source) == nil {
+		a.mu.Unlock()
+		return nil
+	}
+	lease := *stack.Get(source)
+	stack.Remove(source)
+	a.mu.Unlock()
+
+	a.leaseEnded(deviceAddr, lease)
+	return a.render(ctx, deviceAddr, a.devices, true)
+}
+
+// expire drops the expired leases of a lamp and restores the state below them
+func (a *LampArbiter) expire(deviceAddr string) {
+	a.mu.Lock()
+	stack, exists := a.stacks[deviceAddr]
+	if !exists {
+		a.mu.Unlock()
+		return
+	}
+	expired := stack.Prune(time.Now())
+	a.mu.Unlock()
+
+	for _, lease := range expired {
+		a.log.Info("Lease expired", "device", deviceAddr, "lease", lease.Source)
+		a.leaseEnded(deviceAddr, lease)
+	}
+
+	if err := a.render(context.Background(), deviceAddr, a.devices, true); err != nil {
+		a.log.Error("Failed to restore state", "device", deviceAddr, "error", err)
+	}
+}
+
+// leaseEnded reports a released or expired lease to the lease end callback
+func (a *LampArbiter) leaseEnded(deviceAddr string, lease domain.Lease) {
+	if a.onLeaseEnd != nil {
+		a.onLeaseEnd(deviceAddr, lease)
+	}
+}
+
+// render composites the layers of a lamp and sends what changed since the last
+// frame. A lamp without leases gets its base state back and is dropped. Frames
+// the arbiter sends on its own are reported to the change callback.
+func (a *LampArbiter) render(ctx context.Context, deviceAddr string, devices DeviceController, notify bool) error {
+	a.renderMu.Lock()
+	defer a.renderMu.Unlock()
+
+	a.mu.Lock()
+	stack, exists := a.stacks[deviceAddr]
+	if !exists {
+		a.mu.Unlock()
+		return nil
+	}
+	frame := stack.Render(time.Now())
+	change := domain.Diff(a.frames[deviceAddr], frame)
+	a.cleanup(deviceAddr)
+	a.mu.Unlock()
+
+	if change.IsZero() {
+		return nil
+	}
+
+	if err := ApplyChange(ctx, devices, deviceAddr, change); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	if _, exists := a.stacks[deviceAddr]; exists {
+		a.frames[deviceAddr] = frame
+	}
+	a.mu.Unlock()
+
+	if notify && a.onChange != nil {
+		a.onChange(deviceAddr)
+	}
+	return nil
+}
+
+// schedule arms the timer for the next lease expiry of a lamp.
+// It must be called with the lock held.
+func (a *LampArbiter) schedule(deviceAddr string) {
+	if timer, exists := a.timers[deviceAddr]; exists {
+		timer.Stop()
+		delete(a.timers, deviceAddr)
+	}
+
+	stack, exists := a.stacks[deviceAddr]
+	if !exists {
+		return
+	}
+
+	next := stack.NextExpiry()
+	if next.IsZero() {
+		return
+	}
+	a.timers[deviceAddr] = time.AfterFunc(time.Until(next), func() {
+		a.expire(deviceAddr)
+	})
+}
+
+// cleanup drops the stack of a lamp without leases and reschedules the expiry timer.
+// It must be called with the lock held.
+func (a *LampArbiter) cleanup(deviceAddr string) {
+	if stack, exists := a.stacks[deviceAddr]; exists && len(stack.Leases) == 0 {
+		delete(a.stacks, deviceAddr)
+		delete(a.frames, deviceAddr)
+	}
+	a.schedule(deviceAddr)
+}
+
+// SetBase applies a change made by the streamer to the base layer of a lamp.
+// While leases are held it only shows where no opaque lease covers it, and the
+// lamp keeps it once they end. Without leases it goes straight to the lamp.
+func (a *LampArbiter) SetBase(ctx context.Context, deviceAddr string, change domain.StateChange, devices DeviceController) error {
+	a.mu.Lock()
+	stack, exists := a.stacks[deviceAddr]
+	if !exists {
+		a.mu.Unlock()
+		return ApplyChange(ctx, devices, deviceAddr, change)
+	}
+	previous := stack.Base
+	stack.Base = change.Apply(stack.Base)
+	a.mu.Unlock()
+
+	if err := a.render(ctx, deviceAddr, devices, false); err != nil {
+		a.mu.Lock()
+		if stack, exists := a.stacks[deviceAddr]; exists {
+			stack.Base = previous
+		}
+		a.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Pause drops all leases of a lamp without restoring anything and refuses new
+// ones until Resume, e.g. on panic. Live sources such as DMX would otherwise take
+// the lamp back with their next frame. Changes to the base scene still go through.
+func (a *LampArbiter) Pause(deviceAddr string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.stacks, deviceAddr)
+	delete(a.frames, deviceAddr)
+	a.paused[deviceAddr] = true
+	a.schedule(deviceAddr)
+}
+
+// Resume accepts leases on every paused lamp again
+func (a *LampArbiter) Resume() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	clear(a.paused)
+}
+
+// Owner returns a copy of the lease owning a lamp, or nil if no lease is held
+func (a *LampArbiter) Owner(deviceAddr string) *domain.Lease {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stack, exists := a.stacks[deviceAddr]
+	if !exists || stack.Top() == nil {
+		return nil
+	}
+	top := *stack.Top()
+	return &top
+}
+
+// Lease returns a copy of the lease a source holds on a lamp, or nil
+func (a *LampArbiter) Lease(deviceAddr, source string) *domain.Lease {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stack, exists := a.stacks[deviceAddr]
+	if !exists {
+		return nil
+	}
+	lease := stack.Get(source)
+	if lease == nil {
+		return nil
+	}
+	copied := *lease
+	return &copied
+}
+
+// Stacks returns copies of the lease stacks of all lamps with a held lease
+func (a *LampArbiter) Stacks() []DeviceLeases {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stacks := make([]DeviceLeases, 0, len(a.stacks))
+	for deviceAddr, stack := range a.stacks {
+		copied := *stack
+		copied.Leases = append([]domain.Lease(nil), stack.Leases...)
+		stacks = append(stacks, DeviceLeases{DeviceAddress: deviceAddr, Stack: copied})
+	}
+	sort.Slice(stacks, func(i, j int) bool {
+		return stacks[i].DeviceAddress < stacks[j].DeviceAddress
+	})
+	return stacks
+}
+
+// SetChangeCallback sets the callback for lamp changes the arbiter makes on its own,
+// restores and animation frames
+func (a *LampArbiter) SetChangeCallback(callback func(deviceAddr string)) {
+	a.onChange = callback
+}
+
+// SetLeaseEndCallback sets the callback for leases that were released or expired
+func (a *LampArbiter) SetLeaseEndCallback(callback func(deviceAddr string, lease domain.Lease)) {
+	a.onLeaseEnd = callback
+}
@@ -0,0 +1,142 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManualChangeStaysBelowLeases(t *testing.T) {
+	_, arbiter, devices := newTestLamp(t)
+	ctx := context.Background()
+
+	var ended []domain.Lease
+	arbiter.SetLeaseEndCallback(func(_ string, lease domain.Lease) {
+		ended = append(ended, lease)
+	})
+
+	red := &domain.RGB{R: 255}
+	blue := &domain.RGB{B: 255}
+	require.NoError(t, arbiter.Acquire(ctx, testDeviceAddr, domain.Lease{
+		Source:   domain.LeaseSourceViewer,
+		Priority: domain.PriorityViewer,
+		Change:   domain.StateChange{RGB: red},
+	}, devices))
+
+	// The viewer effect keeps the lamp, the manual change waits below it
+	require.NoError(t, arbiter.SetBase(ctx, testDeviceAddr, domain.StateChange{RGB: blue}, devices))
+	assert.Equal(t, red, devices.Color())
+
+	require.NoError(t, arbiter.Release(ctx, testDeviceAddr, domain.LeaseSourceViewer))
+	assert.Equal(t, blue, devices.Color())
+	require.Len(t, ended, 1)
+	assert.Equal(t, domain.LeaseSourceViewer, ended[0].Source)
+
+	// Without leases the change goes straight to the lamp
+	require.NoError(t, arbiter.SetBase(ctx, testDeviceAddr, domain.StateChange{RGB: red}, devices))
+	assert.Equal(t, red, devices.Color())
+	assert.Empty(t, arbiter.Stacks())
+}
+
+func TestActiveEffectEndsWithLease(t *testing.T) {
+	service, _ := newTestTwitchService(t, nil)
+	now := time.Now()
+	lease := domain.Lease{Source: domain.LeaseSourceViewer, AcquiredAt: now}
+	service.activeEffects[testDeviceAddr] = &ActiveEffect{DeviceAddress: testDeviceAddr, StartedAt: now, Duration: time.Minute}
+
+	// The lease of an older effect ending leaves the newer one alone
+	service.handleLeaseEnd(testDeviceAddr, domain.Lease{Source: domain.LeaseSourceViewer, AcquiredAt: now.Add(-time.Minute)})
+	assert.Len(t, service.GetActiveEffects(), 1)
+
+	service.handleLeaseEnd(testDeviceAddr, lease)
+	assert.Empty(t, service.GetActiveEffects())
+	assert.Empty(t, service.activeEffects)
+}
+
+func TestOverlayBlendsWithBaseScene(t *testing.T) {
+	_, arbiter, devices := newTestLamp(t)
+	ctx := context.Background()
+
+	brightness := func() uint8 {
+		devices.mu.Lock()
+		defer devices.mu.Unlock()
+		return devices.state.Brightness
+	}
+
+	// A half bright multiply overlay dims the base scene
+	half := uint8(128)
+	overlay := domain.Lease{
+		Source:   domain.LeaseSourceOverlay,
+		Priority: domain.PriorityOverlay,
+		Change:   domain.StateChange{Brightness: &half},
+		Blend:    domain.BlendMultiply,
+	}
+	require.NoError(t, overlay.Validate())
+	require.NoError(t, arbiter.Acquire(ctx, testDeviceAddr, overlay, devices))
+	assert.InDelta(t, 100, brightness(), 1)
+
+	// It keeps dimming the base scene the streamer changes below it
+	full := uint8(255)
+	require.NoError(t, arbiter.SetBase(ctx, testDeviceAddr, domain.StateChange{Brightness: &full}, devices))
+	assert.InDelta(t, 128, brightness(), 1)
+
+	require.NoError(t, arbiter.Release(ctx, testDeviceAddr, domain.LeaseSourceOverlay))
+	assert.Equal(t, full, brightness())
+}
+
+func TestColorLeasesTurnLampOn(t *testing.T) {
+	red := domain.RGB{R: 255}
+	effect := int(domain.EffectMap["strobe"])
+
+	tests := []struct {
+		name   string
+		change func(t *testing.T) domain.StateChange
+		want   func(t *testing.T, state domain.DeviceState)
+	}{
+		{
+			name: "viewer color",
+			change: func(t *testing.T) domain.StateChange {
+				change, err := commandChange(&domain.ChatCommand{Command: "red"}, domain.CommandSetting{})
+				require.NoError(t, err)
+				return change
+			},
+			want: func(t *testing.T, state domain.DeviceState) { assert.Equal(t, &red, state.RGB) },
+		},
+		{
+			name: "viewer effect",
+			change: func(t *testing.T) domain.StateChange {
+				change, err := commandChange(&domain.ChatCommand{Command: "strobe"}, domain.CommandSetting{})
+				require.NoError(t, err)
+				return change
+			},
+			want: func(t *testing.T, state domain.DeviceState) { assert.Equal(t, &effect, state.Effect) },
+		},
+		{
+			name:   "OBS color",
+			change: func(t *testing.T) domain.StateChange { return domain.OBSAction{Color: &red}.Change() },
+			want:   func(t *testing.T, state domain.DeviceState) { assert.Equal(t, &red, state.RGB) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Lamps start out off after a scan or restart
+			_, arbiter, devices := newTestLampIn(t, domain.NewDeviceState())
+
+			require.NoError(t, arbiter.Acquire(context.Background(), testDeviceAddr, domain.Lease{
+				Source:   domain.LeaseSourceViewer,
+				Priority: domain.PriorityViewer,
+				Change:   tt.change(t),
+			}, devices))
+
+			devices.mu.Lock()
+			defer devices.mu.Unlock()
+			assert.True(t, devices.state.PowerOn)
+			tt.want(t, devices.state)
+		})
+	}
+}
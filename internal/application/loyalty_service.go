@@ -0,0 +1,224 @@
+package application
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+)
+
+// LoyaltyService pays viewers loyalty points for watching and chatting
+// and charges them for commands with a cost.
+// Viewers count as watching from their chat JOIN until their PART, or for one
+// payout interval after any message on platforms that don't report joins.
+type LoyaltyService struct {
+	ledger        *storage.LoyaltyStorage
+	twitchStorage *storage.TwitchStorage
+	viewers       map[string]*viewerPresence // viewer key -> presence
+	lastPayout    time.Time
+	stop          chan struct{}
+	mu            sync.Mutex
+	log           *slog.Logger
+}
+
+// viewerPresence tracks where a viewer was seen
+type viewerPresence struct {
+	displayName string
+	channels    map[string]bool // IDs of the chats the viewer joined
+	lastMessage time.Time
+}
+
+// NewLoyaltyService creates a new loyalty service
+func NewLoyaltyService(ledger *storage.LoyaltyStorage, twitchStorage *storage.TwitchStorage) *LoyaltyService {
+	return &LoyaltyService{
+		ledger:        ledger,
+		twitchStorage: twitchStorage,
+		viewers:       make(map[string]*viewerPresence),
+		lastPayout:    time.Now(),
+		log:           logging.Source("loyalty"),
+	}
+}
+
+// Start pays present viewers once per configured interval until Stop is called
+func (s *LoyaltyService) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return // Already running
+	}
+	stop := make(chan struct{})
+	s.stop = stop
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			// Re-read the interval so config changes apply from the next payout
+			config := s.config()
+			interval := config.IntervalOrDefault()
+
+			select {
+			case <-stop:
+				return
+			case <-time.After(interval):
+				s.Payout()
+			}
+		}
+	}()
+}
+
+// Stop stops the payouts
+func (s *LoyaltyService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+// Observe records viewer activity reported by a chat source
+func (s *LoyaltyService) Observe(presence *domain.ChatPresence) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if presence.Event == domain.PresenceClosed {
+		for key, viewer := range s.viewers {
+			delete(viewer.channels, presence.Channel)
+			if len(viewer.channels) == 0 && viewer.lastMessage.Before(s.lastPayout) {
+				delete(s.viewers, key)
+			}
+		}
+		return
+	}
+
+	key := domain.ViewerKey(presence.Platform, presence.Username)
+	viewer, exists := s.viewers[key]
+	if !exists {
+		viewer = &viewerPresence{channels: make(map[string]bool)}
+		s.viewers[key] = viewer
+	}
+	if presence.DisplayName != "" {
+		viewer.displayName = presence.DisplayName
+	}
+
+	switch presence.Event {
+	case domain.PresenceJoin:
+		viewer.channels[presence.Channel] = true
+	case domain.PresencePart:
+		delete(viewer.channels, presence.Channel)
+	case domain.PresenceMessage:
+		viewer.lastMessage = time.Now()
+	}
+}
+
+// Payout credits every present viewer with the watch points,
+// viewers who chatted since the last payout also get the chat points
+func (s *LoyaltyService) Payout() {
+	config := s.config()
+
+	s.mu.Lock()
+	now := time.Now()
+	since := s.lastPayout
+	s.lastPayout = now
+
+	type payout struct {
+		viewer      string
+		displayName string
+		amount      int
+	}
+	payouts := make([]payout, 0, len(s.viewers))
+
+	for key, viewer := range s.viewers {
+		chatted := viewer.lastMessage.After(since)
+		if len(viewer.channels) == 0 && !chatted {
+			delete(s.viewers, key) // Left or went quiet
+			continue
+		}
+
+		amount := config.WatchPoints
+		if chatted {
+			amount += config.ChatPoints
+		}
+		payouts = append(payouts, payout{viewer: key, displayName: viewer.displayName, amount: amount})
+	}
+	s.mu.Unlock()
+
+	if !config.Enabled || len(payouts) == 0 {
+		return
+	}
+
+	err := s.ledger.Update(func(ledger *domain.LoyaltyLedger) error {
+		for _, p := range payouts {
+			if p.amount > 0 {
+				ledger.Earn(p.viewer, p.displayName, p.amount)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("Failed to save payout", "error", err)
+		return
+	}
+
+	s.log.Info("Paid viewers", "viewers", len(payouts))
+}
+
+// Balance returns the points of a viewer
+func (s *LoyaltyService) Balance(viewer string) int {
+	return s.ledger.Balance(viewer)
+}
+
+// Spend deducts the cost of a command
+func (s *LoyaltyService) Spend(viewer string, amount int) error {
+	return s.ledger.Update(func(ledger *domain.LoyaltyLedger) error {
+		return ledger.Spend(viewer, amount)
+	})
+}
+
+// Refund returns the cost of a command that failed
+func (s *LoyaltyService) Refund(viewer string, amount int) {
+	err := s.ledger.Update(func(ledger *domain.LoyaltyLedger) error {
+		ledger.Refund(viewer, amount)
+		return nil
+	})
+	if err != nil {
+		s.log.Error("Failed to save refund", "viewer", viewer, "error", err)
+	}
+}
+
+// Adjust changes a viewer's balance by delta and returns the updated account
+func (s *LoyaltyService) Adjust(viewer string, delta int) (domain.PointsAccount, error) {
+	var account domain.PointsAccount
+	err := s.ledger.Update(func(ledger *domain.LoyaltyLedger) error {
+		account = *ledger.Adjust(viewer, delta)
+		return nil
+	})
+	return account, err
+}
+
+// GetAccount returns the account of a viewer
+func (s *LoyaltyService) GetAccount(viewer string) (domain.PointsAccount, bool) {
+	return s.ledger.Account(viewer)
+}
+
+// GetLeaderboard returns the viewers with the most points
+func (s *LoyaltyService) GetLeaderboard(limit int) []domain.PointsAccount {
+	return s.ledger.Leaderboard(limit)
+}
+
+// PresentViewers returns the number of viewers currently earning points
+func (s *LoyaltyService) PresentViewers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.viewers)
+}
+
+// config returns the current loyalty configuration
+func (s *LoyaltyService) config() domain.LoyaltyConfig {
+	return s.twitchStorage.Get().Loyalty
+}
@@ -0,0 +1,262 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/mqtt"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+)
+
+// MQTTService bridges the lamps to an MQTT broker. It announces every lamp to
+// Home Assistant with a retained discovery config, publishes state changes and
+// applies commands from the lamp command topics to the base layer below viewer
+// effects and alerts, like changes made by the streamer.
+type MQTTService struct {
+	deviceService *DeviceService
+	apply         ChangeFunc // Where commands are sent, straight to the lamp unless set
+	storage       *storage.MQTTStorage
+	client        *mqtt.Client
+	topics        mqtt.Topics
+	announced     map[string]string // nodeID -> deviceAddr, lamps with a discovery config on the broker
+	mu            sync.RWMutex
+	log           *slog.Logger
+
+	// Callbacks
+	onStatusChange func(status domain.ConnectionStatus)
+}
+
+// NewMQTTService creates a new MQTT service
+func NewMQTTService(deviceService *DeviceService, storage *storage.MQTTStorage) *MQTTService {
+	return &MQTTService{
+		deviceService: deviceService,
+		apply: func(ctx context.Context, deviceAddr string, change domain.StateChange) error {
+			return ApplyChange(ctx, deviceService, deviceAddr, change)
+		},
+		storage:   storage,
+		announced: make(map[string]string),
+		log:       logging.Source("mqtt"),
+	}
+}
+
+// Start connects to the broker
+func (s *MQTTService) Start(ctx context.Context) error {
+	config := s.storage.Get()
+
+	if !config.Enabled {
+		return fmt.Errorf("MQTT bridge is disabled")
+	}
+
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// Drop a previous connection before replacing the client
+	s.Stop()
+
+	topics := mqtt.Topics{
+		Base:      config.BaseTopicOrDefault(),
+		Discovery: config.DiscoveryPrefixOrDefault(),
+	}
+
+	// The broker marks the lamps unavailable when the bridge drops off
+	client := mqtt.NewClient(mqtt.Options{
+		Address:     config.BrokerAddress(),
+		ClientID:    config.ClientIDOrDefault(),
+		Username:    config.Username,
+		Password:    config.Password,
+		WillTopic:   topics.Availability(),
+		WillPayload: []byte(mqtt.PayloadOffline),
+		WillRetain:  true,
+	})
+	client.Subscribe(topics.CommandFilter())
+	client.SetMessageHandler(s.handleMessage)
+	client.SetConnectHandler(s.announce)
+	client.SetStateHandler(func(domain.ConnectionStatus) {
+		s.notifyStatus()
+	})
+
+	s.mu.Lock()
+	s.client = client
+	s.topics = topics
+	s.mu.Unlock()
+
+	if err := client.Connect(ctx); err != nil {
+		s.Stop()
+		return fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+
+	s.log.Info("Started bridge", "broker", config.BrokerAddress())
+	return nil
+}
+
+// Stop disconnects from the broker
+func (s *MQTTService) Stop() error {
+	s.mu.Lock()
+	client := s.client
+	s.client = nil
+	s.announced = make(map[string]string)
+	s.mu.Unlock()
+
+	if client == nil {
+		return nil
+	}
+	return client.Disconnect()
+}
+
+// GetStatus returns the broker connection status
+func (s *MQTTService) GetStatus() domain.ConnectionStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.client == nil {
+		return domain.NewConnectionStatus(domain.ConnectionDisconnected)
+	}
+	return s.client.Status()
+}
+
+// GetConfig returns the MQTT configuration
+func (s *MQTTService) GetConfig() *domain.MQTTConfig {
+	return s.storage.Get()
+}
+
+// announce marks the bridge online and announces all known lamps after every
+// connect, so a restarted broker or Home Assistant picks them up again
+func (s *MQTTService) announce() {
+	s.mu.Lock()
+	client := s.client
+	topics := s.topics
+	s.announced = make(map[string]string)
+	s.mu.Unlock()
+
+	if client == nil {
+		return
+	}
+
+	if err := client.Publish(topics.Availability(), []byte(mqtt.PayloadOnline), true); err != nil {
+		s.log.Error("Failed to publish availability", "error", err)
+		return
+	}
+
+	for _, device := range s.deviceService.ListDevices() {
+		s.PublishState(device.Address, device.State)
+	}
+}
+
+// PublishState publishes the state of a lamp, announcing the lamp first if needed
+func (s *MQTTService) PublishState(deviceAddr string, state domain.DeviceState) {
+	s.mu.Lock()
+	client := s.client
+	topics := s.topics
+	nodeID := mqtt.NodeID(deviceAddr)
+	_, announced := s.announced[nodeID]
+	s.mu.Unlock()
+
+	if client == nil || !client.IsConnected() {
+		return
+	}
+
+	// Lamps found by a later scan are announced with their first state
+	if !announced {
+		device, err := s.deviceService.GetDevice(deviceAddr)
+		if err != nil {
+			return
+		}
+		config, err := topics.DiscoveryPayload(device)
+		if err != nil {
+			s.log.Error("Failed to build discovery config", "device", deviceAddr, "error", err)
+			return
+		}
+		if err := client.Publish(topics.Config(nodeID), config, true); err != nil {
+			s.log.Error("Failed to announce device", "device", deviceAddr, "error", err)
+			return
+		}
+
+		s.mu.Lock()
+		s.announced[nodeID] = deviceAddr
+		s.mu.Unlock()
+	}
+
+	payload, err := mqtt.StatePayload(state)
+	if err != nil {
+		s.log.Error("Failed to build state", "device", deviceAddr, "error", err)
+		return
+	}
+	if err := client.Publish(topics.State(nodeID), payload, true); err != nil {
+		s.log.Error("Failed to publish state", "device", deviceAddr, "error", err)
+	}
+}
+
+// handleMessage applies a command received on a lamp command topic
+func (s *MQTTService) handleMessage(topic string, payload []byte) {
+	s.mu.RLock()
+	topics := s.topics
+	s.mu.RUnlock()
+
+	nodeID, ok := topics.ParseCommandTopic(topic)
+	if !ok {
+		return
+	}
+
+	deviceAddr, err := s.deviceFor(nodeID)
+	if err != nil {
+		s.log.Warn("Ignoring command", "topic", topic, "error", err)
+		return
+	}
+
+	change, err := mqtt.ParseCommand(payload)
+	if err != nil {
+		s.log.Warn("Ignoring command", "device", deviceAddr, "error", err)
+		return
+	}
+
+	if err := s.apply(context.Background(), deviceAddr, change); err != nil {
+		s.log.Error("Failed to apply command", "device", deviceAddr, "error", err)
+
+		// Home Assistant shows the requested state until it hears otherwise
+		if device, err := s.deviceService.GetDevice(deviceAddr); err == nil {
+			s.PublishState(deviceAddr, device.State)
+		}
+		return
+	}
+}
+
+// deviceFor returns the address of the lamp with a node ID
+func (s *MQTTService) deviceFor(nodeID string) (string, error) {
+	s.mu.RLock()
+	deviceAddr, exists := s.announced[nodeID]
+	s.mu.RUnlock()
+
+	if exists {
+		return deviceAddr, nil
+	}
+
+	for _, device := range s.deviceService.ListDevices() {
+		if mqtt.NodeID(device.Address) == nodeID {
+			return device.Address, nil
+		}
+	}
+	return "", domain.ErrDeviceNotFound
+}
+
+// notifyStatus reports the current status to the status callback
+func (s *MQTTService) notifyStatus() {
+	if s.onStatusChange != nil {
+		s.onStatusChange(s.GetStatus())
+	}
+}
+
+// SetStatusChangeCallback sets the callback for broker connection changes
+func (s *MQTTService) SetStatusChangeCallback(callback func(status domain.ConnectionStatus)) {
+	s.onStatusChange = callback
+}
+
+// SetChangeFunc routes commands through another function, e.g. the server state
+// applying them to the base layer below viewer effects and alerts
+func (s *MQTTService) SetChangeFunc(apply ChangeFunc) {
+	s.apply = apply
+}
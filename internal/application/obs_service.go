@@ -0,0 +1,231 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/obs"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+)
+
+// OBSService lets the lamps follow OBS: it listens to scene, stream and record
+// events and applies the lamp action of the first matching mapping.
+// OBS changes add up in a lease held until a restore action releases it.
+type OBSService struct {
+	DeviceRoute
+	arbiter   *LampArbiter
+	storage   *storage.OBSStorage
+	client    *obs.Client
+	scene     string
+	streaming bool
+	recording bool
+	mu        sync.RWMutex
+	log       *slog.Logger
+
+	// Callbacks
+	onStatusChange    func(status OBSStatus)
+	onLampChange      func(deviceAddr string, change domain.StateChange)
+	getSelectedDevice func() (string, error)
+}
+
+// OBSStatus describes the OBS connection and what OBS is doing
+type OBSStatus struct {
+	Connection domain.ConnectionStatus
+	Scene      string
+	Streaming  bool
+	Recording  bool
+}
+
+// NewOBSService creates a new OBS service
+func NewOBSService(deviceService *DeviceService, arbiter *LampArbiter, storage *storage.OBSStorage) *OBSService {
+	return &OBSService{
+		DeviceRoute: NewDeviceRoute(deviceService),
+		arbiter:     arbiter,
+		storage:     storage,
+		log:         logging.Source("obs"),
+	}
+}
+
+// Start connects to OBS
+func (s *OBSService) Start(ctx context.Context) error {
+	config := s.storage.Get()
+
+	if !config.Enabled {
+		return fmt.Errorf("OBS integration is disabled")
+	}
+
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// Drop a previous connection before replacing the client
+	s.Stop()
+
+	client := obs.NewClient(config.AddressOrDefault(), config.Password)
+	client.SetEventHandler(s.HandleEvent)
+	client.SetStateHandler(func(domain.ConnectionStatus) {
+		s.notifyStatus()
+	})
+
+	s.mu.Lock()
+	s.client = client
+	s.mu.Unlock()
+
+	if err := client.Connect(ctx); err != nil {
+		s.Stop()
+		return fmt.Errorf("failed to connect to OBS: %w", err)
+	}
+
+	s.log.Info("Started integration", "address", config.AddressOrDefault())
+	return nil
+}
+
+// Stop disconnects from OBS
+func (s *OBSService) Stop() error {
+	s.mu.Lock()
+	client := s.client
+	s.client = nil
+	s.scene = ""
+	s.streaming = false
+	s.recording = false
+	s.mu.Unlock()
+
+	if client == nil {
+		return nil
+	}
+	return client.Disconnect()
+}
+
+// GetStatus returns the OBS connection status and the last known OBS state
+func (s *OBSService) GetStatus() OBSStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := OBSStatus{
+		Connection: domain.NewConnectionStatus(domain.ConnectionDisconnected),
+		Scene:      s.scene,
+		Streaming:  s.streaming,
+		Recording:  s.recording,
+	}
+	if s.client != nil {
+		status.Connection = s.client.Status()
+	}
+	return status
+}
+
+// GetConfig returns the OBS configuration
+func (s *OBSService) GetConfig() *domain.OBSConfig {
+	return s.storage.Get()
+}
+
+// HandleEvent tracks the OBS state and applies the mapping matching the event
+func (s *OBSService) HandleEvent(event *domain.OBSEvent) {
+	s.mu.Lock()
+	switch event.Type {
+	case domain.OBSSceneChanged:
+		s.scene = event.SceneName
+	case domain.OBSStreamStateChanged:
+		s.streaming = event.Active
+	case domain.OBSRecordStateChanged:
+		s.recording = event.Active
+	}
+	s.mu.Unlock()
+
+	s.notifyStatus()
+
+	mapping := s.storage.Get().Match(event)
+	if mapping == nil {
+		return
+	}
+
+	if err := s.apply(mapping, event); err != nil {
+		s.log.Error("Failed to apply mapping", "event", event.Type, "error", err)
+	}
+}
+
+// apply runs the lamp action of a mapping
+func (s *OBSService) apply(mapping *domain.OBSMapping, event *domain.OBSEvent) error {
+	deviceAddr, err := s.deviceFor(mapping)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	// The lamp returns to the state below the OBS lease
+	if mapping.Action.Restore {
+		return s.arbiter.Release(ctx, deviceAddr, domain.LeaseSourceOBS)
+	}
+
+	// Build on the earlier OBS changes so a restore reverts all of them
+	change := mapping.Action.Change()
+	combined := change
+	if held := s.arbiter.Lease(deviceAddr, domain.LeaseSourceOBS); held != nil {
+		combined = held.Change.Then(change)
+	}
+
+	holder := string(event.Type)
+	if event.SceneName != "" {
+		holder = event.SceneName
+	}
+
+	lease := domain.Lease{
+		Source:   domain.LeaseSourceOBS,
+		Holder:   holder,
+		Priority: domain.PriorityAutomation,
+		Change:   combined,
+		Blend:    mapping.Blend,
+		Opacity:  mapping.Opacity,
+		Fade:     mapping.Fade,
+	}
+	if err := s.arbiter.Acquire(ctx, deviceAddr, lease, s.devices); err != nil {
+		return err
+	}
+	s.notifyLampChange(deviceAddr, change)
+	return nil
+}
+
+// deviceFor returns the device a mapping drives, falling back to the selected device
+func (s *OBSService) deviceFor(mapping *domain.OBSMapping) (string, error) {
+	if mapping.DeviceAddress != "" {
+		return mapping.DeviceAddress, nil
+	}
+
+	if s.getSelectedDevice == nil {
+		return "", fmt.Errorf("no device selected")
+	}
+	return s.getSelectedDevice()
+}
+
+// notifyStatus reports the current status to the status callback
+func (s *OBSService) notifyStatus() {
+	if s.onStatusChange != nil {
+		s.onStatusChange(s.GetStatus())
+	}
+}
+
+// notifyLampChange reports a lamp change to the lamp change callback
+func (s *OBSService) notifyLampChange(deviceAddr string, change domain.StateChange) {
+	if s.onLampChange != nil {
+		s.onLampChange(deviceAddr, change)
+	}
+}
+
+// SetGetSelectedDeviceFunc sets the function returning the selected device
+func (s *OBSService) SetGetSelectedDeviceFunc(fn func() (string, error)) {
+	s.getSelectedDevice = fn
+}
+
+// SetStatusChangeCallback sets the callback for connection and OBS state changes
+func (s *OBSService) SetStatusChangeCallback(callback func(status OBSStatus)) {
+	s.onStatusChange = callback
+}
+
+// SetLampChangeCallback sets the callback for lamp changes made by a mapping
+func (s *OBSService) SetLampChangeCallback(callback func(deviceAddr string, change domain.StateChange)) {
+	s.onLampChange = callback
+}
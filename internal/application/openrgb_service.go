@@ -0,0 +1,316 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/openrgb"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+)
+
+// openRGBWatchInterval is how often the lamps are checked for a changed device list
+const openRGBWatchInterval = 5 * time.Second
+
+// OpenRGBService serves the OpenRGB network SDK, so OpenRGB shows each lamp as
+// a single LED strip and its effect plugins can drive the lamps. Each lamp gets
+// a frame limiter, plugins send colors far faster than BLE writes go through.
+// The colors are a lease above OBS automation, so viewer effects and alerts still
+// show on top, and the lamps return below it once the last client leaves.
+type OpenRGBService struct {
+	DeviceRoute
+	deviceService *DeviceService
+	arbiter       *LampArbiter
+	storage       *storage.OpenRGBStorage
+	server        *openrgb.Server
+	serving       sync.WaitGroup
+	limiters      map[string]*FrameLimiter // deviceAddr -> limiter
+	interval      time.Duration
+	port          int
+	stopWatch     chan struct{}
+	lastErr       string
+	mu            sync.RWMutex
+	log           *slog.Logger
+
+	// Callbacks
+	onStatusChange func(status domain.OpenRGBStatus)
+	onLampChange   func(deviceAddr string, change domain.StateChange)
+}
+
+// NewOpenRGBService creates a new OpenRGB service
+func NewOpenRGBService(deviceService *DeviceService, arbiter *LampArbiter, storage *storage.OpenRGBStorage) *OpenRGBService {
+	return &OpenRGBService{
+		DeviceRoute:   NewDeviceRoute(deviceService),
+		deviceService: deviceService,
+		arbiter:       arbiter,
+		storage:       storage,
+		log:           logging.Source("openrgb"),
+	}
+}
+
+// Start serves the SDK on the configured port
+func (s *OpenRGBService) Start(ctx context.Context) error {
+	config := s.storage.Get()
+
+	if !config.Enabled {
+		return fmt.Errorf("OpenRGB server is disabled")
+	}
+
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// Free the port before listening again
+	s.Stop()
+
+	port := config.PortOrDefault()
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		err = fmt.Errorf("failed to listen on port %d: %w", port, err)
+		s.setError(err.Error())
+		return err
+	}
+
+	server := openrgb.NewServer(s, s.clientsChanged)
+	stopWatch := make(chan struct{})
+
+	s.mu.Lock()
+	s.server = server
+	s.limiters = make(map[string]*FrameLimiter)
+	s.interval = time.Second / time.Duration(config.MaxRateOrDefault())
+	s.port = port
+	s.stopWatch = stopWatch
+	s.lastErr = ""
+	s.serving.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.serving.Done()
+		if err := server.Serve(listener); err != nil {
+			s.log.Error("Server stopped", "error", err)
+			s.setError(err.Error())
+		}
+	}()
+	go s.watch(server, stopWatch)
+
+	s.log.Info("SDK server listening", "port", port)
+	s.notifyStatus()
+	return nil
+}
+
+// Stop shuts the server down, disconnecting the clients. The lamps keep their colors.
+func (s *OpenRGBService) Stop() error {
+	s.mu.Lock()
+	server := s.server
+	limiters := s.limiters
+	stopWatch := s.stopWatch
+	s.server = nil
+	s.limiters = nil
+	s.stopWatch = nil
+	s.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+
+	close(stopWatch)
+	err := server.Close()
+	s.serving.Wait()
+
+	deviceAddrs := make([]string, 0, len(limiters))
+	for deviceAddr, limiter := range limiters {
+		limiter.Stop()
+		deviceAddrs = append(deviceAddrs, deviceAddr)
+	}
+	s.release(deviceAddrs)
+
+	s.notifyStatus()
+	return err
+}
+
+// GetStatus returns the server status
+func (s *OpenRGBService) GetStatus() domain.OpenRGBStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := domain.OpenRGBStatus{
+		Running: s.server != nil,
+		Port:    s.port,
+		Clients: []string{},
+		Error:   s.lastErr,
+	}
+	if s.server != nil {
+		status.Clients = s.server.Clients()
+	}
+	return status
+}
+
+// GetConfig returns the OpenRGB configuration
+func (s *OpenRGBService) GetConfig() *domain.OpenRGBConfig {
+	return s.storage.Get()
+}
+
+// Controllers returns the known lamps as controllers
+func (s *OpenRGBService) Controllers() []openrgb.Controller {
+	devices := s.deviceService.ListDevices()
+	controllers := make([]openrgb.Controller, 0, len(devices))
+	for _, device := range devices {
+		controllers = append(controllers, openrgb.NewController(device))
+	}
+	openrgb.SortControllers(controllers)
+	return controllers
+}
+
+// SetColor shows a color sent by a client, turning the lamp on
+func (s *OpenRGBService) SetColor(deviceAddr string, color domain.RGB) {
+	on := true
+	s.push(deviceAddr, domain.StateChange{PowerOn: &on, RGB: &color})
+}
+
+// SetEffect runs an effect picked as mode by a client, turning the lamp on
+func (s *OpenRGBService) SetEffect(deviceAddr string, effect, speed uint8) {
+	on := true
+	effectInt := int(effect)
+	s.push(deviceAddr, domain.StateChange{PowerOn: &on, Effect: &effectInt, EffectSpeed: &speed})
+}
+
+// push hands a change to the limiter of a lamp, creating it on first use
+func (s *OpenRGBService) push(deviceAddr string, change domain.StateChange) {
+	s.mu.Lock()
+	if s.limiters == nil {
+		s.mu.Unlock()
+		return
+	}
+	limiter, exists := s.limiters[deviceAddr]
+	if !exists {
+		limiter = NewFrameLimiter(s.interval, s.sendTo(deviceAddr))
+		s.limiters[deviceAddr] = limiter
+	}
+	s.mu.Unlock()
+
+	limiter.Push(change)
+}
+
+// clientsChanged reports connecting and leaving clients. Other sources may have
+// changed the lamps since a client last sent a look, so it is sent in full again.
+func (s *OpenRGBService) clientsChanged() {
+	s.mu.RLock()
+	server := s.server
+	deviceAddrs := make([]string, 0, len(s.limiters))
+	for deviceAddr, limiter := range s.limiters {
+		limiter.Forget()
+		deviceAddrs = append(deviceAddrs, deviceAddr)
+	}
+	s.mu.RUnlock()
+
+	if server != nil && len(server.Clients()) == 0 {
+		s.release(deviceAddrs)
+	}
+
+	s.notifyStatus()
+}
+
+// release ends the OpenRGB leases, the lamps return to the state below them
+func (s *OpenRGBService) release(deviceAddrs []string) {
+	for _, deviceAddr := range deviceAddrs {
+		if err := s.arbiter.Release(context.Background(), deviceAddr, domain.LeaseSourceOpenRGB); err != nil {
+			s.log.Error("Failed to restore device", "device", deviceAddr, "error", err)
+		}
+	}
+}
+
+// watch tells the clients about lamps being found until stopped
+func (s *OpenRGBService) watch(server *openrgb.Server, stop chan struct{}) {
+	ticker := time.NewTicker(openRGBWatchInterval)
+	defer ticker.Stop()
+
+	addresses := s.addresses()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		current := s.addresses()
+		if !slices.Equal(current, addresses) {
+			addresses = current
+			server.DeviceListUpdated()
+		}
+	}
+}
+
+// addresses returns the addresses of the controllers in device index order
+func (s *OpenRGBService) addresses() []string {
+	controllers := s.Controllers()
+	addresses := make([]string, len(controllers))
+	for i, controller := range controllers {
+		addresses[i] = controller.Address
+	}
+	return addresses
+}
+
+// sendTo returns the send function of the limiter of a lamp. A lamp that keeps
+// failing is only logged once, plugins would otherwise log every frame.
+func (s *OpenRGBService) sendTo(deviceAddr string) func(change domain.StateChange) error {
+	failing := false // Only used by the limiter goroutine
+
+	return func(change domain.StateChange) error {
+		lease := domain.Lease{
+			Source:   domain.LeaseSourceOpenRGB,
+			Holder:   "openrgb",
+			Priority: domain.PriorityLive,
+			Change:   change,
+		}
+		if err := s.arbiter.Acquire(context.Background(), deviceAddr, lease, s.devices); err != nil {
+			if !failing {
+				s.log.Error("Failed to update device", "device", deviceAddr, "error", err)
+			}
+			failing = true
+			return err
+		}
+
+		failing = false
+		s.notifyLampChange(deviceAddr, change)
+		return nil
+	}
+}
+
+// setError records why the server is not running
+func (s *OpenRGBService) setError(message string) {
+	s.mu.Lock()
+	s.lastErr = message
+	s.mu.Unlock()
+
+	s.notifyStatus()
+}
+
+// notifyStatus reports the current status to the status callback
+func (s *OpenRGBService) notifyStatus() {
+	if s.onStatusChange != nil {
+		s.onStatusChange(s.GetStatus())
+	}
+}
+
+// notifyLampChange reports a lamp change to the lamp change callback
+func (s *OpenRGBService) notifyLampChange(deviceAddr string, change domain.StateChange) {
+	if s.onLampChange != nil {
+		s.onLampChange(deviceAddr, change)
+	}
+}
+
+// SetStatusChangeCallback sets the callback for server status changes
+func (s *OpenRGBService) SetStatusChangeCallback(callback func(status domain.OpenRGBStatus)) {
+	s.onStatusChange = callback
+}
+
+// SetLampChangeCallback sets the callback for lamp changes made by a client
+func (s *OpenRGBService) SetLampChangeCallback(callback func(deviceAddr string, change domain.StateChange)) {
+	s.onLampChange = callback
+}
@@ -0,0 +1,27 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenRGBLeaseEndsWithClients(t *testing.T) {
+	deviceService, arbiter, devices := newTestLamp(t)
+	service := NewOpenRGBService(deviceService, arbiter, nil)
+	service.SetDeviceController(devices)
+
+	on := true
+	red := &domain.RGB{R: 255}
+	require.NoError(t, service.sendTo(testDeviceAddr)(domain.StateChange{PowerOn: &on, RGB: red}))
+	assert.Equal(t, red, devices.Color())
+	require.NotNil(t, arbiter.Owner(testDeviceAddr))
+	assert.Equal(t, domain.LeaseSourceOpenRGB, arbiter.Owner(testDeviceAddr).Source)
+
+	// Once the last client left the lamp is back in warm white
+	service.release([]string{testDeviceAddr})
+	assert.Nil(t, devices.Color())
+	assert.Nil(t, arbiter.Owner(testDeviceAddr))
+}
@@ -0,0 +1,158 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+)
+
+// SafetyFilter sits between the lamp commands and the DeviceService.
+// It rejects viewer commands that flash the lamp too often or change its hue too fast,
+// can block flashing effects for viewers, and in epilepsy-safe mode clamps every
+// source, including the streamer, to WCAG-safe flash rates.
+type SafetyFilter struct {
+	devices *DeviceService
+	storage *storage.TwitchStorage
+	guards  map[string]*domain.FlashGuard // deviceAddr -> recent changes
+	mu      sync.Mutex
+	log     *slog.Logger
+}
+
+// NewSafetyFilter creates a new safety filter
+func NewSafetyFilter(devices *DeviceService, storage *storage.TwitchStorage) *SafetyFilter {
+	return &SafetyFilter{
+		devices: devices,
+		storage: storage,
+		guards:  make(map[string]*domain.FlashGuard),
+		log:     logging.Source("safety"),
+	}
+}
+
+// Viewer returns a controller for chat commands
+func (f *SafetyFilter) Viewer() DeviceController {
+	return &safeDevices{filter: f, viewer: true}
+}
+
+// Streamer returns a controller for the streamer's own changes,
+// only limited in epilepsy-safe mode
+func (f *SafetyFilter) Streamer() DeviceController {
+	return &safeDevices{filter: f, viewer: false}
+}
+
+// Limits returns the current limits for viewer commands or streamer changes
+func (f *SafetyFilter) Limits(viewer bool) domain.SafetyLimits {
+	return f.storage.Get().Safety.LimitsFor(viewer)
+}
+
+// admit checks a change against the limits of its source and records it
+func (f *SafetyFilter) admit(address string, change domain.StateChange, limits domain.SafetyLimits) (domain.StateChange, error) {
+	if limits.Unlimited() {
+		return change, nil
+	}
+
+	device, err := f.devices.GetDevice(address)
+	if err != nil {
+		return change, nil // Unknown devices fail in the DeviceService
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	guard, exists := f.guards[address]
+	if !exists {
+		guard = &domain.FlashGuard{}
+		f.guards[address] = guard
+	}
+
+	change, err = guard.Admit(device.State, change, limits, time.Now())
+	if err != nil {
+		f.log.Info("Blocked change", "device", address, "reason", err)
+	}
+	return change, err
+}
+
+// safeDevices passes the changes of one source through the safety filter
+type safeDevices struct {
+	filter *SafetyFilter
+	viewer bool
+}
+
+// send admits a change and passes it on to the DeviceService
+func (d *safeDevices) send(address string, change domain.StateChange, apply func(change domain.StateChange) error) error {
+	change, err := d.filter.admit(address, change, d.filter.Limits(d.viewer))
+	if err != nil {
+		return err
+	}
+	return apply(change)
+}
+
+// SetPower sets the power state of a device
+func (d *safeDevices) SetPower(ctx context.Context, address string, on bool) error {
+	return d.send(address, domain.StateChange{PowerOn: &on}, func(domain.StateChange) error {
+		return d.filter.devices.SetPower(ctx, address, on)
+	})
+}
+
+// SetColor sets the RGB color of a device
+func (d *safeDevices) SetColor(ctx context.Context, address string, r, g, b uint8) error {
+	rgb := domain.RGB{R: r, G: g, B: b}
+	return d.send(address, domain.StateChange{RGB: &rgb}, func(domain.StateChange) error {
+		return d.filter.devices.SetColor(ctx, address, r, g, b)
+	})
+}
+
+// SetBrightness sets the brightness of a device
+func (d *safeDevices) SetBrightness(ctx context.Context, address string, level uint8) error {
+	return d.send(address, domain.StateChange{Brightness: &level}, func(domain.StateChange) error {
+		return d.filter.devices.SetBrightness(ctx, address, level)
+	})
+}
+
+// SetWhiteBalance sets the white balance of a device
+func (d *safeDevices) SetWhiteBalance(ctx context.Context, address string, warm, cold uint8) error {
+	wb := domain.WhiteBalance{Warm: warm, Cold: cold}
+	return d.send(address, domain.StateChange{WhiteBalance: &wb}, func(domain.StateChange) error {
+		return d.filter.devices.SetWhiteBalance(ctx, address, warm, cold)
+	})
+}
+
+// SetEffect sets an effect on a device, flashing effects may run slower than requested
+func (d *safeDevices) SetEffect(ctx context.Context, address string, effect, speed uint8) error {
+	effectInt := int(effect)
+	change := domain.StateChange{Effect: &effectInt, EffectSpeed: &speed}
+	return d.send(address, change, func(change domain.StateChange) error {
+		return d.filter.devices.SetEffect(ctx, address, effect, *change.EffectSpeed)
+	})
+}
+
+// ApplyState restores a full device state.
+// Restores and safe scenes are never blocked, only the effect speed is clamped.
+func (d *safeDevices) ApplyState(ctx context.Context, address string, state domain.DeviceState) error {
+	limits := d.filter.Limits(d.viewer)
+	limits.MaxFlashesPerSecond = 0
+	limits.MinHueChangeInterval = 0
+	limits.BlockFlashingEffects = false
+
+	if state.Effect != nil && state.EffectSpeed == nil {
+		speed := domain.DefaultEffectSpeed
+		state.EffectSpeed = &speed
+	}
+
+	change, _ := d.filter.admit(address, domain.ChangeTo(state), limits)
+	state.EffectSpeed = change.EffectSpeed
+
+	return d.filter.devices.ApplyState(ctx, address, state)
+}
+
+// isSafetyError reports whether the safety filter rejected a change
+func isSafetyError(err error) bool {
+	return errors.Is(err, domain.ErrFlashRateExceeded) ||
+		errors.Is(err, domain.ErrHueChangeTooSoon) ||
+		errors.Is(err, domain.ErrStrobeBlocked)
+}
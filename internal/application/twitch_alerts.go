@@ -0,0 +1,33 @@
+package application
+
+import (
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// AlertSource is implemented by chat sources that announce subs, gifted subs and raids
+type AlertSource interface {
+	SetAlertHandler(handler domain.AlertHandler)
+}
+
+// alertRemaining returns how long a stream alert keeps the lamp busy
+func (s *TwitchService) alertRemaining(deviceAddr string) time.Duration {
+	owner := s.arbiter.Owner(deviceAddr)
+	if owner == nil || owner.Priority <= domain.PriorityViewer {
+		return 0
+	}
+	return owner.Remaining(time.Now())
+}
+
+// handleAlert passes subs, gifted subs and raids announced in chat to the alert callback
+func (s *TwitchService) handleAlert(alert *domain.Alert) {
+	if s.onAlert != nil {
+		s.onAlert(alert)
+	}
+}
+
+// SetAlertCallback sets the callback for subs, gifted subs and raids announced in chat
+func (s *TwitchService) SetAlertCallback(callback domain.AlertHandler) {
+	s.onAlert = callback
+}
@@ -0,0 +1,196 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// Lock blocks all viewer commands until Unlock is called.
+// It returns false if the lamp was already locked.
+func (s *TwitchService) Lock(by string) bool {
+	s.mu.Lock()
+	if s.override.Locked {
+		s.mu.Unlock()
+		return false
+	}
+	s.override.Locked = true
+	s.override.LockedBy = by
+	s.override.LockedAt = time.Now()
+	override := s.override
+	s.mu.Unlock()
+
+	// A running poll can't be applied while locked
+	s.voteManager.Cancel()
+
+	s.log.Info("Lamp locked", "by", by)
+	s.announce(domain.ReplyLock, domain.ReplyData{})
+	s.notifyOverrideChange(override)
+	return true
+}
+
+// Unlock allows viewer commands again. It returns false if the lamp was not locked.
+func (s *TwitchService) Unlock(by string) bool {
+	s.mu.Lock()
+	if !s.override.Locked {
+		s.mu.Unlock()
+		return false
+	}
+	s.override.Locked = false
+	s.override.LockedBy = ""
+	s.override.LockedAt = time.Time{}
+	override := s.override
+	s.mu.Unlock()
+
+	// Sources paused by a panic may take the lamps again
+	s.arbiter.Resume()
+
+	s.log.Info("Lamp unlocked", "by", by)
+	s.announce(domain.ReplyUnlock, domain.ReplyData{})
+	s.notifyOverrideChange(override)
+	return true
+}
+
+// IsLocked returns whether viewer commands are blocked
+func (s *TwitchService) IsLocked() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.override.Locked
+}
+
+// GetOverride returns the current override state
+func (s *TwitchService) GetOverride() domain.OverrideState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.override
+}
+
+// Panic cancels every running viewer effect, alert and poll, locks the lamp and applies
+// the configured safe scene to the selected device and every device a chat drives.
+// The leases of every source are dropped, and OBS, DMX, OpenRGB and queued alerts
+// can't take the lamps back until Unlock.
+func (s *TwitchService) Panic(ctx context.Context, by string) error {
+	s.voteManager.Cancel()
+
+	s.mu.Lock()
+	for deviceAddr := range s.activeEffects {
+		delete(s.activeEffects, deviceAddr)
+	}
+	s.override.LastPanicAt = time.Now()
+	s.mu.Unlock()
+
+	if !s.Lock(by) {
+		// Already locked, still report the panic timestamp
+		s.notifyOverrideChange(s.GetOverride())
+	}
+
+	s.log.Warn("Panic triggered", "by", by)
+
+	config := s.storage.Get()
+
+	devices := s.targetDevices()
+	if len(devices) == 0 {
+		return fmt.Errorf("no device selected")
+	}
+
+	var errs []error
+	for _, deviceAddr := range devices {
+		// Drop every lease so no effect ending later restores over the safe scene
+		s.arbiter.Pause(deviceAddr)
+		if err := s.safety.Streamer().ApplyState(ctx, deviceAddr, config.SafeScene); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", deviceAddr, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// targetDevices returns the selected device and the devices driven by chats
+func (s *TwitchService) targetDevices() []string {
+	devices := make([]string, 0)
+	seen := make(map[string]bool)
+
+	add := func(deviceAddr string) {
+		if deviceAddr != "" && !seen[deviceAddr] {
+			seen[deviceAddr] = true
+			devices = append(devices, deviceAddr)
+		}
+	}
+
+	if deviceAddr, err := s.deviceFor(nil); err == nil {
+		add(deviceAddr)
+	}
+
+	s.mu.RLock()
+	for _, conn := range s.connections {
+		add(conn.channel.DeviceAddress)
+	}
+	s.mu.RUnlock()
+
+	return devices
+}
+
+// ApplyManualChange sends a change made by the streamer through the safety filter
+// to the base layer of a lamp. Running viewer effects and alerts stay on top, and
+// the lamp keeps the change once they end.
+func (s *TwitchService) ApplyManualChange(ctx context.Context, deviceAddr string, change domain.StateChange) error {
+	return s.arbiter.SetBase(ctx, deviceAddr, change, s.safety.Streamer())
+}
+
+// SetOverlay lays a change over the base scene of a lamp, below OBS automation,
+// viewer effects and alerts. It stays until cleared, e.g. a dimmed evening tint.
+func (s *TwitchService) SetOverlay(ctx context.Context, deviceAddr string, overlay domain.Lease) error {
+	overlay.Source = domain.LeaseSourceOverlay
+	overlay.Priority = domain.PriorityOverlay
+	overlay.ExpiresAt = time.Time{}
+	return s.arbiter.Acquire(ctx, deviceAddr, overlay, s.safety.Streamer())
+}
+
+// ClearOverlay removes the overlay of a lamp, revealing the base scene
+func (s *TwitchService) ClearOverlay(ctx context.Context, deviceAddr string) error {
+	return s.arbiter.Release(ctx, deviceAddr, domain.LeaseSourceOverlay)
+}
+
+// handleOverrideCommand handles the moderator chat commands lock, unlock and panic.
+// It returns false if the command is not an override command.
+func (s *TwitchService) handleOverrideCommand(cmd *domain.ChatCommand) bool {
+	switch cmd.Command {
+	case "lock", "unlock", "panic":
+	default:
+		return false
+	}
+
+	if !cmd.Role().Includes(domain.RoleModerator) {
+		s.recordCommand(cmd, domain.OutcomeDenied, fmt.Sprintf("requires role %s", domain.RoleModerator))
+		return true
+	}
+
+	switch cmd.Command {
+	case "lock":
+		s.Lock(cmd.Username)
+	case "unlock":
+		s.Unlock(cmd.Username)
+	case "panic":
+		if err := s.Panic(context.Background(), cmd.Username); err != nil {
+			s.log.Error("Panic failed", "error", err)
+		}
+	}
+
+	return true
+}
+
+// notifyOverrideChange calls the override change callback if set
+func (s *TwitchService) notifyOverrideChange(override domain.OverrideState) {
+	if s.onOverrideChange != nil {
+		s.onOverrideChange(override)
+	}
+}
+
+// SetOverrideChangeCallback sets callback for lock and panic changes
+func (s *TwitchService) SetOverrideChangeCallback(callback func(domain.OverrideState)) {
+	s.onOverrideChange = callback
+}
@@ -2,59 +2,135 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/metrics"
 	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
-	"github.com/codeneuss/lampcontrol/internal/infrastructure/twitch"
 )
 
-// TwitchService orchestrates Twitch chat integration
+// TwitchService orchestrates the chat integration.
+// Besides the main Twitch channel it listens to additional chats, see ChatSource.
 type TwitchService struct {
-	deviceService   *DeviceService
-	snapshotService *StateSnapshotService
-	storage         *storage.TwitchStorage
-	ircClient       *twitch.IRCClient
-	cooldownManager *CooldownManager
-
-	activeEffect *ActiveEffect
-	mu           sync.RWMutex
+	deviceService *DeviceService
+	safety        *SafetyFilter
+	arbiter       *LampArbiter // Viewer effects are leases on top of the streamer's state
+	storage       *storage.TwitchStorage
+	history       *storage.HistoryStorage
+	apiClient     FollowerLookup
+	youtubeAPIKey string
+	consoleChat   bool // Adds a simulated chat read from the terminal
+	voteManager   *VoteManager
+	points        PointsLedger // Charges command costs, nil makes all commands free
+
+	connections   []*chatConnection           // Running chat sources, the main Twitch channel first
+	cooldowns     map[string]*CooldownManager // deviceAddr -> cooldowns of the lamp
+	activeEffects map[string]*ActiveEffect    // deviceAddr -> running viewer effect
+	override      domain.OverrideState
+	streamID      string                           // Identifies the current integration session in the history
+	followerCache map[string]followerCacheEntry    // userID -> follow status
+	followerQueue map[string][]*domain.ChatCommand // userID -> commands waiting for a running follower lookup
+	mu            sync.RWMutex
+	log           *slog.Logger
 
 	// Callbacks
-	onStatusChange    func(connected bool)
+	onStatusChange    func(status domain.ConnectionStatus)
 	onCommandSuccess  func(username, command string)
+	onVoteUpdate      func(session *domain.VoteSession)
+	onOverrideChange  func(override domain.OverrideState)
+	onPresence        domain.ChatPresenceHandler
+	onAlert           domain.AlertHandler
 	getSelectedDevice func() (string, error)
 }
 
-// ActiveEffect tracks currently active viewer effect
+// ActiveEffect tracks currently active viewer effect.
+// The lamp arbiter restores the lamp when it ends.
 type ActiveEffect struct {
-	Username  string
-	Command   string
-	StartedAt time.Time
-	Timer     *time.Timer
+	Username      string
+	Command       string
+	Channel       string // ID of the chat channel the effect came from
+	DeviceAddress string
+	StartedAt     time.Time
+	Duration      time.Duration
+}
+
+// Remaining returns how long the effect keeps running
+func (e *ActiveEffect) Remaining() time.Duration {
+	remaining := e.Duration - time.Since(e.StartedAt)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// PointsLedger holds the loyalty points viewers pay command costs with
+type PointsLedger interface {
+	Balance(viewer string) int
+	// Spend deducts points, returning domain.ErrInsufficientPoints if the viewer can't afford them
+	Spend(viewer string, amount int) error
+	Refund(viewer string, amount int)
+}
+
+// FollowerLookup resolves whether a Twitch user follows a channel
+type FollowerLookup interface {
+	IsFollower(accessToken, broadcasterID, userID string) (bool, error)
+}
+
+// followerCacheEntry caches a follower lookup to avoid hitting the Helix API per message
+type followerCacheEntry struct {
+	isFollower bool
+	expiresAt  time.Time
 }
 
+const (
+	followerCacheTTL   = 10 * time.Minute // How long a follower lookup stays valid
+	followerFailureTTL = time.Minute      // How long a failed lookup counts as not following
+)
+
 // NewTwitchService creates a new Twitch service
 func NewTwitchService(
 	deviceService *DeviceService,
+	arbiter *LampArbiter,
 	storage *storage.TwitchStorage,
+	history *storage.HistoryStorage,
 ) *TwitchService {
-	return &TwitchService{
-		deviceService:   deviceService,
-		snapshotService: NewStateSnapshotService(),
-		storage:         storage,
-		cooldownManager: NewCooldownManager(),
+	s := &TwitchService{
+		deviceService: deviceService,
+		safety:        NewSafetyFilter(deviceService, storage),
+		arbiter:       arbiter,
+		storage:       storage,
+		history:       history,
+		voteManager:   NewVoteManager(),
+		cooldowns:     make(map[string]*CooldownManager),
+		activeEffects: make(map[string]*ActiveEffect),
+		followerCache: make(map[string]followerCacheEntry),
+		followerQueue: make(map[string][]*domain.ChatCommand),
+		log:           logging.Source("twitch"),
 	}
+
+	s.voteManager.SetUpdateCallback(func(session *domain.VoteSession) {
+		if s.onVoteUpdate != nil {
+			s.onVoteUpdate(session)
+		}
+	})
+	s.voteManager.SetCloseCallback(s.applyVoteResult)
+	arbiter.SetLeaseEndCallback(s.handleLeaseEnd)
+
+	return s
 }
 
 // Start starts the Twitch integration
 func (s *TwitchService) Start(ctx context.Context) error {
 	config := s.storage.Get()
 
-	if !config.Enabled {
+	if !config.Enabled && !s.consoleChat {
 		return fmt.Errorf("twitch integration is disabled")
 	}
 
@@ -62,25 +138,76 @@ func (s *TwitchService) Start(ctx context.Context) error {
 		return err
 	}
 
-	// Create IRC client
-	s.ircClient = twitch.NewIRCClient(
-		config.BotUsername,
-		config.AccessToken,
-		config.Channel,
-		s.handleCommand,
-	)
+	// Drop previous connections before replacing the sources
+	s.disconnect()
+
+	channels := make([]domain.ChatChannel, 0)
+	if config.Enabled {
+		channels = config.ChatChannels()
+	}
+	if s.consoleChat {
+		channels = append(channels, domain.NewChatChannel(domain.PlatformConsole, "console"))
+	}
+
+	// The bot account writes to every Twitch chat, Twitch limits its messages as a whole
+	twitchLimiter := NewReplyLimiter(s.replyRateLimit, domain.ReplyRateWindow)
+
+	connections := make([]*chatConnection, 0)
+	for _, channel := range channels {
+		source, err := s.newChatSource(channel, config)
+		if err != nil {
+			s.log.Warn("Skipping chat", "platform", channel.Platform, "channel", channel.Channel, "error", err)
+			continue
+		}
+
+		// Co-streamers and their moderators must not lock, panic or bypass bans on this lamp
+		trusted := config.TrustsBadges(channel)
+		source.SetMessageHandler(func(cmd *domain.ChatCommand) {
+			cmd.Channel = channel.ID
+			if !trusted {
+				cmd.DropChannelPrivileges()
+			}
+			s.handleCommand(cmd)
+		})
+		source.SetStateHandler(func(status domain.ConnectionStatus) {
+			if s.onStatusChange != nil {
+				s.onStatusChange(status)
+			}
+		})
+		source.SetPresenceHandler(func(presence *domain.ChatPresence) {
+			presence.Channel = channel.ID
+			if s.onPresence != nil {
+				s.onPresence(presence)
+			}
+		})
+		if alerts, ok := source.(AlertSource); ok {
+			alerts.SetAlertHandler(s.handleAlert)
+		}
 
-	fmt.Println(config)
+		limiter := twitchLimiter
+		if channel.Platform != domain.PlatformTwitch {
+			limiter = NewReplyLimiter(s.replyRateLimit, domain.ReplyRateWindow)
+		}
+		outbox := NewChatOutbox(source.SendMessage, s.renderReply, limiter)
+		connections = append(connections, &chatConnection{channel: channel, source: source, outbox: outbox})
+	}
 
-	// Connect to Twitch
-	if err := s.ircClient.Connect(ctx); err != nil {
-		return fmt.Errorf("failed to connect to Twitch: %w", err)
+	if len(connections) == 0 {
+		return fmt.Errorf("no chat channel could be started")
 	}
 
-	log.Printf("[Twitch] Started integration for channel: %s", config.Channel)
+	s.mu.Lock()
+	s.connections = connections
+	s.streamID = time.Now().Format("20060102-150405")
+	s.mu.Unlock()
 
-	if s.onStatusChange != nil {
-		s.onStatusChange(true)
+	// Connect the chats, each source reports progress through its state handler
+	for _, conn := range connections {
+		if err := conn.source.Connect(ctx); err != nil {
+			s.disconnect()
+			return fmt.Errorf("failed to connect to %s chat %s: %w", conn.channel.Platform, conn.channel.Channel, err)
+		}
+		s.log.Info("Started integration", "platform", conn.channel.Platform, "channel", conn.channel.Channel)
 	}
 
 	return nil
@@ -89,185 +216,691 @@ func (s *TwitchService) Start(ctx context.Context) error {
 // Stop stops the Twitch integration
 func (s *TwitchService) Stop() error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
-	// Cancel active effect timer
-	if s.activeEffect != nil && s.activeEffect.Timer != nil {
-		s.activeEffect.Timer.Stop()
-		s.activeEffect = nil
+	// Drop any running poll
+	s.voteManager.Cancel()
+
+	// End running viewer effects
+	devices := make([]string, 0, len(s.activeEffects))
+	for deviceAddr := range s.activeEffects {
+		devices = append(devices, deviceAddr)
+		delete(s.activeEffects, deviceAddr)
 	}
 
-	// Disconnect from IRC
-	if s.ircClient != nil {
-		if err := s.ircClient.Disconnect(); err != nil {
-			return err
+	s.mu.Unlock()
+
+	for _, deviceAddr := range devices {
+		if err := s.arbiter.Release(context.Background(), deviceAddr, domain.LeaseSourceViewer); err != nil {
+			s.log.Error("Failed to restore state", "device", deviceAddr, "error", err)
 		}
 	}
 
-	if s.onStatusChange != nil {
-		s.onStatusChange(false)
+	return s.disconnect()
+}
+
+// disconnect closes all chat connections.
+// It must be called without holding the lock, sources report their final state synchronously.
+func (s *TwitchService) disconnect() error {
+	s.mu.Lock()
+	connections := s.connections
+	s.connections = nil
+	s.mu.Unlock()
+
+	var firstErr error
+	for _, conn := range connections {
+		conn.outbox.Close()
+		if err := conn.source.Disconnect(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		// Parts are not reported once the connection is gone
+		if s.onPresence != nil {
+			s.onPresence(&domain.ChatPresence{
+				Platform:  conn.channel.Platform,
+				Channel:   conn.channel.ID,
+				Event:     domain.PresenceClosed,
+				Timestamp: time.Now(),
+			})
+		}
 	}
 
-	return nil
+	return firstErr
 }
 
-// IsConnected returns connection status
+// IsConnected returns whether any chat is joined
 func (s *TwitchService) IsConnected() bool {
+	return s.GetConnectionStatus().State == domain.ConnectionJoined
+}
+
+// GetConnectionStatus returns the overall state of the chat connections:
+// the first joined chat, otherwise the state of the main chat
+func (s *TwitchService) GetConnectionStatus() domain.ConnectionStatus {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return s.ircClient != nil && s.ircClient.IsConnected()
+	if len(s.connections) == 0 {
+		return domain.NewConnectionStatus(domain.ConnectionDisconnected)
+	}
+
+	for _, conn := range s.connections {
+		if status := conn.source.Status(); status.State == domain.ConnectionJoined {
+			return status
+		}
+	}
+	return s.connections[0].source.Status()
+}
+
+// ChannelStatus is the connection state of a single chat
+type ChannelStatus struct {
+	Channel domain.ChatChannel
+	Status  domain.ConnectionStatus
+}
+
+// GetChannelStatuses returns the state of every running chat
+func (s *TwitchService) GetChannelStatuses() []ChannelStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]ChannelStatus, 0, len(s.connections))
+	for _, conn := range s.connections {
+		statuses = append(statuses, ChannelStatus{Channel: conn.channel, Status: conn.source.Status()})
+	}
+	return statuses
+}
+
+// GetConfig returns the current Twitch configuration
+func (s *TwitchService) GetConfig() *domain.TwitchConfig {
+	return s.storage.Get()
 }
 
 // handleCommand processes a Twitch chat command
-func (s *TwitchService) handleCommand(cmd *domain.TwitchCommand) {
+func (s *TwitchService) handleCommand(cmd *domain.ChatCommand) {
 	config := s.storage.Get()
 
+	// Meta commands don't touch the lamp
+	if cmd.Command == "stats" {
+		s.sendViewerStats(cmd)
+		return
+	}
+	if cmd.Command == "points" && s.points != nil && config.Loyalty.Enabled {
+		s.reply(cmd, domain.ReplyPoints, domain.ReplyData{Balance: s.points.Balance(cooldownKey(cmd))})
+		return
+	}
+
+	if s.handleOverrideCommand(cmd) {
+		return
+	}
+
+	// The streamer's lock blocks everyone else
+	if s.IsLocked() && !cmd.IsBroadcaster {
+		s.recordCommand(cmd, domain.OutcomeDenied, "lamp locked")
+		s.reply(cmd, domain.ReplyLocked, domain.ReplyData{})
+		return
+	}
+
+	channel := s.channelOf(cmd)
+
+	// Check permissions
+	if !s.authorize(cmd, config, channel) {
+		return
+	}
+
+	if !domain.IsPower(cmd.Command) && !domain.IsColor(cmd.Command) && !domain.IsEffect(cmd.Command) {
+		s.recordCommand(cmd, domain.OutcomeUnknown, "unknown command")
+		s.reply(cmd, domain.ReplyUnknown, domain.ReplyData{})
+		return
+	}
+
+	if channel != nil && !channel.AllowsCommand(cmd.Command) {
+		s.recordCommand(cmd, domain.OutcomeDenied, "disabled in channel")
+		s.reply(cmd, domain.ReplyDisabled, domain.ReplyData{})
+		return
+	}
+
+	setting := config.ResolveCommand(cmd.Command)
+
+	// In vote mode free colors and effects are ballots instead of commands.
+	// Paid commands skip the poll, the viewer pays to change the lamp right away.
+	if config.VoteMode && (domain.IsColor(cmd.Command) || domain.IsEffect(cmd.Command)) &&
+		s.costOf(cmd, config, setting) == 0 {
+		s.handleVote(cmd, config)
+		return
+	}
+
 	// Check if user bypasses cooldown
 	bypassCooldown := (cmd.IsVIP && config.VIPBypassCooldown) ||
 		(cmd.IsSub && config.SubBypassCooldown) ||
 		(cmd.IsMod && config.ModBypassCooldown)
 
+	deviceAddr, err := s.deviceFor(channel)
+	if err != nil {
+		s.recordCommand(cmd, domain.OutcomeFailure, err.Error())
+		s.reply(cmd, domain.ReplyFailure, domain.ReplyData{Reason: err.Error()})
+		return
+	}
+	cooldowns := s.cooldownFor(deviceAddr)
+
 	// Check cooldowns
 	if !bypassCooldown {
-		if ok, remaining := s.cooldownManager.CheckGlobal(config.GlobalCooldown); !ok {
-			s.sendCooldownMessage(cmd.Username, remaining, "global")
+		if ok, remaining := cooldowns.CheckGlobal(config.GlobalCooldown); !ok {
+			s.recordCommand(cmd, domain.OutcomeCooldown, "global cooldown")
+			s.sendCooldownMessage(cmd, remaining, "global")
 			return
 		}
 
-		if ok, remaining := s.cooldownManager.CheckUser(cmd.Username, config.UserCooldown); !ok {
-			s.sendCooldownMessage(cmd.Username, remaining, "personal")
+		if ok, remaining := cooldowns.CheckCommand(cmd.Command, setting.Cooldown); !ok {
+			s.recordCommand(cmd, domain.OutcomeCooldown, "command cooldown")
+			s.sendCooldownMessage(cmd, remaining, "command")
+			return
+		}
+
+		if ok, remaining := cooldowns.CheckUser(cooldownKey(cmd), config.UserCooldown); !ok {
+			s.recordCommand(cmd, domain.OutcomeCooldown, "personal cooldown")
+			s.sendCooldownMessage(cmd, remaining, "personal")
+			return
+		}
+	}
+
+	// Charge the command cost
+	cost := s.costOf(cmd, config, setting)
+	if cost > 0 {
+		if err := s.points.Spend(cooldownKey(cmd), cost); err != nil {
+			s.recordCommand(cmd, domain.OutcomeDenied, err.Error())
+			s.reply(cmd, domain.ReplyCost, domain.ReplyData{Cost: cost, Balance: s.points.Balance(cooldownKey(cmd))})
 			return
 		}
 	}
 
 	// Execute command
-	if err := s.executeCommand(cmd, config); err != nil {
-		log.Printf("[Twitch] Command failed for %s: %v", cmd.Username, err)
-		s.ircClient.SendMessage(fmt.Sprintf("@%s Sorry, that command failed: %v", cmd.DisplayName, err))
+	if err := s.executeCommand(cmd, setting, deviceAddr); err != nil {
+		if cost > 0 {
+			s.points.Refund(cooldownKey(cmd), cost)
+		}
+		if isSafetyError(err) {
+			s.recordCommand(cmd, domain.OutcomeDenied, err.Error())
+			s.reply(cmd, domain.ReplyUnsafe, domain.ReplyData{Reason: err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrAlertPlaying) {
+			s.recordCommand(cmd, domain.OutcomeDenied, err.Error())
+			s.reply(cmd, domain.ReplyBusy, domain.ReplyData{Seconds: int(s.alertRemaining(deviceAddr).Seconds()) + 1})
+			return
+		}
+		s.log.Error("Command failed", "user", cmd.Username, "command", cmd.Command, "error", err)
+		s.recordCommand(cmd, domain.OutcomeFailure, err.Error())
+		s.reply(cmd, domain.ReplyFailure, domain.ReplyData{Reason: err.Error()})
 		return
 	}
 
 	// Record cooldown
-	s.cooldownManager.RecordCommand(cmd.Username)
+	cooldowns.RecordCommand(cooldownKey(cmd), cmd.Command)
+	s.recordCommand(cmd, domain.OutcomeSuccess, "")
 
 	// Send success message
-	s.ircClient.SendMessage(fmt.Sprintf("@%s Lamp set to %s for %d seconds!",
-		cmd.DisplayName, cmd.Command, int(config.EffectDuration.Seconds())))
+	s.reply(cmd, domain.ReplySuccess, domain.ReplyData{Seconds: int(setting.Duration.Seconds())})
 
 	if s.onCommandSuccess != nil {
 		s.onCommandSuccess(cmd.Username, cmd.Command)
 	}
 }
 
-// executeCommand executes a lamp command
-func (s *TwitchService) executeCommand(cmd *domain.TwitchCommand, config *domain.TwitchConfig) error {
+// handleVote counts a chat message as a poll vote, opening a poll if none is running.
+// A poll collects votes from all chats and drives the selected device.
+func (s *TwitchService) handleVote(cmd *domain.ChatCommand, config *domain.TwitchConfig) {
+	if !s.voteManager.IsOpen() {
+		deviceAddr, err := s.deviceFor(nil)
+		if err != nil {
+			s.recordCommand(cmd, domain.OutcomeFailure, err.Error())
+			return
+		}
+
+		// The global cooldown separates one poll result from the next poll
+		if ok, remaining := s.cooldownFor(deviceAddr).CheckGlobal(config.GlobalCooldown); !ok {
+			s.recordCommand(cmd, domain.OutcomeCooldown, "global cooldown")
+			s.sendCooldownMessage(cmd, remaining, "global")
+			return
+		}
+
+		if s.voteManager.Start(config.VoteDurationOrDefault()) {
+			s.announceVoteStart(config)
+		}
+	}
+
+	if s.voteManager.Vote(cooldownKey(cmd), cmd.Command) {
+		s.recordCommand(cmd, domain.OutcomeVote, "")
+		s.reply(cmd, domain.ReplyQueued, domain.ReplyData{})
+	}
+}
+
+// applyVoteResult applies the winning option of a finished poll
+func (s *TwitchService) applyVoteResult(session *domain.VoteSession) {
+	winner := session.Winner()
+	if winner == nil || s.IsLocked() {
+		return
+	}
+
+	config := s.storage.Get()
+	// Poll results are recorded without a username so they don't count for a single viewer
+	cmd := &domain.ChatCommand{
+		DisplayName: "Chat poll",
+		Command:     winner.Option,
+		Timestamp:   time.Now(),
+	}
+
+	setting := config.ResolveCommand(winner.Option)
+	deviceAddr, err := s.deviceFor(nil)
+	if err == nil {
+		err = s.executeCommand(cmd, setting, deviceAddr)
+	}
+	if err != nil {
+		s.log.Error("Failed to apply poll result", "option", winner.Option, "error", err)
+		s.recordCommand(cmd, domain.OutcomeFailure, err.Error())
+		s.announce(domain.ReplyPollFailed, domain.ReplyData{Command: winner.Option})
+		return
+	}
+
+	s.cooldownFor(deviceAddr).RecordGlobal()
+	s.recordCommand(cmd, domain.OutcomeSuccess, "")
+
+	s.announce(domain.ReplyPollResult, domain.ReplyData{
+		Command: winner.Option,
+		Count:   winner.Count,
+		Total:   len(session.Votes),
+		Seconds: int(setting.Duration.Seconds()),
+	})
+
+	if s.onCommandSuccess != nil {
+		s.onCommandSuccess(cmd.DisplayName, cmd.Command)
+	}
+}
+
+// StartVote opens a poll manually. It returns false if a poll is already running.
+func (s *TwitchService) StartVote() bool {
+	config := s.storage.Get()
+
+	if !s.voteManager.Start(config.VoteDurationOrDefault()) {
+		return false
+	}
+
+	s.announceVoteStart(config)
+	return true
+}
+
+// announceVoteStart tells chat how to vote
+func (s *TwitchService) announceVoteStart(config *domain.TwitchConfig) {
+	s.announce(domain.ReplyPollStart, domain.ReplyData{Seconds: int(config.VoteDurationOrDefault().Seconds())})
+}
+
+// CancelVote cancels the running poll. It returns false if no poll is running.
+func (s *TwitchService) CancelVote() bool {
+	return s.voteManager.Cancel()
+}
+
+// GetVote returns the current or most recent poll
+func (s *TwitchService) GetVote() *domain.VoteSession {
+	return s.voteManager.Current()
+}
+
+// executeCommand executes a lamp command on a device
+func (s *TwitchService) executeCommand(cmd *domain.ChatCommand, setting domain.CommandSetting, deviceAddr string) error {
 	ctx := context.Background()
 
-	// Get selected device
+	// Stream alerts take priority over viewer commands
+	if s.alertRemaining(deviceAddr) > 0 {
+		return domain.ErrAlertPlaying
+	}
+
+	change, err := commandChange(cmd, setting)
+	if err != nil {
+		return err
+	}
+
+	// Execute the command through the safety filter. The lease replaces the
+	// previous viewer effect, the arbiter restores the lamp when it expires.
+	now := time.Now()
+	lease := domain.Lease{
+		Source:     domain.LeaseSourceViewer,
+		Holder:     cmd.Username,
+		Priority:   domain.PriorityViewer,
+		Change:     change,
+		Blend:      setting.Blend,
+		Opacity:    setting.Opacity,
+		Fade:       setting.Fade,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(setting.Duration),
+	}
+	if err := s.arbiter.Acquire(ctx, deviceAddr, lease, s.safety.Viewer()); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.activeEffects[deviceAddr] = &ActiveEffect{
+		Username:      cmd.Username,
+		Command:       cmd.Command,
+		Channel:       cmd.Channel,
+		DeviceAddress: deviceAddr,
+		StartedAt:     now,
+		Duration:      setting.Duration,
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// commandChange returns the lamp change of a command. Colors and effects turn
+// the lamp on, since a lamp that is off would not show them.
+func commandChange(cmd *domain.ChatCommand, setting domain.CommandSetting) (domain.StateChange, error) {
+	var change domain.StateChange
+	on := true
+	if domain.IsPower(cmd.Command) {
+		power := domain.PowerMap[cmd.Command]
+		change.PowerOn = &power
+	} else if domain.IsColor(cmd.Command) {
+		rgb, _ := domain.GetRGB(cmd.Command)
+		change.PowerOn = &on
+		change.RGB = &rgb
+	} else if domain.IsEffect(cmd.Command) {
+		effect, _ := domain.GetEffect(cmd.Command)
+		index := int(effect)
+		change.PowerOn = &on
+		change.Effect = &index
+		change.EffectSpeed = setting.Speed
+	} else {
+		return change, fmt.Errorf("unknown command: %s", cmd.Command)
+	}
+	return change, nil
+}
+
+// handleLeaseEnd forgets the viewer effect whose lease was released or expired
+func (s *TwitchService) handleLeaseEnd(deviceAddr string, lease domain.Lease) {
+	if lease.Source != domain.LeaseSourceViewer {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A newer effect has replaced the lease already
+	if effect, exists := s.activeEffects[deviceAddr]; exists && effect.StartedAt.Equal(lease.AcquiredAt) {
+		delete(s.activeEffects, deviceAddr)
+	}
+}
+
+// deviceFor returns the device a chat channel drives, falling back to the selected device
+func (s *TwitchService) deviceFor(channel *domain.ChatChannel) (string, error) {
+	if channel != nil && channel.DeviceAddress != "" {
+		return channel.DeviceAddress, nil
+	}
+
 	if s.getSelectedDevice == nil {
-		return fmt.Errorf("no device selection callback configured")
+		return "", fmt.Errorf("no device selection callback configured")
 	}
 
 	deviceAddr, err := s.getSelectedDevice()
 	if err != nil || deviceAddr == "" {
-		return fmt.Errorf("no device selected")
+		return "", fmt.Errorf("no device selected")
 	}
+	return deviceAddr, nil
+}
 
-	// Cancel existing effect timer if any
+// cooldownFor returns the cooldowns of a device, so chats driving different lamps don't block each other
+func (s *TwitchService) cooldownFor(deviceAddr string) *CooldownManager {
 	s.mu.Lock()
-	if s.activeEffect != nil && s.activeEffect.Timer != nil {
-		s.activeEffect.Timer.Stop()
+	defer s.mu.Unlock()
+
+	manager, exists := s.cooldowns[deviceAddr]
+	if !exists {
+		manager = NewCooldownManager()
+		s.cooldowns[deviceAddr] = manager
 	}
-	s.mu.Unlock()
+	return manager
+}
+
+// costOf returns the points a command costs its sender.
+// Commands are free without an enabled ledger and for the broadcaster.
+func (s *TwitchService) costOf(cmd *domain.ChatCommand, config *domain.TwitchConfig, setting domain.CommandSetting) int {
+	if s.points == nil || !config.Loyalty.Enabled || cmd.IsBroadcaster {
+		return 0
+	}
+	return setting.Cost
+}
+
+// GetCooldowns returns the running cooldowns of every device, sorted by address
+func (s *TwitchService) GetCooldowns() []CooldownStatus {
+	config := s.storage.Get()
 
-	// Save current state (only if no active effect)
 	s.mu.RLock()
-	shouldSnapshot := s.activeEffect == nil
-	s.mu.RUnlock()
+	defer s.mu.RUnlock()
 
-	if shouldSnapshot {
-		device, err := s.deviceService.GetDevice(deviceAddr)
-		if err != nil {
-			return err
-		}
-		s.snapshotService.SaveSnapshot(deviceAddr, device.State, "twitch_viewer_command")
+	statuses := make([]CooldownStatus, 0, len(s.cooldowns))
+	for deviceAddr, manager := range s.cooldowns {
+		status := manager.Status(config)
+		status.DeviceAddress = deviceAddr
+		statuses = append(statuses, status)
 	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].DeviceAddress < statuses[j].DeviceAddress
+	})
+	return statuses
+}
 
-	// Execute the command
-	if domain.IsColor(cmd.Command) {
-		rgb, _ := domain.GetRGB(cmd.Command)
-		if err := s.deviceService.SetColor(ctx, deviceAddr, rgb.R, rgb.G, rgb.B); err != nil {
-			return err
+// cooldownKey identifies a viewer across platforms
+func cooldownKey(cmd *domain.ChatCommand) string {
+	return domain.ViewerKey(cmd.Platform, cmd.Username)
+}
+
+// channelOf returns the chat channel a command arrived in, or nil
+func (s *TwitchService) channelOf(cmd *domain.ChatCommand) *domain.ChatChannel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, conn := range s.connections {
+		if conn.channel.ID == cmd.Channel {
+			channel := conn.channel
+			return &channel
 		}
-	} else if domain.IsEffect(cmd.Command) {
-		effect, _ := domain.GetEffect(cmd.Command)
-		if err := s.deviceService.SetEffect(ctx, deviceAddr, effect, 128); err != nil {
-			return err
+	}
+	return nil
+}
+
+// reply answers a command in the chat it arrived in.
+// The sender and command are filled in from cmd.
+func (s *TwitchService) reply(cmd *domain.ChatCommand, event domain.ReplyEvent, data domain.ReplyData) {
+	if s.storage.Get().IsReplySilenced(event) {
+		return
+	}
+
+	data.User = cmd.DisplayName
+	if data.Command == "" {
+		data.Command = cmd.Command
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, conn := range s.connections {
+		if conn.channel.ID == cmd.Channel {
+			conn.outbox.Enqueue(event, data)
+			return
 		}
-	} else {
-		return fmt.Errorf("unknown command: %s", cmd.Command)
 	}
+}
 
-	// Set timer to restore state
-	timer := time.AfterFunc(config.EffectDuration, func() {
-		s.restoreStreamerState(deviceAddr)
-	})
+// announce sends a message to every chat
+func (s *TwitchService) announce(event domain.ReplyEvent, data domain.ReplyData) {
+	if s.storage.Get().IsReplySilenced(event) {
+		return
+	}
 
-	s.mu.Lock()
-	s.activeEffect = &ActiveEffect{
-		Username:  cmd.Username,
-		Command:   cmd.Command,
-		StartedAt: time.Now(),
-		Timer:     timer,
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, conn := range s.connections {
+		conn.outbox.Enqueue(event, data)
 	}
-	s.mu.Unlock()
+}
 
-	return nil
+// renderReply renders a reply with the configured template, returning "" on error
+func (s *TwitchService) renderReply(event domain.ReplyEvent, data domain.ReplyData) string {
+	config := s.storage.Get()
+
+	message, err := domain.RenderReply(event, config.ReplyTemplate(event), data)
+	if err != nil {
+		s.log.Error("Failed to render reply", "event", event, "error", err)
+		return ""
+	}
+	return message
+}
+
+// replyRateLimit returns the configured bot message limit per chat
+func (s *TwitchService) replyRateLimit() int {
+	return s.storage.Get().ReplyRateLimitOrDefault()
 }
 
-// restoreStreamerState restores the saved state
-func (s *TwitchService) restoreStreamerState(deviceAddr string) {
-	snapshot := s.snapshotService.GetLatestSnapshot(deviceAddr)
-	if snapshot == nil {
-		log.Printf("[Twitch] No snapshot to restore for device: %s", deviceAddr)
+// recordCommand counts a command attempt and persists it in the history
+func (s *TwitchService) recordCommand(cmd *domain.ChatCommand, outcome domain.CommandOutcome, reason string) {
+	metrics.TwitchCommands.With(string(cmd.Platform), string(outcome)).Inc()
+
+	if s.history == nil {
 		return
 	}
 
-	ctx := context.Background()
-	state := snapshot.State
-
-	// Restore state based on what was active
-	if state.RGB != nil {
-		s.deviceService.SetColor(ctx, deviceAddr, state.RGB.R, state.RGB.G, state.RGB.B)
-	} else if state.WhiteBalance != nil {
-		s.deviceService.SetWhiteBalance(ctx, deviceAddr, state.WhiteBalance.Warm, state.WhiteBalance.Cold)
-	} else if state.Effect != nil {
-		speed := uint8(128)
-		if state.EffectSpeed != nil {
-			speed = *state.EffectSpeed
+	s.mu.RLock()
+	streamID := s.streamID
+	s.mu.RUnlock()
+
+	if err := s.history.Append(domain.NewCommandRecord(streamID, cmd, outcome, reason)); err != nil {
+		s.log.Error("Failed to record command history", "error", err)
+	}
+}
+
+// sendViewerStats replies with the sender's own command usage
+func (s *TwitchService) sendViewerStats(cmd *domain.ChatCommand) {
+	if s.history == nil {
+		return
+	}
+
+	records := s.history.Query(domain.CommandHistoryFilter{
+		Username: cmd.Username,
+		Outcome:  domain.OutcomeSuccess,
+	})
+	stats := domain.ComputeViewerStats(records, cmd.Username)
+
+	if stats.TotalCommands == 0 {
+		s.reply(cmd, domain.ReplyStatsEmpty, domain.ReplyData{})
+		return
+	}
+
+	s.reply(cmd, domain.ReplyStats, domain.ReplyData{Count: stats.TotalCommands, Favorite: stats.Favorite})
+}
+
+// GetHistory returns recorded command attempts matching the filter, newest first
+func (s *TwitchService) GetHistory(filter domain.CommandHistoryFilter) []*domain.CommandRecord {
+	if s.history == nil {
+		return []*domain.CommandRecord{}
+	}
+	return s.history.Query(filter)
+}
+
+// GetStats aggregates the command history
+func (s *TwitchService) GetStats(filter domain.CommandHistoryFilter, limit int) domain.CommandStats {
+	return domain.ComputeCommandStats(s.GetHistory(filter), limit)
+}
+
+// authorize checks the permission rules for a command and notifies chat on rejection
+func (s *TwitchService) authorize(cmd *domain.ChatCommand, config *domain.TwitchConfig, channel *domain.ChatChannel) bool {
+	// Follower status is not part of IRC badges, resolve it only when a rule needs it
+	if permission := config.PermissionFor(cmd.Command, channel); permission != nil &&
+		permission.MinRole == domain.RoleFollower && !cmd.Role().Includes(domain.RoleFollower) {
+		following, known := s.cachedFollower(cmd)
+		if !known {
+			// The command is handled again once the lookup is cached
+			s.lookupFollower(cmd, config)
+			return false
 		}
-		s.deviceService.SetEffect(ctx, deviceAddr, uint8(*state.Effect), speed)
+		cmd.IsFollower = following
 	}
 
-	log.Printf("[Twitch] Restored state for device: %s", deviceAddr)
+	required, err := config.Authorize(cmd, channel)
+	switch err {
+	case nil:
+		return true
+	case domain.ErrUserBanned:
+		s.log.Info("Ignoring command from banned user", "user", cmd.Username, "command", cmd.Command)
+		s.recordCommand(cmd, domain.OutcomeDenied, err.Error())
+	case domain.ErrInsufficientRole:
+		s.log.Info("User lacks role for command", "user", cmd.Username, "role", required, "command", cmd.Command)
+		s.recordCommand(cmd, domain.OutcomeDenied, fmt.Sprintf("requires role %s", required))
+		s.reply(cmd, domain.ReplyDenied, domain.ReplyData{Audience: config.Audience(required)})
+	default:
+		s.log.Error("Permission check failed", "user", cmd.Username, "error", err)
+	}
+
+	return false
+}
+
+// cachedFollower returns the cached follow status of the command sender,
+// known is false if it has to be looked up first
+func (s *TwitchService) cachedFollower(cmd *domain.ChatCommand) (following, known bool) {
+	if s.apiClient == nil || cmd.Platform != domain.PlatformTwitch || cmd.UserID == "" || cmd.ChannelID == "" {
+		return false, true
+	}
 
+	s.mu.RLock()
+	entry, cached := s.followerCache[cmd.UserID]
+	s.mu.RUnlock()
+
+	if !cached || time.Now().After(entry.expiresAt) {
+		return false, false
+	}
+	return entry.isFollower, true
+}
+
+// lookupFollower asks the Helix API off the chat goroutine whether the command
+// sender follows the channel and handles the waiting commands with the result.
+// Commands of a user with a lookup in flight wait for it instead of starting another.
+func (s *TwitchService) lookupFollower(cmd *domain.ChatCommand, config *domain.TwitchConfig) {
 	s.mu.Lock()
-	s.activeEffect = nil
+	waiting, running := s.followerQueue[cmd.UserID]
+	s.followerQueue[cmd.UserID] = append(waiting, cmd)
 	s.mu.Unlock()
+
+	if running {
+		return
+	}
+
+	token := strings.TrimPrefix(config.AccessToken, "oauth:")
+	go func() {
+		ttl := followerCacheTTL
+		following, err := s.apiClient.IsFollower(token, cmd.ChannelID, cmd.UserID)
+		if err != nil {
+			// Cache the failure too, a Helix outage must not cost a request per message
+			s.log.Warn("Follower check failed", "user", cmd.Username, "error", err)
+			following, ttl = false, followerFailureTTL
+		}
+
+		s.mu.Lock()
+		s.followerCache[cmd.UserID] = followerCacheEntry{isFollower: following, expiresAt: time.Now().Add(ttl)}
+		waiting := s.followerQueue[cmd.UserID]
+		delete(s.followerQueue, cmd.UserID)
+		s.mu.Unlock()
+
+		for _, waitingCmd := range waiting {
+			s.handleCommand(waitingCmd)
+		}
+	}()
 }
 
 // sendCooldownMessage sends a cooldown message to chat
-func (s *TwitchService) sendCooldownMessage(username string, remaining time.Duration, cooldownType string) {
-	seconds := int(remaining.Seconds())
-	if s.ircClient != nil {
-		s.ircClient.SendMessage(fmt.Sprintf("@%s Please wait %d seconds (%s cooldown)",
-			username, seconds, cooldownType))
-	}
+func (s *TwitchService) sendCooldownMessage(cmd *domain.ChatCommand, remaining time.Duration, cooldownType string) {
+	s.reply(cmd, domain.ReplyCooldown, domain.ReplyData{
+		Seconds:  int(remaining.Seconds()),
+		Cooldown: cooldownType,
+	})
 }
 
 // SetStatusChangeCallback sets callback for connection status changes
-func (s *TwitchService) SetStatusChangeCallback(callback func(bool)) {
+func (s *TwitchService) SetStatusChangeCallback(callback func(domain.ConnectionStatus)) {
 	s.onStatusChange = callback
 }
 
@@ -276,14 +909,82 @@ func (s *TwitchService) SetCommandSuccessCallback(callback func(string, string))
 	s.onCommandSuccess = callback
 }
 
+// SetAPIClient sets the Twitch API client used for follower lookups
+func (s *TwitchService) SetAPIClient(client FollowerLookup) {
+	s.apiClient = client
+}
+
+// SetPointsLedger sets the ledger command costs are charged to
+func (s *TwitchService) SetPointsLedger(ledger PointsLedger) {
+	s.points = ledger
+}
+
+// SetPresenceCallback sets callback for viewer activity in any chat
+func (s *TwitchService) SetPresenceCallback(callback domain.ChatPresenceHandler) {
+	s.onPresence = callback
+}
+
+// SetYouTubeAPIKey sets the API key used to read YouTube Live chats
+func (s *TwitchService) SetYouTubeAPIKey(apiKey string) {
+	s.youtubeAPIKey = apiKey
+}
+
+// EnableConsoleChat adds a simulated chat read from the terminal, even if Twitch is disabled
+func (s *TwitchService) EnableConsoleChat() {
+	s.consoleChat = true
+}
+
+// SetVoteUpdateCallback sets callback for poll tally changes
+func (s *TwitchService) SetVoteUpdateCallback(callback func(*domain.VoteSession)) {
+	s.onVoteUpdate = callback
+}
+
 // SetGetSelectedDeviceFunc sets the function to get selected device address
 func (s *TwitchService) SetGetSelectedDeviceFunc(fn func() (string, error)) {
 	s.getSelectedDevice = fn
 }
 
-// GetActiveEffect returns the currently active effect
+// SafetyFilter returns the filter lamp changes pass through
+func (s *TwitchService) SafetyFilter() *SafetyFilter {
+	return s.safety
+}
+
+// Arbiter returns the arbiter deciding which source controls the lamps
+func (s *TwitchService) Arbiter() *LampArbiter {
+	return s.arbiter
+}
+
+// GetActiveEffect returns the active effect on the selected device, or any active effect
 func (s *TwitchService) GetActiveEffect() *ActiveEffect {
+	deviceAddr, _ := s.deviceFor(nil)
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.activeEffect
+
+	if effect, exists := s.activeEffects[deviceAddr]; exists && effect.Remaining() > 0 {
+		return effect
+	}
+	for _, effect := range s.activeEffects {
+		if effect.Remaining() > 0 {
+			return effect
+		}
+	}
+	return nil
+}
+
+// GetActiveEffects returns the active effects of all devices
+func (s *TwitchService) GetActiveEffects() []*ActiveEffect {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	effects := make([]*ActiveEffect, 0, len(s.activeEffects))
+	for _, effect := range s.activeEffects {
+		if effect.Remaining() > 0 {
+			effects = append(effects, effect)
+		}
+	}
+	sort.Slice(effects, func(i, j int) bool {
+		return effects[i].StartedAt.Before(effects[j].StartedAt)
+	})
+	return effects
 }
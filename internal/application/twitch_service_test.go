@@ -0,0 +1,154 @@
+package application
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTwitchService creates a service with storages in a temporary home and no lamp
+func newTestTwitchService(t *testing.T, configure func(config *domain.TwitchConfig)) (*TwitchService, *storage.HistoryStorage) {
+	t.Setenv("HOME", t.TempDir())
+
+	twitchStorage, err := storage.NewTwitchStorage()
+	require.NoError(t, err)
+	history, err := storage.NewHistoryStorage()
+	require.NoError(t, err)
+
+	if configure != nil {
+		config := twitchStorage.Get().Clone()
+		configure(config)
+		require.NoError(t, twitchStorage.Save(config))
+	}
+
+	deviceService := NewDeviceService(nil)
+	return NewTwitchService(deviceService, NewLampArbiter(deviceService), twitchStorage, history), history
+}
+
+// fakeFollowerLookup answers follower lookups once released
+type fakeFollowerLookup struct {
+	mu        sync.Mutex
+	calls     int
+	following bool
+	err       error
+	release   chan struct{}
+}
+
+func (f *fakeFollowerLookup) IsFollower(_, _, _ string) (bool, error) {
+	f.mu.Lock()
+	f.calls++
+	f.mu.Unlock()
+
+	<-f.release
+	return f.following, f.err
+}
+
+func (f *fakeFollowerLookup) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func TestFollowerLookupIsAsyncAndCached(t *testing.T) {
+	tests := []struct {
+		name        string
+		following   bool
+		err         error
+		wantOutcome domain.CommandOutcome
+	}{
+		// Without a lamp an allowed command fails at the device lookup
+		{name: "follower", following: true, wantOutcome: domain.OutcomeFailure},
+		{name: "not following", wantOutcome: domain.OutcomeDenied},
+		{name: "lookup failed", err: errors.New("helix unavailable"), wantOutcome: domain.OutcomeDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, history := newTestTwitchService(t, func(config *domain.TwitchConfig) {
+				config.SetPermission(domain.CommandPermission{Command: "red", MinRole: domain.RoleFollower})
+			})
+			lookup := &fakeFollowerLookup{following: tt.following, err: tt.err, release: make(chan struct{})}
+			service.SetAPIClient(lookup)
+
+			command := func() *domain.ChatCommand {
+				return &domain.ChatCommand{
+					Platform:  domain.PlatformTwitch,
+					Username:  "viewer",
+					UserID:    "42",
+					ChannelID: "1",
+					Command:   "red",
+				}
+			}
+
+			// Neither command blocks on the lookup, and they share it
+			service.handleCommand(command())
+			service.handleCommand(command())
+			assert.Eventually(t, func() bool { return lookup.Calls() == 1 }, time.Second, 5*time.Millisecond)
+			assert.Empty(t, history.Query(domain.CommandHistoryFilter{}))
+
+			close(lookup.release)
+			assert.Eventually(t, func() bool {
+				return len(history.Query(domain.CommandHistoryFilter{})) == 2
+			}, time.Second, 5*time.Millisecond)
+
+			// Later commands use the cached answer
+			service.handleCommand(command())
+			records := history.Query(domain.CommandHistoryFilter{})
+			require.Len(t, records, 3)
+			for _, record := range records {
+				assert.Equal(t, tt.wantOutcome, record.Outcome)
+			}
+			assert.Equal(t, 1, lookup.Calls())
+		})
+	}
+}
+
+// fakeLedger holds points in memory
+type fakeLedger map[string]int
+
+func (l fakeLedger) Balance(viewer string) int { return l[viewer] }
+
+func (l fakeLedger) Spend(viewer string, amount int) error {
+	if l[viewer] < amount {
+		return domain.ErrInsufficientPoints
+	}
+	l[viewer] -= amount
+	return nil
+}
+
+func (l fakeLedger) Refund(viewer string, amount int) { l[viewer] += amount }
+
+func TestPaidCommandsSkipPolls(t *testing.T) {
+	service, history := newTestTwitchService(t, func(config *domain.TwitchConfig) {
+		config.VoteMode = true
+		config.Loyalty.Enabled = true
+		config.SetCommandSetting(domain.CommandSetting{Command: "rainbow", Cost: 50})
+	})
+	service.SetPointsLedger(fakeLedger{})
+	service.SetGetSelectedDeviceFunc(func() (string, error) { return "AA:BB:CC:DD:EE:FF", nil })
+
+	command := func(name string) *domain.ChatCommand {
+		return &domain.ChatCommand{Platform: domain.PlatformTwitch, Username: "viewer", Command: name}
+	}
+
+	// A free command is a ballot, a paid one is charged instead of voting for free
+	service.handleCommand(command("red"))
+	service.handleCommand(command("rainbow"))
+	defer service.CancelVote()
+
+	records := history.Query(domain.CommandHistoryFilter{})
+	require.Len(t, records, 2)
+	assert.Equal(t, domain.OutcomeDenied, records[0].Outcome)
+	assert.Equal(t, domain.ErrInsufficientPoints.Error(), records[0].Reason)
+	assert.Equal(t, domain.OutcomeVote, records[1].Outcome)
+
+	vote := service.GetVote()
+	require.NotNil(t, vote)
+	assert.Len(t, vote.Votes, 1)
+}
@@ -0,0 +1,131 @@
+package application
+
+import (
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// VoteManager runs chat polls and closes them when their window expires
+type VoteManager struct {
+	session *domain.VoteSession
+	timer   *time.Timer
+	mu      sync.Mutex
+
+	// Callbacks receive copies of the session
+	onUpdate func(session *domain.VoteSession)
+	onClose  func(session *domain.VoteSession)
+}
+
+// NewVoteManager creates a new vote manager
+func NewVoteManager() *VoteManager {
+	return &VoteManager{}
+}
+
+// IsOpen returns whether a poll is currently accepting votes
+func (m *VoteManager) IsOpen() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.session != nil && !m.session.Closed
+}
+
+// Start opens a new poll. It returns false if a poll is already running.
+// A duration that isn't positive falls back to the default.
+func (m *VoteManager) Start(duration time.Duration) bool {
+	if duration <= 0 {
+		duration = domain.DefaultVoteDuration
+	}
+
+	m.mu.Lock()
+	if m.session != nil && !m.session.Closed {
+		m.mu.Unlock()
+		return false
+	}
+
+	m.session = domain.NewVoteSession(duration)
+	m.timer = time.AfterFunc(duration, m.close)
+	snapshot := m.session.Clone()
+	m.mu.Unlock()
+
+	m.notify(m.onUpdate, snapshot)
+	return true
+}
+
+// Vote records a vote in the running poll. It returns false if no poll is open.
+func (m *VoteManager) Vote(username, option string) bool {
+	m.mu.Lock()
+	if m.session == nil || !m.session.CastVote(username, option) {
+		m.mu.Unlock()
+		return false
+	}
+	snapshot := m.session.Clone()
+	m.mu.Unlock()
+
+	m.notify(m.onUpdate, snapshot)
+	return true
+}
+
+// Cancel closes the running poll without applying a winner
+func (m *VoteManager) Cancel() bool {
+	m.mu.Lock()
+	if m.session == nil || m.session.Closed {
+		m.mu.Unlock()
+		return false
+	}
+
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+	m.session.Closed = true
+	m.session.Canceled = true
+	snapshot := m.session.Clone()
+	m.mu.Unlock()
+
+	m.notify(m.onUpdate, snapshot)
+	return true
+}
+
+// Current returns a copy of the latest poll, or nil if there never was one
+func (m *VoteManager) Current() *domain.VoteSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.session == nil {
+		return nil
+	}
+	return m.session.Clone()
+}
+
+// close ends the running poll and hands the result to the close callback
+func (m *VoteManager) close() {
+	m.mu.Lock()
+	if m.session == nil || m.session.Closed {
+		m.mu.Unlock()
+		return
+	}
+	m.session.Closed = true
+	snapshot := m.session.Clone()
+	m.mu.Unlock()
+
+	m.notify(m.onUpdate, snapshot)
+	m.notify(m.onClose, snapshot)
+}
+
+// notify calls a callback if it is set
+func (m *VoteManager) notify(callback func(*domain.VoteSession), session *domain.VoteSession) {
+	if callback != nil {
+		callback(session)
+	}
+}
+
+// SetUpdateCallback sets callback for poll changes
+func (m *VoteManager) SetUpdateCallback(callback func(*domain.VoteSession)) {
+	m.onUpdate = callback
+}
+
+// SetCloseCallback sets callback for polls that ran out
+func (m *VoteManager) SetCloseCallback(callback func(*domain.VoteSession)) {
+	m.onClose = callback
+}
@@ -0,0 +1,117 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteManagerDefaultsDuration(t *testing.T) {
+	manager := NewVoteManager()
+	defer manager.Cancel()
+
+	// Configs from before polls existed carry no duration
+	assert.True(t, manager.Start(0))
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, manager.IsOpen())
+}
+
+// castVotes votes in order, pausing so every vote gets its own timestamp
+func castVotes(t *testing.T, manager *VoteManager, votes [][2]string) {
+	t.Helper()
+	for _, vote := range votes {
+		require.True(t, manager.Vote(vote[0], vote[1]))
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestVoteManagerClosesWithWinner(t *testing.T) {
+	manager := NewVoteManager()
+	closed := make(chan *domain.VoteSession, 1)
+	manager.SetCloseCallback(func(session *domain.VoteSession) { closed <- session })
+
+	require.True(t, manager.Start(50*time.Millisecond))
+	castVotes(t, manager, [][2]string{{"alice", "red"}, {"bob", "blue"}, {"carol", "red"}})
+
+	select {
+	case session := <-closed:
+		assert.True(t, session.Closed)
+		assert.False(t, session.Canceled)
+		require.NotNil(t, session.Winner())
+		assert.Equal(t, "red", session.Winner().Option)
+		assert.Equal(t, 2, session.Winner().Count)
+	case <-time.After(time.Second):
+		t.Fatal("poll did not close")
+	}
+	assert.False(t, manager.IsOpen())
+	assert.False(t, manager.Vote("dave", "blue"))
+}
+
+func TestVoteManagerCancelAppliesNothing(t *testing.T) {
+	manager := NewVoteManager()
+	closed := make(chan *domain.VoteSession, 1)
+	manager.SetCloseCallback(func(session *domain.VoteSession) { closed <- session })
+
+	require.True(t, manager.Start(30*time.Millisecond))
+	castVotes(t, manager, [][2]string{{"alice", "red"}})
+	require.True(t, manager.Cancel())
+
+	session := manager.Current()
+	require.NotNil(t, session)
+	assert.True(t, session.Closed)
+	assert.True(t, session.Canceled)
+	assert.False(t, manager.IsOpen())
+
+	// The timer must not fire the close callback after a cancel
+	select {
+	case <-closed:
+		t.Fatal("canceled poll applied a result")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestVoteManagerTally(t *testing.T) {
+	tests := []struct {
+		name   string
+		votes  [][2]string
+		winner string
+		count  int
+		total  int
+	}{
+		{
+			name:   "revote replaces earlier vote",
+			votes:  [][2]string{{"alice", "red"}, {"bob", "red"}, {"alice", "Blue"}, {"carol", "blue"}},
+			winner: "blue",
+			count:  2,
+			total:  3,
+		},
+		{
+			name:   "tie goes to first option reaching the count",
+			votes:  [][2]string{{"alice", "red"}, {"bob", "blue"}, {"carol", "blue"}, {"dave", "red"}},
+			winner: "blue",
+			count:  2,
+			total:  4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewVoteManager()
+			defer manager.Cancel()
+
+			require.True(t, manager.Start(time.Minute))
+			castVotes(t, manager, tt.votes)
+
+			session := manager.Current()
+			require.NotNil(t, session)
+			assert.Len(t, session.Votes, tt.total)
+			winner := session.Winner()
+			require.NotNil(t, winner)
+			assert.Equal(t, tt.winner, winner.Option)
+			assert.Equal(t, tt.count, winner.Count)
+		})
+	}
+}
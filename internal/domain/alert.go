@@ -0,0 +1,253 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// AlertType is a stream event the lamp can react to
+type AlertType string
+
+const (
+	AlertFollow   AlertType = "follow"
+	AlertSub      AlertType = "sub"
+	AlertRaid     AlertType = "raid"
+	AlertDonation AlertType = "donation"
+	AlertGift     AlertType = "gift" // Gifted subs, a gift bomb reports all gifts at once
+)
+
+// AlertTypes lists all alert types
+var AlertTypes = []AlertType{AlertFollow, AlertSub, AlertRaid, AlertDonation, AlertGift}
+
+// MaxAlertDuration caps how long an alert effect runs, including scaling by amount
+const MaxAlertDuration = time.Minute
+
+// IsValid checks if the alert type is supported
+func (t AlertType) IsValid() bool {
+	for _, alertType := range AlertTypes {
+		if t == alertType {
+			return true
+		}
+	}
+	return false
+}
+
+// Alert is a stream event reported by a webhook or an alert provider
+type Alert struct {
+	Type      AlertType
+	Source    string  // Webhook name or provider, e.g. "streamelements"
+	User      string  // Who followed, subscribed, raided or donated
+	Amount    float64 // Donation amount, raid viewers, sub months or gift count
+	Message   string
+	Timestamp time.Time
+}
+
+// AlertHandler is called for every received alert
+type AlertHandler func(alert *Alert)
+
+// Alert providers with socket clients
+const (
+	AlertProviderStreamElements = "streamelements"
+	AlertProviderStreamlabs     = "streamlabs"
+)
+
+// DefaultSignatureHeader carries the webhook HMAC signature unless configured otherwise
+const DefaultSignatureHeader = "X-Signature"
+
+// webhookNamePattern restricts webhook names to URL-safe path segments
+var webhookNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// WebhookConfig describes an inbound alert webhook at POST /api/hooks/{name}.
+// Requests must be signed with an HMAC-SHA256 of the body using the secret.
+// Fields are extracted with JSONPath-style paths like "$.data.user.name" or "$.items[0].amount".
+type WebhookConfig struct {
+	Name            string               `json:"name"`
+	Secret          string               `json:"secret"`                // HMAC key (encrypted in storage)
+	SignatureHeader string               `json:"signature_header"`      // Header with the hex signature (default: X-Signature)
+	Type            AlertType            `json:"type,omitempty"`        // Fixed alert type for hooks that send one kind of event
+	TypePath        string               `json:"type_path,omitempty"`   // Path to the event type in the payload
+	TypeValues      map[string]AlertType `json:"type_values,omitempty"` // Payload event types -> alert types, unmapped values are ignored
+	UserPath        string               `json:"user_path,omitempty"`
+	AmountPath      string               `json:"amount_path,omitempty"`
+	MessagePath     string               `json:"message_path,omitempty"`
+}
+
+// Validate validates the webhook configuration
+func (h *WebhookConfig) Validate() error {
+	if !webhookNamePattern.MatchString(h.Name) {
+		return fmt.Errorf("webhook name must be lowercase letters, digits, - or _")
+	}
+	if h.Secret == "" {
+		return fmt.Errorf("webhook %s needs a secret", h.Name)
+	}
+	if h.Type == "" && h.TypePath == "" {
+		return fmt.Errorf("webhook %s needs a type or a type path", h.Name)
+	}
+	if h.Type != "" && !h.Type.IsValid() {
+		return fmt.Errorf("unknown alert type: %s", h.Type)
+	}
+	for _, alertType := range h.TypeValues {
+		if !alertType.IsValid() {
+			return fmt.Errorf("unknown alert type: %s", alertType)
+		}
+	}
+	return nil
+}
+
+// SignatureHeaderOrDefault returns the signature header, falling back to the default
+func (h *WebhookConfig) SignatureHeaderOrDefault() string {
+	if h.SignatureHeader == "" {
+		return DefaultSignatureHeader
+	}
+	return h.SignatureHeader
+}
+
+// AlertSocketConfig enables the socket client of an alert provider
+type AlertSocketConfig struct {
+	Enabled bool   `json:"enabled"`
+	Token   string `json:"token"` // StreamElements JWT or Streamlabs socket token (encrypted in storage)
+}
+
+// AlertReaction is the temporary lamp effect for an alert type
+type AlertReaction struct {
+	Type        AlertType     `json:"type"`
+	MinAmount   float64       `json:"min_amount"` // Only for alerts of at least this amount, e.g. raids with 10+ viewers
+	Color       *RGB          `json:"color,omitempty"`
+	Effect      *int          `json:"effect,omitempty"`
+	EffectSpeed *uint8        `json:"effect_speed,omitempty"`
+	Duration    time.Duration `json:"duration"` // How long the effect lasts before the lamp is restored
+
+	// DurationPerAmount extends the effect per unit of the alert amount, e.g. per raider
+	DurationPerAmount time.Duration `json:"duration_per_amount,omitempty"`
+
+	// Blend, Opacity and Fade combine the effect with the lamp state, e.g. a dimmed flash
+	Blend   BlendMode     `json:"blend,omitempty"`
+	Opacity float64       `json:"opacity,omitempty"` // 0 to 1, zero means fully opaque
+	Fade    time.Duration `json:"fade,omitempty"`
+}
+
+// Validate validates the reaction
+func (r *AlertReaction) Validate() error {
+	if !r.Type.IsValid() {
+		return fmt.Errorf("unknown alert type: %s", r.Type)
+	}
+	if (r.Color == nil) == (r.Effect == nil) {
+		return fmt.Errorf("%s reaction needs either a color or an effect", r.Type)
+	}
+	if r.Effect != nil && (*r.Effect < 0 || *r.Effect > 255) {
+		return ErrInvalidEffect
+	}
+	if r.Duration < time.Second || r.Duration > MaxAlertDuration {
+		return fmt.Errorf("%s reaction duration must be between 1 second and 1 minute", r.Type)
+	}
+	if r.DurationPerAmount < 0 {
+		return fmt.Errorf("%s reaction duration per amount cannot be negative", r.Type)
+	}
+	if r.MinAmount < 0 {
+		return fmt.Errorf("%s reaction minimum amount cannot be negative", r.Type)
+	}
+	if err := ValidateBlend(r.Blend, r.Opacity, r.Fade); err != nil {
+		return fmt.Errorf("%s reaction: %w", r.Type, err)
+	}
+	return nil
+}
+
+// DurationFor returns how long the effect runs for an alert amount
+func (r *AlertReaction) DurationFor(amount float64) time.Duration {
+	duration := r.Duration
+	if r.DurationPerAmount > 0 && amount > 0 {
+		duration += time.Duration(amount * float64(r.DurationPerAmount))
+	}
+	if duration > MaxAlertDuration || duration < 0 {
+		return MaxAlertDuration
+	}
+	return duration
+}
+
+// Change returns the reaction as a device state change
+func (r *AlertReaction) Change() StateChange {
+	on := true
+	return StateChange{PowerOn: &on, RGB: r.Color, Effect: r.Effect, EffectSpeed: r.EffectSpeed}
+}
+
+// AlertConfig represents the alert ingest configuration
+type AlertConfig struct {
+	Webhooks       []WebhookConfig   `json:"webhooks"`
+	StreamElements AlertSocketConfig `json:"streamelements"`
+	Streamlabs     AlertSocketConfig `json:"streamlabs"`
+	Reactions      []AlertReaction   `json:"reactions"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// NewAlertConfig creates the default alert configuration
+func NewAlertConfig() *AlertConfig {
+	rainbow := int(EffectMap["rainbow"])
+	pulse := int(EffectMap["pulse"])
+
+	return &AlertConfig{
+		Webhooks: []WebhookConfig{},
+		Reactions: []AlertReaction{
+			{Type: AlertFollow, Color: &RGB{R: 145, G: 70, B: 255}, Duration: 5 * time.Second},
+			{Type: AlertSub, Effect: &rainbow, Duration: 10 * time.Second},
+			{Type: AlertRaid, Effect: &pulse, Duration: 10 * time.Second, DurationPerAmount: 200 * time.Millisecond},
+			{Type: AlertDonation, Color: &RGB{R: 255, G: 215, B: 0}, Duration: 8 * time.Second},
+			{Type: AlertGift, Effect: &rainbow, Duration: 8 * time.Second, DurationPerAmount: time.Second},
+		},
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Validate validates the alert configuration
+func (c *AlertConfig) Validate() error {
+	seen := make(map[string]bool)
+	for i := range c.Webhooks {
+		if err := c.Webhooks[i].Validate(); err != nil {
+			return err
+		}
+		if seen[c.Webhooks[i].Name] {
+			return fmt.Errorf("duplicate webhook: %s", c.Webhooks[i].Name)
+		}
+		seen[c.Webhooks[i].Name] = true
+	}
+
+	if c.StreamElements.Enabled && c.StreamElements.Token == "" {
+		return fmt.Errorf("StreamElements token is required")
+	}
+	if c.Streamlabs.Enabled && c.Streamlabs.Token == "" {
+		return fmt.Errorf("Streamlabs socket token is required")
+	}
+
+	for i := range c.Reactions {
+		if err := c.Reactions[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetWebhook returns the webhook with the given name, or nil if there is none
+func (c *AlertConfig) GetWebhook(name string) *WebhookConfig {
+	for i := range c.Webhooks {
+		if c.Webhooks[i].Name == name {
+			return &c.Webhooks[i]
+		}
+	}
+	return nil
+}
+
+// ReactionFor returns the reaction for an alert: the one of its type with the
+// highest minimum amount the alert reaches, or nil if none applies
+func (c *AlertConfig) ReactionFor(alert *Alert) *AlertReaction {
+	var best *AlertReaction
+	for i := range c.Reactions {
+		reaction := &c.Reactions[i]
+		if reaction.Type != alert.Type || alert.Amount < reaction.MinAmount {
+			continue
+		}
+		if best == nil || reaction.MinAmount > best.MinAmount {
+			best = reaction
+		}
+	}
+	return best
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertConfigReactionFor(t *testing.T) {
+	config := NewAlertConfig()
+	assert.NoError(t, config.Validate())
+
+	strobe := int(EffectMap["strobe"])
+	config.Reactions = append(config.Reactions, AlertReaction{Type: AlertRaid, MinAmount: 50, Effect: &strobe, Duration: 20 * time.Second})
+
+	tests := []struct {
+		name       string
+		alert      Alert
+		wantEffect *int
+		wantNone   bool
+	}{
+		{name: "follow uses color", alert: Alert{Type: AlertFollow}},
+		{name: "small raid", alert: Alert{Type: AlertRaid, Amount: 10}, wantEffect: config.Reactions[2].Effect},
+		{name: "big raid uses highest reached minimum", alert: Alert{Type: AlertRaid, Amount: 80}, wantEffect: &strobe},
+		{name: "unknown type", alert: Alert{Type: "cheer"}, wantNone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaction := config.ReactionFor(&tt.alert)
+			if tt.wantNone {
+				assert.Nil(t, reaction)
+				return
+			}
+			if assert.NotNil(t, reaction) {
+				assert.Equal(t, tt.alert.Type, reaction.Type)
+				assert.Equal(t, tt.wantEffect, reaction.Effect)
+			}
+		})
+	}
+}
+
+func TestAlertConfigValidate(t *testing.T) {
+	hook := WebhookConfig{Name: "kofi", Secret: "secret", Type: AlertDonation}
+
+	assert.NoError(t, (&AlertConfig{Webhooks: []WebhookConfig{hook}}).Validate())
+	assert.Error(t, (&AlertConfig{Webhooks: []WebhookConfig{hook, hook}}).Validate(), "duplicate names")
+	assert.Error(t, (&AlertConfig{Webhooks: []WebhookConfig{{Name: "Ko Fi", Secret: "s", Type: AlertDonation}}}).Validate())
+	assert.Error(t, (&AlertConfig{Webhooks: []WebhookConfig{{Name: "kofi", Type: AlertDonation}}}).Validate(), "unsigned webhook")
+	assert.Error(t, (&AlertConfig{Webhooks: []WebhookConfig{{Name: "kofi", Secret: "s"}}}).Validate(), "no type")
+	assert.Error(t, (&AlertConfig{StreamElements: AlertSocketConfig{Enabled: true}}).Validate(), "missing token")
+	assert.Error(t, (&AlertConfig{Reactions: []AlertReaction{{Type: AlertSub, Color: &RGB{R: 1}}}}).Validate(), "no duration")
+}
+
+func TestAlertReactionDurationFor(t *testing.T) {
+	raid := AlertReaction{Type: AlertRaid, Duration: 10 * time.Second, DurationPerAmount: 200 * time.Millisecond}
+
+	assert.Equal(t, 10*time.Second, raid.DurationFor(0))
+	assert.Equal(t, 20*time.Second, raid.DurationFor(50), "scales with raiders")
+	assert.Equal(t, MaxAlertDuration, raid.DurationFor(5000), "capped")
+
+	fixed := AlertReaction{Type: AlertFollow, Duration: 5 * time.Second}
+	assert.Equal(t, 5*time.Second, fixed.DurationFor(100))
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChatPlatform identifies where chat commands come from
+type ChatPlatform string
+
+const (
+	PlatformTwitch  ChatPlatform = "twitch"
+	PlatformYouTube ChatPlatform = "youtube"
+	PlatformConsole ChatPlatform = "console" // Simulated chat typed into the terminal
+)
+
+// IsValid checks if the platform is supported
+func (p ChatPlatform) IsValid() bool {
+	switch p {
+	case PlatformTwitch, PlatformYouTube, PlatformConsole:
+		return true
+	default:
+		return false
+	}
+}
+
+// ChatCommandHandler is called when a chat source receives a lamp command
+type ChatCommandHandler func(cmd *ChatCommand)
+
+// ConnectionStateHandler is called when a chat source changes its connection state
+type ConnectionStateHandler func(status ConnectionStatus)
+
+// ChatChannel represents a chat the integration listens to in addition to the main Twitch channel
+type ChatChannel struct {
+	ID            string              `json:"id"`
+	Platform      ChatPlatform        `json:"platform"`
+	Channel       string              `json:"channel"` // Twitch channel name, YouTube video ID or console label
+	Enabled       bool                `json:"enabled"`
+	DeviceAddress string              `json:"device_address"` // Lamp driven by this chat, empty for the selected device
+	Commands      []string            `json:"commands"`       // Allowed commands, empty allows all
+	Permissions   []CommandPermission `json:"permissions"`    // Rules that replace the global rule for the same command
+}
+
+// NewChatChannel creates an enabled chat channel
+func NewChatChannel(platform ChatPlatform, channel string) ChatChannel {
+	channel = strings.TrimPrefix(strings.TrimSpace(channel), "#")
+	return ChatChannel{
+		ID:          ChatChannelID(platform, channel),
+		Platform:    platform,
+		Channel:     channel,
+		Enabled:     true,
+		Commands:    []string{},
+		Permissions: []CommandPermission{},
+	}
+}
+
+// ChatChannelID builds the ID of a chat channel
+func ChatChannelID(platform ChatPlatform, channel string) string {
+	return string(platform) + ":" + strings.ToLower(channel)
+}
+
+// Validate validates the chat channel
+func (c *ChatChannel) Validate() error {
+	if !c.Platform.IsValid() {
+		return fmt.Errorf("unsupported chat platform: %s", c.Platform)
+	}
+	if c.Channel == "" {
+		return fmt.Errorf("channel is required for %s chat", c.Platform)
+	}
+	for i := range c.Permissions {
+		if err := c.Permissions[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AllowsCommand checks the channel's command list
+func (c *ChatChannel) AllowsCommand(command string) bool {
+	if len(c.Commands) == 0 {
+		return true
+	}
+	for _, allowed := range c.Commands {
+		if strings.EqualFold(allowed, command) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPermission returns the channel's own rule for a command, or nil
+func (c *ChatChannel) GetPermission(command string) *CommandPermission {
+	for i := range c.Permissions {
+		if strings.EqualFold(c.Permissions[i].Command, command) {
+			return &c.Permissions[i]
+		}
+	}
+	return nil
+}
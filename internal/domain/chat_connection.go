@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// ConnectionState represents the lifecycle state of a chat connection
+type ConnectionState string
+
+const (
+	ConnectionDisconnected ConnectionState = "disconnected"
+	ConnectionConnecting   ConnectionState = "connecting"
+	ConnectionConnected    ConnectionState = "connected" // Logged in, waiting for join confirmation
+	ConnectionJoined       ConnectionState = "joined"
+	ConnectionReconnecting ConnectionState = "reconnecting"
+	ConnectionAuthFailed   ConnectionState = "auth_failed"
+)
+
+// ConnectionStatus describes the current state of a chat connection
+type ConnectionStatus struct {
+	State   ConnectionState `json:"state"`
+	Error   string          `json:"error,omitempty"`   // Last connection error, if any
+	Attempt int             `json:"attempt,omitempty"` // Reconnect attempt counter
+	RetryAt time.Time       `json:"retry_at"`          // Next reconnect attempt while reconnecting
+	Since   time.Time       `json:"since"`             // When the state was entered
+}
+
+// NewConnectionStatus creates a status entering the given state now
+func NewConnectionStatus(state ConnectionState) ConnectionStatus {
+	return ConnectionStatus{
+		State: state,
+		Since: time.Now(),
+	}
+}
+
+// IsActive reports whether the connection is up or being established
+func (s ConnectionStatus) IsActive() bool {
+	return s.State != ConnectionDisconnected && s.State != ConnectionAuthFailed
+}
@@ -0,0 +1,230 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ReplyEvent identifies a kind of bot chat reply
+type ReplyEvent string
+
+const (
+	ReplySuccess      ReplyEvent = "success"       // Command changed the lamp
+	ReplyCooldown     ReplyEvent = "cooldown"      // Command hit a cooldown
+	ReplyCooldownMany ReplyEvent = "cooldown_many" // Several cooldown replies collapsed into one
+	ReplyUnknown      ReplyEvent = "unknown"       // Command does not exist
+	ReplyFailure      ReplyEvent = "failure"       // Command failed on the device
+	ReplyQueued       ReplyEvent = "queued"        // Command was counted as a poll vote
+	ReplyCost         ReplyEvent = "cost"          // Sender can't afford the command
+	ReplyUnsafe       ReplyEvent = "unsafe"        // The safety filter blocked the command
+	ReplyDenied       ReplyEvent = "denied"        // Sender lacks the required role
+	ReplyDisabled     ReplyEvent = "disabled"      // Command is not allowed in this chat
+	ReplyLocked       ReplyEvent = "locked"        // The streamer locked the lamp
+	ReplyBusy         ReplyEvent = "busy"          // A stream alert is playing on the lamp
+	ReplyLock         ReplyEvent = "lock"          // Announces a lock
+	ReplyUnlock       ReplyEvent = "unlock"        // Announces an unlock
+	ReplyStats        ReplyEvent = "stats"         // Viewer asked for their stats
+	ReplyStatsEmpty   ReplyEvent = "stats_empty"   // Viewer asked for stats without any command
+	ReplyPoints       ReplyEvent = "points"        // Viewer asked for their loyalty points
+	ReplyPollStart    ReplyEvent = "poll_start"    // A poll was opened
+	ReplyPollResult   ReplyEvent = "poll_result"   // A poll winner was applied
+	ReplyPollFailed   ReplyEvent = "poll_failed"   // A poll winner could not be applied
+)
+
+// DefaultReplyLanguage is used when no language is configured
+const DefaultReplyLanguage = "en"
+
+// Twitch allows regular accounts 20 messages per 30 seconds, moderators 100
+const (
+	ReplyRateWindow       = 30 * time.Second
+	DefaultReplyRateLimit = 20
+	MaxReplyRateLimit     = 100
+)
+
+// ReplyData holds the values available in reply templates
+type ReplyData struct {
+	User     string   // Display name of the sender
+	Users    []string // Display names of all senders of a collapsed reply
+	Command  string
+	Seconds  int    // Effect duration, remaining cooldown or poll duration
+	Cooldown string // Cooldown kind, "global", "command" or "personal"
+	Audience string // Who may run a restricted command
+	Reason   string // Error message of a failed command
+	Count    int    // Commands of a viewer or votes of a poll winner
+	Total    int    // Votes of a poll
+	Favorite string // Most used command of a viewer
+	Cost     int    // Loyalty points a command costs
+	Balance  int    // Loyalty points of the sender
+}
+
+// Mention returns the @-mentions of all senders
+func (d ReplyData) Mention() string {
+	users := d.Users
+	if len(users) == 0 && d.User != "" {
+		users = []string{d.User}
+	}
+	if len(users) == 0 {
+		return ""
+	}
+	return "@" + strings.Join(users, " @")
+}
+
+// ReplyLanguages holds the built-in reply templates per language
+var ReplyLanguages = map[string]map[ReplyEvent]string{
+	"en": {
+		ReplySuccess:      "{{.Mention}} Lamp set to {{.Command}} for {{.Seconds}} seconds!",
+		ReplyCooldown:     "{{.Mention}} Please wait {{.Seconds}} seconds ({{.Cooldown}} cooldown)",
+		ReplyCooldownMany: "{{.Mention}} The lamp is on cooldown, please wait a moment",
+		ReplyUnknown:      "{{.Mention}} Unknown lamp command: {{.Command}}",
+		ReplyFailure:      "{{.Mention}} Sorry, that command failed: {{.Reason}}",
+		ReplyQueued:       "{{.Mention}} Your vote for {{.Command}} is counted",
+		ReplyCost:         "{{.Mention}} !lamp {{.Command}} costs {{.Cost}} points, you have {{.Balance}}",
+		ReplyUnsafe:       "{{.Mention}} !lamp {{.Command}} was blocked to keep the stream safe: {{.Reason}}",
+		ReplyDenied:       "{{.Mention}} !lamp {{.Command}} is only available to {{.Audience}}",
+		ReplyDisabled:     "{{.Mention}} !lamp {{.Command}} is not available in this chat",
+		ReplyLocked:       "{{.Mention}} The lamp is locked by the streamer right now",
+		ReplyBusy:         "{{.Mention}} A stream alert is playing on the lamp, try again in {{.Seconds}} seconds",
+		ReplyLock:         "The lamp is locked by the streamer, viewer commands are paused",
+		ReplyUnlock:       "The lamp is unlocked, !lamp commands are back!",
+		ReplyStats:        "{{.Mention}} You changed the lamp {{.Count}} times, your favorite is {{.Favorite}}",
+		ReplyStatsEmpty:   "{{.Mention}} You haven't changed the lamp yet. Try !lamp red",
+		ReplyPoints:       "{{.Mention}} You have {{.Balance}} points",
+		ReplyPollStart:    "Lamp poll started! Vote with !lamp <color> or !lamp <effect> for the next {{.Seconds}} seconds",
+		ReplyPollResult:   "Lamp poll closed: {{.Command}} wins with {{.Count}} of {{.Total}} votes! Active for {{.Seconds}} seconds",
+		ReplyPollFailed:   "Lamp poll closed: {{.Command}} won, but the lamp could not be updated",
+	},
+	"de": {
+		ReplySuccess:      "{{.Mention}} Lampe für {{.Seconds}} Sekunden auf {{.Command}} gesetzt!",
+		ReplyCooldown:     "{{.Mention}} Bitte warte noch {{.Seconds}} Sekunden ({{if eq .Cooldown \"global\"}}globaler{{else if eq .Cooldown \"command\"}}Befehls-{{else}}persönlicher{{end}} Cooldown)",
+		ReplyCooldownMany: "{{.Mention}} Die Lampe hat gerade Cooldown, bitte wartet einen Moment",
+		ReplyUnknown:      "{{.Mention}} Unbekannter Lampenbefehl: {{.Command}}",
+		ReplyFailure:      "{{.Mention}} Sorry, das hat nicht geklappt: {{.Reason}}",
+		ReplyQueued:       "{{.Mention}} Deine Stimme für {{.Command}} wurde gezählt",
+		ReplyCost:         "{{.Mention}} !lamp {{.Command}} kostet {{.Cost}} Punkte, du hast {{.Balance}}",
+		ReplyUnsafe:       "{{.Mention}} !lamp {{.Command}} wurde zum Schutz lichtempfindlicher Zuschauer blockiert",
+		ReplyDenied:       "{{.Mention}} !lamp {{.Command}} ist nur für {{.Audience}} verfügbar",
+		ReplyDisabled:     "{{.Mention}} !lamp {{.Command}} ist in diesem Chat nicht verfügbar",
+		ReplyLocked:       "{{.Mention}} Die Lampe ist gerade vom Streamer gesperrt",
+		ReplyBusy:         "{{.Mention}} Auf der Lampe läuft gerade ein Stream-Alert, versuch es in {{.Seconds}} Sekunden nochmal",
+		ReplyLock:         "Die Lampe wurde vom Streamer gesperrt, Zuschauerbefehle sind pausiert",
+		ReplyUnlock:       "Die Lampe ist wieder frei, !lamp Befehle sind zurück!",
+		ReplyStats:        "{{.Mention}} Du hast die Lampe {{.Count}} Mal geändert, dein Favorit ist {{.Favorite}}",
+		ReplyStatsEmpty:   "{{.Mention}} Du hast die Lampe noch nicht geändert. Probier mal !lamp red",
+		ReplyPoints:       "{{.Mention}} Du hast {{.Balance}} Punkte",
+		ReplyPollStart:    "Lampen-Umfrage gestartet! Stimmt mit !lamp <farbe> oder !lamp <effekt> in den nächsten {{.Seconds}} Sekunden ab",
+		ReplyPollResult:   "Umfrage beendet: {{.Command}} gewinnt mit {{.Count}} von {{.Total}} Stimmen! Aktiv für {{.Seconds}} Sekunden",
+		ReplyPollFailed:   "Umfrage beendet: {{.Command}} hat gewonnen, aber die Lampe konnte nicht geändert werden",
+	},
+}
+
+// ReplyAudiences holds the chat-friendly plural of each role per language
+var ReplyAudiences = map[string]map[Role]string{
+	"en": {
+		RoleEveryone:    "everyone",
+		RoleFollower:    "followers",
+		RoleSubscriber:  "subscribers and VIPs",
+		RoleVIP:         "VIPs and moderators",
+		RoleModerator:   "moderators",
+		RoleBroadcaster: "the broadcaster",
+	},
+	"de": {
+		RoleEveryone:    "alle",
+		RoleFollower:    "Follower",
+		RoleSubscriber:  "Abonnenten und VIPs",
+		RoleVIP:         "VIPs und Moderatoren",
+		RoleModerator:   "Moderatoren",
+		RoleBroadcaster: "den Streamer",
+	},
+}
+
+// ReplyEvents returns all reply events in a stable order
+func ReplyEvents() []ReplyEvent {
+	events := make([]ReplyEvent, 0, len(ReplyLanguages[DefaultReplyLanguage]))
+	for event := range ReplyLanguages[DefaultReplyLanguage] {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
+	return events
+}
+
+// ReplyLanguageNames returns the available language packs in a stable order
+func ReplyLanguageNames() []string {
+	names := make([]string, 0, len(ReplyLanguages))
+	for name := range ReplyLanguages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsValid checks if the event has a built-in template
+func (e ReplyEvent) IsValid() bool {
+	_, exists := ReplyLanguages[DefaultReplyLanguage][e]
+	return exists
+}
+
+// ReplyLanguageOrDefault returns the configured reply language, falling back to English
+func (c *TwitchConfig) ReplyLanguageOrDefault() string {
+	if _, exists := ReplyLanguages[c.ReplyLanguage]; exists {
+		return c.ReplyLanguage
+	}
+	return DefaultReplyLanguage
+}
+
+// ReplyTemplate returns the template text for an event:
+// a custom template, else the language pack, else English
+func (c *TwitchConfig) ReplyTemplate(event ReplyEvent) string {
+	if text, exists := c.ReplyTemplates[event]; exists && text != "" {
+		return text
+	}
+	if text, exists := ReplyLanguages[c.ReplyLanguageOrDefault()][event]; exists {
+		return text
+	}
+	return ReplyLanguages[DefaultReplyLanguage][event]
+}
+
+// IsReplySilenced checks if the bot should never send replies of the event.
+// Silencing cooldown also silences collapsed cooldown replies.
+func (c *TwitchConfig) IsReplySilenced(event ReplyEvent) bool {
+	for _, silenced := range c.SilencedReplies {
+		if silenced == event || (silenced == ReplyCooldown && event == ReplyCooldownMany) {
+			return true
+		}
+	}
+	return false
+}
+
+// Audience returns the chat-friendly plural of a role in the reply language
+func (c *TwitchConfig) Audience(role Role) string {
+	if audience, exists := ReplyAudiences[c.ReplyLanguageOrDefault()][role]; exists {
+		return audience
+	}
+	return ReplyAudiences[DefaultReplyLanguage][role]
+}
+
+// ParseReplyTemplate parses a reply template
+func ParseReplyTemplate(event ReplyEvent, text string) (*template.Template, error) {
+	tmpl, err := template.New(string(event)).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s reply template: %w", event, err)
+	}
+	return tmpl, nil
+}
+
+// RenderReply renders a reply template with the given data
+func RenderReply(event ReplyEvent, text string, data ReplyData) (string, error) {
+	tmpl, err := ParseReplyTemplate(event, text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s reply: %w", event, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderReply(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		custom   map[ReplyEvent]string
+		event    ReplyEvent
+		data     ReplyData
+		want     string
+	}{
+		{
+			name:  "english success",
+			event: ReplySuccess,
+			data:  ReplyData{User: "Alice", Command: "red", Seconds: 30},
+			want:  "@Alice Lamp set to red for 30 seconds!",
+		},
+		{
+			name:     "german cooldown",
+			language: "de",
+			event:    ReplyCooldown,
+			data:     ReplyData{User: "Alice", Seconds: 12, Cooldown: "global"},
+			want:     "@Alice Bitte warte noch 12 Sekunden (globaler Cooldown)",
+		},
+		{
+			name:  "collapsed cooldown mentions everyone",
+			event: ReplyCooldownMany,
+			data:  ReplyData{User: "Alice", Users: []string{"Alice", "Bob"}},
+			want:  "@Alice @Bob The lamp is on cooldown, please wait a moment",
+		},
+		{
+			name:     "custom template replaces the language pack",
+			language: "de",
+			custom:   map[ReplyEvent]string{ReplySuccess: "{{.Command}} it is, {{.User}}"},
+			event:    ReplySuccess,
+			data:     ReplyData{User: "Alice", Command: "blue"},
+			want:     "blue it is, Alice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewTwitchConfig()
+			if tt.language != "" {
+				config.ReplyLanguage = tt.language
+			}
+			if tt.custom != nil {
+				config.ReplyTemplates = tt.custom
+			}
+
+			got, err := RenderReply(tt.event, config.ReplyTemplate(tt.event), tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplyLanguagesAreComplete(t *testing.T) {
+	for language, pack := range ReplyLanguages {
+		for _, event := range ReplyEvents() {
+			text, exists := pack[event]
+			if assert.True(t, exists, "%s misses %s", language, event) {
+				_, err := RenderReply(event, text, ReplyData{})
+				assert.NoError(t, err)
+			}
+		}
+		for _, role := range []Role{RoleEveryone, RoleFollower, RoleSubscriber, RoleVIP, RoleModerator, RoleBroadcaster} {
+			assert.NotEmpty(t, ReplyAudiences[language][role], "%s misses audience %s", language, role)
+		}
+	}
+}
+
+func TestReplyConfigValidation(t *testing.T) {
+	config := NewTwitchConfig()
+	config.Channel = "streamer"
+	config.BotUsername = "bot"
+	config.AccessToken = "token"
+	require.NoError(t, config.Validate())
+
+	assert.True(t, config.IsReplySilenced(ReplyQueued))
+	config.SilencedReplies = []ReplyEvent{ReplyCooldown}
+	assert.True(t, config.IsReplySilenced(ReplyCooldownMany))
+
+	config.ReplyTemplates = map[ReplyEvent]string{ReplySuccess: "{{.User"}
+	assert.Error(t, config.Validate())
+
+	config.ReplyTemplates = map[ReplyEvent]string{"greeting": "hi"}
+	assert.Error(t, config.Validate())
+
+	config.ReplyTemplates = nil
+	config.ReplyLanguage = "fr"
+	assert.Error(t, config.Validate())
+
+	config.ReplyLanguage = "de"
+	config.ReplyRateLimit = MaxReplyRateLimit + 1
+	assert.Error(t, config.Validate())
+}
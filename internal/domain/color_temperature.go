@@ -0,0 +1,25 @@
+package domain
+
+import "math"
+
+// Color temperature range in mireds, coldest to warmest white the lamps mix
+const (
+	MinMireds = 153 // ~6500K, cold white only
+	MaxMireds = 500 // 2000K, warm white only
+)
+
+// MiredsToWhiteBalance mixes the warm and cold white channels for a color temperature
+func MiredsToWhiteBalance(mireds int) WhiteBalance {
+	mireds = max(MinMireds, min(MaxMireds, mireds))
+	warm := uint8(math.Round(255 * float64(mireds-MinMireds) / float64(MaxMireds-MinMireds)))
+	return WhiteBalance{Warm: warm, Cold: 255 - warm}
+}
+
+// WhiteBalanceToMireds returns the color temperature of a warm and cold white mix
+func WhiteBalanceToMireds(wb WhiteBalance) int {
+	total := int(wb.Warm) + int(wb.Cold)
+	if total == 0 {
+		return (MinMireds + MaxMireds) / 2
+	}
+	return MinMireds + int(math.Round(float64(MaxMireds-MinMireds)*float64(wb.Warm)/float64(total)))
+}
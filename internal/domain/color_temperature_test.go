@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiredsRoundTrip(t *testing.T) {
+	for _, mireds := range []int{MinMireds, 250, 327, 400, MaxMireds} {
+		assert.InDelta(t, mireds, WhiteBalanceToMireds(MiredsToWhiteBalance(mireds)), 1)
+	}
+	assert.Equal(t, WhiteBalance{Cold: 255}, MiredsToWhiteBalance(100), "clamped to the coldest white")
+}
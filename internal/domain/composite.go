@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// BlendMode is how a layer combines with the layers below it
+type BlendMode string
+
+const (
+	BlendReplace   BlendMode = "replace"   // Covers the layers below, mixed by opacity
+	BlendMultiply  BlendMode = "multiply"  // Scales brightness and color below, e.g. to dim them
+	BlendAdd       BlendMode = "add"       // Adds its brightness and color to the layers below
+	BlendCrossfade BlendMode = "crossfade" // Replace that fades in over the fade duration
+)
+
+// BlendModes lists the supported blend modes
+var BlendModes = []BlendMode{BlendReplace, BlendMultiply, BlendAdd, BlendCrossfade}
+
+// IsValid checks if the blend mode is supported. Empty means replace.
+func (m BlendMode) IsValid() bool {
+	if m == "" {
+		return true
+	}
+	for _, mode := range BlendModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateBlend validates a blend mode with its opacity and fade duration
+func ValidateBlend(mode BlendMode, opacity float64, fade time.Duration) error {
+	if !mode.IsValid() {
+		return fmt.Errorf("unsupported blend mode: %s", mode)
+	}
+	if opacity < 0 || opacity > 1 {
+		return fmt.Errorf("opacity must be between 0 and 1")
+	}
+	if fade < 0 {
+		return fmt.Errorf("fade duration cannot be negative")
+	}
+	return nil
+}
+
+// Blend returns the state with the change combined into it by a blend mode and opacity.
+// Brightness and RGB colors mix. Power, white balance and effects run in the lamp
+// hardware and can't be mixed, they cover the state below from half opacity on.
+func (c StateChange) Blend(state DeviceState, mode BlendMode, opacity float64) DeviceState {
+	if opacity <= 0 {
+		return state
+	}
+	opacity = math.Min(opacity, 1)
+
+	if opacity == 1 && (mode == "" || mode == BlendReplace || mode == BlendCrossfade) {
+		return c.Apply(state)
+	}
+
+	covers := opacity >= 0.5
+	if c.PowerOn != nil && covers {
+		state.PowerOn = *c.PowerOn
+	}
+
+	if c.Brightness != nil {
+		state.Brightness = blendChannel(mode, state.Brightness, *c.Brightness, opacity)
+	}
+
+	switch {
+	case c.RGB != nil && state.RGB != nil:
+		state.RGB = &RGB{
+			R: blendChannel(mode, state.RGB.R, c.RGB.R, opacity),
+			G: blendChannel(mode, state.RGB.G, c.RGB.G, opacity),
+			B: blendChannel(mode, state.RGB.B, c.RGB.B, opacity),
+		}
+	case covers:
+		state = StateChange{RGB: c.RGB, WhiteBalance: c.WhiteBalance, Effect: c.Effect, EffectSpeed: c.EffectSpeed}.Apply(state)
+	}
+
+	state.LastUpdated = time.Now()
+	return state
+}
+
+// blendChannel combines one color or brightness channel of a layer with the one below
+func blendChannel(mode BlendMode, below, layer uint8, opacity float64) uint8 {
+	target := float64(layer)
+	switch mode {
+	case BlendMultiply:
+		target = float64(below) * float64(layer) / 255
+	case BlendAdd:
+		target = math.Min(float64(below)+float64(layer), 255)
+	}
+	return uint8(math.Round(float64(below) + (target-float64(below))*opacity))
+}
+
+// Diff returns the change that turns one state into another, with only the fields that differ
+func Diff(from, to DeviceState) StateChange {
+	var change StateChange
+
+	if from.PowerOn != to.PowerOn {
+		change.PowerOn = &to.PowerOn
+	}
+	if !to.PowerOn {
+		return change
+	}
+
+	if from.Brightness != to.Brightness {
+		change.Brightness = &to.Brightness
+	}
+
+	switch {
+	case to.RGB != nil:
+		if from.RGB == nil || *from.RGB != *to.RGB {
+			change.RGB = to.RGB
+		}
+	case to.WhiteBalance != nil:
+		if from.WhiteBalance == nil || *from.WhiteBalance != *to.WhiteBalance {
+			change.WhiteBalance = to.WhiteBalance
+		}
+	case to.Effect != nil:
+		if from.Effect == nil || *from.Effect != *to.Effect || !equalSpeed(from.EffectSpeed, to.EffectSpeed) {
+			change.Effect = to.Effect
+			change.EffectSpeed = to.EffectSpeed
+		}
+	}
+
+	return change
+}
+
+// equalSpeed compares two optional effect speeds
+func equalSpeed(a, b *uint8) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
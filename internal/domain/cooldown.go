@@ -8,12 +8,14 @@ import (
 type CooldownState struct {
 	LastGlobalCommand time.Time
 	UserLastCommand   map[string]time.Time // username -> last command time
+	CommandLastRun    map[string]time.Time // command -> last run time
 }
 
 // NewCooldownState creates a new cooldown state
 func NewCooldownState() *CooldownState {
 	return &CooldownState{
 		UserLastCommand: make(map[string]time.Time),
+		CommandLastRun:  make(map[string]time.Time),
 	}
 }
 
@@ -50,15 +52,36 @@ func (c *CooldownState) CheckUserCooldown(username string, cooldown time.Duratio
 	return true, 0
 }
 
+// CheckCommandCooldown checks if the cooldown of a single command has expired
+func (c *CooldownState) CheckCommandCooldown(command string, cooldown time.Duration) (bool, time.Duration) {
+	if cooldown == 0 {
+		return true, 0
+	}
+
+	lastRun, exists := c.CommandLastRun[command]
+	if !exists {
+		return true, 0
+	}
+
+	elapsed := time.Since(lastRun)
+	if elapsed < cooldown {
+		return false, cooldown - elapsed
+	}
+
+	return true, 0
+}
+
 // RecordCommand records a command execution
-func (c *CooldownState) RecordCommand(username string) {
+func (c *CooldownState) RecordCommand(username, command string) {
 	now := time.Now()
 	c.LastGlobalCommand = now
 	c.UserLastCommand[username] = now
+	c.CommandLastRun[command] = now
 }
 
 // Reset resets all cooldowns
 func (c *CooldownState) Reset() {
 	c.LastGlobalCommand = time.Time{}
 	c.UserLastCommand = make(map[string]time.Time)
+	c.CommandLastRun = make(map[string]time.Time)
 }
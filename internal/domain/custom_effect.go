@@ -1,17 +1,32 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"slices"
+	"time"
+)
 
 // CustomEffect represents a user-defined lighting effect
 type CustomEffect struct {
 	ID          string      `json:"id"`
 	Name        string      `json:"name"`
 	Colors      []RGBColor  `json:"colors"`
-	Pattern     string      `json:"pattern"` // "fade", "strobe", "jump", "pulse"
+	Pattern     string      `json:"pattern"` // One of EffectPatterns
 	Speed       uint8       `json:"speed"`
 	CreatedAt   time.Time   `json:"created_at"`
 }
 
+// Patterns a custom effect runs its colors in
+const (
+	PatternFade   = "fade"
+	PatternJump   = "jump"
+	PatternStrobe = "strobe"
+	PatternPulse  = "pulse"
+)
+
+// EffectPatterns lists the supported custom effect patterns
+var EffectPatterns = []string{PatternFade, PatternJump, PatternStrobe, PatternPulse}
+
 // RGBColor represents an RGB color value
 type RGBColor struct {
 	R uint8 `json:"r"`
@@ -31,6 +46,20 @@ func NewCustomEffect(name string, colors []RGBColor, pattern string, speed uint8
 	}
 }
 
+// Validate checks that the effect has a name, colors and a supported pattern
+func (e *CustomEffect) Validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(e.Colors) == 0 {
+		return fmt.Errorf("at least one color is required")
+	}
+	if !slices.Contains(EffectPatterns, e.Pattern) {
+		return fmt.Errorf("unsupported pattern: %s", e.Pattern)
+	}
+	return nil
+}
+
 // generateID generates a simple ID based on timestamp
 func generateID() string {
 	return time.Now().Format("20060102150405")
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomEffectValidate(t *testing.T) {
+	colors := []RGBColor{{R: 255}}
+
+	tests := []struct {
+		name    string
+		effect  *CustomEffect
+		wantErr bool
+	}{
+		{name: "valid", effect: NewCustomEffect("Sunset", colors, PatternFade, 30)},
+		{name: "pulse", effect: NewCustomEffect("Sunset", colors, PatternPulse, 30)},
+		{name: "missing name", effect: NewCustomEffect("", colors, PatternFade, 30), wantErr: true},
+		{name: "no colors", effect: NewCustomEffect("Sunset", nil, PatternFade, 30), wantErr: true},
+		{name: "unknown pattern", effect: NewCustomEffect("Sunset", colors, "spin", 30), wantErr: true},
+		{name: "pattern is case sensitive", effect: NewCustomEffect("Sunset", colors, "Fade", 30), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.effect.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
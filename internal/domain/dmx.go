@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DMX input defaults
+const (
+	DefaultArtNetPort      = 6454
+	DefaultSACNPort        = 5568
+	DefaultDMXMaxRate      = 10 // Lamp updates per second, BLE can't keep up with the 44 fps of DMX
+	DefaultDMXInputTimeout = 3  // Seconds without frames before the input counts as lost
+	MaxDMXMaxRate          = 30
+	DMXUniverseSize        = 512
+	MaxSACNUniverse        = 63999
+)
+
+// dmxEffectThreshold is the lowest effect channel value that starts an effect, lower values show the color
+const dmxEffectThreshold = 10
+
+// DMXLossBehavior is what the lamps do when the console stops sending
+type DMXLossBehavior string
+
+const (
+	DMXLossHold     DMXLossBehavior = "hold"     // Keep the last look
+	DMXLossBlackout DMXLossBehavior = "blackout" // Turn the patched lamps off
+)
+
+// DMXConfig represents the Art-Net and sACN input configuration
+type DMXConfig struct {
+	Enabled         bool            `json:"enabled"`
+	ArtNet          bool            `json:"artnet"`                      // Listen for Art-Net on UDP 6454
+	SACN            bool            `json:"sacn"`                        // Listen for sACN (E1.31) on UDP 5568
+	MaxRate         int             `json:"max_rate,omitempty"`          // Lamp updates per second (default: 10)
+	InputTimeoutSec int             `json:"input_timeout_sec,omitempty"` // Seconds until the input counts as lost (default: 3)
+	OnInputLoss     DMXLossBehavior `json:"on_input_loss,omitempty"`     // Default: hold
+	Fixtures        []DMXFixture    `json:"fixtures"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// DMXFixture patches a lamp to DMX channels. Channels are 1-512 within the
+// universe, 0 leaves a function unpatched.
+type DMXFixture struct {
+	DeviceAddress string `json:"device_address"`
+	Universe      int    `json:"universe"` // Art-Net port address or sACN universe
+	Red           int    `json:"red,omitempty"`
+	Green         int    `json:"green,omitempty"`
+	Blue          int    `json:"blue,omitempty"`
+	Dimmer        int    `json:"dimmer,omitempty"`
+	WarmWhite     int    `json:"warm_white,omitempty"`
+	ColdWhite     int    `json:"cold_white,omitempty"`
+	Effect        int    `json:"effect,omitempty"` // 0-9 shows the color, higher values pick an effect
+	EffectSpeed   int    `json:"effect_speed,omitempty"`
+}
+
+// NewDMXConfig creates the default DMX configuration
+func NewDMXConfig() *DMXConfig {
+	return &DMXConfig{
+		Enabled:         false,
+		ArtNet:          true,
+		SACN:            true,
+		MaxRate:         DefaultDMXMaxRate,
+		InputTimeoutSec: DefaultDMXInputTimeout,
+		OnInputLoss:     DMXLossHold,
+		Fixtures:        []DMXFixture{},
+		UpdatedAt:       time.Now(),
+	}
+}
+
+// Validate validates the DMX configuration
+func (c *DMXConfig) Validate() error {
+	if c.Enabled && !c.ArtNet && !c.SACN {
+		return fmt.Errorf("enable Art-Net, sACN or both")
+	}
+	if c.MaxRate < 0 || c.MaxRate > MaxDMXMaxRate {
+		return fmt.Errorf("DMX update rate must be between 1 and %d per second", MaxDMXMaxRate)
+	}
+	if c.InputTimeoutSec < 0 {
+		return fmt.Errorf("DMX input timeout must not be negative")
+	}
+	switch c.OnInputLoss {
+	case "", DMXLossHold, DMXLossBlackout:
+	default:
+		return fmt.Errorf("invalid DMX input loss behavior: %s", c.OnInputLoss)
+	}
+
+	seen := make(map[string]bool, len(c.Fixtures))
+	for _, fixture := range c.Fixtures {
+		if err := fixture.Validate(); err != nil {
+			return err
+		}
+		if seen[fixture.DeviceAddress] {
+			return fmt.Errorf("device %s is patched twice", fixture.DeviceAddress)
+		}
+		seen[fixture.DeviceAddress] = true
+	}
+	return nil
+}
+
+// MaxRateOrDefault returns the lamp update rate, falling back to the default
+func (c *DMXConfig) MaxRateOrDefault() int {
+	if c.MaxRate == 0 {
+		return DefaultDMXMaxRate
+	}
+	return c.MaxRate
+}
+
+// InputTimeout returns how long the input may be silent before it counts as lost
+func (c *DMXConfig) InputTimeout() time.Duration {
+	if c.InputTimeoutSec == 0 {
+		return DefaultDMXInputTimeout * time.Second
+	}
+	return time.Duration(c.InputTimeoutSec) * time.Second
+}
+
+// OnInputLossOrDefault returns the input loss behavior, falling back to holding the last look
+func (c *DMXConfig) OnInputLossOrDefault() DMXLossBehavior {
+	if c.OnInputLoss == "" {
+		return DMXLossHold
+	}
+	return c.OnInputLoss
+}
+
+// Universes returns the patched universes in order
+func (c *DMXConfig) Universes() []int {
+	seen := make(map[int]bool)
+	universes := make([]int, 0)
+	for _, fixture := range c.Fixtures {
+		if !seen[fixture.Universe] {
+			seen[fixture.Universe] = true
+			universes = append(universes, fixture.Universe)
+		}
+	}
+	sort.Ints(universes)
+	return universes
+}
+
+// Validate validates the fixture patch
+func (f *DMXFixture) Validate() error {
+	if f.DeviceAddress == "" {
+		return ErrInvalidAddress
+	}
+	if f.Universe < 0 || f.Universe > MaxSACNUniverse {
+		return fmt.Errorf("DMX universe must be between 0 and %d", MaxSACNUniverse)
+	}
+
+	patched := false
+	for _, channel := range f.channels() {
+		if channel < 0 || channel > DMXUniverseSize {
+			return fmt.Errorf("DMX channel must be between 1 and %d", DMXUniverseSize)
+		}
+		patched = patched || channel > 0
+	}
+	if !patched {
+		return fmt.Errorf("device %s has no DMX channel patched", f.DeviceAddress)
+	}
+	return nil
+}
+
+// channels returns all channel assignments of the fixture
+func (f *DMXFixture) channels() []int {
+	return []int{f.Red, f.Green, f.Blue, f.Dimmer, f.WarmWhite, f.ColdWhite, f.Effect, f.EffectSpeed}
+}
+
+// Change returns the look a DMX frame sets on the lamp. The effect channel wins
+// over white, which wins over RGB; white is only used while the RGB channels are
+// dark. The lamp is off while the dimmer or every color channel is at zero.
+func (f *DMXFixture) Change(data []byte) StateChange {
+	value := func(channel int) uint8 {
+		if channel < 1 || channel > len(data) {
+			return 0
+		}
+		return data[channel-1]
+	}
+
+	brightness := uint8(255)
+	if f.Dimmer > 0 {
+		brightness = value(f.Dimmer)
+	}
+
+	rgb := RGB{R: value(f.Red), G: value(f.Green), B: value(f.Blue)}
+	white := WhiteBalance{Warm: value(f.WarmWhite), Cold: value(f.ColdWhite)}
+	colorPatched := f.Red > 0 || f.Green > 0 || f.Blue > 0 || f.WarmWhite > 0 || f.ColdWhite > 0
+
+	var change StateChange
+	switch {
+	case f.Effect > 0 && value(f.Effect) >= dmxEffectThreshold:
+		names := EffectNames()
+		band := int(value(f.Effect)-dmxEffectThreshold) * len(names) / (256 - dmxEffectThreshold)
+		effect := int(EffectMap[names[band]])
+		change.Effect = &effect
+		if f.EffectSpeed > 0 {
+			speed := value(f.EffectSpeed)
+			change.EffectSpeed = &speed
+		}
+	case white != (WhiteBalance{}) && rgb == (RGB{}):
+		change.WhiteBalance = &white
+	case rgb != (RGB{}):
+		change.RGB = &rgb
+	case colorPatched:
+		// Every color channel is at zero
+		brightness = 0
+	}
+
+	on := brightness > 0
+	change.PowerOn = &on
+	if !on {
+		return StateChange{PowerOn: &on}
+	}
+	change.Brightness = &brightness
+	return change
+}
+
+// DMXStatus is the state of the DMX input
+type DMXStatus struct {
+	Running   bool                `json:"running"`
+	Universes []DMXUniverseStatus `json:"universes"`
+	Error     string              `json:"error,omitempty"`
+}
+
+// DMXUniverseStatus is the input state of one patched universe
+type DMXUniverseStatus struct {
+	Universe  int       `json:"universe"`
+	Active    bool      `json:"active"`           // Whether frames arrive
+	Protocol  string    `json:"protocol"`         // Protocol of the last frame, artnet or sacn
+	Source    string    `json:"source,omitempty"` // Sender of the last frame
+	LastFrame time.Time `json:"last_frame"`
+	Frames    uint64    `json:"frames"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDMXFixtureChange(t *testing.T) {
+	on := true
+	off := false
+	full := uint8(255)
+	half := uint8(128)
+	fade := int(EffectMap["fade"])
+	strobe := int(EffectMap["strobe"])
+	slow := uint8(20)
+
+	fixture := DMXFixture{DeviceAddress: "BE:27:EB:00:00:01", Dimmer: 1, Red: 2, Green: 3, Blue: 4, WarmWhite: 5, ColdWhite: 6, Effect: 7, EffectSpeed: 8}
+
+	tests := []struct {
+		name    string
+		fixture DMXFixture
+		data    []byte
+		want    StateChange
+	}{
+		{name: "color", fixture: fixture, data: []byte{128, 255, 0, 64}, want: StateChange{PowerOn: &on, Brightness: &half, RGB: &RGB{R: 255, B: 64}}},
+		{name: "dimmer at zero", fixture: fixture, data: []byte{0, 255}, want: StateChange{PowerOn: &off}},
+		{name: "all channels at zero", fixture: fixture, data: []byte{255}, want: StateChange{PowerOn: &off}},
+		{name: "white while rgb is dark", fixture: fixture, data: []byte{255, 0, 0, 0, 200, 50}, want: StateChange{PowerOn: &on, Brightness: &full, WhiteBalance: &WhiteBalance{Warm: 200, Cold: 50}}},
+		{name: "rgb wins over white", fixture: fixture, data: []byte{255, 1, 0, 0, 200}, want: StateChange{PowerOn: &on, Brightness: &full, RGB: &RGB{R: 1}}},
+		{name: "effect below threshold shows color", fixture: fixture, data: []byte{255, 255, 0, 0, 0, 0, 9}, want: StateChange{PowerOn: &on, Brightness: &full, RGB: &RGB{R: 255}}},
+		{name: "first effect band", fixture: fixture, data: []byte{255, 0, 0, 0, 0, 0, 10, 20}, want: StateChange{PowerOn: &on, Brightness: &full, Effect: &fade, EffectSpeed: &slow}},
+		{name: "last effect band", fixture: fixture, data: []byte{255, 0, 0, 0, 0, 0, 255, 20}, want: StateChange{PowerOn: &on, Brightness: &full, Effect: &strobe, EffectSpeed: &slow}},
+		{name: "no dimmer patched", fixture: DMXFixture{Red: 1, Green: 2, Blue: 3}, data: []byte{0, 0, 255}, want: StateChange{PowerOn: &on, Brightness: &full, RGB: &RGB{B: 255}}},
+		{name: "short frame", fixture: DMXFixture{Red: 510}, data: []byte{255}, want: StateChange{PowerOn: &off}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.fixture.Change(tt.data))
+		})
+	}
+}
+
+func TestDMXConfigValidate(t *testing.T) {
+	config := NewDMXConfig()
+	config.Fixtures = []DMXFixture{{DeviceAddress: "BE:27:EB:00:00:01", Universe: 1, Red: 1}}
+	assert.NoError(t, config.Validate())
+
+	config.Fixtures = append(config.Fixtures, DMXFixture{DeviceAddress: "BE:27:EB:00:00:01", Universe: 2, Red: 1})
+	assert.Error(t, config.Validate(), "device patched twice")
+
+	config.Fixtures = []DMXFixture{{DeviceAddress: "BE:27:EB:00:00:01", Universe: 1}}
+	assert.Error(t, config.Validate(), "no channel patched")
+
+	config.Fixtures = []DMXFixture{{DeviceAddress: "BE:27:EB:00:00:01", Universe: 1, Red: 513}}
+	assert.Error(t, config.Validate(), "channel out of range")
+}
+
+func TestStateChangeSince(t *testing.T) {
+	on := true
+	dim := uint8(50)
+	rainbow := int(EffectMap["rainbow"])
+	slow := uint8(20)
+	fast := uint8(200)
+
+	prev := StateChange{PowerOn: &on, Brightness: &dim, RGB: &RGB{R: 255}}
+	assert.True(t, StateChange{PowerOn: &on, Brightness: &dim, RGB: &RGB{R: 255}}.Since(prev).IsZero())
+	assert.Equal(t, StateChange{RGB: &RGB{G: 255}}, StateChange{PowerOn: &on, Brightness: &dim, RGB: &RGB{G: 255}}.Since(prev))
+
+	prev = StateChange{Effect: &rainbow, EffectSpeed: &slow}
+	assert.Equal(t, StateChange{Effect: &rainbow, EffectSpeed: &fast}, StateChange{Effect: &rainbow, EffectSpeed: &fast}.Since(prev), "speed change resends the effect")
+}
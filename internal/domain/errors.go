@@ -19,4 +19,23 @@ var (
 	// State errors
 	ErrDeviceNotReady    = errors.New("device not ready")
 	ErrInvalidState      = errors.New("invalid device state")
+
+	// Twitch errors
+	ErrUserBanned       = errors.New("user is banned from lamp commands")
+	ErrInsufficientRole = errors.New("insufficient role for command")
+	ErrInsufficientPoints = errors.New("not enough loyalty points")
+
+	// Safety errors
+	ErrFlashRateExceeded = errors.New("lamp is changing too fast")
+	ErrHueChangeTooSoon  = errors.New("color changed too recently")
+	ErrStrobeBlocked     = errors.New("flashing effects are disabled for viewers")
+
+	// Alert errors
+	ErrAlertPlaying = errors.New("a stream alert is playing on the lamp")
+
+	// Arbiter errors
+	ErrLampPaused = errors.New("lamp is paused after a panic")
+
+	// Hue errors
+	ErrHueUserNotFound = errors.New("Hue app is not paired")
 )
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultHuePort is the port Hue apps expect the bridge on
+const DefaultHuePort = 80
+
+// HueLinkWindow is how long pairing stays open after the link button is pressed, like on a real bridge
+const HueLinkWindow = 30 * time.Second
+
+// HueConfig represents the Hue bridge emulation configuration
+type HueConfig struct {
+	Enabled   bool           `json:"enabled"`
+	Port      int            `json:"port,omitempty"` // HTTP port of the bridge (default: 80)
+	Users     []HueUser      `json:"users"`          // Paired apps
+	LightIDs  map[string]int `json:"light_ids"`      // deviceAddr -> light ID, kept so apps find their lights after a restart
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// HueUser is an app paired with the bridge through the link button
+type HueUser struct {
+	Username   string    `json:"username"` // Encrypted in storage
+	DeviceType string    `json:"device_type"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// HueStatus is the state of the Hue bridge emulation
+type HueStatus struct {
+	Running   bool      `json:"running"`
+	Port      int       `json:"port"`
+	Discovery bool      `json:"discovery"`       // Whether SSDP discovery is answered
+	LinkUntil time.Time `json:"link_until"`      // End of the pairing window, zero unless the link button was pressed
+	Error     string    `json:"error,omitempty"` // Why the bridge is not running
+}
+
+// NewHueConfig creates the default Hue configuration
+func NewHueConfig() *HueConfig {
+	return &HueConfig{
+		Enabled:   false,
+		Port:      DefaultHuePort,
+		Users:     []HueUser{},
+		LightIDs:  make(map[string]int),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Validate validates the Hue configuration
+func (c *HueConfig) Validate() error {
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("Hue port must be between 1 and 65535")
+	}
+
+	seen := make(map[string]bool, len(c.Users))
+	for _, user := range c.Users {
+		if user.Username == "" {
+			return fmt.Errorf("Hue user without username")
+		}
+		if seen[user.Username] {
+			return fmt.Errorf("duplicate Hue user")
+		}
+		seen[user.Username] = true
+	}
+
+	ids := make(map[int]bool, len(c.LightIDs))
+	for _, id := range c.LightIDs {
+		if id < 1 || ids[id] {
+			return fmt.Errorf("invalid Hue light ID %d", id)
+		}
+		ids[id] = true
+	}
+	return nil
+}
+
+// Clone returns a copy that shares no users or light IDs with the config
+func (c *HueConfig) Clone() *HueConfig {
+	clone := *c
+	clone.Users = append([]HueUser{}, c.Users...)
+	clone.LightIDs = make(map[string]int, len(c.LightIDs))
+	for addr, id := range c.LightIDs {
+		clone.LightIDs[addr] = id
+	}
+	return &clone
+}
+
+// PortOrDefault returns the bridge port, falling back to the default
+func (c *HueConfig) PortOrDefault() int {
+	if c.Port == 0 {
+		return DefaultHuePort
+	}
+	return c.Port
+}
+
+// HasUser returns whether an app with the username is paired
+func (c *HueConfig) HasUser(username string) bool {
+	for _, user := range c.Users {
+		if user.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+// AssignLightIDs gives every lamp without a light ID the next free one.
+// It returns whether an ID was assigned.
+func (c *HueConfig) AssignLightIDs(addresses []string) bool {
+	if c.LightIDs == nil {
+		c.LightIDs = make(map[string]int)
+	}
+
+	next := 1
+	for _, id := range c.LightIDs {
+		next = max(next, id+1)
+	}
+
+	assigned := false
+	for _, addr := range addresses {
+		if _, exists := c.LightIDs[addr]; !exists {
+			c.LightIDs[addr] = next
+			next++
+			assigned = true
+		}
+	}
+	return assigned
+}
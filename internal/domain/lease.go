@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// LeasePriority orders the sources competing for a lamp, the higher priority owns it
+type LeasePriority int
+
+const (
+	PriorityOverlay    LeasePriority = 5  // Overlay on the base scene, e.g. an evening tint
+	PriorityAutomation LeasePriority = 10 // OBS mappings
+	PriorityLive       LeasePriority = 15 // Streaming inputs, DMX consoles and OpenRGB
+	PriorityViewer     LeasePriority = 20 // Chat commands and poll winners
+	PriorityAlert      LeasePriority = 30 // Stream alerts
+)
+
+// Lease sources. A source holds at most one lease per lamp.
+const (
+	LeaseSourceOverlay = "overlay"
+	LeaseSourceOBS     = "obs"
+	LeaseSourceDMX     = "dmx"
+	LeaseSourceOpenRGB = "openrgb"
+	LeaseSourceViewer  = "viewer"
+	LeaseSourceAlert   = "alert"
+)
+
+// Lease is the claim of a source on a lamp. It is a layer of the lamp
+// state, combined with the layers below by its blend mode and opacity.
+type Lease struct {
+	Source     string
+	Holder     string // Who acquired it, e.g. the viewer or the OBS scene
+	Priority   LeasePriority
+	Change     StateChange // What the source shows on the lamp
+	Blend      BlendMode   // Empty means replace
+	Opacity    float64     // 0 to 1, zero means fully opaque
+	Fade       time.Duration
+	AcquiredAt time.Time
+	ExpiresAt  time.Time // Zero for leases held until released
+}
+
+// Validate validates the change of the lease and how it blends
+func (l *Lease) Validate() error {
+	if l.Change.IsZero() {
+		return fmt.Errorf("%s lease changes nothing", l.Source)
+	}
+	if l.Change.RGB != nil && l.Change.WhiteBalance != nil {
+		return fmt.Errorf("%s lease cannot set both color and white balance", l.Source)
+	}
+	return ValidateBlend(l.Blend, l.Opacity, l.Fade)
+}
+
+// OpacityAt returns the opacity of the lease at the given time.
+// A crossfade ramps up to the opacity over the fade duration.
+func (l *Lease) OpacityAt(now time.Time) float64 {
+	opacity := l.Opacity
+	if opacity <= 0 || opacity > 1 {
+		opacity = 1
+	}
+
+	if l.Fading(now) {
+		opacity *= float64(now.Sub(l.AcquiredAt)) / float64(l.Fade)
+	}
+	return math.Max(opacity, 0)
+}
+
+// Fading checks if the crossfade of the lease is in progress at the given time
+func (l *Lease) Fading(now time.Time) bool {
+	return l.Blend == BlendCrossfade && l.Fade > 0 && now.Before(l.AcquiredAt.Add(l.Fade))
+}
+
+// Expired checks if the lease has run out at the given time
+func (l *Lease) Expired(now time.Time) bool {
+	return !l.ExpiresAt.IsZero() && !now.Before(l.ExpiresAt)
+}
+
+// Remaining returns how long the lease keeps running, zero for leases held until released
+func (l *Lease) Remaining(now time.Time) time.Duration {
+	if l.ExpiresAt.IsZero() || !now.Before(l.ExpiresAt) {
+		return 0
+	}
+	return l.ExpiresAt.Sub(now)
+}
+
+// LeaseStack is the layered state of a lamp: the base state the streamer set,
+// with the change of every lease applied on top in priority order
+type LeaseStack struct {
+	Base   DeviceState
+	Leases []Lease // Lowest priority first, equal priorities in acquisition order
+}
+
+// NewLeaseStack creates a lease stack on top of a state
+func NewLeaseStack(base DeviceState) *LeaseStack {
+	return &LeaseStack{Base: base}
+}
+
+// Put adds a lease, replacing the lease of the same source
+func (s *LeaseStack) Put(lease Lease) {
+	s.Remove(lease.Source)
+	s.Leases = append(s.Leases, lease)
+	sort.SliceStable(s.Leases, func(i, j int) bool {
+		if s.Leases[i].Priority != s.Leases[j].Priority {
+			return s.Leases[i].Priority < s.Leases[j].Priority
+		}
+		return s.Leases[i].AcquiredAt.Before(s.Leases[j].AcquiredAt)
+	})
+}
+
+// Get returns the lease of a source, or nil
+func (s *LeaseStack) Get(source string) *Lease {
+	for i := range s.Leases {
+		if s.Leases[i].Source == source {
+			return &s.Leases[i]
+		}
+	}
+	return nil
+}
+
+// Remove drops the lease of a source. It returns false if the source holds no lease.
+func (s *LeaseStack) Remove(source string) bool {
+	for i := range s.Leases {
+		if s.Leases[i].Source == source {
+			s.Leases = append(s.Leases[:i], s.Leases[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Prune drops the leases expired at the given time and returns them
+func (s *LeaseStack) Prune(now time.Time) []Lease {
+	var expired []Lease
+	kept := s.Leases[:0]
+	for _, lease := range s.Leases {
+		if lease.Expired(now) {
+			expired = append(expired, lease)
+		} else {
+			kept = append(kept, lease)
+		}
+	}
+	s.Leases = kept
+	return expired
+}
+
+// Top returns the lease owning the lamp, or nil if the base state shows
+func (s *LeaseStack) Top() *Lease {
+	if len(s.Leases) == 0 {
+		return nil
+	}
+	return &s.Leases[len(s.Leases)-1]
+}
+
+// Render returns the frame the lamp shows at the given time: the base
+// state with every lease blended on top, lowest priority first
+func (s *LeaseStack) Render(now time.Time) DeviceState {
+	state := s.Base
+	for _, lease := range s.Leases {
+		state = lease.Change.Blend(state, lease.Blend, lease.OpacityAt(now))
+	}
+	return state
+}
+
+// Fading checks if a crossfade is in progress at some point between two times
+func (s *LeaseStack) Fading(from, to time.Time) bool {
+	for _, lease := range s.Leases {
+		if lease.Blend != BlendCrossfade || lease.Fade <= 0 {
+			continue
+		}
+		if lease.AcquiredAt.Before(to) && !lease.AcquiredAt.Add(lease.Fade).Before(from) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextExpiry returns when the next lease runs out, zero if no lease expires
+func (s *LeaseStack) NextExpiry() time.Time {
+	var next time.Time
+	for _, lease := range s.Leases {
+		if !lease.ExpiresAt.IsZero() && (next.IsZero() || lease.ExpiresAt.Before(next)) {
+			next = lease.ExpiresAt
+		}
+	}
+	return next
+}
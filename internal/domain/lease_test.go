@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaseStack(t *testing.T) {
+	now := time.Now()
+	on := true
+	off := false
+	rainbow := int(EffectMap["rainbow"])
+
+	stack := NewLeaseStack(DeviceState{PowerOn: false, Brightness: 255, RGB: &RGB{R: 255, G: 255, B: 255}})
+	assert.Nil(t, stack.Top())
+
+	stack.Put(Lease{Source: LeaseSourceOBS, Priority: PriorityAutomation, Change: StateChange{PowerOn: &on, RGB: &RGB{B: 255}}, AcquiredAt: now})
+	stack.Put(Lease{Source: LeaseSourceAlert, Priority: PriorityAlert, Change: StateChange{Effect: &rainbow}, AcquiredAt: now, ExpiresAt: now.Add(5 * time.Second)})
+	stack.Put(Lease{Source: LeaseSourceViewer, Holder: "alice", Priority: PriorityViewer, Change: StateChange{RGB: &RGB{R: 255}}, AcquiredAt: now.Add(time.Second), ExpiresAt: now.Add(30 * time.Second)})
+
+	assert.Equal(t, LeaseSourceAlert, stack.Top().Source, "highest priority owns the lamp regardless of order")
+	assert.Equal(t, now.Add(5*time.Second), stack.NextExpiry())
+	assert.Equal(t, &rainbow, stack.Render(now).Effect)
+
+	// The alert runs out, the viewer color shows on top of the OBS power on
+	expired := stack.Prune(now.Add(10 * time.Second))
+	if assert.Len(t, expired, 1) {
+		assert.Equal(t, LeaseSourceAlert, expired[0].Source)
+	}
+	state := stack.Render(now)
+	assert.True(t, state.PowerOn)
+	assert.Equal(t, &RGB{R: 255}, state.RGB)
+	assert.Nil(t, state.Effect)
+
+	// A new viewer command replaces the previous one
+	stack.Put(Lease{Source: LeaseSourceViewer, Holder: "bob", Priority: PriorityViewer, Change: StateChange{PowerOn: &off}, AcquiredAt: now.Add(11 * time.Second)})
+	assert.Len(t, stack.Leases, 2)
+	assert.Equal(t, "bob", stack.Top().Holder)
+	assert.False(t, stack.Render(now).PowerOn)
+
+	assert.True(t, stack.Remove(LeaseSourceViewer))
+	assert.False(t, stack.Remove(LeaseSourceViewer))
+	assert.Equal(t, &RGB{B: 255}, stack.Render(now).RGB)
+	assert.True(t, stack.NextExpiry().IsZero(), "OBS lease is held until released")
+}
+
+func TestLeaseRemaining(t *testing.T) {
+	now := time.Now()
+
+	lease := Lease{ExpiresAt: now.Add(10 * time.Second)}
+	assert.Equal(t, 10*time.Second, lease.Remaining(now))
+	assert.False(t, lease.Expired(now))
+	assert.True(t, lease.Expired(now.Add(10*time.Second)))
+	assert.Zero(t, lease.Remaining(now.Add(time.Minute)))
+
+	held := Lease{}
+	assert.False(t, held.Expired(now.Add(time.Hour)))
+	assert.Zero(t, held.Remaining(now))
+}
+
+func TestStateChangeThen(t *testing.T) {
+	on := true
+	effect := int(EffectMap["strobe"])
+	speed := uint8(80)
+	base := NewDeviceState()
+
+	tests := []struct {
+		name  string
+		first StateChange
+		next  StateChange
+	}{
+		{name: "color replaces effect", first: StateChange{Effect: &effect}, next: StateChange{RGB: &RGB{G: 255}}},
+		{name: "effect keeps power", first: StateChange{PowerOn: &on, RGB: &RGB{R: 255}}, next: StateChange{Effect: &effect, EffectSpeed: &speed}},
+		{name: "white balance replaces color", first: StateChange{RGB: &RGB{R: 255}}, next: StateChange{WhiteBalance: &WhiteBalance{Warm: 100}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.next.Apply(tt.first.Apply(base))
+			got := tt.first.Then(tt.next).Apply(base)
+
+			want.LastUpdated, got.LastUpdated = time.Time{}, time.Time{}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestLeaseStackRender(t *testing.T) {
+	now := time.Now()
+	dim := uint8(128)
+	strobe := int(EffectMap["strobe"])
+	base := DeviceState{PowerOn: true, Brightness: 200, RGB: &RGB{R: 255, G: 160, B: 40}}
+
+	tests := []struct {
+		name  string
+		lease Lease
+		at    time.Duration
+		want  DeviceState
+	}{
+		{
+			name:  "replace covers",
+			lease: Lease{Change: StateChange{RGB: &RGB{B: 255}}},
+			want:  DeviceState{PowerOn: true, Brightness: 200, RGB: &RGB{B: 255}},
+		},
+		{
+			name:  "half transparent flash mixes",
+			lease: Lease{Change: StateChange{RGB: &RGB{R: 255, G: 255, B: 255}}, Opacity: 0.5},
+			want:  DeviceState{PowerOn: true, Brightness: 200, RGB: &RGB{R: 255, G: 208, B: 148}},
+		},
+		{
+			name:  "multiply dims",
+			lease: Lease{Change: StateChange{Brightness: &dim}, Blend: BlendMultiply},
+			want:  DeviceState{PowerOn: true, Brightness: 100, RGB: &RGB{R: 255, G: 160, B: 40}},
+		},
+		{
+			name:  "add saturates",
+			lease: Lease{Change: StateChange{RGB: &RGB{R: 100, B: 100}}, Blend: BlendAdd},
+			want:  DeviceState{PowerOn: true, Brightness: 200, RGB: &RGB{R: 255, G: 160, B: 140}},
+		},
+		{
+			name:  "crossfade halfway",
+			lease: Lease{Change: StateChange{RGB: &RGB{}}, Blend: BlendCrossfade, Fade: 2 * time.Second},
+			at:    time.Second,
+			want:  DeviceState{PowerOn: true, Brightness: 200, RGB: &RGB{R: 128, G: 80, B: 20}},
+		},
+		{
+			name:  "effect can't mix below half opacity",
+			lease: Lease{Change: StateChange{Effect: &strobe}, Opacity: 0.3},
+			want:  base,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stack := NewLeaseStack(base)
+			tt.lease.Source = LeaseSourceAlert
+			tt.lease.AcquiredAt = now
+			stack.Put(tt.lease)
+
+			got := stack.Render(now.Add(tt.at))
+			got.LastUpdated, tt.want.LastUpdated = time.Time{}, time.Time{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	on := true
+	from := DeviceState{PowerOn: true, Brightness: 200, RGB: &RGB{R: 255}}
+
+	assert.True(t, Diff(from, from).IsZero())
+	assert.Equal(t, StateChange{RGB: &RGB{G: 255}}, Diff(from, DeviceState{PowerOn: true, Brightness: 200, RGB: &RGB{G: 255}}))
+
+	off := Diff(from, DeviceState{Brightness: 10})
+	assert.Equal(t, StateChange{PowerOn: new(bool)}, off, "nothing else is sent to a lamp turning off")
+
+	wb := &WhiteBalance{Warm: 255}
+	assert.Equal(t, StateChange{PowerOn: &on, WhiteBalance: wb}, Diff(DeviceState{Brightness: 200}, DeviceState{PowerOn: true, Brightness: 200, WhiteBalance: wb}))
+}
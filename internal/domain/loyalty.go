@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PresenceEvent is something a viewer did that shows they are watching
+type PresenceEvent string
+
+const (
+	PresenceJoin    PresenceEvent = "join"    // Viewer entered the chat
+	PresencePart    PresenceEvent = "part"    // Viewer left the chat
+	PresenceMessage PresenceEvent = "message" // Viewer wrote any chat message
+	PresenceClosed  PresenceEvent = "closed"  // The chat connection closed, every viewer left
+)
+
+// ChatPresence reports viewer activity seen by a chat source
+type ChatPresence struct {
+	Platform    ChatPlatform
+	Channel     string // ID of the chat channel
+	Username    string
+	DisplayName string
+	Event       PresenceEvent
+	Timestamp   time.Time
+}
+
+// ChatPresenceHandler is called when a chat source sees viewer activity
+type ChatPresenceHandler func(presence *ChatPresence)
+
+// ViewerKey identifies a viewer across platforms.
+// Twitch viewers keep their plain username so existing records stay valid.
+func ViewerKey(platform ChatPlatform, username string) string {
+	username = strings.ToLower(username)
+	if platform == "" || platform == PlatformTwitch {
+		return username
+	}
+	return string(platform) + ":" + username
+}
+
+// LoyaltyConfig controls how viewers earn loyalty points
+type LoyaltyConfig struct {
+	Enabled     bool          `json:"enabled"`
+	Interval    time.Duration `json:"interval"`     // How often present viewers are paid (default: 5m)
+	WatchPoints int           `json:"watch_points"` // Points per interval for being in chat (default: 10)
+	ChatPoints  int           `json:"chat_points"`  // Bonus per interval for writing in chat (default: 5)
+}
+
+// NewLoyaltyConfig creates the default loyalty configuration
+func NewLoyaltyConfig() LoyaltyConfig {
+	return LoyaltyConfig{
+		Enabled:     false,
+		Interval:    5 * time.Minute,
+		WatchPoints: 10,
+		ChatPoints:  5,
+	}
+}
+
+// Validate validates the loyalty configuration
+func (c *LoyaltyConfig) Validate() error {
+	if c.Interval != 0 && c.Interval < time.Minute {
+		return fmt.Errorf("loyalty interval must be at least 1 minute")
+	}
+	if c.WatchPoints < 0 || c.ChatPoints < 0 {
+		return fmt.Errorf("loyalty points cannot be negative")
+	}
+	return nil
+}
+
+// IntervalOrDefault returns the payout interval, falling back to the default
+func (c *LoyaltyConfig) IntervalOrDefault() time.Duration {
+	if c.Interval <= 0 {
+		return NewLoyaltyConfig().Interval
+	}
+	return c.Interval
+}
+
+// PointsAccount holds the loyalty points of a viewer
+type PointsAccount struct {
+	Viewer      string    `json:"viewer"` // See ViewerKey
+	DisplayName string    `json:"display_name"`
+	Balance     int       `json:"balance"`
+	Earned      int       `json:"earned"` // Points earned by watching and chatting
+	Spent       int       `json:"spent"`  // Points spent on commands, refunds are subtracted
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// LoyaltyLedger holds all points accounts
+type LoyaltyLedger struct {
+	Accounts map[string]*PointsAccount `json:"accounts"` // viewer key -> account
+}
+
+// NewLoyaltyLedger creates an empty ledger
+func NewLoyaltyLedger() *LoyaltyLedger {
+	return &LoyaltyLedger{
+		Accounts: make(map[string]*PointsAccount),
+	}
+}
+
+// Account returns the account of a viewer, opening it if needed
+func (l *LoyaltyLedger) Account(viewer, displayName string) *PointsAccount {
+	account, exists := l.Accounts[viewer]
+	if !exists {
+		account = &PointsAccount{Viewer: viewer, DisplayName: displayName}
+		l.Accounts[viewer] = account
+	}
+	if displayName != "" {
+		account.DisplayName = displayName
+	}
+	return account
+}
+
+// Balance returns the points of a viewer
+func (l *LoyaltyLedger) Balance(viewer string) int {
+	if account, exists := l.Accounts[viewer]; exists {
+		return account.Balance
+	}
+	return 0
+}
+
+// Earn credits points a viewer earned
+func (l *LoyaltyLedger) Earn(viewer, displayName string, amount int) {
+	account := l.Account(viewer, displayName)
+	account.Balance += amount
+	account.Earned += amount
+	account.UpdatedAt = time.Now()
+}
+
+// Spend deducts points, leaving the account untouched if the viewer can't afford them
+func (l *LoyaltyLedger) Spend(viewer string, amount int) error {
+	if l.Balance(viewer) < amount {
+		return ErrInsufficientPoints
+	}
+
+	account := l.Account(viewer, "")
+	account.Balance -= amount
+	account.Spent += amount
+	account.UpdatedAt = time.Now()
+	return nil
+}
+
+// Refund returns spent points, e.g. when a paid command failed
+func (l *LoyaltyLedger) Refund(viewer string, amount int) {
+	account := l.Account(viewer, "")
+	account.Balance += amount
+	account.Spent -= amount
+	account.UpdatedAt = time.Now()
+}
+
+// Adjust changes a balance by an admin, the balance never drops below zero
+func (l *LoyaltyLedger) Adjust(viewer string, delta int) *PointsAccount {
+	account := l.Account(viewer, "")
+	account.Balance += delta
+	if account.Balance < 0 {
+		account.Balance = 0
+	}
+	account.UpdatedAt = time.Now()
+	return account
+}
+
+// Leaderboard returns the accounts with the highest balances, limit <= 0 returns all
+func (l *LoyaltyLedger) Leaderboard(limit int) []PointsAccount {
+	accounts := make([]PointsAccount, 0, len(l.Accounts))
+	for _, account := range l.Accounts {
+		accounts = append(accounts, *account)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].Balance != accounts[j].Balance {
+			return accounts[i].Balance > accounts[j].Balance
+		}
+		return accounts[i].Viewer < accounts[j].Viewer
+	})
+
+	if limit > 0 && len(accounts) > limit {
+		accounts = accounts[:limit]
+	}
+	return accounts
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoyaltyLedger(t *testing.T) {
+	ledger := NewLoyaltyLedger()
+	ledger.Earn("alice", "Alice", 30)
+	ledger.Earn("bob", "Bob", 50)
+
+	// Spending more than the balance leaves the account untouched
+	assert.ErrorIs(t, ledger.Spend("alice", 40), ErrInsufficientPoints)
+	assert.Equal(t, 30, ledger.Balance("alice"))
+
+	assert.NoError(t, ledger.Spend("alice", 20))
+	ledger.Refund("alice", 20)
+	assert.NoError(t, ledger.Spend("alice", 25))
+
+	alice := ledger.Accounts["alice"]
+	assert.Equal(t, 5, alice.Balance)
+	assert.Equal(t, 30, alice.Earned)
+	assert.Equal(t, 25, alice.Spent)
+	assert.Equal(t, "Alice", alice.DisplayName)
+
+	// Admin adjustments never go below zero
+	assert.Equal(t, 0, ledger.Adjust("alice", -100).Balance)
+	assert.Equal(t, 10, ledger.Adjust("carol", 10).Balance)
+
+	leaderboard := ledger.Leaderboard(2)
+	assert.Len(t, leaderboard, 2)
+	assert.Equal(t, "bob", leaderboard[0].Viewer)
+	assert.Equal(t, "carol", leaderboard[1].Viewer)
+}
+
+func TestViewerKey(t *testing.T) {
+	assert.Equal(t, "alice", ViewerKey(PlatformTwitch, "Alice"))
+	assert.Equal(t, "alice", ViewerKey("", "alice"))
+	assert.Equal(t, "youtube:bob", ViewerKey(PlatformYouTube, "Bob"))
+}
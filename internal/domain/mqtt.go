@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MQTT defaults, matching the Home Assistant MQTT integration
+const (
+	DefaultMQTTBroker          = "tcp://localhost:1883"
+	DefaultMQTTClientID        = "lampcontrol"
+	DefaultMQTTBaseTopic       = "lampcontrol"
+	DefaultMQTTDiscoveryPrefix = "homeassistant"
+)
+
+// MQTTConfig represents the MQTT bridge configuration
+type MQTTConfig struct {
+	Enabled         bool      `json:"enabled"`
+	Broker          string    `json:"broker"` // tcp:// or mqtt:// URL (default: tcp://localhost:1883)
+	Username        string    `json:"username,omitempty"`
+	Password        string    `json:"password,omitempty"` // Encrypted in storage
+	ClientID        string    `json:"client_id,omitempty"`
+	BaseTopic       string    `json:"base_topic,omitempty"`       // Prefix of the state, command and availability topics
+	DiscoveryPrefix string    `json:"discovery_prefix,omitempty"` // Home Assistant discovery prefix
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// NewMQTTConfig creates the default MQTT configuration
+func NewMQTTConfig() *MQTTConfig {
+	return &MQTTConfig{
+		Enabled:         false,
+		Broker:          DefaultMQTTBroker,
+		ClientID:        DefaultMQTTClientID,
+		BaseTopic:       DefaultMQTTBaseTopic,
+		DiscoveryPrefix: DefaultMQTTDiscoveryPrefix,
+		UpdatedAt:       time.Now(),
+	}
+}
+
+// Validate validates the MQTT configuration
+func (c *MQTTConfig) Validate() error {
+	if c.Broker != "" {
+		u, err := url.Parse(c.Broker)
+		if err != nil || (u.Scheme != "tcp" && u.Scheme != "mqtt") || u.Host == "" {
+			return fmt.Errorf("MQTT broker must be a tcp:// or mqtt:// URL")
+		}
+	}
+
+	for name, topic := range map[string]string{"base topic": c.BaseTopic, "discovery prefix": c.DiscoveryPrefix} {
+		if strings.ContainsAny(topic, "+#") || strings.HasPrefix(topic, "/") || strings.HasSuffix(topic, "/") {
+			return fmt.Errorf("MQTT %s must be a topic without wildcards or leading and trailing slashes", name)
+		}
+	}
+
+	if c.Password != "" && c.Username == "" {
+		return fmt.Errorf("MQTT password requires a username")
+	}
+	return nil
+}
+
+// BrokerAddress returns the host:port of the broker, falling back to the default
+func (c *MQTTConfig) BrokerAddress() string {
+	broker := c.Broker
+	if broker == "" {
+		broker = DefaultMQTTBroker
+	}
+
+	u, err := url.Parse(broker)
+	if err != nil {
+		return "localhost:1883"
+	}
+	if u.Port() == "" {
+		return u.Host + ":1883"
+	}
+	return u.Host
+}
+
+// ClientIDOrDefault returns the MQTT client ID, falling back to the default
+func (c *MQTTConfig) ClientIDOrDefault() string {
+	if c.ClientID == "" {
+		return DefaultMQTTClientID
+	}
+	return c.ClientID
+}
+
+// BaseTopicOrDefault returns the base topic, falling back to the default
+func (c *MQTTConfig) BaseTopicOrDefault() string {
+	if c.BaseTopic == "" {
+		return DefaultMQTTBaseTopic
+	}
+	return c.BaseTopic
+}
+
+// DiscoveryPrefixOrDefault returns the discovery prefix, falling back to the default
+func (c *MQTTConfig) DiscoveryPrefixOrDefault() string {
+	if c.DiscoveryPrefix == "" {
+		return DefaultMQTTDiscoveryPrefix
+	}
+	return c.DiscoveryPrefix
+}
@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultOBSAddress is where obs-websocket listens unless configured otherwise
+const DefaultOBSAddress = "ws://localhost:4455"
+
+// OBSEventType is an obs-websocket event the lamp can react to
+type OBSEventType string
+
+const (
+	OBSSceneChanged       OBSEventType = "CurrentProgramSceneChanged"
+	OBSStreamStateChanged OBSEventType = "StreamStateChanged"
+	OBSRecordStateChanged OBSEventType = "RecordStateChanged"
+)
+
+// IsValid checks if the event type is supported
+func (t OBSEventType) IsValid() bool {
+	switch t {
+	case OBSSceneChanged, OBSStreamStateChanged, OBSRecordStateChanged:
+		return true
+	}
+	return false
+}
+
+// OBSEvent is an event reported by OBS
+type OBSEvent struct {
+	Type      OBSEventType
+	SceneName string // Program scene for scene changes
+	Active    bool   // Whether the stream or recording started (true) or stopped (false)
+	Timestamp time.Time
+}
+
+// OBSEventHandler is called for every supported OBS event
+type OBSEventHandler func(event *OBSEvent)
+
+// OBSAction describes what the lamp does when a mapping matches.
+// Unset fields are left untouched.
+type OBSAction struct {
+	Power       *bool  `json:"power,omitempty"`
+	Color       *RGB   `json:"color,omitempty"`
+	Brightness  *uint8 `json:"brightness,omitempty"`
+	Effect      *int   `json:"effect,omitempty"`
+	EffectSpeed *uint8 `json:"effect_speed,omitempty"`
+	Restore     bool   `json:"restore,omitempty"` // Return to the state from before the first OBS change
+}
+
+// Change returns the action as a device state change. A color or effect
+// turns the lamp on unless the action sets the power itself.
+func (a OBSAction) Change() StateChange {
+	power := a.Power
+	if power == nil && (a.Color != nil || a.Effect != nil) {
+		on := true
+		power = &on
+	}
+	return StateChange{
+		PowerOn:     power,
+		Brightness:  a.Brightness,
+		RGB:         a.Color,
+		Effect:      a.Effect,
+		EffectSpeed: a.EffectSpeed,
+	}
+}
+
+// OBSMapping maps an OBS event to a lamp action
+type OBSMapping struct {
+	Event         OBSEventType `json:"event"`
+	Scene         string       `json:"scene,omitempty"`          // Scene name for scene changes, empty matches every scene
+	Active        *bool        `json:"active,omitempty"`         // Stream or recording started (true) or stopped (false), nil matches both
+	DeviceAddress string       `json:"device_address,omitempty"` // Lamp to change, empty uses the selected device
+	Action        OBSAction    `json:"action"`
+
+	// Blend, Opacity and Fade combine the action with the lamp state, e.g. a scene tint
+	Blend   BlendMode     `json:"blend,omitempty"`
+	Opacity float64       `json:"opacity,omitempty"` // 0 to 1, zero means fully opaque
+	Fade    time.Duration `json:"fade,omitempty"`
+}
+
+// Validate validates the mapping
+func (m *OBSMapping) Validate() error {
+	if !m.Event.IsValid() {
+		return fmt.Errorf("unsupported OBS event: %s", m.Event)
+	}
+	if m.Scene != "" && m.Event != OBSSceneChanged {
+		return fmt.Errorf("scene can only be set for %s mappings", OBSSceneChanged)
+	}
+	if m.Active != nil && m.Event == OBSSceneChanged {
+		return fmt.Errorf("active can only be set for stream and record mappings")
+	}
+	if err := ValidateBlend(m.Blend, m.Opacity, m.Fade); err != nil {
+		return fmt.Errorf("mapping for %s: %w", m.Event, err)
+	}
+
+	action := m.Action
+	if action.Restore {
+		if action.Power != nil || action.Color != nil || action.Brightness != nil || action.Effect != nil {
+			return fmt.Errorf("restore mappings cannot change the lamp")
+		}
+		return nil
+	}
+	if action.Power == nil && action.Color == nil && action.Brightness == nil && action.Effect == nil {
+		return fmt.Errorf("mapping for %s has no action", m.Event)
+	}
+	if action.Color != nil && action.Effect != nil {
+		return fmt.Errorf("mapping for %s cannot set both color and effect", m.Event)
+	}
+	if action.Effect != nil && (*action.Effect < 0 || *action.Effect > 255) {
+		return ErrInvalidEffect
+	}
+	return nil
+}
+
+// Matches checks if the mapping applies to an event
+func (m *OBSMapping) Matches(event *OBSEvent) bool {
+	if m.Event != event.Type {
+		return false
+	}
+	if m.Scene != "" && !strings.EqualFold(m.Scene, event.SceneName) {
+		return false
+	}
+	if m.Active != nil && *m.Active != event.Active {
+		return false
+	}
+	return true
+}
+
+// OBSConfig represents the OBS WebSocket integration configuration
+type OBSConfig struct {
+	Enabled   bool         `json:"enabled"`
+	Address   string       `json:"address"`  // obs-websocket URL (default: ws://localhost:4455)
+	Password  string       `json:"password"` // obs-websocket password (encrypted in storage)
+	Mappings  []OBSMapping `json:"mappings"` // Checked in order, the first match wins
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// NewOBSConfig creates the default OBS configuration with example mappings:
+// red while recording, a slow pulse on a "BRB" scene and off when the stream ends
+func NewOBSConfig() *OBSConfig {
+	on, off := true, false
+	pulse := int(EffectMap["pulse"])
+	slow := uint8(32)
+
+	return &OBSConfig{
+		Enabled: false,
+		Address: DefaultOBSAddress,
+		Mappings: []OBSMapping{
+			{Event: OBSRecordStateChanged, Active: &on, Action: OBSAction{Color: &RGB{R: 255}}},
+			{Event: OBSRecordStateChanged, Active: &off, Action: OBSAction{Restore: true}},
+			{Event: OBSSceneChanged, Scene: "BRB", Action: OBSAction{Effect: &pulse, EffectSpeed: &slow}},
+			{Event: OBSStreamStateChanged, Active: &off, Action: OBSAction{Power: &off}},
+		},
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Validate validates the OBS configuration
+func (c *OBSConfig) Validate() error {
+	if c.Address != "" {
+		u, err := url.Parse(c.Address)
+		if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+			return fmt.Errorf("OBS address must be a ws:// or wss:// URL")
+		}
+	}
+
+	for i := range c.Mappings {
+		if err := c.Mappings[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddressOrDefault returns the obs-websocket URL, falling back to the default
+func (c *OBSConfig) AddressOrDefault() string {
+	if c.Address == "" {
+		return DefaultOBSAddress
+	}
+	return c.Address
+}
+
+// Match returns the first mapping for an event, or nil if none matches
+func (c *OBSConfig) Match(event *OBSEvent) *OBSMapping {
+	for i := range c.Mappings {
+		if c.Mappings[i].Matches(event) {
+			return &c.Mappings[i]
+		}
+	}
+	return nil
+}
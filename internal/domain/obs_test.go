@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOBSConfigMatch(t *testing.T) {
+	config := NewOBSConfig()
+	assert.NoError(t, config.Validate())
+
+	tests := []struct {
+		name       string
+		event      OBSEvent
+		wantColor  bool
+		wantEffect bool
+		wantPower  bool
+		wantNone   bool
+	}{
+		{name: "recording started", event: OBSEvent{Type: OBSRecordStateChanged, Active: true}, wantColor: true},
+		{name: "scene names ignore case", event: OBSEvent{Type: OBSSceneChanged, SceneName: "brb"}, wantEffect: true},
+		{name: "other scenes", event: OBSEvent{Type: OBSSceneChanged, SceneName: "Gameplay"}, wantNone: true},
+		{name: "stream ended", event: OBSEvent{Type: OBSStreamStateChanged, Active: false}, wantPower: true},
+		{name: "stream started", event: OBSEvent{Type: OBSStreamStateChanged, Active: true}, wantNone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping := config.Match(&tt.event)
+			if tt.wantNone {
+				assert.Nil(t, mapping)
+				return
+			}
+			if assert.NotNil(t, mapping) {
+				assert.Equal(t, tt.wantColor, mapping.Action.Color != nil)
+				assert.Equal(t, tt.wantEffect, mapping.Action.Effect != nil)
+				assert.Equal(t, tt.wantPower, mapping.Action.Power != nil)
+			}
+		})
+	}
+}
+
+func TestOBSMappingValidate(t *testing.T) {
+	on := true
+	effect := 3
+
+	assert.Error(t, (&OBSMapping{Event: "SceneItemEnableStateChanged", Action: OBSAction{Power: &on}}).Validate())
+	assert.Error(t, (&OBSMapping{Event: OBSSceneChanged}).Validate(), "mapping without action")
+	assert.Error(t, (&OBSMapping{Event: OBSSceneChanged, Active: &on, Action: OBSAction{Power: &on}}).Validate())
+	assert.Error(t, (&OBSMapping{Event: OBSRecordStateChanged, Action: OBSAction{Restore: true, Power: &on}}).Validate())
+	assert.Error(t, (&OBSMapping{Event: OBSRecordStateChanged, Action: OBSAction{Color: &RGB{R: 1}, Effect: &effect}}).Validate())
+	assert.NoError(t, (&OBSMapping{Event: OBSRecordStateChanged, Active: &on, Action: OBSAction{Effect: &effect}}).Validate())
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// OpenRGB SDK server defaults
+const (
+	DefaultOpenRGBPort    = 6742
+	DefaultOpenRGBMaxRate = 10 // Lamp updates per second, effect plugins send far more
+	MaxOpenRGBMaxRate     = 30
+)
+
+// OpenRGBConfig represents the OpenRGB SDK server configuration
+type OpenRGBConfig struct {
+	Enabled   bool      `json:"enabled"`
+	Port      int       `json:"port,omitempty"`     // TCP port of the SDK server (default: 6742)
+	MaxRate   int       `json:"max_rate,omitempty"` // Lamp updates per second (default: 10)
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OpenRGBStatus is the state of the OpenRGB SDK server
+type OpenRGBStatus struct {
+	Running bool     `json:"running"`
+	Port    int      `json:"port"`
+	Clients []string `json:"clients"`         // Names the connected clients gave themselves
+	Error   string   `json:"error,omitempty"` // Why the server is not running
+}
+
+// NewOpenRGBConfig creates the default OpenRGB configuration
+func NewOpenRGBConfig() *OpenRGBConfig {
+	return &OpenRGBConfig{
+		Enabled:   false,
+		Port:      DefaultOpenRGBPort,
+		MaxRate:   DefaultOpenRGBMaxRate,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Validate validates the OpenRGB configuration
+func (c *OpenRGBConfig) Validate() error {
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("OpenRGB port must be between 1 and 65535")
+	}
+	if c.MaxRate < 0 || c.MaxRate > MaxOpenRGBMaxRate {
+		return fmt.Errorf("OpenRGB update rate must be between 1 and %d per second", MaxOpenRGBMaxRate)
+	}
+	return nil
+}
+
+// PortOrDefault returns the SDK server port, falling back to the default
+func (c *OpenRGBConfig) PortOrDefault() int {
+	if c.Port == 0 {
+		return DefaultOpenRGBPort
+	}
+	return c.Port
+}
+
+// MaxRateOrDefault returns the lamp update rate, falling back to the default
+func (c *OpenRGBConfig) MaxRateOrDefault() int {
+	if c.MaxRate == 0 {
+		return DefaultOpenRGBMaxRate
+	}
+	return c.MaxRate
+}
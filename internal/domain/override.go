@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// OverrideState tracks whether the streamer has locked out viewer control
+type OverrideState struct {
+	Locked      bool      `json:"locked"`
+	LockedBy    string    `json:"locked_by,omitempty"`
+	LockedAt    time.Time `json:"locked_at"`
+	LastPanicAt time.Time `json:"last_panic_at"`
+}
+
+// DefaultSafeScene returns a calm warm white scene used by the panic action
+func DefaultSafeScene() DeviceState {
+	return DeviceState{
+		PowerOn:      true,
+		Brightness:   128,
+		WhiteBalance: &WhiteBalance{Warm: 255, Cold: 0},
+	}
+}
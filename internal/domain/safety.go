@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Safety defaults based on WCAG 2.3.1 (Three Flashes or Below Threshold)
+const (
+	WCAGMaxFlashesPerSecond       = 3  // Most luminance flashes per second considered safe
+	MaxFlashesPerSecond           = 10 // Upper bound for the configurable flash limit
+	SafeEffectSpeed         uint8 = 64 // Fastest speed flashing effects run at in epilepsy-safe mode
+	FlashWindow                   = time.Second
+)
+
+// flashLuminanceDelta is the relative luminance change WCAG counts as a flash
+const flashLuminanceDelta = 0.1
+
+// hueChangeDegrees is the smallest hue shift that counts as a hue change
+const hueChangeDegrees = 30
+
+// FlashingEffects are the built-in effects that switch abruptly between colors:
+// Seven Color Jump, the two-color jumps, the strobes, Random Colors and the chat strobe
+var FlashingEffects = map[int]bool{
+	0: true, 8: true, 9: true, 10: true,
+	11: true, 12: true, 13: true, 14: true, 15: true, 16: true, 17: true, 18: true,
+	20: true, 0x26: true,
+}
+
+// IsFlashingEffect checks if a built-in effect flashes
+func IsFlashingEffect(effect int) bool {
+	return FlashingEffects[effect]
+}
+
+// SafetyConfig limits how fast the lamp may change to protect photosensitive viewers
+type SafetyConfig struct {
+	MaxFlashesPerSecond  int           `json:"max_flashes_per_second"`  // Max luminance flashes per second from viewer commands (default: 3)
+	MinHueChangeInterval time.Duration `json:"min_hue_change_interval"` // Min time between two hue changes from viewer commands
+	BlockViewerStrobe    bool          `json:"block_viewer_strobe"`     // Viewers can't start flashing effects
+	EpilepsySafe         bool          `json:"epilepsy_safe"`           // Clamps every source, including the streamer, to WCAG-safe rates
+}
+
+// NewSafetyConfig creates the default safety configuration
+func NewSafetyConfig() SafetyConfig {
+	return SafetyConfig{
+		MaxFlashesPerSecond:  WCAGMaxFlashesPerSecond,
+		MinHueChangeInterval: time.Second,
+		BlockViewerStrobe:    false,
+		EpilepsySafe:         false,
+	}
+}
+
+// Validate validates the safety configuration
+func (c *SafetyConfig) Validate() error {
+	if c.MaxFlashesPerSecond < 0 || c.MaxFlashesPerSecond > MaxFlashesPerSecond {
+		return fmt.Errorf("max flashes per second must be between 0 (default) and %d", MaxFlashesPerSecond)
+	}
+	if c.MinHueChangeInterval < 0 {
+		return fmt.Errorf("min hue change interval cannot be negative")
+	}
+	if c.MinHueChangeInterval > time.Minute {
+		return fmt.Errorf("min hue change interval must be at most 1 minute")
+	}
+	return nil
+}
+
+// MaxFlashesOrDefault returns the viewer flash limit, falling back to the WCAG limit
+func (c *SafetyConfig) MaxFlashesOrDefault() int {
+	if c.MaxFlashesPerSecond <= 0 {
+		return WCAGMaxFlashesPerSecond
+	}
+	return c.MaxFlashesPerSecond
+}
+
+// SafetyLimits are the limits a single lamp command has to stay within
+type SafetyLimits struct {
+	MaxFlashesPerSecond  int           // 0 disables the flash limit
+	MinHueChangeInterval time.Duration // 0 disables the hue limit
+	BlockFlashingEffects bool
+	MaxEffectSpeed       uint8 // Speed flashing effects are clamped to
+}
+
+// Unlimited reports whether the limits allow everything
+func (l SafetyLimits) Unlimited() bool {
+	return l.MaxFlashesPerSecond == 0 && l.MinHueChangeInterval == 0 &&
+		!l.BlockFlashingEffects && l.MaxEffectSpeed == math.MaxUint8
+}
+
+// LimitsFor returns the limits for viewer commands or for the streamer's own changes.
+// The streamer is only limited in epilepsy-safe mode.
+func (c *SafetyConfig) LimitsFor(viewer bool) SafetyLimits {
+	limits := SafetyLimits{MaxEffectSpeed: math.MaxUint8}
+
+	if viewer {
+		limits.MaxFlashesPerSecond = c.MaxFlashesOrDefault()
+		limits.MinHueChangeInterval = c.MinHueChangeInterval
+		limits.BlockFlashingEffects = c.BlockViewerStrobe
+	}
+
+	if c.EpilepsySafe {
+		if limits.MaxFlashesPerSecond == 0 || limits.MaxFlashesPerSecond > WCAGMaxFlashesPerSecond {
+			limits.MaxFlashesPerSecond = WCAGMaxFlashesPerSecond
+		}
+		limits.MaxEffectSpeed = SafeEffectSpeed
+	}
+
+	return limits
+}
+
+// FlashGuard tracks the recent light changes of one lamp
+type FlashGuard struct {
+	flashes       []time.Time
+	lastHueChange time.Time
+}
+
+// Admit checks a change against the limits and records it.
+// It returns the change to send, with the effect speed clamped if needed,
+// or an error if the change would exceed the limits.
+func (g *FlashGuard) Admit(current DeviceState, change StateChange, limits SafetyLimits, now time.Time) (StateChange, error) {
+	if change.Effect != nil && IsFlashingEffect(*change.Effect) {
+		if limits.BlockFlashingEffects {
+			return change, ErrStrobeBlocked
+		}
+		if change.EffectSpeed != nil && *change.EffectSpeed > limits.MaxEffectSpeed {
+			speed := limits.MaxEffectSpeed
+			change.EffectSpeed = &speed
+		}
+	}
+
+	next := change.Apply(current)
+	flash := IsFlash(current, next, change)
+	hueChange := IsHueChange(current, next)
+
+	g.prune(now)
+
+	if flash && limits.MaxFlashesPerSecond > 0 && len(g.flashes) >= limits.MaxFlashesPerSecond {
+		return change, ErrFlashRateExceeded
+	}
+	if hueChange && limits.MinHueChangeInterval > 0 && now.Sub(g.lastHueChange) < limits.MinHueChangeInterval {
+		return change, ErrHueChangeTooSoon
+	}
+
+	if flash {
+		g.flashes = append(g.flashes, now)
+	}
+	if hueChange {
+		g.lastHueChange = now
+	}
+	return change, nil
+}
+
+// prune drops flashes that left the flash window
+func (g *FlashGuard) prune(now time.Time) {
+	kept := g.flashes[:0]
+	for _, at := range g.flashes {
+		if now.Sub(at) < FlashWindow {
+			kept = append(kept, at)
+		}
+	}
+	g.flashes = kept
+}
+
+// IsFlash reports whether going from one state to the next is a flash:
+// a luminance change of at least 10% or the start of another effect
+func IsFlash(from, to DeviceState, change StateChange) bool {
+	if change.Effect != nil && to.PowerOn {
+		return true
+	}
+	return math.Abs(Luminance(to)-Luminance(from)) >= flashLuminanceDelta
+}
+
+// IsHueChange reports whether the color of the light shifts noticeably
+func IsHueChange(from, to DeviceState) bool {
+	fromHue, ok := hue(from)
+	if !ok {
+		return false
+	}
+	toHue, ok := hue(to)
+	if !ok {
+		return false
+	}
+
+	diff := math.Abs(fromHue - toHue)
+	if diff > 180 {
+		diff = 360 - diff
+	}
+	return diff >= hueChangeDegrees
+}
+
+// Luminance returns the approximate relative luminance (0-1) a lamp puts out
+func Luminance(state DeviceState) float64 {
+	if !state.PowerOn {
+		return 0
+	}
+
+	level := float64(state.Brightness) / 255
+	switch {
+	case state.RGB != nil:
+		return level * (0.2126*linear(state.RGB.R) + 0.7152*linear(state.RGB.G) + 0.0722*linear(state.RGB.B))
+	case state.WhiteBalance != nil:
+		return level * (float64(state.WhiteBalance.Warm) + float64(state.WhiteBalance.Cold)) / 510
+	default:
+		return level // Effects cycle through colors, assume full output
+	}
+}
+
+// linear converts an sRGB channel to linear light
+func linear(channel uint8) float64 {
+	c := float64(channel) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// hue returns the hue of a lit RGB state in degrees, ok is false for white or unlit states
+func hue(state DeviceState) (float64, bool) {
+	if !state.PowerOn || state.RGB == nil {
+		return 0, false
+	}
+
+	r := float64(state.RGB.R) / 255
+	g := float64(state.RGB.G) / 255
+	b := float64(state.RGB.B) / 255
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	delta := max - min
+	if max == 0 || delta/max < 0.2 {
+		return 0, false // Too unsaturated to have a visible hue
+	}
+
+	var h float64
+	switch max {
+	case r:
+		h = math.Mod((g-b)/delta, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, true
+}
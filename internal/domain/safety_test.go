@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlashGuardLimitsFlashRate(t *testing.T) {
+	config := NewSafetyConfig()
+	config.MinHueChangeInterval = 0
+	limits := config.LimitsFor(true)
+
+	guard := &FlashGuard{}
+	state := NewDeviceState()
+	state.PowerOn = true
+	now := time.Now()
+
+	// Power toggles are full luminance flashes
+	for i := 0; i < WCAGMaxFlashesPerSecond; i++ {
+		on := !state.PowerOn
+		change := StateChange{PowerOn: &on}
+		_, err := guard.Admit(state, change, limits, now)
+		require.NoError(t, err)
+		state = change.Apply(state)
+	}
+
+	off := !state.PowerOn
+	_, err := guard.Admit(state, StateChange{PowerOn: &off}, limits, now.Add(500*time.Millisecond))
+	assert.ErrorIs(t, err, ErrFlashRateExceeded)
+
+	// Small brightness steps are no flashes
+	level := state.Brightness - 10
+	_, err = guard.Admit(state, StateChange{Brightness: &level}, limits, now.Add(500*time.Millisecond))
+	assert.NoError(t, err)
+
+	// The window moves on
+	_, err = guard.Admit(state, StateChange{PowerOn: &off}, limits, now.Add(FlashWindow))
+	assert.NoError(t, err)
+}
+
+func TestFlashGuardLimitsHueChanges(t *testing.T) {
+	limits := SafetyLimits{MinHueChangeInterval: time.Second, MaxEffectSpeed: 255}
+	guard := &FlashGuard{}
+	state := NewDeviceState()
+	state.PowerOn = true
+	state.RGB = &RGB{R: 255}
+	now := time.Now()
+
+	blue := RGB{B: 255}
+	_, err := guard.Admit(state, StateChange{RGB: &blue}, limits, now)
+	require.NoError(t, err)
+	state = StateChange{RGB: &blue}.Apply(state)
+
+	green := RGB{G: 255}
+	_, err = guard.Admit(state, StateChange{RGB: &green}, limits, now.Add(200*time.Millisecond))
+	assert.ErrorIs(t, err, ErrHueChangeTooSoon)
+
+	// A similar shade keeps the hue
+	navy := RGB{R: 20, B: 200}
+	_, err = guard.Admit(state, StateChange{RGB: &navy}, limits, now.Add(200*time.Millisecond))
+	assert.NoError(t, err)
+
+	_, err = guard.Admit(state, StateChange{RGB: &green}, limits, now.Add(time.Second))
+	assert.NoError(t, err)
+}
+
+func TestFlashGuardEffects(t *testing.T) {
+	strobe := EffectMap["strobe"]
+	fade := EffectMap["fade"]
+	fast := uint8(255)
+
+	tests := []struct {
+		name      string
+		config    SafetyConfig
+		viewer    bool
+		effect    uint8
+		wantErr   error
+		wantSpeed uint8
+	}{
+		{
+			name:      "streamer runs strobes at any speed",
+			config:    NewSafetyConfig(),
+			effect:    strobe,
+			wantSpeed: fast,
+		},
+		{
+			name:    "viewers can be blocked from strobes",
+			config:  SafetyConfig{BlockViewerStrobe: true},
+			viewer:  true,
+			effect:  strobe,
+			wantErr: ErrStrobeBlocked,
+		},
+		{
+			name:      "blocking strobes keeps fades",
+			config:    SafetyConfig{BlockViewerStrobe: true},
+			viewer:    true,
+			effect:    fade,
+			wantSpeed: fast,
+		},
+		{
+			name:      "epilepsy-safe mode slows the streamer's strobes",
+			config:    SafetyConfig{EpilepsySafe: true},
+			effect:    strobe,
+			wantSpeed: SafeEffectSpeed,
+		},
+		{
+			name:      "epilepsy-safe mode keeps fades",
+			config:    SafetyConfig{EpilepsySafe: true},
+			effect:    fade,
+			wantSpeed: fast,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := NewDeviceState()
+			state.PowerOn = true
+			effect := int(tt.effect)
+			speed := fast
+
+			change, err := (&FlashGuard{}).Admit(state, StateChange{Effect: &effect, EffectSpeed: &speed}, tt.config.LimitsFor(tt.viewer), time.Now())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSpeed, *change.EffectSpeed)
+		})
+	}
+}
+
+func TestSafetyLimitsFor(t *testing.T) {
+	config := SafetyConfig{MaxFlashesPerSecond: 8}
+	assert.True(t, config.LimitsFor(false).Unlimited())
+	assert.Equal(t, 8, config.LimitsFor(true).MaxFlashesPerSecond)
+
+	// Epilepsy-safe mode caps everyone at the WCAG limit
+	config.EpilepsySafe = true
+	assert.Equal(t, WCAGMaxFlashesPerSecond, config.LimitsFor(false).MaxFlashesPerSecond)
+	assert.Equal(t, WCAGMaxFlashesPerSecond, config.LimitsFor(true).MaxFlashesPerSecond)
+}
@@ -0,0 +1,138 @@
+package domain
+
+import "time"
+
+// StateChange describes a partial update to a device state.
+// Nil fields are left untouched.
+type StateChange struct {
+	PowerOn      *bool
+	Brightness   *uint8
+	RGB          *RGB
+	WhiteBalance *WhiteBalance
+	Effect       *int
+	EffectSpeed  *uint8
+}
+
+// Apply returns the state with the change applied.
+// Color modes are exclusive: RGB, white balance and effects clear each other.
+func (c StateChange) Apply(state DeviceState) DeviceState {
+	if c.PowerOn != nil {
+		state.PowerOn = *c.PowerOn
+	}
+
+	if c.Brightness != nil {
+		state.Brightness = *c.Brightness
+	}
+
+	if c.RGB != nil {
+		rgb := *c.RGB
+		state.RGB = &rgb
+		state.WhiteBalance = nil
+		state.Effect = nil
+	}
+
+	if c.WhiteBalance != nil {
+		wb := *c.WhiteBalance
+		state.WhiteBalance = &wb
+		state.RGB = nil
+		state.Effect = nil
+	}
+
+	if c.Effect != nil {
+		effect := *c.Effect
+		state.Effect = &effect
+		state.RGB = nil
+		state.WhiteBalance = nil
+		if c.EffectSpeed != nil {
+			speed := *c.EffectSpeed
+			state.EffectSpeed = &speed
+		}
+	}
+
+	state.LastUpdated = time.Now()
+	return state
+}
+
+// Then returns a change that has the effect of applying c followed by next
+func (c StateChange) Then(next StateChange) StateChange {
+	if next.PowerOn != nil {
+		c.PowerOn = next.PowerOn
+	}
+
+	if next.Brightness != nil {
+		c.Brightness = next.Brightness
+	}
+
+	if next.RGB != nil {
+		c.RGB = next.RGB
+		c.WhiteBalance = nil
+		c.Effect = nil
+	}
+
+	if next.WhiteBalance != nil {
+		c.WhiteBalance = next.WhiteBalance
+		c.RGB = nil
+		c.Effect = nil
+	}
+
+	if next.Effect != nil {
+		c.Effect = next.Effect
+		c.RGB = nil
+		c.WhiteBalance = nil
+		if next.EffectSpeed != nil {
+			c.EffectSpeed = next.EffectSpeed
+		}
+	}
+
+	return c
+}
+
+// Since returns the part of c that differs from prev, the last change sent to
+// a lamp. An effect is sent again with its speed if either changed.
+func (c StateChange) Since(prev StateChange) StateChange {
+	var delta StateChange
+
+	if c.PowerOn != nil && (prev.PowerOn == nil || *prev.PowerOn != *c.PowerOn) {
+		delta.PowerOn = c.PowerOn
+	}
+
+	if c.Brightness != nil && (prev.Brightness == nil || *prev.Brightness != *c.Brightness) {
+		delta.Brightness = c.Brightness
+	}
+
+	if c.RGB != nil && (prev.RGB == nil || *prev.RGB != *c.RGB) {
+		delta.RGB = c.RGB
+	}
+
+	if c.WhiteBalance != nil && (prev.WhiteBalance == nil || *prev.WhiteBalance != *c.WhiteBalance) {
+		delta.WhiteBalance = c.WhiteBalance
+	}
+
+	if c.Effect != nil {
+		speedChanged := c.EffectSpeed != nil && (prev.EffectSpeed == nil || *prev.EffectSpeed != *c.EffectSpeed)
+		if prev.Effect == nil || *prev.Effect != *c.Effect || speedChanged {
+			delta.Effect = c.Effect
+			delta.EffectSpeed = c.EffectSpeed
+		}
+	}
+
+	return delta
+}
+
+// IsZero checks if the change leaves every field untouched
+func (c StateChange) IsZero() bool {
+	return c.PowerOn == nil && c.Brightness == nil && c.RGB == nil &&
+		c.WhiteBalance == nil && c.Effect == nil && c.EffectSpeed == nil
+}
+
+// ChangeTo returns the change that turns any state into the given one
+func ChangeTo(state DeviceState) StateChange {
+	return StateChange{
+		PowerOn:      &state.PowerOn,
+		Brightness:   &state.Brightness,
+		RGB:          state.RGB,
+		WhiteBalance: state.WhiteBalance,
+		Effect:       state.Effect,
+		EffectSpeed:  state.EffectSpeed,
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateChangeColorModesAreExclusive(t *testing.T) {
+	effect := 3
+	speed := uint8(200)
+
+	tests := []struct {
+		name   string
+		change StateChange
+		want   DeviceState
+	}{
+		{
+			name:   "rgb clears white balance and effect",
+			change: StateChange{RGB: &RGB{R: 255}},
+			want:   DeviceState{RGB: &RGB{R: 255}},
+		},
+		{
+			name:   "white balance clears rgb and effect",
+			change: StateChange{WhiteBalance: &WhiteBalance{Warm: 255}},
+			want:   DeviceState{WhiteBalance: &WhiteBalance{Warm: 255}},
+		},
+		{
+			name:   "effect clears rgb and white balance",
+			change: StateChange{Effect: &effect, EffectSpeed: &speed},
+			want:   DeviceState{Effect: &effect, EffectSpeed: &speed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			otherEffect := 1
+			state := DeviceState{RGB: &RGB{B: 255}, WhiteBalance: &WhiteBalance{Cold: 255}, Effect: &otherEffect}
+
+			got := tt.change.Apply(state)
+			assert.Equal(t, tt.want.RGB, got.RGB)
+			assert.Equal(t, tt.want.WhiteBalance, got.WhiteBalance)
+			assert.Equal(t, tt.want.Effect, got.Effect)
+
+			// Chaining onto a change of another mode gives the same result
+			chained := StateChange{RGB: &RGB{B: 255}, WhiteBalance: &WhiteBalance{Cold: 255}, Effect: &otherEffect}.Then(tt.change)
+			assert.Equal(t, tt.want.RGB, chained.RGB)
+			assert.Equal(t, tt.want.WhiteBalance, chained.WhiteBalance)
+			assert.Equal(t, tt.want.Effect, chained.Effect)
+		})
+	}
+}
@@ -2,26 +2,63 @@ package domain
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
 
-// TwitchCommand represents a parsed Twitch chat command
-type TwitchCommand struct {
-	Username    string
-	DisplayName string
-	Command     string // "red", "rainbow", etc.
-	IsVIP       bool
-	IsSub       bool
-	IsMod       bool
-	Timestamp   time.Time
+// ChatCommand represents a parsed chat command from any chat platform
+type ChatCommand struct {
+	Platform      ChatPlatform
+	Channel       string // ID of the chat channel the command arrived in
+	Username      string
+	DisplayName   string
+	UserID        string
+	ChannelID     string // Platform ID of the channel, used for Twitch follower lookups
+	Command       string // "red", "rainbow", etc.
+	IsVIP         bool
+	IsSub         bool
+	IsMod         bool
+	IsFounder     bool
+	IsBroadcaster bool
+	IsFollower    bool // Only resolved when a permission rule requires it
+	SubTier       int  // 1-3 for subscribers, 0 otherwise
+	Timestamp     time.Time
 }
 
 // UserBadges represents user privileges
 type UserBadges struct {
-	IsVIP bool
-	IsSub bool
-	IsMod bool
+	IsVIP         bool
+	IsSub         bool
+	IsMod         bool
+	IsFounder     bool
+	IsBroadcaster bool
+	SubTier       int // 1-3 for subscribers, 0 otherwise
+}
+
+// DropChannelPrivileges removes the broadcaster and moderator badges.
+// They are granted by the chat's owner and only count in the streamer's own chat.
+func (c *ChatCommand) DropChannelPrivileges() {
+	c.IsBroadcaster = false
+	c.IsMod = false
+}
+
+// Role returns the highest role the command sender holds
+func (c *ChatCommand) Role() Role {
+	switch {
+	case c.IsBroadcaster:
+		return RoleBroadcaster
+	case c.IsMod:
+		return RoleModerator
+	case c.IsVIP:
+		return RoleVIP
+	case c.IsSub || c.IsFounder:
+		return RoleSubscriber
+	case c.IsFollower:
+		return RoleFollower
+	default:
+		return RoleEveryone
+	}
 }
 
 // ColorMap maps color names to RGB values
@@ -47,6 +84,22 @@ var EffectMap = map[string]uint8{
 	"pulse":   0x28,
 }
 
+// EffectNames returns the effect names in a stable order
+func EffectNames() []string {
+	names := make([]string, 0, len(EffectMap))
+	for name := range EffectMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PowerMap maps power command names to power states
+var PowerMap = map[string]bool{
+	"on":  true,
+	"off": false,
+}
+
 // ParseTwitchCommand parses a chat message like "!lamp red"
 func ParseTwitchCommand(message string) (string, error) {
 	message = strings.TrimSpace(strings.ToLower(message))
@@ -77,6 +130,12 @@ func IsEffect(command string) bool {
 	return exists
 }
 
+// IsPower checks if command is a power command
+func IsPower(command string) bool {
+	_, exists := PowerMap[strings.ToLower(command)]
+	return exists
+}
+
 // GetRGB returns RGB values for a color command
 func GetRGB(command string) (RGB, error) {
 	rgb, exists := ColorMap[strings.ToLower(command)]
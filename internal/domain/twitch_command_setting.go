@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultEffectSpeed is the speed effect commands run at unless configured otherwise
+const DefaultEffectSpeed uint8 = 128
+
+// CommandSetting overrides how a single chat command runs.
+// Zero values fall back to the global settings of the config.
+type CommandSetting struct {
+	Command  string        `json:"command"`         // Command name, e.g. "rainbow" or "red"
+	Duration time.Duration `json:"duration"`        // How long the effect lasts (default: EffectDuration)
+	Speed    *uint8        `json:"speed,omitempty"` // Effect speed (default: 128)
+	Cooldown time.Duration `json:"cooldown"`        // Cooldown between two runs of this command by anyone
+	Cost     int           `json:"cost"`            // Loyalty points a viewer pays per run
+
+	// Blend, Opacity and Fade combine the effect with the lamp state, e.g. a half transparent tint
+	Blend   BlendMode     `json:"blend,omitempty"`
+	Opacity float64       `json:"opacity,omitempty"` // 0 to 1, zero means fully opaque
+	Fade    time.Duration `json:"fade,omitempty"`
+}
+
+// Validate validates the command setting
+func (s *CommandSetting) Validate() error {
+	if strings.TrimSpace(s.Command) == "" {
+		return fmt.Errorf("command setting command is required")
+	}
+	if s.Duration != 0 && s.Duration < 5*time.Second {
+		return fmt.Errorf("duration of command %s must be at least 5 seconds", s.Command)
+	}
+	if s.Cooldown < 0 {
+		return fmt.Errorf("cooldown of command %s cannot be negative", s.Command)
+	}
+	if s.Cost < 0 {
+		return fmt.Errorf("cost of command %s cannot be negative", s.Command)
+	}
+	if err := ValidateBlend(s.Blend, s.Opacity, s.Fade); err != nil {
+		return fmt.Errorf("command %s: %w", s.Command, err)
+	}
+	return nil
+}
+
+// GetCommandSetting returns the setting for a command, or nil if the command uses the global settings
+func (c *TwitchConfig) GetCommandSetting(command string) *CommandSetting {
+	command = strings.ToLower(command)
+	for i := range c.CommandSettings {
+		if strings.ToLower(c.CommandSettings[i].Command) == command {
+			return &c.CommandSettings[i]
+		}
+	}
+	return nil
+}
+
+// SetCommandSetting adds or replaces the setting for a command
+func (c *TwitchConfig) SetCommandSetting(setting CommandSetting) {
+	setting.Command = strings.ToLower(setting.Command)
+	if existing := c.GetCommandSetting(setting.Command); existing != nil {
+		*existing = setting
+		return
+	}
+	c.CommandSettings = append(c.CommandSettings, setting)
+}
+
+// RemoveCommandSetting removes the setting for a command, returning false if there was none
+func (c *TwitchConfig) RemoveCommandSetting(command string) bool {
+	command = strings.ToLower(command)
+	for i := range c.CommandSettings {
+		if strings.ToLower(c.CommandSettings[i].Command) == command {
+			c.CommandSettings = append(c.CommandSettings[:i], c.CommandSettings[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveCommand returns the effective setting of a command with all defaults filled in
+func (c *TwitchConfig) ResolveCommand(command string) CommandSetting {
+	resolved := CommandSetting{Command: strings.ToLower(command)}
+	if setting := c.GetCommandSetting(command); setting != nil {
+		resolved = *setting
+	}
+
+	if resolved.Duration == 0 {
+		resolved.Duration = c.EffectDuration
+	}
+	if resolved.Speed == nil {
+		speed := DefaultEffectSpeed
+		resolved.Speed = &speed
+	}
+
+	return resolved
+}
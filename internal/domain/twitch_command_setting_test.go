@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveCommand(t *testing.T) {
+	config := NewTwitchConfig()
+	speed := uint8(40)
+	config.SetCommandSetting(CommandSetting{Command: "Strobe", Duration: 10 * time.Second, Speed: &speed, Cooldown: time.Minute, Cost: 50})
+
+	strobe := config.ResolveCommand("strobe")
+	assert.Equal(t, 10*time.Second, strobe.Duration)
+	assert.Equal(t, uint8(40), *strobe.Speed)
+	assert.Equal(t, time.Minute, strobe.Cooldown)
+	assert.Equal(t, 50, strobe.Cost)
+
+	// Commands without a setting use the global values
+	red := config.ResolveCommand("red")
+	assert.Equal(t, config.EffectDuration, red.Duration)
+	assert.Equal(t, DefaultEffectSpeed, *red.Speed)
+	assert.Equal(t, time.Duration(0), red.Cooldown)
+	assert.Equal(t, 0, red.Cost)
+
+	assert.True(t, config.RemoveCommandSetting("STROBE"))
+	assert.False(t, config.RemoveCommandSetting("strobe"))
+}
+
+func TestCommandSettingValidate(t *testing.T) {
+	assert.NoError(t, (&CommandSetting{Command: "red"}).Validate())
+	assert.Error(t, (&CommandSetting{}).Validate())
+	assert.Error(t, (&CommandSetting{Command: "red", Duration: time.Second}).Validate())
+	assert.Error(t, (&CommandSetting{Command: "red", Cooldown: -time.Second}).Validate())
+	assert.Error(t, (&CommandSetting{Command: "red", Cost: -1}).Validate())
+	assert.NoError(t, (&CommandSetting{Command: "red", Blend: BlendMultiply, Opacity: 0.5}).Validate())
+	assert.Error(t, (&CommandSetting{Command: "red", Blend: "screen"}).Validate())
+	assert.Error(t, (&CommandSetting{Command: "red", Opacity: 2}).Validate())
+}
+
+func TestCommandCooldown(t *testing.T) {
+	state := NewCooldownState()
+	state.RecordCommand("alice", "strobe")
+
+	ok, remaining := state.CheckCommandCooldown("strobe", time.Minute)
+	assert.False(t, ok)
+	assert.Greater(t, remaining, 59*time.Second)
+
+	// Other commands and disabled cooldowns are not affected
+	ok, _ = state.CheckCommandCooldown("red", time.Minute)
+	assert.True(t, ok)
+	ok, _ = state.CheckCommandCooldown("strobe", 0)
+	assert.True(t, ok)
+}
@@ -21,11 +21,40 @@ type TwitchConfig struct {
 	GlobalCooldown time.Duration `json:"global_cooldown"` // Cooldown between ANY commands (default: 5s)
 	UserCooldown   time.Duration `json:"user_cooldown"`   // Per-user cooldown (default: 30s)
 
+	// Per-command duration, speed, cooldown and cost
+	CommandSettings []CommandSetting `json:"command_settings"`
+
+	// Loyalty points viewers earn and spend on commands
+	Loyalty LoyaltyConfig `json:"loyalty"`
+
+	// Flash and hue change limits for photosensitive viewers
+	Safety SafetyConfig `json:"safety"`
+
+	// Voting settings
+	VoteMode     bool          `json:"vote_mode"`     // Color and effect commands count as poll votes
+	VoteDuration time.Duration `json:"vote_duration"` // How long a poll stays open (default: 30s)
+
+	// Override settings
+	SafeScene DeviceState `json:"safe_scene"` // Scene applied by the panic action
+
 	// Privilege settings
 	VIPBypassCooldown bool `json:"vip_bypass_cooldown"` // VIPs bypass cooldown
 	SubBypassCooldown bool `json:"sub_bypass_cooldown"` // Subscribers bypass cooldown
 	ModBypassCooldown bool `json:"mod_bypass_cooldown"` // Moderators bypass cooldown
 
+	// Permission settings
+	Permissions []CommandPermission `json:"permissions"`  // Per-command role restrictions
+	BannedUsers []string            `json:"banned_users"` // Users blocked from all commands
+
+	// Reply settings
+	ReplyLanguage   string                `json:"reply_language"`   // Language pack for bot replies (default: en)
+	ReplyTemplates  map[ReplyEvent]string `json:"reply_templates"`  // Custom templates replacing the language pack
+	SilencedReplies []ReplyEvent          `json:"silenced_replies"` // Reply events the bot never sends
+	ReplyRateLimit  int                   `json:"reply_rate_limit"` // Max bot messages per chat and 30 seconds (default: 20)
+
+	// Additional chats, e.g. co-streamers or YouTube Live
+	Channels []ChatChannel `json:"channels"`
+
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
@@ -36,9 +65,21 @@ func NewTwitchConfig() *TwitchConfig {
 		EffectDuration:    30 * time.Second,
 		GlobalCooldown:    5 * time.Second,
 		UserCooldown:      30 * time.Second,
+		VoteDuration:      DefaultVoteDuration,
+		Loyalty:           NewLoyaltyConfig(),
+		Safety:            NewSafetyConfig(),
+		SafeScene:         DefaultSafeScene(),
 		VIPBypassCooldown: true,
 		SubBypassCooldown: true,
 		ModBypassCooldown: true,
+		CommandSettings:   []CommandSetting{},
+		Permissions:       DefaultPermissions(),
+		BannedUsers:       []string{},
+		ReplyLanguage:     DefaultReplyLanguage,
+		ReplyTemplates:    map[ReplyEvent]string{},
+		SilencedReplies:   []ReplyEvent{ReplyQueued}, // Votes would flood the chat during a poll
+		ReplyRateLimit:    DefaultReplyRateLimit,
+		Channels:          []ChatChannel{},
 		UpdatedAt:         time.Now(),
 	}
 }
@@ -46,14 +87,16 @@ func NewTwitchConfig() *TwitchConfig {
 // Validate validates the Twitch configuration
 func (c *TwitchConfig) Validate() error {
 	if c.Enabled {
-		if c.Channel == "" {
+		if c.Channel == "" && len(c.Channels) == 0 {
 			return fmt.Errorf("channel name is required")
 		}
-		if c.BotUsername == "" {
-			return fmt.Errorf("bot username is required")
-		}
-		if c.AccessToken == "" {
-			return fmt.Errorf("access token is required")
+		if c.usesTwitch() {
+			if c.BotUsername == "" {
+				return fmt.Errorf("bot username is required")
+			}
+			if c.AccessToken == "" {
+				return fmt.Errorf("access token is required")
+			}
 		}
 	}
 
@@ -69,5 +112,175 @@ func (c *TwitchConfig) Validate() error {
 		return fmt.Errorf("user cooldown cannot be negative")
 	}
 
+	if c.VoteMode && c.VoteDurationOrDefault() < 5*time.Second {
+		return fmt.Errorf("vote duration must be at least 5 seconds")
+	}
+
+	if err := c.Loyalty.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Safety.Validate(); err != nil {
+		return err
+	}
+
+	for i := range c.CommandSettings {
+		if err := c.CommandSettings[i].Validate(); err != nil {
+			return err
+		}
+	}
+
+	for i := range c.Permissions {
+		if err := c.Permissions[i].Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.ReplyLanguage != "" {
+		if _, exists := ReplyLanguages[c.ReplyLanguage]; !exists {
+			return fmt.Errorf("unsupported reply language: %s", c.ReplyLanguage)
+		}
+	}
+
+	for event, text := range c.ReplyTemplates {
+		if !event.IsValid() {
+			return fmt.Errorf("unknown reply event: %s", event)
+		}
+		if _, err := RenderReply(event, text, ReplyData{}); err != nil {
+			return err
+		}
+	}
+
+	for _, event := range c.SilencedReplies {
+		if !event.IsValid() {
+			return fmt.Errorf("unknown reply event: %s", event)
+		}
+	}
+
+	if c.ReplyRateLimit < 0 || c.ReplyRateLimit > MaxReplyRateLimit {
+		return fmt.Errorf("reply rate limit must be between 0 (default) and %d messages", MaxReplyRateLimit)
+	}
+
+	seen := make(map[string]bool)
+	for i := range c.Channels {
+		if err := c.Channels[i].Validate(); err != nil {
+			return err
+		}
+		if seen[c.Channels[i].ID] {
+			return fmt.Errorf("duplicate chat channel: %s", c.Channels[i].ID)
+		}
+		seen[c.Channels[i].ID] = true
+	}
+
 	return nil
 }
+
+// Clone returns a copy that shares no slices or maps with the config,
+// so changes can be validated before they replace the stored config
+func (c *TwitchConfig) Clone() *TwitchConfig {
+	clone := *c
+	clone.CommandSettings = append([]CommandSetting{}, c.CommandSettings...)
+	clone.Permissions = clonePermissions(c.Permissions)
+	clone.BannedUsers = append([]string{}, c.BannedUsers...)
+	clone.ReplyTemplates = make(map[ReplyEvent]string, len(c.ReplyTemplates))
+	for event, template := range c.ReplyTemplates {
+		clone.ReplyTemplates[event] = template
+	}
+	clone.SilencedReplies = append([]ReplyEvent{}, c.SilencedReplies...)
+	clone.Channels = make([]ChatChannel, len(c.Channels))
+	for i, channel := range c.Channels {
+		channel.Commands = append([]string{}, channel.Commands...)
+		channel.Permissions = clonePermissions(channel.Permissions)
+		clone.Channels[i] = channel
+	}
+	return &clone
+}
+
+// clonePermissions copies permission rules including their user lists
+func clonePermissions(permissions []CommandPermission) []CommandPermission {
+	clone := make([]CommandPermission, len(permissions))
+	for i, permission := range permissions {
+		permission.AllowUsers = append([]string(nil), permission.AllowUsers...)
+		permission.DenyUsers = append([]string(nil), permission.DenyUsers...)
+		clone[i] = permission
+	}
+	return clone
+}
+
+// VoteDurationOrDefault returns the configured poll duration, falling back to the default
+// for configs saved before polls existed
+func (c *TwitchConfig) VoteDurationOrDefault() time.Duration {
+	if c.VoteDuration <= 0 {
+		return DefaultVoteDuration
+	}
+	return c.VoteDuration
+}
+
+// ReplyRateLimitOrDefault returns the configured reply rate limit, falling back to the default
+func (c *TwitchConfig) ReplyRateLimitOrDefault() int {
+	if c.ReplyRateLimit <= 0 {
+		return DefaultReplyRateLimit
+	}
+	return c.ReplyRateLimit
+}
+
+// ChatChannels returns all chats to listen to: the main Twitch channel
+// followed by the enabled additional channels
+func (c *TwitchConfig) ChatChannels() []ChatChannel {
+	channels := make([]ChatChannel, 0, len(c.Channels)+1)
+	if c.Channel != "" {
+		channels = append(channels, NewChatChannel(PlatformTwitch, c.Channel))
+	}
+	for _, channel := range c.Channels {
+		if channel.Enabled {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// TrustsBadges returns whether broadcaster and moderator badges from a chat count.
+// Only the streamer's own chat is trusted: the main Twitch channel, or the first
+// additional chat without one, and the local console.
+func (c *TwitchConfig) TrustsBadges(channel ChatChannel) bool {
+	if channel.Platform == PlatformConsole {
+		return true
+	}
+
+	channels := c.ChatChannels()
+	return len(channels) > 0 && channels[0].ID == channel.ID
+}
+
+// FindChannel returns the chat channel with the given ID, or nil
+func (c *TwitchConfig) FindChannel(id string) *ChatChannel {
+	for i := range c.Channels {
+		if c.Channels[i].ID == id {
+			return &c.Channels[i]
+		}
+	}
+	return nil
+}
+
+// RemoveChannel removes an additional chat channel, returning false if there was none
+func (c *TwitchConfig) RemoveChannel(id string) bool {
+	for i := range c.Channels {
+		if c.Channels[i].ID == id {
+			c.Channels = append(c.Channels[:i], c.Channels[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// usesTwitch checks if any chat needs the bot's Twitch credentials
+func (c *TwitchConfig) usesTwitch() bool {
+	if c.Channel != "" {
+		return true
+	}
+	for _, channel := range c.Channels {
+		if channel.Enabled && channel.Platform == PlatformTwitch {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwitchConfigClone(t *testing.T) {
+	config := NewTwitchConfig()
+	config.BannedUsers = []string{"troll"}
+	config.ReplyTemplates[ReplySuccess] = "done"
+	channel := NewChatChannel(PlatformTwitch, "costreamer")
+	channel.Permissions = []CommandPermission{{Command: "red", MinRole: RoleVIP, AllowUsers: []string{"friend"}}}
+	config.Channels = []ChatChannel{channel}
+
+	clone := config.Clone()
+	clone.BannedUsers[0] = "friend"
+	clone.ReplyTemplates[ReplySuccess] = "changed"
+	clone.Permissions[0].MinRole = RoleBroadcaster
+	clone.Channels[0].Permissions[0].AllowUsers[0] = "troll"
+
+	assert.Equal(t, []string{"troll"}, config.BannedUsers)
+	assert.Equal(t, "done", config.ReplyTemplates[ReplySuccess])
+	assert.NotEqual(t, RoleBroadcaster, config.Permissions[0].MinRole)
+	assert.Equal(t, []string{"friend"}, config.Channels[0].Permissions[0].AllowUsers)
+}
+
+func TestTwitchConfigTrustsBadges(t *testing.T) {
+	costream := NewChatChannel(PlatformTwitch, "costreamer")
+	youtube := NewChatChannel(PlatformYouTube, "video-1")
+	console := NewChatChannel(PlatformConsole, "console")
+
+	config := NewTwitchConfig()
+	config.Channel = "streamer"
+	config.Channels = []ChatChannel{costream, youtube}
+
+	assert.True(t, config.TrustsBadges(NewChatChannel(PlatformTwitch, "Streamer")))
+	assert.False(t, config.TrustsBadges(costream))
+	assert.False(t, config.TrustsBadges(youtube))
+	assert.True(t, config.TrustsBadges(console))
+
+	// Without a main Twitch channel the first chat is the streamer's own
+	config.Channel = ""
+	config.Channels = []ChatChannel{youtube, costream}
+	assert.True(t, config.TrustsBadges(youtube))
+	assert.False(t, config.TrustsBadges(costream))
+}
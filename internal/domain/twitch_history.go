@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// CommandOutcome describes what happened to a chat command
+type CommandOutcome string
+
+const (
+	OutcomeSuccess  CommandOutcome = "success"
+	OutcomeCooldown CommandOutcome = "cooldown"
+	OutcomeUnknown  CommandOutcome = "unknown"
+	OutcomeFailure  CommandOutcome = "failure"
+	OutcomeDenied   CommandOutcome = "denied"
+	OutcomeVote     CommandOutcome = "vote"
+)
+
+// CommandRecord is a persisted chat command attempt
+type CommandRecord struct {
+	ID          string         `json:"id"`
+	StreamID    string         `json:"stream_id"` // Twitch integration session the command arrived in
+	Platform    ChatPlatform   `json:"platform,omitempty"`
+	Channel     string         `json:"channel,omitempty"` // ID of the chat channel, empty for poll results
+	Username    string         `json:"username"`
+	DisplayName string         `json:"display_name"`
+	Command     string         `json:"command"`
+	Outcome     CommandOutcome `json:"outcome"`
+	Reason      string         `json:"reason,omitempty"` // Why the command was rejected or failed
+	Timestamp   time.Time      `json:"timestamp"`
+}
+
+// NewCommandRecord creates a record for a command attempt
+func NewCommandRecord(streamID string, cmd *ChatCommand, outcome CommandOutcome, reason string) *CommandRecord {
+	timestamp := cmd.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	return &CommandRecord{
+		ID:          timestamp.Format("20060102150405.000000000") + "-" + cmd.Username,
+		StreamID:    streamID,
+		Platform:    cmd.Platform,
+		Channel:     cmd.Channel,
+		Username:    cmd.Username,
+		DisplayName: cmd.DisplayName,
+		Command:     cmd.Command,
+		Outcome:     outcome,
+		Reason:      reason,
+		Timestamp:   timestamp,
+	}
+}
+
+// CommandHistoryFilter selects command records
+type CommandHistoryFilter struct {
+	Username string
+	Command  string
+	Outcome  CommandOutcome
+	StreamID string
+	Since    time.Time
+	Until    time.Time
+	Limit    int // 0 means no limit
+}
+
+// Matches checks if a record passes the filter
+func (f *CommandHistoryFilter) Matches(record *CommandRecord) bool {
+	if f.Username != "" && !strings.EqualFold(f.Username, record.Username) {
+		return false
+	}
+	if f.Command != "" && !strings.EqualFold(f.Command, record.Command) {
+		return false
+	}
+	if f.Outcome != "" && f.Outcome != record.Outcome {
+		return false
+	}
+	if f.StreamID != "" && f.StreamID != record.StreamID {
+		return false
+	}
+	if !f.Since.IsZero() && record.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && record.Timestamp.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// UsageCount counts how often a user or command appears
+type UsageCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// StreamUsage summarizes the commands of one stream
+type StreamUsage struct {
+	StreamID  string    `json:"stream_id"`
+	StartedAt time.Time `json:"started_at"`
+	Commands  int       `json:"commands"`  // All attempts
+	Succeeded int       `json:"succeeded"` // Attempts that changed the lamp
+}
+
+// CommandStats aggregates command history
+type CommandStats struct {
+	TotalCommands int                    `json:"total_commands"`
+	ByOutcome     map[CommandOutcome]int `json:"by_outcome"`
+	TopViewers    []UsageCount           `json:"top_viewers"`
+	TopColors     []UsageCount           `json:"top_colors"`
+	TopEffects    []UsageCount           `json:"top_effects"`
+	Streams       []StreamUsage          `json:"streams"`
+}
+
+// ViewerStats summarizes a single viewer's successful commands
+type ViewerStats struct {
+	Username      string    `json:"username"`
+	TotalCommands int       `json:"total_commands"`
+	Favorite      string    `json:"favorite,omitempty"`
+	LastCommandAt time.Time `json:"last_command_at"`
+}
+
+// ComputeCommandStats aggregates records, keeping at most limit entries per ranking
+func ComputeCommandStats(records []*CommandRecord, limit int) CommandStats {
+	stats := CommandStats{
+		ByOutcome: make(map[CommandOutcome]int),
+	}
+
+	viewers := make(map[string]int)
+	colors := make(map[string]int)
+	effects := make(map[string]int)
+	streams := make(map[string]*StreamUsage)
+
+	for _, record := range records {
+		stats.TotalCommands++
+		stats.ByOutcome[record.Outcome]++
+
+		stream, exists := streams[record.StreamID]
+		if !exists {
+			stream = &StreamUsage{StreamID: record.StreamID, StartedAt: record.Timestamp}
+			streams[record.StreamID] = stream
+		}
+		stream.Commands++
+		if record.Timestamp.Before(stream.StartedAt) {
+			stream.StartedAt = record.Timestamp
+		}
+
+		if record.Outcome != OutcomeSuccess {
+			continue
+		}
+
+		stream.Succeeded++
+		if record.Username != "" {
+			viewers[record.Username]++
+		}
+		if IsColor(record.Command) {
+			colors[record.Command]++
+		} else if IsEffect(record.Command) {
+			effects[record.Command]++
+		}
+	}
+
+	stats.TopViewers = rankUsage(viewers, limit)
+	stats.TopColors = rankUsage(colors, limit)
+	stats.TopEffects = rankUsage(effects, limit)
+
+	stats.Streams = make([]StreamUsage, 0, len(streams))
+	for _, stream := range streams {
+		stats.Streams = append(stats.Streams, *stream)
+	}
+	sort.Slice(stats.Streams, func(i, j int) bool {
+		return stats.Streams[i].StartedAt.After(stats.Streams[j].StartedAt)
+	})
+
+	return stats
+}
+
+// ComputeViewerStats summarizes the successful commands of a single viewer
+func ComputeViewerStats(records []*CommandRecord, username string) ViewerStats {
+	stats := ViewerStats{Username: strings.ToLower(username)}
+	commands := make(map[string]int)
+
+	for _, record := range records {
+		if record.Outcome != OutcomeSuccess || !strings.EqualFold(record.Username, username) {
+			continue
+		}
+
+		stats.TotalCommands++
+		commands[record.Command]++
+		if record.Timestamp.After(stats.LastCommandAt) {
+			stats.LastCommandAt = record.Timestamp
+		}
+	}
+
+	if ranked := rankUsage(commands, 1); len(ranked) > 0 {
+		stats.Favorite = ranked[0].Name
+	}
+
+	return stats
+}
+
+// rankUsage sorts counts descending, breaking ties alphabetically
+func rankUsage(counts map[string]int, limit int) []UsageCount {
+	ranked := make([]UsageCount, 0, len(counts))
+	for name, count := range counts {
+		ranked = append(ranked, UsageCount{Name: name, Count: count})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeCommandStats(t *testing.T) {
+	start := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	record := func(streamID, username, command string, outcome CommandOutcome, offset time.Duration) *CommandRecord {
+		cmd := &ChatCommand{Username: username, Command: command, Timestamp: start.Add(offset)}
+		return NewCommandRecord(streamID, cmd, outcome, "")
+	}
+
+	records := []*CommandRecord{
+		record("first", "alice", "red", OutcomeSuccess, 0),
+		record("first", "bob", "red", OutcomeSuccess, time.Minute),
+		record("first", "bob", "rainbow", OutcomeCooldown, 2*time.Minute),
+		record("second", "alice", "blue", OutcomeSuccess, time.Hour),
+		record("second", "alice", "rainbow", OutcomeSuccess, time.Hour+time.Minute),
+		// Poll results count for the stream but for no viewer
+		record("second", "", "blue", OutcomeSuccess, time.Hour+2*time.Minute),
+	}
+
+	stats := ComputeCommandStats(records, 2)
+
+	assert.Equal(t, 6, stats.TotalCommands)
+	assert.Equal(t, map[CommandOutcome]int{OutcomeSuccess: 5, OutcomeCooldown: 1}, stats.ByOutcome)
+	assert.Equal(t, []UsageCount{{Name: "alice", Count: 3}, {Name: "bob", Count: 1}}, stats.TopViewers)
+	// Ties are broken alphabetically
+	assert.Equal(t, []UsageCount{{Name: "blue", Count: 2}, {Name: "red", Count: 2}}, stats.TopColors)
+	assert.Equal(t, []UsageCount{{Name: "rainbow", Count: 1}}, stats.TopEffects)
+	assert.Equal(t, []StreamUsage{
+		{StreamID: "second", StartedAt: start.Add(time.Hour), Commands: 3, Succeeded: 3},
+		{StreamID: "first", StartedAt: start, Commands: 3, Succeeded: 2},
+	}, stats.Streams)
+}
@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role represents a chat user's privilege level, ordered from least to most privileged.
+// VIPs rank above subscribers, as in Twitch chat, so a subscriber rule also admits
+// VIPs who are not subscribed.
+type Role string
+
+const (
+	RoleEveryone    Role = "everyone"
+	RoleFollower    Role = "follower"
+	RoleSubscriber  Role = "subscriber"
+	RoleVIP         Role = "vip"
+	RoleModerator   Role = "moderator"
+	RoleBroadcaster Role = "broadcaster"
+)
+
+// roleRanks orders roles so that a higher rank includes all lower ones, e.g. a VIP
+// satisfies a subscriber rule
+var roleRanks = map[Role]int{
+	RoleEveryone:    0,
+	RoleFollower:    1,
+	RoleSubscriber:  2,
+	RoleVIP:         3,
+	RoleModerator:   4,
+	RoleBroadcaster: 5,
+}
+
+// IsValid checks if the role is known
+func (r Role) IsValid() bool {
+	_, exists := roleRanks[r]
+	return exists
+}
+
+// Includes reports whether a user holding r satisfies the required role
+func (r Role) Includes(required Role) bool {
+	return roleRanks[r] >= roleRanks[required]
+}
+
+// MaxSubTier is the highest Twitch subscription tier
+const MaxSubTier = 3
+
+// CommandPermission restricts who may run a single chat command
+type CommandPermission struct {
+	Command    string   `json:"command"`                // Command name, e.g. "strobe" or "off"
+	MinRole    Role     `json:"min_role"`               // Lowest role allowed to run the command
+	MinSubTier int      `json:"min_sub_tier,omitempty"` // Lowest tier a subscriber needs, 0 for any. Higher roles pass regardless.
+	AllowUsers []string `json:"allow_users,omitempty"`  // Users allowed regardless of role
+	DenyUsers  []string `json:"deny_users,omitempty"`   // Users never allowed to run the command
+}
+
+// Validate validates the command permission
+func (p *CommandPermission) Validate() error {
+	if strings.TrimSpace(p.Command) == "" {
+		return fmt.Errorf("permission command is required")
+	}
+	if !p.MinRole.IsValid() {
+		return fmt.Errorf("invalid role for command %s: %s", p.Command, p.MinRole)
+	}
+	if p.MinSubTier < 0 || p.MinSubTier > MaxSubTier {
+		return fmt.Errorf("subscription tier for command %s must be between 0 and %d", p.Command, MaxSubTier)
+	}
+	return nil
+}
+
+// DefaultPermissions returns the permission rules for a fresh configuration.
+// Strobe is open to subscribers and, since they rank higher, VIPs and moderators.
+func DefaultPermissions() []CommandPermission {
+	return []CommandPermission{
+		{Command: "strobe", MinRole: RoleSubscriber},
+		{Command: "off", MinRole: RoleModerator},
+	}
+}
+
+// GetPermission returns the rule for a command, or nil if the command is open to everyone
+func (c *TwitchConfig) GetPermission(command string) *CommandPermission {
+	command = strings.ToLower(command)
+	for i := range c.Permissions {
+		if strings.ToLower(c.Permissions[i].Command) == command {
+			return &c.Permissions[i]
+		}
+	}
+	return nil
+}
+
+// SetPermission adds or replaces the rule for a command
+func (c *TwitchConfig) SetPermission(permission CommandPermission) {
+	permission.Command = strings.ToLower(permission.Command)
+	if existing := c.GetPermission(permission.Command); existing != nil {
+		*existing = permission
+		return
+	}
+	c.Permissions = append(c.Permissions, permission)
+}
+
+// RemovePermission removes the rule for a command, returning false if there was none
+func (c *TwitchConfig) RemovePermission(command string) bool {
+	command = strings.ToLower(command)
+	for i := range c.Permissions {
+		if strings.ToLower(c.Permissions[i].Command) == command {
+			c.Permissions = append(c.Permissions[:i], c.Permissions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// IsBanned checks if a user is banned from all lamp commands
+func (c *TwitchConfig) IsBanned(username string) bool {
+	return containsUser(c.BannedUsers, username)
+}
+
+// Authorize checks whether the sender of cmd may run it in the given chat channel.
+// A rule of the channel replaces the global rule for the same command, channel may be nil.
+// On ErrInsufficientRole the returned role is the one the command requires.
+func (c *TwitchConfig) Authorize(cmd *ChatCommand, channel *ChatChannel) (Role, error) {
+	// The broadcaster can always control their own lamp
+	if cmd.IsBroadcaster {
+		return RoleBroadcaster, nil
+	}
+
+	if c.IsBanned(cmd.Username) {
+		return RoleEveryone, ErrUserBanned
+	}
+
+	permission := c.PermissionFor(cmd.Command, channel)
+	if permission == nil {
+		return RoleEveryone, nil
+	}
+
+	if containsUser(permission.DenyUsers, cmd.Username) {
+		return permission.MinRole, ErrUserBanned
+	}
+
+	if containsUser(permission.AllowUsers, cmd.Username) {
+		return permission.MinRole, nil
+	}
+
+	role := cmd.Role()
+	if !role.Includes(permission.MinRole) {
+		return permission.MinRole, ErrInsufficientRole
+	}
+	if role == RoleSubscriber && cmd.SubTier < permission.MinSubTier {
+		return permission.MinRole, ErrInsufficientRole
+	}
+
+	return permission.MinRole, nil
+}
+
+// PermissionFor returns the rule that applies to a command in a chat channel, or nil
+func (c *TwitchConfig) PermissionFor(command string, channel *ChatChannel) *CommandPermission {
+	if channel != nil {
+		if permission := channel.GetPermission(command); permission != nil {
+			return permission
+		}
+	}
+	return c.GetPermission(command)
+}
+
+// containsUser checks a user list case-insensitively
+func containsUser(users []string, username string) bool {
+	for _, user := range users {
+		if strings.EqualFold(user, username) {
+			return true
+		}
+	}
+	return false
+}
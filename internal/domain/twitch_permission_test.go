@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwitchConfigAuthorize(t *testing.T) {
+	config := NewTwitchConfig()
+	config.BannedUsers = []string{"troll"}
+	config.SetPermission(CommandPermission{
+		Command:    "rainbow",
+		MinRole:    RoleVIP,
+		AllowUsers: []string{"friend"},
+		DenyUsers:  []string{"spammer"},
+	})
+
+	config.SetPermission(CommandPermission{Command: "fade", MinRole: RoleSubscriber, MinSubTier: 2})
+
+	costream := NewChatChannel(PlatformTwitch, "costreamer")
+	costream.Permissions = []CommandPermission{{Command: "strobe", MinRole: RoleEveryone}}
+
+	tests := []struct {
+		name     string
+		cmd      ChatCommand
+		channel  *ChatChannel
+		expected error
+	}{
+		{
+			name: "open command for everyone",
+			cmd:  ChatCommand{Username: "viewer", Command: "red"},
+		},
+		{
+			name:     "banned user",
+			cmd:      ChatCommand{Username: "Troll", Command: "red"},
+			expected: ErrUserBanned,
+		},
+		{
+			name:     "strobe requires subscriber",
+			cmd:      ChatCommand{Username: "viewer", Command: "strobe"},
+			expected: ErrInsufficientRole,
+		},
+		{
+			name: "subscriber can strobe",
+			cmd:  ChatCommand{Username: "sub", Command: "strobe", IsSub: true},
+		},
+		{
+			name: "founder counts as subscriber",
+			cmd:  ChatCommand{Username: "founder", Command: "strobe", IsFounder: true},
+		},
+		{
+			name: "vip ranks above subscriber",
+			cmd:  ChatCommand{Username: "vip", Command: "strobe", IsVIP: true},
+		},
+		{
+			name:     "tier 1 subscriber below required tier",
+			cmd:      ChatCommand{Username: "sub", Command: "fade", IsSub: true, SubTier: 1},
+			expected: ErrInsufficientRole,
+		},
+		{
+			name: "tier 2 subscriber meets required tier",
+			cmd:  ChatCommand{Username: "sub", Command: "fade", IsSub: true, SubTier: 2},
+		},
+		{
+			name: "moderator passes tier rule",
+			cmd:  ChatCommand{Username: "mod", Command: "fade", IsMod: true},
+		},
+		{
+			name:     "vip cannot power off",
+			cmd:      ChatCommand{Username: "vip", Command: "off", IsVIP: true},
+			expected: ErrInsufficientRole,
+		},
+		{
+			name: "moderator can power off",
+			cmd:  ChatCommand{Username: "mod", Command: "off", IsMod: true},
+		},
+		{
+			name: "allow list bypasses role",
+			cmd:  ChatCommand{Username: "friend", Command: "rainbow"},
+		},
+		{
+			name:     "deny list beats role",
+			cmd:      ChatCommand{Username: "spammer", Command: "rainbow", IsMod: true},
+			expected: ErrUserBanned,
+		},
+		{
+			name: "broadcaster is never blocked",
+			cmd:  ChatCommand{Username: "spammer", Command: "rainbow", IsBroadcaster: true},
+		},
+		{
+			name:    "channel rule replaces global rule",
+			cmd:     ChatCommand{Username: "viewer", Command: "strobe"},
+			channel: &costream,
+		},
+		{
+			name:     "global rule applies without channel rule",
+			cmd:      ChatCommand{Username: "viewer", Command: "off"},
+			channel:  &costream,
+			expected: ErrInsufficientRole,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := config.Authorize(&tt.cmd, tt.channel)
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
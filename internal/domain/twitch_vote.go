@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultVoteDuration is how long a poll stays open unless configured otherwise
+const DefaultVoteDuration = 30 * time.Second
+
+// Vote represents a single viewer's ballot in a chat poll
+type Vote struct {
+	Username string    `json:"username"`
+	Option   string    `json:"option"`
+	CastAt   time.Time `json:"cast_at"`
+}
+
+// VoteResult represents the tally of a single poll option
+type VoteResult struct {
+	Option    string    `json:"option"`
+	Count     int       `json:"count"`
+	ReachedAt time.Time `json:"reached_at"` // When the option reached its current count
+}
+
+// VoteSession represents a chat poll for the next lamp color or effect
+type VoteSession struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"started_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Closed    bool      `json:"closed"`
+	Canceled  bool      `json:"canceled"` // Closed without applying a winner
+	Votes     []Vote    `json:"votes"`    // One vote per user, in casting order
+}
+
+// NewVoteSession creates a new poll that is open for the given duration
+func NewVoteSession(duration time.Duration) *VoteSession {
+	now := time.Now()
+	return &VoteSession{
+		ID:        generateID(),
+		StartedAt: now,
+		EndsAt:    now.Add(duration),
+		Votes:     make([]Vote, 0),
+	}
+}
+
+// CastVote records a user's vote. A user voting again replaces their previous vote.
+// It returns false if the poll is already closed.
+func (s *VoteSession) CastVote(username, option string) bool {
+	if s.Closed {
+		return false
+	}
+
+	username = strings.ToLower(username)
+	for i, vote := range s.Votes {
+		if vote.Username == username {
+			s.Votes = append(s.Votes[:i], s.Votes[i+1:]...)
+			break
+		}
+	}
+
+	s.Votes = append(s.Votes, Vote{
+		Username: username,
+		Option:   strings.ToLower(option),
+		CastAt:   time.Now(),
+	})
+	return true
+}
+
+// Tally returns the results ordered from winner to loser.
+// Ties are broken in favor of the option that reached the tied count first.
+func (s *VoteSession) Tally() []VoteResult {
+	results := make(map[string]*VoteResult)
+	order := make([]string, 0)
+
+	for _, vote := range s.Votes {
+		result, exists := results[vote.Option]
+		if !exists {
+			result = &VoteResult{Option: vote.Option}
+			results[vote.Option] = result
+			order = append(order, vote.Option)
+		}
+		result.Count++
+		result.ReachedAt = vote.CastAt
+	}
+
+	tally := make([]VoteResult, 0, len(order))
+	for _, option := range order {
+		tally = append(tally, *results[option])
+	}
+
+	sort.SliceStable(tally, func(i, j int) bool {
+		if tally[i].Count != tally[j].Count {
+			return tally[i].Count > tally[j].Count
+		}
+		return tally[i].ReachedAt.Before(tally[j].ReachedAt)
+	})
+
+	return tally
+}
+
+// Winner returns the winning result, or nil if nobody voted
+func (s *VoteSession) Winner() *VoteResult {
+	tally := s.Tally()
+	if len(tally) == 0 {
+		return nil
+	}
+	return &tally[0]
+}
+
+// Remaining returns the time left until the poll closes
+func (s *VoteSession) Remaining() time.Duration {
+	if s.Closed {
+		return 0
+	}
+	remaining := time.Until(s.EndsAt)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// Clone returns a copy that is safe to hand out while the poll keeps running
+func (s *VoteSession) Clone() *VoteSession {
+	clone := *s
+	clone.Votes = append([]Vote(nil), s.Votes...)
+	return &clone
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoteSessionTally(t *testing.T) {
+	session := NewVoteSession(time.Minute)
+
+	session.CastVote("alice", "red")
+	session.CastVote("bob", "blue")
+	session.CastVote("carol", "blue")
+	session.CastVote("dave", "red")
+
+	// Red reached two votes last, so blue wins the tie
+	winner := session.Winner()
+	assert.Equal(t, "blue", winner.Option)
+	assert.Equal(t, 2, winner.Count)
+
+	// Changing a vote replaces the previous one
+	session.CastVote("Carol", "red")
+	assert.Len(t, session.Votes, 4)
+	assert.Equal(t, VoteResult{Option: "red", Count: 3, ReachedAt: session.Votes[3].CastAt}, *session.Winner())
+
+	// Closed polls reject votes
+	session.Closed = true
+	assert.False(t, session.CastVote("erin", "green"))
+	assert.Equal(t, time.Duration(0), session.Remaining())
+}
+
+func TestVoteSessionWinnerWithoutVotes(t *testing.T) {
+	session := NewVoteSession(time.Minute)
+	assert.Nil(t, session.Winner())
+}
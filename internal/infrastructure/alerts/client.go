@@ -0,0 +1,496 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/gorilla/websocket"
+)
+
+// Engine.IO v3 packet types, with Socket.IO packet types following the "4" message type
+const (
+	packetOpen    = "0"
+	packetClose   = "1"
+	packetPing    = "2"
+	packetPong    = "3"
+	packetConnect = "40"
+	packetEnd     = "41"
+	packetEvent   = "42"
+	packetError   = "44"
+)
+
+// Reconnect backoff and handshake defaults
+const (
+	defaultMinBackoff   = 1 * time.Second
+	defaultMaxBackoff   = 60 * time.Second
+	defaultPingInterval = 25 * time.Second
+	handshakeTimeout    = 10 * time.Second
+)
+
+// ErrAuthenticationFailed is returned when the provider rejects the token
+var ErrAuthenticationFailed = errors.New("alert provider authentication failed")
+
+// openData is the Engine.IO handshake sent by the server
+type openData struct {
+	PingInterval int `json:"pingInterval"` // Milliseconds
+}
+
+// Client receives alerts from the Socket.IO API of an alert provider.
+// Like the OBS client it reconnects with exponential backoff and stops
+// retrying when the provider rejects the token.
+type Client struct {
+	provider     provider
+	address      string
+	token        string
+	alertHandler domain.AlertHandler
+	stateHandler domain.ConnectionStateHandler
+	status       domain.ConnectionStatus
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	conn         *websocket.Conn
+	writeMu      sync.Mutex // Serializes pings and emits on conn
+	cancel       context.CancelFunc
+	done         chan struct{}
+	mu           sync.RWMutex
+	log          *slog.Logger
+}
+
+// NewStreamElementsClient creates a client for StreamElements alerts
+func NewStreamElementsClient(token string) *Client {
+	return newClient(streamElements, token)
+}
+
+// NewStreamlabsClient creates a client for Streamlabs alerts
+func NewStreamlabsClient(token string) *Client {
+	return newClient(streamlabs, token)
+}
+
+// newClient creates a client for a provider
+func newClient(provider provider, token string) *Client {
+	return &Client{
+		provider:   provider,
+		address:    provider.address,
+		token:      token,
+		status:     domain.NewConnectionStatus(domain.ConnectionDisconnected),
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+		log:        logging.Source("alerts").With("provider", provider.name),
+	}
+}
+
+// Provider returns the name of the alert provider
+func (c *Client) Provider() string {
+	return c.provider.name
+}
+
+// SetAddress overrides the provider's server address, e.g. "wss://example.com"
+func (c *Client) SetAddress(address string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.address = strings.TrimSuffix(address, "/")
+}
+
+// SetBackoff sets the minimum and maximum delay between reconnect attempts
+func (c *Client) SetBackoff(min, max time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.minBackoff = min
+	c.maxBackoff = max
+}
+
+// SetAlertHandler sets the handler for received alerts
+func (c *Client) SetAlertHandler(handler domain.AlertHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.alertHandler = handler
+}
+
+// SetStateHandler sets the handler for connection state changes
+func (c *Client) SetStateHandler(handler domain.ConnectionStateHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stateHandler = handler
+}
+
+// Connect starts the managed connection in the background.
+// The connection outlives ctx; call Disconnect to stop it.
+func (c *Client) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	if c.cancel != nil {
+		c.mu.Unlock()
+		return nil // Already running
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	c.mu.Unlock()
+
+	go c.run(runCtx)
+
+	return nil
+}
+
+// run connects and reconnects until the context is canceled or authentication fails
+func (c *Client) run(ctx context.Context) {
+	defer close(c.done)
+
+	attempt := 0
+	for {
+		if attempt == 0 {
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionConnecting))
+		}
+
+		connected, err := c.session(ctx)
+
+		if ctx.Err() != nil {
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionDisconnected))
+			return
+		}
+
+		if errors.Is(err, ErrAuthenticationFailed) {
+			c.log.Error("Authentication failed, check the token")
+			status := domain.NewConnectionStatus(domain.ConnectionAuthFailed)
+			status.Error = err.Error()
+			c.setStatus(status)
+			c.clearRunning()
+			return
+		}
+
+		// A connection that got authenticated starts a fresh backoff
+		if connected {
+			attempt = 0
+		}
+		attempt++
+
+		delay := c.backoff(attempt)
+		c.log.Warn("Connection lost", "error", err, "retry_in", delay)
+
+		status := domain.NewConnectionStatus(domain.ConnectionReconnecting)
+		status.Attempt = attempt
+		status.RetryAt = time.Now().Add(delay)
+		if err != nil {
+			status.Error = err.Error()
+		}
+		c.setStatus(status)
+
+		select {
+		case <-ctx.Done():
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionDisconnected))
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// session runs a single connection until it drops.
+// It reports whether the connection got authenticated.
+func (c *Client) session(ctx context.Context) (bool, error) {
+	c.mu.RLock()
+	address := c.address
+	c.mu.RUnlock()
+
+	query := c.provider.query(c.token)
+	query.Set("EIO", "3")
+	query.Set("transport", "websocket")
+
+	dialer := websocket.Dialer{HandshakeTimeout: handshakeTimeout}
+	conn, _, err := dialer.DialContext(ctx, address+"/socket.io/?"+query.Encode(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.conn = nil
+		c.mu.Unlock()
+		conn.Close()
+	}()
+
+	// Disconnect raced with the dial
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	pingInterval, err := c.handshake(conn)
+	if err != nil {
+		return false, err
+	}
+
+	c.log.Info("Connected")
+	c.setStatus(domain.NewConnectionStatus(domain.ConnectionConnected))
+
+	// Engine.IO v3 clients keep the connection alive with pings
+	stopPing := make(chan struct{})
+	defer close(stopPing)
+	go c.ping(conn, pingInterval, stopPing)
+
+	for {
+		packet, err := readPacket(conn)
+		if err != nil {
+			return true, err
+		}
+
+		switch {
+		case packet == packetPing:
+			c.write(conn, packetPong)
+		case packet == packetClose, packet == packetEnd:
+			return true, fmt.Errorf("%s closed the connection", c.provider.name)
+		case strings.HasPrefix(packet, packetEvent):
+			c.handleEvent(packet)
+		}
+	}
+}
+
+// handshake reads the Engine.IO open and Socket.IO connect packets and authenticates.
+// It returns the ping interval requested by the server.
+func (c *Client) handshake(conn *websocket.Conn) (time.Duration, error) {
+	conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	packet, err := readPacket(conn)
+	if err != nil {
+		return 0, err
+	}
+	if !strings.HasPrefix(packet, packetOpen) {
+		return 0, fmt.Errorf("unexpected packet before open: %q", packet)
+	}
+
+	pingInterval := defaultPingInterval
+	var open openData
+	if err := json.Unmarshal([]byte(packet[len(packetOpen):]), &open); err == nil && open.PingInterval > 0 {
+		pingInterval = time.Duration(open.PingInterval) * time.Millisecond
+	}
+
+	// The server confirms the namespace connection or rejects the token
+	for {
+		packet, err := readPacket(conn)
+		if err != nil {
+			return 0, err
+		}
+		if strings.HasPrefix(packet, packetError) {
+			return 0, ErrAuthenticationFailed
+		}
+		if strings.HasPrefix(packet, packetConnect) {
+			break
+		}
+	}
+
+	event, data := c.provider.authenticate(c.token)
+	if event == "" {
+		return pingInterval, nil
+	}
+
+	if err := c.emit(conn, event, data); err != nil {
+		return 0, err
+	}
+
+	for {
+		packet, err := readPacket(conn)
+		if err != nil {
+			return 0, err
+		}
+		if !strings.HasPrefix(packet, packetEvent) {
+			continue
+		}
+		name, _, err := decodeEvent(packet)
+		if err != nil {
+			return 0, err
+		}
+		switch name {
+		case "authenticated":
+			return pingInterval, nil
+		case "unauthorized":
+			return 0, ErrAuthenticationFailed
+		}
+	}
+}
+
+// ping sends Engine.IO pings until stop is closed
+func (c *Client) ping(conn *websocket.Conn, interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := c.write(conn, packetPing); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// emit sends a Socket.IO event
+func (c *Client) emit(conn *websocket.Conn, event string, data any) error {
+	payload, err := json.Marshal([]any{event, data})
+	if err != nil {
+		return err
+	}
+	return c.write(conn, packetEvent+string(payload))
+}
+
+// write sends a packet
+func (c *Client) write(conn *websocket.Conn, packet string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return conn.WriteMessage(websocket.TextMessage, []byte(packet))
+}
+
+// readPacket reads the next text packet
+func readPacket(conn *websocket.Conn) (string, error) {
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// decodeEvent splits a Socket.IO event packet into event name and data
+func decodeEvent(packet string) (string, json.RawMessage, error) {
+	// Skip the packet type and an optional acknowledgement id
+	payload := strings.TrimLeft(packet[len(packetEvent):], "0123456789")
+
+	var args []json.RawMessage
+	if err := json.Unmarshal([]byte(payload), &args); err != nil || len(args) == 0 {
+		return "", nil, fmt.Errorf("invalid event packet: %q", packet)
+	}
+
+	var name string
+	if err := json.Unmarshal(args[0], &name); err != nil {
+		return "", nil, fmt.Errorf("invalid event name: %q", packet)
+	}
+
+	var data json.RawMessage
+	if len(args) > 1 {
+		data = args[1]
+	}
+	return name, data, nil
+}
+
+// handleEvent converts a provider event and passes the alerts to the alert handler
+func (c *Client) handleEvent(packet string) {
+	name, data, err := decodeEvent(packet)
+	if err != nil {
+		c.log.Warn("Invalid event", "error", err)
+		return
+	}
+
+	c.mu.RLock()
+	handler := c.alertHandler
+	c.mu.RUnlock()
+
+	if handler == nil {
+		return
+	}
+
+	for _, alert := range c.provider.parse(name, data) {
+		handler(alert)
+	}
+}
+
+// backoff returns the delay before the given reconnect attempt
+func (c *Client) backoff(attempt int) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	delay := c.minBackoff
+	for i := 1; i < attempt && delay < c.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+	return delay
+}
+
+// Disconnect stops the managed connection and waits for it to shut down
+func (c *Client) Disconnect() error {
+	c.mu.Lock()
+	cancel := c.cancel
+	done := c.done
+	conn := c.conn
+	c.cancel = nil
+	if cancel != nil {
+		cancel() // Under the lock so a session either sees the cancel or stored its conn
+	}
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	if conn != nil {
+		conn.Close() // Unblocks the read loop
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		c.log.Warn("Timed out waiting for connection to close")
+	}
+
+	return nil
+}
+
+// clearRunning marks the managed connection as stopped without a Disconnect call
+func (c *Client) clearRunning() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
+
+// IsConnected returns whether the client is authenticated with the provider
+func (c *Client) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status.State == domain.ConnectionConnected
+}
+
+// Status returns the current connection status
+func (c *Client) Status() domain.ConnectionStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
+}
+
+// setStatus stores a new status and notifies the state handler
+func (c *Client) setStatus(status domain.ConnectionStatus) {
+	c.mu.Lock()
+	if c.status.State == status.State && status.State != domain.ConnectionReconnecting {
+		c.mu.Unlock()
+		return
+	}
+	c.status = status
+	handler := c.stateHandler
+	c.mu.Unlock()
+
+	c.log.Info("Connection state changed", "state", status.State)
+
+	if handler != nil {
+		handler(status)
+	}
+}
@@ -0,0 +1,211 @@
+package alerts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "valid-token"
+
+// fakeSocketServer speaks just enough Engine.IO v3 / Socket.IO to authenticate
+// clients like StreamElements and Streamlabs do and push events
+type fakeSocketServer struct {
+	server *httptest.Server
+	mu     sync.Mutex
+	conns  []*websocket.Conn
+}
+
+func newFakeSocketServer(t *testing.T) *fakeSocketServer {
+	t.Helper()
+
+	s := &fakeSocketServer{}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+func (s *fakeSocketServer) url() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+func (s *fakeSocketServer) handle(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	conn.WriteMessage(websocket.TextMessage, []byte(`0{"sid":"abc","upgrades":[],"pingInterval":25000,"pingTimeout":5000}`))
+
+	// Streamlabs style: the token is in the URL
+	if token := r.URL.Query().Get("token"); token != "" {
+		if token != testToken {
+			conn.WriteMessage(websocket.TextMessage, []byte(`44"Not authorized"`))
+			conn.Close()
+			return
+		}
+		s.register(conn)
+		return
+	}
+
+	// StreamElements style: the token is sent in an authenticate event
+	conn.WriteMessage(websocket.TextMessage, []byte(packetConnect))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		conn.Close()
+		return
+	}
+	_, auth, err := decodeEvent(string(data))
+	var credentials struct {
+		Token string `json:"token"`
+	}
+	if err != nil || json.Unmarshal(auth, &credentials) != nil || credentials.Token != testToken {
+		conn.WriteMessage(websocket.TextMessage, []byte(`42["unauthorized",{"message":"invalid token"}]`))
+		conn.Close()
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn.WriteMessage(websocket.TextMessage, []byte(`42["authenticated",{"channelId":"123"}]`))
+	s.conns = append(s.conns, conn)
+}
+
+// register confirms the namespace connection of a client authenticated by URL
+func (s *fakeSocketServer) register(conn *websocket.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn.WriteMessage(websocket.TextMessage, []byte(packetConnect))
+	s.conns = append(s.conns, conn)
+}
+
+// emit sends a Socket.IO event to every authenticated client
+func (s *fakeSocketServer) emit(t *testing.T, event string, data any) {
+	t.Helper()
+
+	payload, err := json.Marshal([]any{event, data})
+	require.NoError(t, err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, append([]byte(packetEvent), payload...)))
+	}
+}
+
+// alertRecorder collects alerts reported by the client
+type alertRecorder struct {
+	mu     sync.Mutex
+	alerts []domain.Alert
+}
+
+func (r *alertRecorder) handle(alert *domain.Alert) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, *alert)
+}
+
+func (r *alertRecorder) get() []domain.Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.Alert(nil), r.alerts...)
+}
+
+func newTestClient(client *Client, url string) *alertRecorder {
+	client.SetAddress(url)
+	client.SetBackoff(10*time.Millisecond, 50*time.Millisecond)
+
+	recorder := &alertRecorder{}
+	client.SetAlertHandler(recorder.handle)
+
+	return recorder
+}
+
+func TestStreamElementsClient(t *testing.T) {
+	server := newFakeSocketServer(t)
+	client := NewStreamElementsClient(testToken)
+	recorder := newTestClient(client, server.url())
+
+	require.NoError(t, client.Connect(t.Context()))
+	require.Eventually(t, client.IsConnected, 2*time.Second, 10*time.Millisecond)
+
+	server.emit(t, "event", map[string]any{"type": "follower", "data": map[string]any{"username": "alice", "displayName": "Alice"}})
+	server.emit(t, "event", map[string]any{"type": "cheer", "data": map[string]any{"username": "bob", "amount": 100}})
+	server.emit(t, "event", map[string]any{"type": "tip", "data": map[string]any{"username": "carol", "amount": 4.2, "message": "hi"}})
+	server.emit(t, "event:test", map[string]any{"listener": "raid-latest", "event": map[string]any{"name": "dave", "amount": 12}})
+
+	require.Eventually(t, func() bool { return len(recorder.get()) == 3 }, 2*time.Second, 10*time.Millisecond)
+	alerts := recorder.get()
+	assert.Equal(t, domain.Alert{Type: domain.AlertFollow, Source: "streamelements", User: "Alice", Timestamp: alerts[0].Timestamp}, alerts[0])
+	assert.Equal(t, domain.AlertDonation, alerts[1].Type, "cheers are skipped")
+	assert.Equal(t, 4.2, alerts[1].Amount)
+	assert.Equal(t, "hi", alerts[1].Message)
+	assert.Equal(t, domain.AlertRaid, alerts[2].Type)
+	assert.Equal(t, float64(12), alerts[2].Amount)
+
+	require.NoError(t, client.Disconnect())
+	assert.Equal(t, domain.ConnectionDisconnected, client.Status().State)
+}
+
+func TestStreamlabsClient(t *testing.T) {
+	server := newFakeSocketServer(t)
+	client := NewStreamlabsClient(testToken)
+	recorder := newTestClient(client, server.url())
+
+	require.NoError(t, client.Connect(t.Context()))
+	require.Eventually(t, client.IsConnected, 2*time.Second, 10*time.Millisecond)
+
+	server.emit(t, "event", map[string]any{
+		"type": "donation",
+		"for":  "streamlabs",
+		"message": []any{
+			map[string]any{"name": "erin", "amount": "13.37", "message": "gg"},
+			map[string]any{"name": "frank", "amount": 5},
+		},
+	})
+	server.emit(t, "event", map[string]any{"type": "raid", "message": []any{map[string]any{"name": "grace", "raiders": 25}}})
+
+	require.Eventually(t, func() bool { return len(recorder.get()) == 3 }, 2*time.Second, 10*time.Millisecond)
+	alerts := recorder.get()
+	assert.Equal(t, 13.37, alerts[0].Amount)
+	assert.Equal(t, "frank", alerts[1].User)
+	assert.Equal(t, domain.AlertRaid, alerts[2].Type)
+	assert.Equal(t, float64(25), alerts[2].Amount)
+
+	require.NoError(t, client.Disconnect())
+}
+
+func TestClientStopsOnWrongToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		client *Client
+	}{
+		{name: "streamelements", client: NewStreamElementsClient("wrong")},
+		{name: "streamlabs", client: NewStreamlabsClient("wrong")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeSocketServer(t)
+			newTestClient(tt.client, server.url())
+
+			require.NoError(t, tt.client.Connect(t.Context()))
+			assert.Eventually(t, func() bool {
+				return tt.client.Status().State == domain.ConnectionAuthFailed
+			}, 2*time.Second, 10*time.Millisecond)
+
+			require.NoError(t, tt.client.Disconnect())
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExtractJSONPath returns the value at a JSONPath-style path in decoded JSON.
+// Supported are member access ("$.data.name"), bracketed names ("$['display-name']")
+// and array indices ("$.items[0]"); the leading "$" is optional.
+func ExtractJSONPath(data any, path string) (any, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := data
+	for _, segment := range segments {
+		switch node := current.(type) {
+		case map[string]any:
+			value, exists := node[segment]
+			if !exists {
+				return nil, fmt.Errorf("%s: no field %q", path, segment)
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %q is not an array index", path, segment)
+			}
+			if index < 0 {
+				index += len(node) // Negative indices count from the end
+			}
+			if index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("%s: index %s out of range", path, segment)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("%s: cannot select %q from a scalar", path, segment)
+		}
+	}
+	return current, nil
+}
+
+// parseJSONPath splits a path into field names and array indices
+func parseJSONPath(path string) ([]string, error) {
+	rest := strings.TrimSpace(path)
+	if rest == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+	rest = strings.TrimPrefix(rest, "$")
+
+	var segments []string
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("%s: empty field name", path)
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("%s: missing ]", path)
+			}
+			segment := rest[1:end]
+			if len(segment) >= 2 && (segment[0] == '\'' || segment[0] == '"') && segment[len(segment)-1] == segment[0] {
+				segment = segment[1 : len(segment)-1]
+			} else if _, err := strconv.Atoi(segment); err != nil {
+				return nil, fmt.Errorf("%s: invalid index %q", path, segment)
+			}
+			segments = append(segments, segment)
+			rest = rest[end+1:]
+		default:
+			// Paths without "$" may start with a bare field name
+			if len(segments) > 0 {
+				return nil, fmt.Errorf("%s: unexpected %q", path, rest[0])
+			}
+			rest = "." + rest
+		}
+	}
+	return segments, nil
+}
@@ -0,0 +1,165 @@
+package alerts
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// provider describes an alert provider reachable over Socket.IO
+type provider struct {
+	name    string
+	address string // Default server address, without the /socket.io/ path
+
+	// query returns the Socket.IO query parameters for a token
+	query func(token string) url.Values
+
+	// authenticate returns the event that authenticates the socket after connecting,
+	// or an empty name if the token is already part of the URL
+	authenticate func(token string) (event string, data any)
+
+	// parse converts a provider event into alerts
+	parse func(event string, data json.RawMessage) []*domain.Alert
+}
+
+// streamElements receives alerts from the StreamElements realtime socket,
+// authenticated with the JWT from the StreamElements dashboard
+var streamElements = provider{
+	name:    domain.AlertProviderStreamElements,
+	address: "wss://realtime.streamelements.com",
+	query: func(string) url.Values {
+		return url.Values{}
+	},
+	authenticate: func(token string) (string, any) {
+		return "authenticate", map[string]string{"method": "jwt", "token": token}
+	},
+	parse: parseStreamElementsEvent,
+}
+
+// streamlabs receives alerts from the Streamlabs socket API,
+// authenticated with the socket token from the Streamlabs API settings
+var streamlabs = provider{
+	name:    domain.AlertProviderStreamlabs,
+	address: "wss://sockets.streamlabs.com",
+	query: func(token string) url.Values {
+		return url.Values{"token": {token}}
+	},
+	authenticate: func(string) (string, any) {
+		return "", nil
+	},
+	parse: parseStreamlabsEvent,
+}
+
+// streamElementsTypes maps StreamElements event types to alert types
+var streamElementsTypes = map[string]domain.AlertType{
+	"follower":   domain.AlertFollow,
+	"subscriber": domain.AlertSub,
+	"raid":       domain.AlertRaid,
+	"tip":        domain.AlertDonation,
+}
+
+// streamElementsTestListeners maps the listeners of emulated dashboard events to alert types
+var streamElementsTestListeners = map[string]domain.AlertType{
+	"follower-latest":   domain.AlertFollow,
+	"subscriber-latest": domain.AlertSub,
+	"raid-latest":       domain.AlertRaid,
+	"tip-latest":        domain.AlertDonation,
+}
+
+// parseStreamElementsEvent handles live "event" messages and emulated "event:test" messages
+func parseStreamElementsEvent(event string, data json.RawMessage) []*domain.Alert {
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil
+	}
+
+	var alertType domain.AlertType
+	var base string
+	switch event {
+	case "event":
+		value, _ := ExtractJSONPath(payload, "$.type")
+		alertType = streamElementsTypes[stringValue(value)]
+		base = "$.data"
+	case "event:test":
+		value, _ := ExtractJSONPath(payload, "$.listener")
+		alertType = streamElementsTestListeners[stringValue(value)]
+		base = "$.event"
+	}
+	if alertType == "" {
+		return nil
+	}
+
+	alert := &domain.Alert{
+		Type:      alertType,
+		Source:    domain.AlertProviderStreamElements,
+		User:      firstString(payload, base+".displayName", base+".username", base+".name"),
+		Message:   firstString(payload, base+".message"),
+		Timestamp: time.Now(),
+	}
+	if value, err := ExtractJSONPath(payload, base+".amount"); err == nil {
+		alert.Amount = numberValue(value)
+	}
+	return []*domain.Alert{alert}
+}
+
+// streamlabsTypes maps Streamlabs event types to alert types
+var streamlabsTypes = map[string]domain.AlertType{
+	"follow":       domain.AlertFollow,
+	"subscription": domain.AlertSub,
+	"resub":        domain.AlertSub,
+	"raid":         domain.AlertRaid,
+	"donation":     domain.AlertDonation,
+}
+
+// parseStreamlabsEvent handles "event" messages, which can batch several alerts
+func parseStreamlabsEvent(event string, data json.RawMessage) []*domain.Alert {
+	if event != "event" {
+		return nil
+	}
+
+	var payload struct {
+		Type    string `json:"type"`
+		Message []any  `json:"message"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil
+	}
+
+	alertType := streamlabsTypes[payload.Type]
+	if alertType == "" {
+		return nil
+	}
+
+	var alerts []*domain.Alert
+	for _, message := range payload.Message {
+		alert := &domain.Alert{
+			Type:      alertType,
+			Source:    domain.AlertProviderStreamlabs,
+			User:      firstString(message, "$.display_name", "$.name"),
+			Message:   firstString(message, "$.message"),
+			Timestamp: time.Now(),
+		}
+		for _, path := range []string{"$.amount", "$.raiders", "$.viewers", "$.months"} {
+			if value, err := ExtractJSONPath(message, path); err == nil {
+				alert.Amount = numberValue(value)
+				break
+			}
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+// firstString returns the first non-empty text at one of the paths
+func firstString(payload any, paths ...string) string {
+	for _, path := range paths {
+		if value, err := ExtractJSONPath(payload, path); err == nil {
+			if text := stringValue(value); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package alerts
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// ErrInvalidSignature is returned when a webhook signature doesn't match the body
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns the hex HMAC-SHA256 of a body, as expected in the signature header
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a hex HMAC-SHA256 signature, optionally prefixed with "sha256="
+func VerifySignature(secret string, body []byte, signature string) error {
+	signature = strings.TrimPrefix(strings.TrimSpace(signature), "sha256=")
+	given, err := hex.DecodeString(signature)
+	if err != nil || len(given) == 0 {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(given, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParseWebhook extracts an alert from a webhook payload using the hook's field paths.
+// It returns nil without an error for event types the hook doesn't map.
+func ParseWebhook(hook *domain.WebhookConfig, body []byte) (*domain.Alert, error) {
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid JSON payload: %w", err)
+	}
+
+	alertType := hook.Type
+	if hook.TypePath != "" {
+		value, err := ExtractJSONPath(payload, hook.TypePath)
+		if err != nil {
+			return nil, err
+		}
+		alertType = mapAlertType(hook.TypeValues, stringValue(value))
+		if alertType == "" {
+			return nil, nil
+		}
+	}
+
+	alert := &domain.Alert{
+		Type:      alertType,
+		Source:    hook.Name,
+		Timestamp: time.Now(),
+	}
+
+	// Optional fields stay empty when the payload doesn't have them
+	if hook.UserPath != "" {
+		if value, err := ExtractJSONPath(payload, hook.UserPath); err == nil {
+			alert.User = stringValue(value)
+		}
+	}
+	if hook.MessagePath != "" {
+		if value, err := ExtractJSONPath(payload, hook.MessagePath); err == nil {
+			alert.Message = stringValue(value)
+		}
+	}
+	if hook.AmountPath != "" {
+		if value, err := ExtractJSONPath(payload, hook.AmountPath); err == nil {
+			alert.Amount = numberValue(value)
+		}
+	}
+
+	return alert, nil
+}
+
+// mapAlertType maps a payload event type to an alert type.
+// Without a value map the payload must use the alert type names.
+func mapAlertType(values map[string]domain.AlertType, value string) domain.AlertType {
+	if len(values) > 0 {
+		return values[value]
+	}
+	if alertType := domain.AlertType(strings.ToLower(value)); alertType.IsValid() {
+		return alertType
+	}
+	return ""
+}
+
+// stringValue formats a decoded JSON value as text
+func stringValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// numberValue reads a decoded JSON number or numeric string, returning 0 otherwise
+func numberValue(value any) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case string:
+		n, _ := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,98 @@
+package alerts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractJSONPath(t *testing.T) {
+	var payload any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"event": {"type": "follow", "user": {"display-name": "Alice"}},
+		"items": [{"amount": 5}, {"amount": 7}]
+	}`), &payload))
+
+	tests := []struct {
+		path    string
+		want    any
+		wantErr bool
+	}{
+		{path: "$.event.type", want: "follow"},
+		{path: "event.type", want: "follow"},
+		{path: "$.event.user['display-name']", want: "Alice"},
+		{path: `$["event"]["user"]["display-name"]`, want: "Alice"},
+		{path: "$.items[1].amount", want: float64(7)},
+		{path: "$.items[-1].amount", want: float64(7)},
+		{path: "$.items[2].amount", wantErr: true},
+		{path: "$.event.missing", wantErr: true},
+		{path: "$.event.type.name", wantErr: true},
+		{path: "$.items[first]", wantErr: true},
+		{path: "$.event..type", wantErr: true},
+		{path: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ExtractJSONPath(payload, tt.path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"type":"follow"}`)
+	signature := Sign("secret", body)
+
+	assert.NoError(t, VerifySignature("secret", body, signature))
+	assert.NoError(t, VerifySignature("secret", body, "sha256="+signature))
+	assert.ErrorIs(t, VerifySignature("other", body, signature), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature("secret", []byte(`{"type":"raid"}`), signature), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature("secret", body, ""), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature("secret", body, "not-hex"), ErrInvalidSignature)
+}
+
+func TestParseWebhook(t *testing.T) {
+	hook := &domain.WebhookConfig{
+		Name:        "kofi",
+		Secret:      "secret",
+		TypePath:    "$.type",
+		TypeValues:  map[string]domain.AlertType{"Donation": domain.AlertDonation, "Subscription": domain.AlertSub},
+		UserPath:    "$.from_name",
+		AmountPath:  "$.amount",
+		MessagePath: "$.message",
+	}
+
+	alert, err := ParseWebhook(hook, []byte(`{"type":"Donation","from_name":"Alice","amount":"3.00","message":"Thanks!"}`))
+	require.NoError(t, err)
+	require.NotNil(t, alert)
+	assert.Equal(t, domain.AlertDonation, alert.Type)
+	assert.Equal(t, "kofi", alert.Source)
+	assert.Equal(t, "Alice", alert.User)
+	assert.Equal(t, 3.0, alert.Amount)
+	assert.Equal(t, "Thanks!", alert.Message)
+
+	alert, err = ParseWebhook(hook, []byte(`{"type":"Shop Order","from_name":"Bob"}`))
+	assert.NoError(t, err)
+	assert.Nil(t, alert, "unmapped event types are ignored")
+
+	_, err = ParseWebhook(hook, []byte(`{"from_name":"Bob"}`))
+	assert.Error(t, err, "missing type")
+
+	_, err = ParseWebhook(hook, []byte(`not json`))
+	assert.Error(t, err)
+
+	fixed := &domain.WebhookConfig{Name: "follows", Secret: "secret", Type: domain.AlertFollow, UserPath: "$.user"}
+	alert, err = ParseWebhook(fixed, []byte(`{"viewer":"Carol"}`))
+	require.NoError(t, err)
+	assert.Equal(t, domain.AlertFollow, alert.Type)
+	assert.Empty(t, alert.User, "missing optional fields stay empty")
+}
@@ -4,15 +4,20 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	"tinygo.org/x/bluetooth"
+
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/metrics"
 )
 
 // Adapter wraps the tinygo bluetooth adapter and provides high-level operations
 type Adapter struct {
 	adapter *bluetooth.Adapter
+	log     *slog.Logger
 }
 
 // NewAdapter creates a new Bluetooth adapter
@@ -29,6 +34,7 @@ func NewAdapter() (*Adapter, error) {
 
 	return &Adapter{
 		adapter: adapter,
+		log:     logging.Source("ble"),
 	}, nil
 }
 
@@ -44,6 +50,11 @@ func (a *Adapter) Scan(ctx context.Context, timeout time.Duration) ([]ScanResult
 	results := make([]ScanResult, 0)
 	seen := make(map[string]bool) // Track seen devices to avoid duplicates
 
+	started := time.Now()
+	defer func() {
+		metrics.BLEScanDuration.With().Observe(time.Since(started).Seconds())
+	}()
+
 	scanCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -91,6 +102,7 @@ func (a *Adapter) Scan(ctx context.Context, timeout time.Duration) ([]ScanResult
 	}
 
 	// Return empty array if no devices found (not an error)
+	metrics.BLEScanDevices.With().Set(float64(len(results)))
 	return results, nil
 }
 
@@ -101,55 +113,56 @@ type Connection struct {
 	address        string
 }
 
-func (a *Adapter) Connect(ctx context.Context, address string, timeout time.Duration) (*Connection, error) {
-	fmt.Println("🔍 CONNECT START", address)
+func (a *Adapter) Connect(ctx context.Context, address string, timeout time.Duration) (conn *Connection, err error) {
+	a.log.Debug("Connecting", "device", address)
+
+	started := time.Now()
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = ErrorType(err)
+		}
+		metrics.BLEConnectDuration.With(result).Observe(time.Since(started).Seconds())
+	}()
 
 	var addr bluetooth.Address
 	addr.Set(address)
 
 	dev, err := a.adapter.Connect(addr, bluetooth.ConnectionParams{})
 	if err != nil {
-		fmt.Println("❌ CONNECT FAILED:", err)
 		return nil, fmt.Errorf("%w: %v", ErrConnectionFailed, err)
 	}
-	fmt.Println("✓ CONNECT OK!")
+	a.log.Debug("Connected, discovering services", "device", address)
 
 	services, err := dev.DiscoverServices(nil)
 	if err != nil {
-		fmt.Println("❌ SERVICES FAILED:", err)
 		return nil, fmt.Errorf("%w: %v", ErrServiceNotFound, err)
 	}
 
-	fmt.Println("Found", len(services), "services")
-
 	var writeChar bluetooth.DeviceCharacteristic
 
-	for i, svc := range services {
-		fmt.Println("SERVICE", i, ":", svc.UUID().String())
-
+	for _, svc := range services {
 		chars, err := svc.DiscoverCharacteristics(nil)
 		if err != nil {
-			fmt.Println("  ❌ CHARS FAILED:", err)
+			a.log.Debug("Characteristic discovery failed", "device", address, "service", svc.UUID().String(), "error", err)
 			continue
 		}
-		fmt.Println("  Found", len(chars), "chars")
+		a.log.Debug("Discovered service", "device", address, "service", svc.UUID().String(), "characteristics", len(chars))
 
 		for j, char := range chars {
 			uuidStr := char.UUID().String()
-			fmt.Println("    CHAR", j, ":", uuidStr)
 
-			// NOTIFY AUF fff4 AKTIVIEREN (wichtig!)
+			// The lamps only accept writes once notifications on fff4 are enabled
 			if strings.Contains(uuidStr, "fff4") {
 				char.EnableNotifications(func(buf []byte) {
-					fmt.Println("NOTIFY:", hex.EncodeToString(buf))
+					a.log.Debug("Notification", "device", address, "frame", hex.EncodeToString(buf))
 				})
-				fmt.Println("    ✓ NOTIFY ENABLED!")
 				time.Sleep(50 * time.Millisecond) // Brief handshake wait
 			}
 
 			if j == 1 {
 				writeChar = char
-				fmt.Println("    → WRITE CHAR:", uuidStr)
+				a.log.Debug("Using write characteristic", "device", address, "characteristic", uuidStr)
 			}
 		}
 
@@ -164,18 +177,18 @@ func (a *Adapter) Connect(ctx context.Context, address string, timeout time.Dura
 
 // Write writes data to the device characteristic
 func (a *Adapter) Write(ctx context.Context, conn *Connection, data []byte) error {
-	fmt.Println("Sending:", hex.EncodeToString(data))
+	a.log.Debug("Writing frame", "device", conn.address, "frame", hex.EncodeToString(data))
 
 	for i := 0; i < 3; i++ {
+		started := time.Now()
 		_, err := conn.characteristic.WriteWithoutResponse(data)
+		metrics.BLEWriteDuration.With().Observe(time.Since(started).Seconds())
 		if err != nil {
 			return fmt.Errorf("%w: %v", ErrWriteFailed, err)
 		}
-		fmt.Println("Write", i+1, "OK")
 		time.Sleep(20 * time.Millisecond) // Small delay between writes
 	}
 
-	fmt.Println("✓ All writes successful!")
 	time.Sleep(50 * time.Millisecond) // Minimal delay for effect
 	return nil
 }
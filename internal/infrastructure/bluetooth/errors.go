@@ -1,6 +1,9 @@
 package bluetooth
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 // Infrastructure errors - errors related to BLE operations
 var (
@@ -23,3 +26,24 @@ var (
 	ErrServiceNotFound    = errors.New("service not found")
 	ErrCharacteristicNotFound = errors.New("characteristic not found")
 )
+
+// ErrorType classifies an error for metrics, e.g. "connection_failed" or "timeout"
+func ErrorType(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, ErrConnectionTimeout):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, ErrConnectionFailed):
+		return "connection_failed"
+	case errors.Is(err, ErrServiceNotFound), errors.Is(err, ErrCharacteristicNotFound):
+		return "discovery_failed"
+	case errors.Is(err, ErrWriteFailed):
+		return "write_failed"
+	case errors.Is(err, ErrDisconnectFailed):
+		return "disconnect_failed"
+	}
+	return "other"
+}
@@ -0,0 +1,248 @@
+package console
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// DefaultUsername is used for lines typed without a "user:" prefix
+const DefaultUsername = "console"
+
+var (
+	stdinOnce  sync.Once
+	stdinLines chan string
+)
+
+// StdinLines returns the lines typed into the terminal.
+// Stdin is read by a single goroutine so sources can be recreated without losing input.
+func StdinLines() <-chan string {
+	stdinOnce.Do(func() {
+		stdinLines = make(chan string)
+		go func() {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				stdinLines <- scanner.Text()
+			}
+			close(stdinLines)
+		}()
+	})
+	return stdinLines
+}
+
+// ChatSource simulates a chat from typed lines.
+//
+// Each line is a chat message, optionally prefixed with the roles and name of the sender:
+//
+//	!lamp red
+//	alice: !lamp rainbow
+//	sub vip bob: !lamp strobe
+//
+// Known roles are broadcaster, mod, vip, sub and follower.
+type ChatSource struct {
+	label           string
+	lines           <-chan string
+	out             io.Writer
+	messageHandler  domain.ChatCommandHandler
+	stateHandler    domain.ConnectionStateHandler
+	presenceHandler domain.ChatPresenceHandler
+	status          domain.ConnectionStatus
+	cancel          context.CancelFunc
+	mu              sync.RWMutex
+}
+
+// NewChatSource creates a console chat reading lines from the channel and writing replies to out
+func NewChatSource(label string, lines <-chan string, out io.Writer) *ChatSource {
+	return &ChatSource{
+		label:  label,
+		lines:  lines,
+		out:    out,
+		status: domain.NewConnectionStatus(domain.ConnectionDisconnected),
+	}
+}
+
+// SetMessageHandler sets the handler for lamp commands
+func (s *ChatSource) SetMessageHandler(handler domain.ChatCommandHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messageHandler = handler
+}
+
+// SetStateHandler sets the handler for connection state changes
+func (s *ChatSource) SetStateHandler(handler domain.ConnectionStateHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stateHandler = handler
+}
+
+// SetPresenceHandler sets the handler for viewer activity
+func (s *ChatSource) SetPresenceHandler(handler domain.ChatPresenceHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.presenceHandler = handler
+}
+
+// Connect starts reading lines in the background
+func (s *ChatSource) Connect(ctx context.Context) error {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.mu.Unlock()
+		return nil // Already running
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.mu.Unlock()
+
+	s.setStatus(domain.ConnectionJoined)
+	fmt.Fprintf(s.out, "[Console] Simulated chat %q ready, type messages like: alice: !lamp red\n", s.label)
+
+	go s.run(runCtx)
+
+	return nil
+}
+
+// run feeds lines to the message handler until disconnected or the input ends
+func (s *ChatSource) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-s.lines:
+			if !ok {
+				s.setStatus(domain.ConnectionDisconnected)
+				return
+			}
+			s.handleLine(line)
+		}
+	}
+}
+
+// handleLine parses a typed line, reports the sender as active and passes lamp commands on
+func (s *ChatSource) handleLine(line string) {
+	s.mu.RLock()
+	handler := s.messageHandler
+	presenceHandler := s.presenceHandler
+	s.mu.RUnlock()
+
+	if displayName, _, message := splitSender(line); message != "" && presenceHandler != nil {
+		presenceHandler(&domain.ChatPresence{
+			Platform:    domain.PlatformConsole,
+			Username:    strings.ToLower(displayName),
+			DisplayName: displayName,
+			Event:       domain.PresenceMessage,
+			Timestamp:   time.Now(),
+		})
+	}
+
+	cmd, ok := ParseLine(line)
+	if ok && handler != nil {
+		handler(cmd)
+	}
+}
+
+// Disconnect stops reading lines
+func (s *ChatSource) Disconnect() error {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	s.setStatus(domain.ConnectionDisconnected)
+	return nil
+}
+
+// Status returns the current connection status
+func (s *ChatSource) Status() domain.ConnectionStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// SendMessage prints a bot reply
+func (s *ChatSource) SendMessage(message string) {
+	fmt.Fprintf(s.out, "[Console] %s: %s\n", s.label, message)
+}
+
+// setStatus stores a new state and notifies the state handler
+func (s *ChatSource) setStatus(state domain.ConnectionState) {
+	status := domain.NewConnectionStatus(state)
+
+	s.mu.Lock()
+	s.status = status
+	handler := s.stateHandler
+	s.mu.Unlock()
+
+	if handler != nil {
+		handler(status)
+	}
+}
+
+// ParseLine turns a typed line into a lamp command.
+// It returns false if the line is not a lamp command.
+func ParseLine(line string) (*domain.ChatCommand, bool) {
+	displayName, roles, message := splitSender(line)
+
+	command, err := domain.ParseTwitchCommand(message)
+	if err != nil {
+		return nil, false
+	}
+
+	cmd := &domain.ChatCommand{
+		Platform:    domain.PlatformConsole,
+		Username:    strings.ToLower(displayName),
+		DisplayName: displayName,
+		Command:     command,
+		Timestamp:   time.Now(),
+	}
+
+	for _, role := range roles {
+		switch strings.ToLower(role) {
+		case "broadcaster":
+			cmd.IsBroadcaster = true
+			cmd.IsMod = true
+		case "mod", "moderator":
+			cmd.IsMod = true
+		case "vip":
+			cmd.IsVIP = true
+		case "sub", "subscriber":
+			cmd.IsSub = true
+			cmd.SubTier = 1
+		case "follower":
+			cmd.IsFollower = true
+		}
+	}
+	cmd.UserID = cmd.Username
+
+	return cmd, true
+}
+
+// splitSender splits a typed line into the sender name, the sender roles and the message.
+// Lines without a "user:" prefix are sent by DefaultUsername.
+func splitSender(line string) (string, []string, string) {
+	sender, message := "", strings.TrimSpace(line)
+	if i := strings.Index(message, ":"); i > 0 && !strings.HasPrefix(message, "!") {
+		sender, message = message[:i], strings.TrimSpace(message[i+1:])
+	}
+
+	fields := strings.Fields(sender)
+	if len(fields) == 0 {
+		return DefaultUsername, nil, message
+	}
+	return fields[len(fields)-1], fields[:len(fields)-1], message
+}
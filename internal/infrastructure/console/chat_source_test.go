@@ -0,0 +1,105 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		ok       bool
+		username string
+		command  string
+		role     domain.Role
+	}{
+		{
+			name:     "bare command",
+			line:     "!lamp red",
+			ok:       true,
+			username: DefaultUsername,
+			command:  "red",
+			role:     domain.RoleEveryone,
+		},
+		{
+			name:     "named sender",
+			line:     "Alice: !lamp Rainbow",
+			ok:       true,
+			username: "alice",
+			command:  "rainbow",
+			role:     domain.RoleEveryone,
+		},
+		{
+			name:     "sender with roles",
+			line:     "sub vip bob: !lamp strobe",
+			ok:       true,
+			username: "bob",
+			command:  "strobe",
+			role:     domain.RoleVIP,
+		},
+		{
+			name:     "moderator",
+			line:     "mod carol: !lamp panic",
+			ok:       true,
+			username: "carol",
+			command:  "panic",
+			role:     domain.RoleModerator,
+		},
+		{
+			name: "regular chat message",
+			line: "dave: hello chat",
+		},
+		{
+			name: "empty line",
+			line: "   ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, ok := ParseLine(tt.line)
+			require.Equal(t, tt.ok, ok)
+			if !ok {
+				return
+			}
+
+			assert.Equal(t, domain.PlatformConsole, cmd.Platform)
+			assert.Equal(t, tt.username, cmd.Username)
+			assert.Equal(t, tt.command, cmd.Command)
+			assert.Equal(t, tt.role, cmd.Role())
+		})
+	}
+}
+
+func TestChatSource(t *testing.T) {
+	lines := make(chan string)
+	var out bytes.Buffer
+	source := NewChatSource("test", lines, &out)
+
+	commands := make(chan *domain.ChatCommand, 1)
+	source.SetMessageHandler(func(cmd *domain.ChatCommand) {
+		commands <- cmd
+	})
+
+	require.NoError(t, source.Connect(context.Background()))
+	assert.Equal(t, domain.ConnectionJoined, source.Status().State)
+
+	lines <- "alice: !lamp blue"
+	select {
+	case cmd := <-commands:
+		assert.Equal(t, "alice", cmd.Username)
+		assert.Equal(t, "blue", cmd.Command)
+	case <-time.After(time.Second):
+		t.Fatal("command was not delivered")
+	}
+
+	require.NoError(t, source.Disconnect())
+	assert.Equal(t, domain.ConnectionDisconnected, source.Status().State)
+}
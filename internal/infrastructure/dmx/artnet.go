@@ -0,0 +1,45 @@
+package dmx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Art-Net packet layout
+const (
+	artNetHeaderSize = 18
+	opDMX            = 0x5000
+)
+
+// artNetID starts every Art-Net packet
+var artNetID = []byte("Art-Net\x00")
+
+// ParseArtNet decodes an ArtDmx packet. Other Art-Net packets return ErrNotDMX.
+func ParseArtNet(packet []byte) (Frame, error) {
+	if len(packet) < 10 || !bytes.Equal(packet[:8], artNetID) {
+		return Frame{}, fmt.Errorf("not an Art-Net packet")
+	}
+
+	opcode := binary.LittleEndian.Uint16(packet[8:10])
+	if opcode != opDMX {
+		return Frame{}, ErrNotDMX
+	}
+	if len(packet) < artNetHeaderSize {
+		return Frame{}, fmt.Errorf("truncated ArtDmx packet")
+	}
+
+	// 15 bit port address: net, sub-net and universe
+	universe := int(binary.LittleEndian.Uint16(packet[14:16]) & 0x7fff)
+	length := int(binary.BigEndian.Uint16(packet[16:18]))
+	if length > 512 || artNetHeaderSize+length > len(packet) {
+		return Frame{}, fmt.Errorf("invalid ArtDmx length %d", length)
+	}
+
+	return Frame{
+		Protocol: ProtocolArtNet,
+		Universe: universe,
+		Data:     packet[artNetHeaderSize : artNetHeaderSize+length],
+		Priority: 100,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package dmx
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// artNetPacket builds an ArtDmx packet like QLC+ sends
+func artNetPacket(universe int, data []byte) []byte {
+	packet := append([]byte(nil), artNetID...)
+	packet = binary.LittleEndian.AppendUint16(packet, opDMX)
+	packet = append(packet, 0, 14, 1, 0) // Protocol version, sequence, physical
+	packet = binary.LittleEndian.AppendUint16(packet, uint16(universe))
+	packet = binary.BigEndian.AppendUint16(packet, uint16(len(data)))
+	return append(packet, data...)
+}
+
+// sacnPacket builds an E1.31 data packet
+func sacnPacket(universe int, data []byte, options byte) []byte {
+	packet := make([]byte, sacnHeaderSize, sacnHeaderSize+len(data))
+	binary.BigEndian.PutUint16(packet[0:2], 0x0010)
+	copy(packet[4:16], acnID)
+	binary.BigEndian.PutUint32(packet[18:22], sacnRootVector)
+	binary.BigEndian.PutUint32(packet[40:44], sacnFramingVector)
+	copy(packet[44:108], "xLights")
+	packet[108] = 100
+	packet[sacnOptionsOffset] = options
+	binary.BigEndian.PutUint16(packet[sacnUniverseOffset:], uint16(universe))
+	packet[117] = sacnDMPVector
+	packet[118] = 0xa1
+	binary.BigEndian.PutUint16(packet[121:123], 1)
+	binary.BigEndian.PutUint16(packet[sacnCountOffset:], uint16(len(data)+1))
+	return append(packet, data...)
+}
+
+func TestParseArtNet(t *testing.T) {
+	frame, err := ParseArtNet(artNetPacket(0x0123, []byte{255, 128, 0}))
+	require.NoError(t, err)
+	assert.Equal(t, ProtocolArtNet, frame.Protocol)
+	assert.Equal(t, 0x0123, frame.Universe)
+	assert.Equal(t, []byte{255, 128, 0}, frame.Data)
+
+	poll := append(append([]byte(nil), artNetID...), 0x00, 0x20, 0, 14, 0, 0)
+	_, err = ParseArtNet(poll)
+	assert.ErrorIs(t, err, ErrNotDMX)
+
+	_, err = ParseArtNet(artNetPacket(1, []byte{1, 2, 3})[:19])
+	assert.Error(t, err, "length beyond the packet")
+
+	_, err = ParseArtNet([]byte("hello"))
+	assert.Error(t, err)
+}
+
+func TestParseSACN(t *testing.T) {
+	frame, err := ParseSACN(sacnPacket(7, []byte{10, 20, 30}, 0))
+	require.NoError(t, err)
+	assert.Equal(t, ProtocolSACN, frame.Protocol)
+	assert.Equal(t, 7, frame.Universe)
+	assert.Equal(t, []byte{10, 20, 30}, frame.Data)
+	assert.Equal(t, "xLights", frame.Source)
+	assert.Equal(t, 100, frame.Priority)
+	assert.False(t, frame.Terminated)
+
+	frame, err = ParseSACN(sacnPacket(7, []byte{10}, sacnTerminated))
+	require.NoError(t, err)
+	assert.True(t, frame.Terminated)
+
+	_, err = ParseSACN(sacnPacket(7, []byte{10}, sacnPreview))
+	assert.ErrorIs(t, err, ErrNotDMX, "preview data is not shown")
+
+	_, err = ParseSACN(artNetPacket(1, []byte{1}))
+	assert.Error(t, err)
+}
+
+func TestSACNGroup(t *testing.T) {
+	assert.Equal(t, "239.255.1.2", SACNGroup(258).String())
+}
+
+func TestServe(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	frames := make(chan Frame, 2)
+	done := make(chan error, 1)
+	go func() { done <- Serve(conn, ParseArtNet, func(frame Frame) { frames <- frame }) }()
+
+	client, err := net.Dial("udp4", conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Write([]byte("garbage"))
+	require.NoError(t, err)
+	_, err = client.Write(artNetPacket(3, []byte{1, 2, 3}))
+	require.NoError(t, err)
+
+	select {
+	case frame := <-frames:
+		assert.Equal(t, 3, frame.Universe)
+		assert.Equal(t, []byte{1, 2, 3}, frame.Data)
+		assert.Equal(t, client.LocalAddr().String(), frame.Source)
+	case <-time.After(time.Second):
+		t.Fatal("no frame received")
+	}
+
+	conn.Close()
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,82 @@
+package dmx
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+)
+
+// Protocols a frame can arrive over
+const (
+	ProtocolArtNet = "artnet"
+	ProtocolSACN   = "sacn"
+)
+
+// ErrNotDMX is returned for packets that are valid but carry no DMX levels, e.g. an ArtPoll
+var ErrNotDMX = errors.New("packet carries no DMX data")
+
+// Frame is one DMX universe received from a console
+type Frame struct {
+	Protocol   string
+	Universe   int
+	Data       []byte // Channel levels, channel 1 first
+	Source     string // Sender name (sACN) or address
+	Priority   int    // sACN priority, 100 for Art-Net
+	Terminated bool   // sACN stream terminated, the console stopped sending
+}
+
+// Parser decodes a packet into a frame
+type Parser func(packet []byte) (Frame, error)
+
+// Serve reads packets from conn and passes the DMX frames to handle until conn
+// is closed. Packets that fail to parse are dropped.
+func Serve(conn net.PacketConn, parse Parser, handle func(frame Frame)) error {
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		frame, err := parse(buf[:n])
+		if errors.Is(err, ErrNotDMX) {
+			continue
+		}
+		if err != nil {
+			logging.Source("dmx").Debug("Dropping packet", "remote", addr.String(), "error", err)
+			continue
+		}
+
+		// Frames only borrow the read buffer
+		frame.Data = append([]byte(nil), frame.Data...)
+		if frame.Source == "" {
+			frame.Source = addr.String()
+		}
+		handle(frame)
+	}
+}
+
+// ListenArtNet listens for Art-Net on all interfaces, consoles broadcast or unicast to the port
+func ListenArtNet(port int) (net.PacketConn, error) {
+	conn, err := net.ListenPacket("udp4", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for Art-Net on port %d: %w", port, err)
+	}
+	return conn, nil
+}
+
+// ListenSACN joins the multicast group of a universe. Unicast frames sent to
+// the port arrive as well, frames of other universes must be filtered out.
+func ListenSACN(universe, port int) (net.PacketConn, error) {
+	group := &net.UDPAddr{IP: SACNGroup(universe), Port: port}
+	conn, err := net.ListenMulticastUDP("udp4", nil, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join sACN universe %d: %w", universe, err)
+	}
+	return conn, nil
+}
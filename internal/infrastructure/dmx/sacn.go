@@ -0,0 +1,73 @@
+package dmx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// E1.31 data packet layout
+const (
+	sacnRootVector     = 0x00000004
+	sacnFramingVector  = 0x00000002
+	sacnDMPVector      = 0x02
+	sacnHeaderSize     = 126 // Up to and including the start code
+	sacnOptionsOffset  = 112
+	sacnUniverseOffset = 113
+	sacnCountOffset    = 123
+	sacnTerminated     = 0x40
+	sacnPreview        = 0x80
+)
+
+// acnID identifies ACN packets
+var acnID = []byte("ASC-E1.17\x00\x00\x00")
+
+// SACNGroup returns the multicast group of a universe
+func SACNGroup(universe int) net.IP {
+	return net.IPv4(239, 255, byte(universe>>8), byte(universe))
+}
+
+// ParseSACN decodes an E1.31 data packet. Preview data and sync or discovery
+// packets return ErrNotDMX.
+func ParseSACN(packet []byte) (Frame, error) {
+	if len(packet) < 22 || !bytes.Equal(packet[4:16], acnID) {
+		return Frame{}, fmt.Errorf("not an sACN packet")
+	}
+
+	if binary.BigEndian.Uint32(packet[18:22]) != sacnRootVector {
+		return Frame{}, ErrNotDMX
+	}
+	if len(packet) < sacnHeaderSize {
+		return Frame{}, fmt.Errorf("truncated sACN packet")
+	}
+	if binary.BigEndian.Uint32(packet[40:44]) != sacnFramingVector {
+		return Frame{}, ErrNotDMX
+	}
+	if packet[117] != sacnDMPVector {
+		return Frame{}, fmt.Errorf("invalid sACN DMP vector %d", packet[117])
+	}
+
+	options := packet[sacnOptionsOffset]
+	if options&sacnPreview != 0 {
+		return Frame{}, ErrNotDMX
+	}
+
+	// The property count includes the start code
+	count := int(binary.BigEndian.Uint16(packet[sacnCountOffset : sacnCountOffset+2]))
+	if count < 1 || count > 513 || sacnHeaderSize-1+count > len(packet) {
+		return Frame{}, fmt.Errorf("invalid sACN property count %d", count)
+	}
+	if packet[sacnHeaderSize-1] != 0 {
+		return Frame{}, ErrNotDMX // Alternate start codes carry no levels
+	}
+
+	return Frame{
+		Protocol:   ProtocolSACN,
+		Universe:   int(binary.BigEndian.Uint16(packet[sacnUniverseOffset : sacnUniverseOffset+2])),
+		Data:       packet[sacnHeaderSize : sacnHeaderSize-1+count],
+		Source:     string(bytes.TrimRight(packet[44:108], "\x00")),
+		Priority:   int(packet[108]),
+		Terminated: options&sacnTerminated != 0,
+	}, nil
+}
@@ -0,0 +1,403 @@
+package hue
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// Bridge release the API mimics
+const (
+	APIVersion       = "1.53.0"
+	SWVersion        = "1953188020"
+	DatastoreVersion = "131"
+	ModelID          = "BSB002"
+)
+
+// Hue error types
+const (
+	ErrorUnauthorized        = 1
+	ErrorInvalidJSON         = 2
+	ErrorResourceUnavailable = 3
+	ErrorMissingParameters   = 5
+	ErrorInvalidValue        = 7
+	ErrorLinkButton          = 101
+)
+
+// ErrLinkButtonNotPressed is returned by a backend when an app pairs without the link button pressed
+var ErrLinkButtonNotPressed = errors.New("link button not pressed")
+
+// Backend provides the lights and paired apps of a bridge
+type Backend interface {
+	// Lights returns the lamps ordered by light ID
+	Lights() []Lamp
+	// SetLightState applies a change to the lamp with the address
+	SetLightState(ctx context.Context, address string, change domain.StateChange) error
+	// Authorized returns whether an app with the username is paired
+	Authorized(username string) bool
+	// CreateUser pairs an app, failing with ErrLinkButtonNotPressed outside the link window
+	CreateUser(deviceType string) (string, error)
+}
+
+// Response is an entry of a Hue response list, holding either "success" or "error"
+type Response map[string]any
+
+// Success returns a success entry
+func Success(address string, value any) Response {
+	return Response{"success": map[string]any{address: value}}
+}
+
+// Error is a Hue API error
+type Error struct {
+	Type        int    `json:"type"`
+	Address     string `json:"address"`
+	Description string `json:"description"`
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return fmt.Sprintf("hue error %d at %s: %s", e.Type, e.Address, e.Description)
+}
+
+// Response returns the error entry
+func (e *Error) Response() Response {
+	return Response{"error": e}
+}
+
+// Bridge serves the Hue v1 REST API and the UPnP description over a backend
+type Bridge struct {
+	backend Backend
+	name    string
+	mac     string
+	router  chi.Router
+}
+
+// NewBridge creates a bridge with a name and a 12 digit hex MAC address
+func NewBridge(backend Backend, name, mac string) *Bridge {
+	b := &Bridge{
+		backend: backend,
+		name:    name,
+		mac:     strings.ToLower(mac),
+	}
+
+	r := chi.NewRouter()
+	r.Get("/description.xml", b.description)
+	r.Route("/api", func(r chi.Router) {
+		r.Post("/", b.createUser)
+		r.Get("/config", b.shortConfig)
+		r.Route("/{user}", func(r chi.Router) {
+			r.Get("/", b.authorized(b.datastore))
+			r.Get("/config", b.config)
+			r.Get("/lights", b.authorized(b.lights))
+			r.Get("/lights/{id}", b.authorized(b.light))
+			r.Put("/lights/{id}/state", b.authorized(b.setLightState))
+			r.Get("/groups", b.authorized(b.empty))
+			r.Get("/scenes", b.authorized(b.empty))
+			r.Get("/schedules", b.authorized(b.empty))
+			r.Get("/sensors", b.authorized(b.empty))
+			r.Get("/rules", b.authorized(b.empty))
+		})
+	})
+	b.router = r
+
+	return b
+}
+
+// ServeHTTP implements http.Handler
+func (b *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.router.ServeHTTP(w, r)
+}
+
+// BridgeID returns the bridge ID apps identify a bridge by, derived from the MAC address
+func BridgeID(mac string) string {
+	mac = strings.ToUpper(mac)
+	return mac[:6] + "FFFE" + mac[6:]
+}
+
+// UUID returns the UPnP device UUID of a bridge
+func UUID(mac string) string {
+	return "2f402f80-da50-11e1-9b23-" + strings.ToLower(mac)
+}
+
+// formatMAC formats the MAC address with colons
+func formatMAC(mac string) string {
+	parts := make([]string, 0, 6)
+	for i := 0; i+2 <= len(mac); i += 2 {
+		parts = append(parts, mac[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
+
+// writeJSON writes a response; like a real bridge, errors are sent with status 200
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a single error entry
+func writeError(w http.ResponseWriter, errorType int, address, description string) {
+	writeJSON(w, []Response{(&Error{Type: errorType, Address: address, Description: description}).Response()})
+}
+
+// writeUnavailable reports a resource that does not exist
+func writeUnavailable(w http.ResponseWriter, address string) {
+	writeError(w, ErrorResourceUnavailable, address, "resource, "+address+", not available")
+}
+
+// authorized rejects requests from apps that are not paired
+func (b *Bridge) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !b.backend.Authorized(chi.URLParam(r, "user")) {
+			writeError(w, ErrorUnauthorized, "/", "unauthorized user")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// createUser handles POST /api, pairing an app while the link button is pressed
+func (b *Bridge) createUser(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		DeviceType string `json:"devicetype"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, ErrorInvalidJSON, "/", "body contains invalid json")
+		return
+	}
+	if body.DeviceType == "" {
+		writeError(w, ErrorMissingParameters, "/", "invalid/missing parameters in body")
+		return
+	}
+
+	username, err := b.backend.CreateUser(body.DeviceType)
+	if errors.Is(err, ErrLinkButtonNotPressed) {
+		writeError(w, ErrorLinkButton, "", "link button not pressed")
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to pair: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, []Response{{"success": map[string]string{"username": username}}})
+}
+
+// shortConfig handles GET /api/config
+func (b *Bridge) shortConfig(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, b.publicConfig())
+}
+
+// config handles GET /api/<user>/config, apps that are not paired get the public part
+func (b *Bridge) config(w http.ResponseWriter, r *http.Request) {
+	if !b.backend.Authorized(chi.URLParam(r, "user")) {
+		writeJSON(w, b.publicConfig())
+		return
+	}
+	writeJSON(w, b.fullConfig(r))
+}
+
+// datastore handles GET /api/<user>
+func (b *Bridge) datastore(w http.ResponseWriter, r *http.Request) {
+	empty := map[string]any{}
+	writeJSON(w, map[string]any{
+		"lights":        b.lightMap(),
+		"groups":        empty,
+		"config":        b.fullConfig(r),
+		"schedules":     empty,
+		"scenes":        empty,
+		"rules":         empty,
+		"sensors":       empty,
+		"resourcelinks": empty,
+	})
+}
+
+// lights handles GET /api/<user>/lights
+func (b *Bridge) lights(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, b.lightMap())
+}
+
+// light handles GET /api/<user>/lights/<id>
+func (b *Bridge) light(w http.ResponseWriter, r *http.Request) {
+	lamp, ok := b.lamp(r)
+	if !ok {
+		writeUnavailable(w, "/lights/"+chi.URLParam(r, "id"))
+		return
+	}
+	writeJSON(w, NewLight(lamp.Device))
+}
+
+// setLightState handles PUT /api/<user>/lights/<id>/state
+func (b *Bridge) setLightState(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	lamp, ok := b.lamp(r)
+	if !ok {
+		writeUnavailable(w, "/lights/"+id)
+		return
+	}
+
+	var update StateUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, ErrorInvalidJSON, "/lights/"+id+"/state", "body contains invalid json")
+		return
+	}
+
+	prefix := "/lights/" + id + "/state"
+	change, hueErr := update.Change(prefix, lamp.Device.State)
+	if hueErr != nil {
+		writeJSON(w, []Response{hueErr.Response()})
+		return
+	}
+
+	if change != (domain.StateChange{}) {
+		if err := b.backend.SetLightState(r.Context(), lamp.Device.Address, change); err != nil {
+			http.Error(w, fmt.Sprintf("Command failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	responses := update.Successes(prefix)
+	if responses == nil {
+		responses = []Response{}
+	}
+	writeJSON(w, responses)
+}
+
+// empty handles the resources the bridge has none of
+func (b *Bridge) empty(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{})
+}
+
+// lamp finds the lamp of the light ID in the path
+func (b *Bridge) lamp(r *http.Request) (Lamp, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		return Lamp{}, false
+	}
+	for _, lamp := range b.backend.Lights() {
+		if lamp.ID == id {
+			return lamp, true
+		}
+	}
+	return Lamp{}, false
+}
+
+// lightMap returns all lights keyed by light ID
+func (b *Bridge) lightMap() map[string]Light {
+	lights := make(map[string]Light)
+	for _, lamp := range b.backend.Lights() {
+		lights[strconv.Itoa(lamp.ID)] = NewLight(lamp.Device)
+	}
+	return lights
+}
+
+// publicConfig returns the config shown without pairing
+func (b *Bridge) publicConfig() map[string]any {
+	return map[string]any{
+		"name":             b.name,
+		"datastoreversion": DatastoreVersion,
+		"swversion":        SWVersion,
+		"apiversion":       APIVersion,
+		"mac":              formatMAC(b.mac),
+		"bridgeid":         BridgeID(b.mac),
+		"factorynew":       false,
+		"replacesbridgeid": nil,
+		"modelid":          ModelID,
+		"starterkitid":     "",
+	}
+}
+
+// fullConfig returns the config of paired apps, reporting the address the app reached us at
+func (b *Bridge) fullConfig(r *http.Request) map[string]any {
+	config := b.publicConfig()
+	now := time.Now()
+	config["ipaddress"] = requestHost(r)
+	config["netmask"] = "255.255.255.0"
+	config["gateway"] = "0.0.0.0"
+	config["dhcp"] = true
+	config["proxyaddress"] = "none"
+	config["proxyport"] = 0
+	config["UTC"] = now.UTC().Format("2006-01-02T15:04:05")
+	config["localtime"] = now.Format("2006-01-02T15:04:05")
+	config["timezone"] = now.Location().String()
+	config["zigbeechannel"] = 25
+	config["linkbutton"] = false
+	config["portalservices"] = false
+	return config
+}
+
+// requestHost returns the host of a request without the port
+func requestHost(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.Host); err == nil {
+		return host
+	}
+	return r.Host
+}
+
+// description is the UPnP device description apps fetch after discovery
+type description struct {
+	XMLName     xml.Name `xml:"urn:schemas-upnp-org:device-1-0 root"`
+	SpecVersion struct {
+		Major int `xml:"major"`
+		Minor int `xml:"minor"`
+	} `xml:"specVersion"`
+	URLBase string            `xml:"URLBase"`
+	Device  descriptionDevice `xml:"device"`
+}
+
+type descriptionDevice struct {
+	DeviceType       string `xml:"deviceType"`
+	FriendlyName     string `xml:"friendlyName"`
+	Manufacturer     string `xml:"manufacturer"`
+	ManufacturerURL  string `xml:"manufacturerURL"`
+	ModelDescription string `xml:"modelDescription"`
+	ModelName        string `xml:"modelName"`
+	ModelNumber      string `xml:"modelNumber"`
+	ModelURL         string `xml:"modelURL"`
+	SerialNumber     string `xml:"serialNumber"`
+	UDN              string `xml:"UDN"`
+	PresentationURL  string `xml:"presentationURL"`
+}
+
+// description handles GET /description.xml
+func (b *Bridge) description(w http.ResponseWriter, r *http.Request) {
+	desc := description{
+		URLBase: "http://" + r.Host + "/",
+		Device: descriptionDevice{
+			DeviceType:       "urn:schemas-upnp-org:device:Basic:1",
+			FriendlyName:     fmt.Sprintf("%s (%s)", b.name, requestHost(r)),
+			Manufacturer:     "Signify",
+			ManufacturerURL:  "http://www.philips-hue.com",
+			ModelDescription: "Philips hue Personal Wireless Lighting",
+			ModelName:        "Philips hue bridge 2015",
+			ModelNumber:      ModelID,
+			ModelURL:         "http://www.philips-hue.com",
+			SerialNumber:     b.mac,
+			UDN:              "uuid:" + UUID(b.mac),
+			PresentationURL:  "index.html",
+		},
+	}
+	desc.SpecVersion.Major = 1
+
+	w.Header().Set("Content-Type", "text/xml")
+	w.Write([]byte(xml.Header))
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	enc.Encode(desc)
+}
+
+// SortLamps orders lamps by light ID
+func SortLamps(lamps []Lamp) {
+	sort.Slice(lamps, func(i, j int) bool { return lamps[i].ID < lamps[j].ID })
+}
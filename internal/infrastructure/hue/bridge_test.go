@@ -0,0 +1,182 @@
+package hue
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend records light changes and pairs apps while linking is set
+type fakeBackend struct {
+	mu      sync.Mutex
+	lamps   []Lamp
+	users   map[string]bool
+	linking bool
+	changes map[string]domain.StateChange
+}
+
+func newFakeBackend() *fakeBackend {
+	desk := domain.NewDevice("BE:27:EB:00:00:01", "Desk", -50)
+	desk.Connected = true
+	desk.State = domain.DeviceState{PowerOn: true, Brightness: 255, RGB: &domain.RGB{R: 255}}
+
+	return &fakeBackend{
+		lamps:   []Lamp{{ID: 1, Device: desk}},
+		users:   make(map[string]bool),
+		changes: make(map[string]domain.StateChange),
+	}
+}
+
+func (f *fakeBackend) Lights() []Lamp {
+	return f.lamps
+}
+
+func (f *fakeBackend) SetLightState(ctx context.Context, address string, change domain.StateChange) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.changes[address] = change
+	return nil
+}
+
+func (f *fakeBackend) Authorized(username string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.users[username]
+}
+
+func (f *fakeBackend) CreateUser(deviceType string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.linking {
+		return "", ErrLinkButtonNotPressed
+	}
+	f.users["paireduser"] = true
+	return "paireduser", nil
+}
+
+func request(t *testing.T, server *httptest.Server, method, path, body string) string {
+	t.Helper()
+
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestBridgePairing(t *testing.T) {
+	backend := newFakeBackend()
+	server := httptest.NewServer(NewBridge(backend, "LampControl", "0a1b2c3d4e5f"))
+	defer server.Close()
+
+	body := request(t, server, http.MethodGet, "/api/paireduser/lights", "")
+	assert.JSONEq(t, `[{"error":{"type":1,"address":"/","description":"unauthorized user"}}]`, body)
+
+	body = request(t, server, http.MethodPost, "/api", `{"devicetype":"app#phone"}`)
+	assert.JSONEq(t, `[{"error":{"type":101,"address":"","description":"link button not pressed"}}]`, body)
+
+	backend.linking = true
+	body = request(t, server, http.MethodPost, "/api", `{"devicetype":"app#phone"}`)
+	assert.JSONEq(t, `[{"success":{"username":"paireduser"}}]`, body)
+
+	var config map[string]any
+	require.NoError(t, json.Unmarshal([]byte(request(t, server, http.MethodGet, "/api/paireduser/config", "")), &config))
+	assert.Equal(t, "0A1B2CFFFE3D4E5F", config["bridgeid"])
+	assert.Equal(t, "0a:1b:2c:3d:4e:5f", config["mac"])
+	assert.Equal(t, "127.0.0.1", config["ipaddress"])
+}
+
+func TestBridgeLights(t *testing.T) {
+	backend := newFakeBackend()
+	backend.users["paireduser"] = true
+	server := httptest.NewServer(NewBridge(backend, "LampControl", "0a1b2c3d4e5f"))
+	defer server.Close()
+
+	var lights map[string]Light
+	require.NoError(t, json.Unmarshal([]byte(request(t, server, http.MethodGet, "/api/paireduser/lights", "")), &lights))
+	require.Contains(t, lights, "1")
+	assert.Equal(t, "Desk", lights["1"].Name)
+	assert.Equal(t, "be:27:eb:00:00:01:00:00-0b", lights["1"].UniqueID)
+	assert.True(t, lights["1"].State.Reachable)
+	assert.Equal(t, uint8(254), lights["1"].State.Bri)
+	assert.Equal(t, ColorModeXY, lights["1"].State.ColorMode)
+
+	body := request(t, server, http.MethodPut, "/api/paireduser/lights/1/state", `{"on":true,"bri":127,"ct":500}`)
+	assert.JSONEq(t, `[
+		{"success":{"/lights/1/state/on":true}},
+		{"success":{"/lights/1/state/bri":127}},
+		{"success":{"/lights/1/state/ct":500}}
+	]`, body)
+
+	on := true
+	half := uint8(128)
+	assert.Equal(t, domain.StateChange{PowerOn: &on, Brightness: &half, WhiteBalance: &domain.WhiteBalance{Warm: 255}}, backend.changes["BE:27:EB:00:00:01"])
+
+	body = request(t, server, http.MethodPut, "/api/paireduser/lights/1/state", `{"bri":300}`)
+	assert.JSONEq(t, `[{"error":{"type":7,"address":"/lights/1/state/bri","description":"invalid value, 300, for parameter, bri"}}]`, body)
+
+	body = request(t, server, http.MethodGet, "/api/paireduser/lights/9", "")
+	assert.JSONEq(t, `[{"error":{"type":3,"address":"/lights/9","description":"resource, /lights/9, not available"}}]`, body)
+}
+
+func TestStateUpdateChange(t *testing.T) {
+	on := true
+	rainbow := int(domain.EffectMap["rainbow"])
+	current := domain.DeviceState{PowerOn: true, Brightness: 255, RGB: &domain.RGB{R: 255}}
+
+	tests := []struct {
+		name    string
+		body    string
+		current domain.DeviceState
+		want    domain.StateChange
+		wantErr bool
+	}{
+		{name: "xy wins over ct", body: `{"xy":[0.7006,0.2993],"ct":300}`, current: domain.DeviceState{}, want: domain.StateChange{RGB: &domain.RGB{R: 255}}},
+		{name: "hue keeps saturation", body: `{"hue":43690}`, current: current, want: domain.StateChange{RGB: &domain.RGB{B: 255}}},
+		{name: "sat keeps hue", body: `{"sat":0}`, current: current, want: domain.StateChange{RGB: &domain.RGB{R: 255, G: 255, B: 255}}},
+		{name: "colorloop", body: `{"on":true,"effect":"colorloop"}`, current: current, want: domain.StateChange{PowerOn: &on, Effect: &rainbow}},
+		{name: "stop colorloop", body: `{"effect":"none"}`, current: domain.DeviceState{Effect: &rainbow}, want: domain.StateChange{RGB: &domain.RGB{R: 255, G: 255, B: 255}}},
+		{name: "alert is accepted", body: `{"alert":"select"}`, current: current, want: domain.StateChange{}},
+		{name: "invalid xy", body: `{"xy":[1.5,0.2]}`, current: current, wantErr: true},
+		{name: "invalid effect", body: `{"effect":"disco"}`, current: current, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var update StateUpdate
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &update))
+
+			got, hueErr := update.Change("/lights/1/state", tt.current)
+			if tt.wantErr {
+				assert.NotNil(t, hueErr)
+				return
+			}
+			require.Nil(t, hueErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDescription(t *testing.T) {
+	server := httptest.NewServer(NewBridge(newFakeBackend(), "LampControl", "0a1b2c3d4e5f"))
+	defer server.Close()
+
+	body := request(t, server, http.MethodGet, "/description.xml", "")
+	assert.Contains(t, body, "<URLBase>"+server.URL+"/</URLBase>")
+	assert.Contains(t, body, "<modelNumber>BSB002</modelNumber>")
+	assert.Contains(t, body, "<UDN>uuid:2f402f80-da50-11e1-9b23-0a1b2c3d4e5f</UDN>")
+}
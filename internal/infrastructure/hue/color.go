@@ -0,0 +1,157 @@
+package hue
+
+import (
+	"math"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// Ranges of the Hue light state values
+const (
+	MaxBri = 254
+	MaxHue = 65535
+	MaxSat = 254
+)
+
+// XYToRGB converts a CIE xy color at full brightness to RGB, using the wide
+// gamut conversion from the Hue developer documentation
+func XYToRGB(x, y float64) domain.RGB {
+	if y <= 0 {
+		return domain.RGB{R: 255, G: 255, B: 255}
+	}
+
+	z := 1 - x - y
+	bigY := 1.0
+	bigX := bigY / y * x
+	bigZ := bigY / y * z
+
+	r := bigX*1.656492 - bigY*0.354851 - bigZ*0.255038
+	g := -bigX*0.707196 + bigY*1.655397 + bigZ*0.036152
+	b := bigX*0.051713 - bigY*0.121364 + bigZ*1.011530
+
+	r, g, b = gammaCompress(r), gammaCompress(g), gammaCompress(b)
+
+	// Scale the brightest channel to full, brightness is set separately
+	peak := math.Max(r, math.Max(g, b))
+	if peak <= 0 {
+		return domain.RGB{}
+	}
+	return domain.RGB{R: toByte(r / peak), G: toByte(g / peak), B: toByte(b / peak)}
+}
+
+// RGBToXY converts an RGB color to CIE xy
+func RGBToXY(rgb domain.RGB) (float64, float64) {
+	r := gammaExpand(float64(rgb.R) / 255)
+	g := gammaExpand(float64(rgb.G) / 255)
+	b := gammaExpand(float64(rgb.B) / 255)
+
+	bigX := r*0.664511 + g*0.154324 + b*0.162028
+	bigY := r*0.283881 + g*0.668433 + b*0.047685
+	bigZ := r*0.000088 + g*0.072310 + b*0.986039
+
+	total := bigX + bigY + bigZ
+	if total == 0 {
+		// Black has no chromaticity, report the white point
+		return 0.3227, 0.3290
+	}
+	return round4(bigX / total), round4(bigY / total)
+}
+
+// HueSatToRGB converts a Hue hue (0-65535) and saturation (0-254) at full brightness to RGB
+func HueSatToRGB(hue uint16, sat uint8) domain.RGB {
+	h := float64(hue) / (MaxHue + 1) * 6
+	s := math.Min(float64(sat)/MaxSat, 1)
+
+	sector := math.Floor(h)
+	f := h - sector
+	p := 1 - s
+	q := 1 - s*f
+	t := 1 - s*(1-f)
+
+	var r, g, b float64
+	switch int(sector) % 6 {
+	case 0:
+		r, g, b = 1, t, p
+	case 1:
+		r, g, b = q, 1, p
+	case 2:
+		r, g, b = p, 1, t
+	case 3:
+		r, g, b = p, q, 1
+	case 4:
+		r, g, b = t, p, 1
+	default:
+		r, g, b = 1, p, q
+	}
+	return domain.RGB{R: toByte(r), G: toByte(g), B: toByte(b)}
+}
+
+// RGBToHueSat returns the Hue hue (0-65535) and saturation (0-254) of an RGB color
+func RGBToHueSat(rgb domain.RGB) (uint16, uint8) {
+	r := float64(rgb.R) / 255
+	g := float64(rgb.G) / 255
+	b := float64(rgb.B) / 255
+
+	peak := math.Max(r, math.Max(g, b))
+	low := math.Min(r, math.Min(g, b))
+	delta := peak - low
+	if peak == 0 || delta == 0 {
+		return 0, 0
+	}
+
+	var h float64
+	switch peak {
+	case r:
+		h = math.Mod((g-b)/delta, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+	if h < 0 {
+		h += 6
+	}
+
+	hue := math.Round(h / 6 * (MaxHue + 1))
+	if hue > MaxHue {
+		hue = 0
+	}
+	return uint16(hue), uint8(math.Round(delta / peak * MaxSat))
+}
+
+// BriToBrightness converts a Hue brightness (1-254) to a lamp brightness (0-255)
+func BriToBrightness(bri uint8) uint8 {
+	bri = max(1, min(MaxBri, bri))
+	return uint8(math.Round(float64(bri) * 255 / MaxBri))
+}
+
+// BrightnessToBri converts a lamp brightness (0-255) to a Hue brightness (1-254)
+func BrightnessToBri(brightness uint8) uint8 {
+	return max(1, uint8(math.Round(float64(brightness)*MaxBri/255)))
+}
+
+// gammaCompress applies the sRGB gamma to a linear channel
+func gammaCompress(v float64) float64 {
+	if v <= 0.0031308 {
+		return 12.92 * v
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// gammaExpand removes the sRGB gamma from a channel
+func gammaExpand(v float64) float64 {
+	if v > 0.04045 {
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return v / 12.92
+}
+
+// toByte scales a 0-1 channel to 0-255
+func toByte(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
+
+// round4 rounds to the four decimals Hue reports xy with
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
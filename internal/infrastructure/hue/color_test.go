@@ -0,0 +1,59 @@
+package hue
+
+import (
+	"testing"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXYRoundTrip(t *testing.T) {
+	colors := []domain.RGB{
+		{R: 255},
+		{G: 255},
+		{B: 255},
+		{R: 255, G: 128},
+		{R: 255, G: 255, B: 255},
+	}
+
+	for _, rgb := range colors {
+		x, y := RGBToXY(rgb)
+		got := XYToRGB(x, y)
+		assert.InDelta(t, rgb.R, got.R, 2, rgb.String())
+		assert.InDelta(t, rgb.G, got.G, 2, rgb.String())
+		assert.InDelta(t, rgb.B, got.B, 2, rgb.String())
+	}
+}
+
+func TestHueSat(t *testing.T) {
+	tests := []struct {
+		name string
+		hue  uint16
+		sat  uint8
+		want domain.RGB
+	}{
+		{name: "red", hue: 0, sat: 254, want: domain.RGB{R: 255}},
+		{name: "green", hue: 21845, sat: 254, want: domain.RGB{G: 255}},
+		{name: "blue", hue: 43690, sat: 254, want: domain.RGB{B: 255}},
+		{name: "white", hue: 12345, sat: 0, want: domain.RGB{R: 255, G: 255, B: 255}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HueSatToRGB(tt.hue, tt.sat))
+
+			hue, sat := RGBToHueSat(tt.want)
+			assert.Equal(t, tt.sat, sat)
+			if tt.sat > 0 {
+				assert.InDelta(t, tt.hue, hue, 1)
+			}
+		})
+	}
+}
+
+func TestBri(t *testing.T) {
+	assert.Equal(t, uint8(255), BriToBrightness(254))
+	assert.Equal(t, uint8(1), BriToBrightness(0), "bri 0 is the dimmest level, not off")
+	assert.Equal(t, uint8(254), BrightnessToBri(255))
+	assert.Equal(t, uint8(1), BrightnessToBri(0))
+}
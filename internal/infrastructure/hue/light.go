@@ -0,0 +1,263 @@
+package hue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// Identity reported for every light, apps use it to pick icons and capabilities
+const (
+	lightType         = "Extended color light"
+	lightModelID      = "LCT015"
+	lightProductName  = "Hue color lamp"
+	lightManufacturer = "Signify Netherlands B.V."
+	lightSWVersion    = "1.50.2_r30933"
+)
+
+// Color modes of a light state
+const (
+	ColorModeXY = "xy"
+	ColorModeHS = "hs"
+	ColorModeCT = "ct"
+)
+
+// Effects of a light state, the color loop runs the rainbow effect of the lamp
+const (
+	EffectNone      = "none"
+	EffectColorLoop = "colorloop"
+)
+
+// defaultCT is reported for lamps that are not showing white, a neutral white
+const defaultCT = 366
+
+// Lamp is a lamp exposed as a Hue light
+type Lamp struct {
+	ID     int
+	Device *domain.Device
+}
+
+// Light is a light resource of the Hue API
+type Light struct {
+	State            LightState `json:"state"`
+	Type             string     `json:"type"`
+	Name             string     `json:"name"`
+	ModelID          string     `json:"modelid"`
+	ManufacturerName string     `json:"manufacturername"`
+	ProductName      string     `json:"productname"`
+	UniqueID         string     `json:"uniqueid"`
+	SWVersion        string     `json:"swversion"`
+}
+
+// LightState is the state of a light resource
+type LightState struct {
+	On        bool       `json:"on"`
+	Bri       uint8      `json:"bri"`
+	Hue       uint16     `json:"hue"`
+	Sat       uint8      `json:"sat"`
+	Effect    string     `json:"effect"`
+	XY        [2]float64 `json:"xy"`
+	CT        int        `json:"ct"`
+	Alert     string     `json:"alert"`
+	ColorMode string     `json:"colormode"`
+	Mode      string     `json:"mode"`
+	Reachable bool       `json:"reachable"`
+}
+
+// NewLight describes a lamp as a Hue light
+func NewLight(device *domain.Device) Light {
+	return Light{
+		State:            NewLightState(device),
+		Type:             lightType,
+		Name:             device.Name,
+		ModelID:          lightModelID,
+		ManufacturerName: lightManufacturer,
+		ProductName:      lightProductName,
+		UniqueID:         UniqueID(device.Address),
+		SWVersion:        lightSWVersion,
+	}
+}
+
+// NewLightState describes the state of a lamp in Hue terms
+func NewLightState(device *domain.Device) LightState {
+	state := device.State
+	light := LightState{
+		On:        state.PowerOn,
+		Bri:       BrightnessToBri(state.Brightness),
+		Effect:    EffectNone,
+		CT:        defaultCT,
+		Alert:     "none",
+		ColorMode: ColorModeXY,
+		Mode:      "homeautomation",
+		Reachable: device.Connected,
+	}
+	light.XY[0], light.XY[1] = RGBToXY(domain.RGB{R: 255, G: 255, B: 255})
+
+	switch {
+	case state.Effect != nil:
+		if *state.Effect == int(domain.EffectMap["rainbow"]) {
+			light.Effect = EffectColorLoop
+		}
+		light.ColorMode = ColorModeHS
+		light.Sat = MaxSat
+	case state.WhiteBalance != nil:
+		light.CT = domain.WhiteBalanceToMireds(*state.WhiteBalance)
+		light.ColorMode = ColorModeCT
+	case state.RGB != nil:
+		light.XY[0], light.XY[1] = RGBToXY(*state.RGB)
+		light.Hue, light.Sat = RGBToHueSat(*state.RGB)
+	}
+	return light
+}
+
+// UniqueID returns the Zigbee style unique ID of a lamp, derived from its Bluetooth address
+func UniqueID(address string) string {
+	return strings.ToLower(address) + ":00:00-0b"
+}
+
+// StateUpdate is the body of PUT /api/<user>/lights/<id>/state
+type StateUpdate struct {
+	On             *bool       `json:"on,omitempty"`
+	Bri            *int        `json:"bri,omitempty"`
+	Hue            *int        `json:"hue,omitempty"`
+	Sat            *int        `json:"sat,omitempty"`
+	XY             *[2]float64 `json:"xy,omitempty"`
+	CT             *int        `json:"ct,omitempty"`
+	Effect         *string     `json:"effect,omitempty"`
+	Alert          *string     `json:"alert,omitempty"`
+	TransitionTime *int        `json:"transitiontime,omitempty"` // Accepted, lamps switch instantly
+}
+
+// Change converts the update to a lamp change. Like on a bridge, xy wins over
+// ct, which wins over hue and saturation. A missing hue or saturation is taken
+// from the current state. Alerts are accepted but do not flash the lamp.
+// Invalid values are reported as a Hue error for the parameter under prefix.
+func (u StateUpdate) Change(prefix string, current domain.DeviceState) (domain.StateChange, *Error) {
+	var change domain.StateChange
+
+	if u.On != nil {
+		on := *u.On
+		change.PowerOn = &on
+	}
+
+	if u.Bri != nil {
+		if *u.Bri < 0 || *u.Bri > MaxBri {
+			return change, invalidValue(prefix, "bri", *u.Bri)
+		}
+		brightness := BriToBrightness(uint8(*u.Bri))
+		change.Brightness = &brightness
+	}
+
+	if u.Hue != nil && (*u.Hue < 0 || *u.Hue > MaxHue) {
+		return change, invalidValue(prefix, "hue", *u.Hue)
+	}
+	if u.Sat != nil && (*u.Sat < 0 || *u.Sat > MaxSat) {
+		return change, invalidValue(prefix, "sat", *u.Sat)
+	}
+	if u.CT != nil && *u.CT <= 0 {
+		return change, invalidValue(prefix, "ct", *u.CT)
+	}
+	if u.XY != nil {
+		for _, v := range u.XY {
+			if v < 0 || v > 1 {
+				return change, invalidValue(prefix, "xy", *u.XY)
+			}
+		}
+	}
+
+	switch {
+	case u.XY != nil:
+		rgb := XYToRGB(u.XY[0], u.XY[1])
+		change.RGB = &rgb
+	case u.CT != nil:
+		wb := domain.MiredsToWhiteBalance(*u.CT)
+		change.WhiteBalance = &wb
+	case u.Hue != nil || u.Sat != nil:
+		hue, sat := uint16(0), uint8(MaxSat)
+		if current.RGB != nil {
+			hue, sat = RGBToHueSat(*current.RGB)
+		}
+		if u.Hue != nil {
+			hue = uint16(*u.Hue)
+		}
+		if u.Sat != nil {
+			sat = uint8(*u.Sat)
+		}
+		rgb := HueSatToRGB(hue, sat)
+		change.RGB = &rgb
+	}
+
+	if u.Effect != nil {
+		switch *u.Effect {
+		case EffectColorLoop:
+			rainbow := int(domain.EffectMap["rainbow"])
+			change.Effect = &rainbow
+			change.RGB = nil
+			change.WhiteBalance = nil
+		case EffectNone:
+			// Stopping the loop leaves a white light unless a color is set as well
+			if current.Effect != nil && change.RGB == nil && change.WhiteBalance == nil {
+				change.RGB = &domain.RGB{R: 255, G: 255, B: 255}
+			}
+		default:
+			return change, invalidValue(prefix, "effect", *u.Effect)
+		}
+	}
+
+	if u.Alert != nil {
+		switch *u.Alert {
+		case "none", "select", "lselect":
+		default:
+			return change, invalidValue(prefix, "alert", *u.Alert)
+		}
+	}
+
+	return change, nil
+}
+
+// Successes returns the success entries for the parameters of the update
+func (u StateUpdate) Successes(prefix string) []Response {
+	var responses []Response
+	add := func(name string, value any) {
+		responses = append(responses, Success(prefix+"/"+name, value))
+	}
+
+	if u.On != nil {
+		add("on", *u.On)
+	}
+	if u.Bri != nil {
+		add("bri", *u.Bri)
+	}
+	if u.Hue != nil {
+		add("hue", *u.Hue)
+	}
+	if u.Sat != nil {
+		add("sat", *u.Sat)
+	}
+	if u.XY != nil {
+		add("xy", *u.XY)
+	}
+	if u.CT != nil {
+		add("ct", max(domain.MinMireds, min(domain.MaxMireds, *u.CT)))
+	}
+	if u.Effect != nil {
+		add("effect", *u.Effect)
+	}
+	if u.Alert != nil {
+		add("alert", *u.Alert)
+	}
+	if u.TransitionTime != nil {
+		add("transitiontime", *u.TransitionTime)
+	}
+	return responses
+}
+
+// invalidValue reports an invalid value for a parameter
+func invalidValue(prefix, name string, value any) *Error {
+	return &Error{
+		Type:        ErrorInvalidValue,
+		Address:     prefix + "/" + name,
+		Description: fmt.Sprintf("invalid value, %v, for parameter, %s", value, name),
+	}
+}
@@ -0,0 +1,157 @@
+package hue
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+)
+
+// SSDPAddress is the multicast group apps search for bridges on
+const SSDPAddress = "239.255.255.250:1900"
+
+// Search targets a bridge answers to
+const (
+	searchAll        = "ssdp:all"
+	searchRootDevice = "upnp:rootdevice"
+	searchBasic      = "urn:schemas-upnp-org:device:basic:1"
+)
+
+// Responder answers SSDP searches with the location of the bridge description
+type Responder struct {
+	port int
+	mac  string
+
+	mu   sync.Mutex
+	conn net.PacketConn
+	done chan struct{}
+}
+
+// NewResponder creates a responder for a bridge on the HTTP port with the MAC address
+func NewResponder(port int, mac string) *Responder {
+	return &Responder{port: port, mac: strings.ToLower(mac)}
+}
+
+// Start joins the SSDP multicast group and answers searches until Stop
+func (r *Responder) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn != nil {
+		return fmt.Errorf("SSDP responder already running")
+	}
+
+	group, err := net.ResolveUDPAddr("udp4", SSDPAddress)
+	if err != nil {
+		return err
+	}
+	conn, err := net.ListenMulticastUDP("udp4", nil, group)
+	if err != nil {
+		return fmt.Errorf("failed to join SSDP group: %w", err)
+	}
+
+	r.conn = conn
+	r.done = make(chan struct{})
+	go func(done chan struct{}) {
+		defer close(done)
+		if err := r.Serve(conn); err != nil {
+			logging.Source("hue").Error("SSDP responder stopped", "error", err)
+		}
+	}(r.done)
+
+	return nil
+}
+
+// Stop leaves the multicast group
+func (r *Responder) Stop() {
+	r.mu.Lock()
+	conn, done := r.conn, r.done
+	r.conn, r.done = nil, nil
+	r.mu.Unlock()
+
+	if conn == nil {
+		return
+	}
+	conn.Close()
+	<-done
+}
+
+// Serve answers the searches read from conn until it is closed
+func (r *Responder) Serve(conn net.PacketConn) error {
+	buf := make([]byte, 2048)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, ok := searchTarget(buf[:n])
+		if !ok {
+			continue
+		}
+
+		if _, err := conn.WriteTo(r.response(target, localIP(addr)), addr); err != nil {
+			logging.Source("hue").Warn("Failed to answer SSDP search", "remote", addr.String(), "error", err)
+		}
+	}
+}
+
+// searchTarget returns the search target of an M-SEARCH the bridge answers to
+func searchTarget(packet []byte) (string, bool) {
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(packet)))
+	if err != nil || req.Method != "M-SEARCH" {
+		return "", false
+	}
+
+	target := req.Header.Get("ST")
+	switch strings.ToLower(target) {
+	case searchAll, searchRootDevice, searchBasic:
+		return target, true
+	}
+	return "", false
+}
+
+// response builds the search response
+func (r *Responder) response(target, ip string) []byte {
+	uuid := "uuid:" + UUID(r.mac)
+	st := target
+	if strings.EqualFold(target, searchAll) {
+		st = searchRootDevice
+	}
+
+	lines := []string{
+		"HTTP/1.1 200 OK",
+		"HOST: " + SSDPAddress,
+		"EXT:",
+		"CACHE-CONTROL: max-age=100",
+		fmt.Sprintf("LOCATION: http://%s/description.xml", net.JoinHostPort(ip, fmt.Sprint(r.port))),
+		"SERVER: Linux/3.14.0 UPnP/1.0 IpBridge/" + APIVersion,
+		"hue-bridgeid: " + BridgeID(r.mac),
+		"ST: " + st,
+		"USN: " + uuid + "::" + st,
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n\r\n")
+}
+
+// localIP returns the local address the host reaches the searching app from
+func localIP(addr net.Addr) string {
+	conn, err := net.Dial("udp", addr.String())
+	if err != nil {
+		return "127.0.0.1"
+	}
+	defer conn.Close()
+
+	if udp, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+		return udp.IP.String()
+	}
+	return "127.0.0.1"
+}
@@ -0,0 +1,52 @@
+package hue
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponderAnswersSearch(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	responder := NewResponder(8080, "0a1b2c3d4e5f")
+	done := make(chan error, 1)
+	go func() { done <- responder.Serve(conn) }()
+	defer func() {
+		conn.Close()
+		assert.NoError(t, <-done)
+	}()
+
+	client, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer client.Close()
+
+	search := func(target string) (string, bool) {
+		msg := "M-SEARCH * HTTP/1.1\r\nHOST: 239.255.255.250:1900\r\nMAN: \"ssdp:discover\"\r\nMX: 1\r\nST: " + target + "\r\n\r\n"
+		_, err := client.WriteTo([]byte(msg), conn.LocalAddr())
+		require.NoError(t, err)
+
+		client.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		buf := make([]byte, 2048)
+		n, _, err := client.ReadFrom(buf)
+		if err != nil {
+			return "", false
+		}
+		return string(buf[:n]), true
+	}
+
+	_, answered := search("urn:dial-multiscreen-org:service:dial:1")
+	assert.False(t, answered, "other devices are not answered for")
+
+	resp, answered := search("ssdp:all")
+	require.True(t, answered)
+	assert.True(t, strings.HasPrefix(resp, "HTTP/1.1 200 OK\r\n"))
+	assert.Contains(t, resp, "LOCATION: http://127.0.0.1:8080/description.xml\r\n")
+	assert.Contains(t, resp, "hue-bridgeid: 0A1B2CFFFE3D4E5F\r\n")
+	assert.Contains(t, resp, "USN: uuid:2f402f80-da50-11e1-9b23-0a1b2c3d4e5f::upnp:rootdevice\r\n")
+}
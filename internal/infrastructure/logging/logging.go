@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Attribute keys shared across the codebase, so records can be filtered by them
+const (
+	KeySource    = "source"     // Component a record comes from, e.g. "twitch" or "ble"
+	KeyDevice    = "device"     // Bluetooth address of a lamp
+	KeyRequestID = "request_id" // ID of the HTTP request being served
+)
+
+// Formats accepted by Setup
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ParseLevel parses debug, info, warn or error
+func ParseLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q (use debug, info, warn or error)", level)
+	}
+	return l, nil
+}
+
+// NewHandler creates a text or JSON handler writing records at or above the level
+func NewHandler(w io.Writer, level slog.Level, format string) (slog.Handler, error) {
+	options := &slog.HandlerOptions{Level: level}
+
+	switch strings.ToLower(format) {
+	case FormatText, "":
+		return slog.NewTextHandler(w, options), nil
+	case FormatJSON:
+		return slog.NewJSONHandler(w, options), nil
+	}
+	return nil, fmt.Errorf("invalid log format %q (use text or json)", format)
+}
+
+// Setup makes a handler with the level and format the default logger. Output of
+// the standard log package, e.g. from dependencies, goes through it as well.
+func Setup(w io.Writer, level, format string) error {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	handler, err := NewHandler(w, l, format)
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// Source returns the default logger tagging records with the component they come from
+func Source(name string) *slog.Logger {
+	return slog.Default().With(KeySource, name)
+}
+
+// contextKey keys the request logger in a context
+type contextKey struct{}
+
+// WithLogger returns a context carrying a logger, e.g. one tagged with the request ID
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger of a context, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
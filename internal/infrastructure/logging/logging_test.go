@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		format  string
+		wantErr bool
+		check   func(t *testing.T, out string)
+	}{
+		{
+			name:   "text drops records below the level",
+			level:  "info",
+			format: FormatText,
+			check: func(t *testing.T, out string) {
+				assert.NotContains(t, out, "frame")
+				assert.Contains(t, out, "msg=connected device=BE:27:EB")
+			},
+		},
+		{
+			name:   "json at debug keeps frames",
+			level:  "DEBUG",
+			format: "JSON",
+			check: func(t *testing.T, out string) {
+				lines := bytes.Split(bytes.TrimSpace([]byte(out)), []byte("\n"))
+				require.Len(t, lines, 2)
+				var record map[string]any
+				require.NoError(t, json.Unmarshal(lines[0], &record))
+				assert.Equal(t, "7e0704ff00010201ef", record["frame"])
+			},
+		},
+		{name: "unknown level", level: "loud", format: FormatText, wantErr: true},
+		{name: "unknown format", level: "info", format: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, err := ParseLevel(tt.level)
+			if err == nil {
+				var handler slog.Handler
+				var out bytes.Buffer
+				handler, err = NewHandler(&out, level, tt.format)
+				if err == nil {
+					logger := slog.New(handler)
+					logger.Debug("writing", "frame", "7e0704ff00010201ef")
+					logger.Info("connected", KeyDevice, "BE:27:EB")
+					tt.check(t, out.String())
+				}
+			}
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+
+	logger := slog.Default().With(KeyRequestID, "abc")
+	assert.Same(t, logger, FromContext(WithLogger(context.Background(), logger)))
+}
@@ -0,0 +1,44 @@
+package metrics
+
+// Default holds the series below and is served on /metrics
+var Default = NewRegistry()
+
+// BLE series
+var (
+	BLEWriteDuration = Default.NewHistogram("lampcontrol_ble_write_duration_seconds",
+		"Duration of a single characteristic write.", DefBuckets)
+	BLEConnectDuration = Default.NewHistogram("lampcontrol_ble_connect_duration_seconds",
+		"Duration of connecting to a lamp including service discovery, by result.", DefBuckets, "result")
+	BLEScanDuration = Default.NewHistogram("lampcontrol_ble_scan_duration_seconds",
+		"Duration of device scans.", []float64{1, 2.5, 5, 10, 15, 30, 60})
+	BLEScanDevices = Default.NewGauge("lampcontrol_ble_scan_devices",
+		"Lamps found by the last scan.")
+)
+
+// Device series
+var (
+	DeviceCommandDuration = Default.NewHistogram("lampcontrol_device_command_duration_seconds",
+		"Duration of a lamp command including connecting and retries, by command.", DefBuckets, "command")
+	DeviceCommandRetries = Default.NewCounter("lampcontrol_device_command_retries_total",
+		"Failed command attempts that were retried, by error type.", "error")
+	DeviceCommandFailures = Default.NewCounter("lampcontrol_device_command_failures_total",
+		"Commands that failed after all attempts, by error type of the last attempt.", "error")
+	DeviceConnected = Default.NewGauge("lampcontrol_device_connected",
+		"Whether a lamp is connected (1) or not (0).", "device")
+)
+
+// WebSocket series
+var (
+	WSClients = Default.NewGauge("lampcontrol_websocket_clients",
+		"Connected WebSocket clients.")
+	WSBroadcasts = Default.NewCounter("lampcontrol_websocket_broadcasts_total",
+		"Messages broadcast to the WebSocket clients.")
+	WSBroadcastDrops = Default.NewCounter("lampcontrol_websocket_broadcast_drops_total",
+		"Slow WebSocket clients disconnected because their send buffer was full.")
+)
+
+// Twitch series
+var (
+	TwitchCommands = Default.NewCounter("lampcontrol_twitch_commands_total",
+		"Chat commands by platform and outcome.", "platform", "outcome")
+)
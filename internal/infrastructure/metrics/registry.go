@@ -0,0 +1,301 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefBuckets are histogram buckets in seconds for latencies from 5ms to 10s
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// metricType is the TYPE of a family in the text format
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+// Registry holds metric families and writes them in the Prometheus text format
+type Registry struct {
+	families []*family
+	mu       sync.Mutex
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// family is a metric with all its label combinations
+type family struct {
+	name    string
+	help    string
+	typ     metricType
+	labels  []string
+	buckets []float64 // Upper bounds of a histogram, ascending
+	series  map[string]*series
+	mu      sync.Mutex
+}
+
+// series is one label combination of a family
+type series struct {
+	values  []string
+	value   float64  // Counter or gauge value, histogram sum
+	count   uint64   // Histogram observations
+	buckets []uint64 // Histogram observations per bucket, not cumulative
+	mu      sync.Mutex
+}
+
+// register adds a family, panicking on a duplicate name like a programming error should
+func (r *Registry) register(f *family) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.families {
+		if existing.name == f.name {
+			panic(fmt.Sprintf("metric %s registered twice", f.name))
+		}
+	}
+	f.series = make(map[string]*series)
+	r.families = append(r.families, f)
+	return f
+}
+
+// with returns the series of the label values, creating it on first use
+func (f *family) with(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metric %s takes %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+
+	key := strings.Join(values, "\xff")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, exists := f.series[key]
+	if !exists {
+		s = &series{values: append([]string(nil), values...)}
+		if f.typ == typeHistogram {
+			s.buckets = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// CounterVec is a counter partitioned by labels
+type CounterVec struct {
+	family *family
+}
+
+// Counter only goes up
+type Counter struct {
+	series *series
+}
+
+// NewCounter registers a counter
+func (r *Registry) NewCounter(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{family: r.register(&family{name: name, help: help, typ: typeCounter, labels: labels})}
+}
+
+// With returns the counter of the label values
+func (v *CounterVec) With(values ...string) Counter {
+	return Counter{series: v.family.with(values)}
+}
+
+// Inc adds one
+func (c Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds a non-negative value
+func (c Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.series.mu.Lock()
+	c.series.value += delta
+	c.series.mu.Unlock()
+}
+
+// GaugeVec is a gauge partitioned by labels
+type GaugeVec struct {
+	family *family
+}
+
+// Gauge goes up and down
+type Gauge struct {
+	series *series
+}
+
+// NewGauge registers a gauge
+func (r *Registry) NewGauge(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{family: r.register(&family{name: name, help: help, typ: typeGauge, labels: labels})}
+}
+
+// With returns the gauge of the label values
+func (v *GaugeVec) With(values ...string) Gauge {
+	return Gauge{series: v.family.with(values)}
+}
+
+// Set sets the value
+func (g Gauge) Set(value float64) {
+	g.series.mu.Lock()
+	g.series.value = value
+	g.series.mu.Unlock()
+}
+
+// Add adds a value, which may be negative
+func (g Gauge) Add(delta float64) {
+	g.series.mu.Lock()
+	g.series.value += delta
+	g.series.mu.Unlock()
+}
+
+// HistogramVec is a histogram partitioned by labels
+type HistogramVec struct {
+	family *family
+}
+
+// Histogram counts observations into buckets
+type Histogram struct {
+	family *family
+	series *series
+}
+
+// NewHistogram registers a histogram with ascending bucket upper bounds
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	return &HistogramVec{family: r.register(&family{name: name, help: help, typ: typeHistogram, labels: labels, buckets: bounds})}
+}
+
+// With returns the histogram of the label values
+func (v *HistogramVec) With(values ...string) Histogram {
+	return Histogram{family: v.family, series: v.family.with(values)}
+}
+
+// Observe records a value
+func (h Histogram) Observe(value float64) {
+	i := sort.SearchFloat64s(h.family.buckets, value)
+
+	h.series.mu.Lock()
+	defer h.series.mu.Unlock()
+
+	h.series.value += value
+	h.series.count++
+	if i < len(h.series.buckets) {
+		h.series.buckets[i]++
+	}
+}
+
+// ServeHTTP writes the metrics in the text format
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	r.WriteText(w)
+}
+
+// WriteText writes all families in the Prometheus text exposition format,
+// series sorted by their label values
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	families := append([]*family(nil), r.families...)
+	r.mu.Unlock()
+
+	buf := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(buf)
+	}
+	return buf.Flush()
+}
+
+// write writes the HELP and TYPE lines and every series of the family
+func (f *family) write(w *bufio.Writer) {
+	f.mu.Lock()
+	all := make([]*series, 0, len(f.series))
+	for _, s := range f.series {
+		all = append(all, s)
+	}
+	f.mu.Unlock()
+
+	sort.Slice(all, func(i, j int) bool {
+		return strings.Join(all[i].values, "\xff") < strings.Join(all[j].values, "\xff")
+	})
+
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.typ)
+
+	for _, s := range all {
+		s.mu.Lock()
+		value, count := s.value, s.count
+		buckets := append([]uint64(nil), s.buckets...)
+		s.mu.Unlock()
+
+		if f.typ != typeHistogram {
+			fmt.Fprintf(w, "%s%s %s\n", f.name, f.labelPairs(s.values, ""), formatFloat(value))
+			continue
+		}
+
+		var cumulative uint64
+		for i, bound := range f.buckets {
+			cumulative += buckets[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, f.labelPairs(s.values, formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, f.labelPairs(s.values, "+Inf"), count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", f.name, f.labelPairs(s.values, ""), formatFloat(value))
+		fmt.Fprintf(w, "%s_count%s %d\n", f.name, f.labelPairs(s.values, ""), count)
+	}
+}
+
+// labelPairs formats the labels of a series, adding the le label of a bucket if set
+func (f *family) labelPairs(values []string, le string) string {
+	pairs := make([]string, 0, len(values)+1)
+	for i, label := range f.labels {
+		pairs = append(pairs, label+`="`+escapeLabel(values[i])+`"`)
+	}
+	if le != "" {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatFloat formats a sample value
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
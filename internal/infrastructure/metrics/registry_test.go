@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryWritesTextFormat(t *testing.T) {
+	r := NewRegistry()
+	commands := r.NewCounter("test_commands_total", "Commands by outcome.", "outcome")
+	connected := r.NewGauge("test_connected", "Whether a lamp is connected.", "device")
+	latency := r.NewHistogram("test_latency_seconds", "Write latency.", []float64{0.5, 0.1})
+
+	commands.With("success").Inc()
+	commands.With("success").Add(2)
+	commands.With("cooldown").Inc()
+	commands.With("failure").Add(-1) // Counters never go down
+	connected.With(`BE:27:"EB"`).Set(1)
+	latency.With().Observe(0.05)
+	latency.With().Observe(0.3)
+	latency.With().Observe(2)
+
+	var out strings.Builder
+	require.NoError(t, r.WriteText(&out))
+
+	assert.Equal(t, `# HELP test_commands_total Commands by outcome.
+# TYPE test_commands_total counter
+test_commands_total{outcome="cooldown"} 1
+test_commands_total{outcome="failure"} 0
+test_commands_total{outcome="success"} 3
+# HELP test_connected Whether a lamp is connected.
+# TYPE test_connected gauge
+test_connected{device="BE:27:\"EB\""} 1
+# HELP test_latency_seconds Write latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{le="0.1"} 1
+test_latency_seconds_bucket{le="0.5"} 2
+test_latency_seconds_bucket{le="+Inf"} 3
+test_latency_seconds_sum 2.35
+test_latency_seconds_count 3
+`, out.String())
+}
+
+func TestRegistryServesHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.NewGauge("test_clients", "Connected clients.").With().Set(2)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "test_clients 2\n")
+}
+
+func TestRegistryRejectsMisuse(t *testing.T) {
+	r := NewRegistry()
+	vec := r.NewCounter("test_total", "Test.", "a", "b")
+
+	assert.Panics(t, func() { r.NewGauge("test_total", "Duplicate.") })
+	assert.Panics(t, func() { vec.With("only one") })
+}
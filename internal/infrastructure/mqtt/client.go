@@ -0,0 +1,464 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+)
+
+// Reconnect backoff, handshake and keep alive defaults
+const (
+	defaultMinBackoff = 1 * time.Second
+	defaultMaxBackoff = 30 * time.Second
+	defaultKeepAlive  = 30 * time.Second
+	handshakeTimeout  = 10 * time.Second
+	writeTimeout      = 5 * time.Second
+)
+
+// ErrAuthenticationFailed is returned when the broker rejects the credentials
+var ErrAuthenticationFailed = errors.New("MQTT authentication failed")
+
+// ErrNotConnected is returned when publishing without a broker connection
+var ErrNotConnected = errors.New("not connected to MQTT broker")
+
+// MessageHandler is called for every message on a subscribed topic
+type MessageHandler func(topic string, payload []byte)
+
+// Options configures the broker connection
+type Options struct {
+	Address     string // host:port of the broker
+	ClientID    string
+	Username    string
+	Password    string
+	WillTopic   string // Published by the broker when the connection drops, e.g. availability
+	WillPayload []byte
+	WillRetain  bool
+	KeepAlive   time.Duration // Default: 30s
+}
+
+// Client is a minimal MQTT 3.1.1 client publishing and subscribing at QoS 0.
+// Like the OBS client it reconnects with exponential backoff and stops retrying
+// when the broker rejects the credentials. Subscriptions are renewed on every
+// connect, and the connect handler runs afterwards so retained topics can be
+// published again.
+type Client struct {
+	opts           Options
+	subscriptions  []string
+	messageHandler MessageHandler
+	connectHandler func()
+	stateHandler   domain.ConnectionStateHandler
+	status         domain.ConnectionStatus
+	minBackoff     time.Duration
+	maxBackoff     time.Duration
+	conn           net.Conn
+	cancel         context.CancelFunc
+	done           chan struct{}
+	mu             sync.RWMutex
+	writeMu        sync.Mutex // Keeps packets from interleaving on the connection
+	log            *slog.Logger
+}
+
+// NewClient creates a new MQTT client
+func NewClient(opts Options) *Client {
+	if opts.KeepAlive <= 0 {
+		opts.KeepAlive = defaultKeepAlive
+	}
+
+	return &Client{
+		opts:       opts,
+		status:     domain.NewConnectionStatus(domain.ConnectionDisconnected),
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+		log:        logging.Source("mqtt"),
+	}
+}
+
+// SetBackoff sets the minimum and maximum delay between reconnect attempts
+func (c *Client) SetBackoff(min, max time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.minBackoff = min
+	c.maxBackoff = max
+}
+
+// SetMessageHandler sets the handler for messages on subscribed topics
+func (c *Client) SetMessageHandler(handler MessageHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messageHandler = handler
+}
+
+// SetConnectHandler sets the handler called after every successful connect
+func (c *Client) SetConnectHandler(handler func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.connectHandler = handler
+}
+
+// SetStateHandler sets the handler for connection state changes
+func (c *Client) SetStateHandler(handler domain.ConnectionStateHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stateHandler = handler
+}
+
+// Subscribe adds a topic filter. It takes effect on the next connect,
+// so call it before Connect.
+func (c *Client) Subscribe(filter string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subscriptions = append(c.subscriptions, filter)
+}
+
+// Connect starts the managed connection in the background.
+// The connection outlives ctx; call Disconnect to stop it.
+func (c *Client) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	if c.cancel != nil {
+		c.mu.Unlock()
+		return nil // Already running
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	c.mu.Unlock()
+
+	go c.run(runCtx)
+
+	return nil
+}
+
+// run connects and reconnects until the context is canceled or authentication fails
+func (c *Client) run(ctx context.Context) {
+	defer close(c.done)
+
+	attempt := 0
+	for {
+		if attempt == 0 {
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionConnecting))
+		}
+
+		connected, err := c.session(ctx)
+
+		if ctx.Err() != nil {
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionDisconnected))
+			return
+		}
+
+		if errors.Is(err, ErrAuthenticationFailed) {
+			c.log.Error("Authentication failed, check the username and password")
+			status := domain.NewConnectionStatus(domain.ConnectionAuthFailed)
+			status.Error = err.Error()
+			c.setStatus(status)
+			c.clearRunning()
+			return
+		}
+
+		// A connection the broker accepted starts a fresh backoff
+		if connected {
+			attempt = 0
+		}
+		attempt++
+
+		delay := c.backoff(attempt)
+		c.log.Warn("Connection lost", "error", err, "retry_in", delay)
+
+		status := domain.NewConnectionStatus(domain.ConnectionReconnecting)
+		status.Attempt = attempt
+		status.RetryAt = time.Now().Add(delay)
+		if err != nil {
+			status.Error = err.Error()
+		}
+		c.setStatus(status)
+
+		select {
+		case <-ctx.Done():
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionDisconnected))
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// session runs a single connection until it drops.
+// It reports whether the broker accepted the connection.
+func (c *Client) session(ctx context.Context) (bool, error) {
+	dialer := net.Dialer{Timeout: handshakeTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.opts.Address)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.conn = nil
+		c.mu.Unlock()
+		conn.Close()
+	}()
+
+	// Disconnect raced with the dial
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	reader := bufio.NewReader(conn)
+	if err := c.handshake(conn, reader); err != nil {
+		return false, err
+	}
+
+	c.mu.RLock()
+	filters := append([]string(nil), c.subscriptions...)
+	onConnect := c.connectHandler
+	c.mu.RUnlock()
+
+	if len(filters) > 0 {
+		if err := c.write(conn, encodeSubscribe(1, filters)); err != nil {
+			return true, err
+		}
+	}
+
+	c.log.Info("Connected", "address", c.opts.Address)
+	c.setStatus(domain.NewConnectionStatus(domain.ConnectionConnected))
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.keepAlive(conn, stop)
+
+	if onConnect != nil {
+		go onConnect()
+	}
+
+	for {
+		// The broker answers pings, so silence longer than the keep alive means the connection is gone
+		conn.SetReadDeadline(time.Now().Add(c.opts.KeepAlive * 3 / 2))
+		p, err := readPacket(reader)
+		if err != nil {
+			return true, err
+		}
+
+		switch p.kind {
+		case packetPublish:
+			c.handlePublish(conn, p)
+		case packetSuback:
+			if len(p.body) > 2 {
+				for i, code := range p.body[2:] {
+					if code == subackFailure && i < len(filters) {
+						c.log.Warn("Broker rejected subscription", "topic", filters[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+// handshake sends CONNECT and waits for the broker to accept it
+func (c *Client) handshake(conn net.Conn, reader *bufio.Reader) error {
+	conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	connect := encodeConnect(connectOptions{
+		clientID:   c.opts.ClientID,
+		username:   c.opts.Username,
+		password:   c.opts.Password,
+		keepAlive:  uint16(c.opts.KeepAlive / time.Second),
+		willTopic:  c.opts.WillTopic,
+		willBody:   c.opts.WillPayload,
+		willRetain: c.opts.WillRetain,
+	})
+	if err := c.write(conn, connect); err != nil {
+		return err
+	}
+
+	p, err := readPacket(reader)
+	if err != nil {
+		return err
+	}
+	code, err := decodeConnack(p)
+	if err != nil {
+		return err
+	}
+
+	switch code {
+	case 0:
+		return nil
+	case connackBadCredentials, connackNotAuthorized:
+		return ErrAuthenticationFailed
+	default:
+		return fmt.Errorf("MQTT broker refused connection: return code %d", code)
+	}
+}
+
+// keepAlive pings the broker until the session ends
+func (c *Client) keepAlive(conn net.Conn, stop chan struct{}) {
+	ticker := time.NewTicker(c.opts.KeepAlive / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := c.write(conn, encodePacket(packetPingreq, 0, nil)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handlePublish acknowledges an incoming message if needed and passes it to the message handler
+func (c *Client) handlePublish(conn net.Conn, p packet) {
+	msg, err := decodePublish(p)
+	if err != nil {
+		c.log.Warn("Invalid message", "error", err)
+		return
+	}
+
+	// Subscriptions are QoS 0, but brokers may still deliver retained QoS 1 messages
+	if msg.qos == 1 {
+		c.write(conn, encodePuback(msg.packetID))
+	}
+
+	c.mu.RLock()
+	handler := c.messageHandler
+	c.mu.RUnlock()
+
+	if handler != nil {
+		handler(msg.topic, msg.payload)
+	}
+}
+
+// Publish sends a QoS 0 message
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	c.mu.RLock()
+	conn := c.conn
+	connected := c.status.State == domain.ConnectionConnected
+	c.mu.RUnlock()
+
+	if conn == nil || !connected {
+		return ErrNotConnected
+	}
+	return c.write(conn, encodePublish(topic, payload, retain))
+}
+
+// write sends a packet with a deadline so a stalled broker can't block the caller
+func (c *Client) write(conn net.Conn, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := conn.Write(data)
+	return err
+}
+
+// backoff returns the delay before the given reconnect attempt
+func (c *Client) backoff(attempt int) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	delay := c.minBackoff
+	for i := 1; i < attempt && delay < c.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+	return delay
+}
+
+// Disconnect stops the managed connection and waits for it to shut down.
+// A clean disconnect discards the will, so the will message is published first.
+func (c *Client) Disconnect() error {
+	c.mu.Lock()
+	cancel := c.cancel
+	done := c.done
+	conn := c.conn
+	connected := c.status.State == domain.ConnectionConnected
+	c.cancel = nil
+	if cancel != nil {
+		cancel() // Under the lock so a session either sees the cancel or stored its conn
+	}
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	if conn != nil {
+		if connected {
+			if c.opts.WillTopic != "" {
+				c.write(conn, encodePublish(c.opts.WillTopic, c.opts.WillPayload, c.opts.WillRetain))
+			}
+			c.write(conn, encodePacket(packetDisconnect, 0, nil))
+		}
+		conn.Close() // Unblocks the read loop
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		c.log.Warn("Timed out waiting for connection to close")
+	}
+
+	return nil
+}
+
+// clearRunning marks the managed connection as stopped without a Disconnect call
+func (c *Client) clearRunning() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
+
+// IsConnected returns whether the broker accepted the connection
+func (c *Client) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status.State == domain.ConnectionConnected
+}
+
+// Status returns the current connection status
+func (c *Client) Status() domain.ConnectionStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
+}
+
+// setStatus stores a new status and notifies the state handler
+func (c *Client) setStatus(status domain.ConnectionStatus) {
+	c.mu.Lock()
+	if c.status.State == status.State && status.State != domain.ConnectionReconnecting {
+		c.mu.Unlock()
+		return
+	}
+	c.status = status
+	handler := c.stateHandler
+	c.mu.Unlock()
+
+	c.log.Info("Connection state changed", "state", status.State)
+
+	if handler != nil {
+		handler(status)
+	}
+}
@@ -0,0 +1,170 @@
+package mqtt
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBroker starts an embedded broker on a random local port.
+// Without rules every client is allowed.
+func newTestBroker(t *testing.T, rules auth.AuthRules) (*mochi.Server, string) {
+	t.Helper()
+
+	server := mochi.New(&mochi.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if rules == nil {
+		require.NoError(t, server.AddHook(new(auth.AllowHook), nil))
+	} else {
+		rules = append(rules, auth.AuthRule{Allow: false})
+		ledger := &auth.Ledger{Auth: rules, ACL: auth.ACLRules{{Filters: auth.Filters{"#": auth.ReadWrite}}}}
+		require.NoError(t, server.AddHook(new(auth.Hook), &auth.Options{Ledger: ledger}))
+	}
+
+	listener := listeners.NewTCP(listeners.Config{ID: "test", Address: "127.0.0.1:0"})
+	require.NoError(t, server.AddListener(listener))
+	require.NoError(t, server.Serve())
+	t.Cleanup(func() { server.Close() })
+
+	return server, listener.Address()
+}
+
+// recorder collects the messages of an inline broker subscription
+type recorder struct {
+	mu       sync.Mutex
+	messages map[string][]byte
+}
+
+func record(t *testing.T, server *mochi.Server, filter string) *recorder {
+	t.Helper()
+
+	r := &recorder{messages: make(map[string][]byte)}
+	require.NoError(t, server.Subscribe(filter, 1, func(cl *mochi.Client, sub packets.Subscription, pk packets.Packet) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.messages[pk.TopicName] = append([]byte(nil), pk.Payload...)
+	}))
+	return r
+}
+
+func (r *recorder) get(topic string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	payload, ok := r.messages[topic]
+	return string(payload), ok
+}
+
+func waitForState(t *testing.T, client *Client, state domain.ConnectionState) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return client.Status().State == state
+	}, 5*time.Second, 10*time.Millisecond, "client never reached %s", state)
+}
+
+func TestClientPublishAndSubscribe(t *testing.T) {
+	server, address := newTestBroker(t, nil)
+	published := record(t, server, "lampcontrol/#")
+
+	client := NewClient(Options{
+		Address:     address,
+		ClientID:    "test",
+		WillTopic:   "lampcontrol/status",
+		WillPayload: []byte(PayloadOffline),
+		WillRetain:  true,
+	})
+
+	var mu sync.Mutex
+	received := make(map[string]string)
+	client.SetMessageHandler(func(topic string, payload []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		received[topic] = string(payload)
+	})
+	client.SetConnectHandler(func() {
+		client.Publish("lampcontrol/status", []byte(PayloadOnline), true)
+	})
+	client.Subscribe("lampcontrol/+/set")
+
+	require.NoError(t, client.Connect(context.Background()))
+	waitForState(t, client, domain.ConnectionConnected)
+
+	assert.Eventually(t, func() bool {
+		payload, _ := published.get("lampcontrol/status")
+		return payload == PayloadOnline
+	}, 2*time.Second, 10*time.Millisecond, "connect handler publishes availability")
+
+	// Commands reach the message handler
+	assert.Eventually(t, func() bool {
+		server.Publish("lampcontrol/aabbcc/set", []byte(`{"state":"ON"}`), false, 0)
+		mu.Lock()
+		defer mu.Unlock()
+		return received["lampcontrol/aabbcc/set"] == `{"state":"ON"}`
+	}, 2*time.Second, 50*time.Millisecond)
+
+	// A clean disconnect still marks the bridge offline
+	require.NoError(t, client.Disconnect())
+	assert.Equal(t, domain.ConnectionDisconnected, client.Status().State)
+	assert.Eventually(t, func() bool {
+		payload, _ := published.get("lampcontrol/status")
+		return payload == PayloadOffline
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.ErrorIs(t, client.Publish("lampcontrol/status", []byte(PayloadOnline), true), ErrNotConnected)
+}
+
+func TestClientAuthenticationFailed(t *testing.T) {
+	_, address := newTestBroker(t, auth.AuthRules{{Username: "lamp", Password: "secret", Allow: true}})
+
+	client := NewClient(Options{Address: address, ClientID: "test", Username: "lamp", Password: "wrong"})
+	require.NoError(t, client.Connect(context.Background()))
+	waitForState(t, client, domain.ConnectionAuthFailed)
+	assert.NoError(t, client.Disconnect(), "already stopped")
+
+	client = NewClient(Options{Address: address, ClientID: "test", Username: "lamp", Password: "secret"})
+	require.NoError(t, client.Connect(context.Background()))
+	waitForState(t, client, domain.ConnectionConnected)
+	require.NoError(t, client.Disconnect())
+}
+
+func TestClientReconnects(t *testing.T) {
+	server, address := newTestBroker(t, nil)
+
+	client := NewClient(Options{Address: address, ClientID: "test"})
+	client.SetBackoff(10*time.Millisecond, 50*time.Millisecond)
+
+	var mu sync.Mutex
+	connects := 0
+	client.SetConnectHandler(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		connects++
+	})
+
+	require.NoError(t, client.Connect(context.Background()))
+	defer client.Disconnect()
+	waitForState(t, client, domain.ConnectionConnected)
+
+	// Dropping the connection from the broker side triggers a reconnect
+	cl, ok := server.Clients.Get("test")
+	require.True(t, ok)
+	cl.Stop(io.EOF)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return connects == 2 && client.IsConnected()
+	}, 5*time.Second, 10*time.Millisecond)
+}
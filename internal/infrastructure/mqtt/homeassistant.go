@@ -0,0 +1,244 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// Availability payloads of the bridge
+const (
+	PayloadOnline  = "online"
+	PayloadOffline = "offline"
+)
+
+// Home Assistant color modes of the JSON light schema
+const (
+	colorModeRGB       = "rgb"
+	colorModeColorTemp = "color_temp"
+)
+
+// Topics builds the topics of the bridge and the Home Assistant discovery configs
+type Topics struct {
+	Base      string // e.g. lampcontrol
+	Discovery string // e.g. homeassistant
+}
+
+// NodeID returns the topic segment of a lamp, its address without colons
+func NodeID(address string) string {
+	return strings.ToLower(strings.ReplaceAll(address, ":", ""))
+}
+
+// Availability returns the topic announcing whether the bridge is online
+func (t Topics) Availability() string {
+	return t.Base + "/status"
+}
+
+// State returns the state topic of a lamp
+func (t Topics) State(nodeID string) string {
+	return t.Base + "/" + nodeID + "/state"
+}
+
+// Command returns the command topic of a lamp
+func (t Topics) Command(nodeID string) string {
+	return t.Base + "/" + nodeID + "/set"
+}
+
+// CommandFilter returns the subscription matching the command topics of all lamps
+func (t Topics) CommandFilter() string {
+	return t.Base + "/+/set"
+}
+
+// Config returns the discovery config topic of a lamp
+func (t Topics) Config(nodeID string) string {
+	return t.Discovery + "/light/" + nodeID + "/config"
+}
+
+// ParseCommandTopic returns the node ID of a command topic
+func (t Topics) ParseCommandTopic(topic string) (string, bool) {
+	rest, found := strings.CutPrefix(topic, t.Base+"/")
+	if !found {
+		return "", false
+	}
+	nodeID, found := strings.CutSuffix(rest, "/set")
+	if !found || nodeID == "" || strings.Contains(nodeID, "/") {
+		return "", false
+	}
+	return nodeID, true
+}
+
+// discoveryConfig is a Home Assistant MQTT light using the JSON schema
+type discoveryConfig struct {
+	Name                string          `json:"name"`
+	UniqueID            string          `json:"unique_id"`
+	Schema              string          `json:"schema"`
+	StateTopic          string          `json:"state_topic"`
+	CommandTopic        string          `json:"command_topic"`
+	AvailabilityTopic   string          `json:"availability_topic"`
+	PayloadAvailable    string          `json:"payload_available"`
+	PayloadNotAvailable string          `json:"payload_not_available"`
+	Brightness          bool            `json:"brightness"`
+	BrightnessScale     int             `json:"brightness_scale"`
+	SupportedColorModes []string        `json:"supported_color_modes"`
+	MinMireds           int             `json:"min_mireds"`
+	MaxMireds           int             `json:"max_mireds"`
+	Effect              bool            `json:"effect"`
+	EffectList          []string        `json:"effect_list"`
+	Device              discoveryDevice `json:"device"`
+}
+
+// discoveryDevice groups the light under a device in Home Assistant
+type discoveryDevice struct {
+	Identifiers  []string    `json:"identifiers"`
+	Connections  [][2]string `json:"connections"`
+	Name         string      `json:"name"`
+	Manufacturer string      `json:"manufacturer"`
+	Model        string      `json:"model"`
+}
+
+// DiscoveryPayload returns the retained discovery config announcing a lamp to Home Assistant
+func (t Topics) DiscoveryPayload(device *domain.Device) ([]byte, error) {
+	nodeID := NodeID(device.Address)
+	name := device.Name
+	if name == "" {
+		name = device.Address
+	}
+
+	config := discoveryConfig{
+		Name:                name,
+		UniqueID:            "lampcontrol_" + nodeID,
+		Schema:              "json",
+		StateTopic:          t.State(nodeID),
+		CommandTopic:        t.Command(nodeID),
+		AvailabilityTopic:   t.Availability(),
+		PayloadAvailable:    PayloadOnline,
+		PayloadNotAvailable: PayloadOffline,
+		Brightness:          true,
+		BrightnessScale:     255,
+		SupportedColorModes: []string{colorModeRGB, colorModeColorTemp},
+		MinMireds:           domain.MinMireds,
+		MaxMireds:           domain.MaxMireds,
+		Effect:              true,
+		EffectList:          domain.EffectNames(),
+		Device: discoveryDevice{
+			Identifiers:  []string{"lampcontrol_" + nodeID},
+			Connections:  [][2]string{{"bluetooth", device.Address}},
+			Name:         name,
+			Manufacturer: "ELK-BLEDOM",
+			Model:        "BLE LED strip",
+		},
+	}
+
+	return json.Marshal(config)
+}
+
+// effectName returns the name of an effect index, or "" if it has none
+func effectName(effect int) string {
+	for name, index := range domain.EffectMap {
+		if int(index) == effect {
+			return name
+		}
+	}
+	return ""
+}
+
+// lightColor is the color object of the JSON schema
+type lightColor struct {
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+}
+
+// lightState is the state payload of the JSON schema
+type lightState struct {
+	State      string      `json:"state"`
+	Brightness uint8       `json:"brightness"`
+	ColorMode  string      `json:"color_mode"`
+	Color      *lightColor `json:"color,omitempty"`
+	ColorTemp  *int        `json:"color_temp,omitempty"`
+	Effect     string      `json:"effect,omitempty"`
+}
+
+// StatePayload returns the JSON schema state of a lamp
+func StatePayload(state domain.DeviceState) ([]byte, error) {
+	payload := lightState{
+		State:      "OFF",
+		Brightness: state.Brightness,
+		ColorMode:  colorModeRGB,
+	}
+	if state.PowerOn {
+		payload.State = "ON"
+	}
+
+	switch {
+	case state.RGB != nil:
+		payload.Color = &lightColor{R: state.RGB.R, G: state.RGB.G, B: state.RGB.B}
+	case state.WhiteBalance != nil:
+		mireds := domain.WhiteBalanceToMireds(*state.WhiteBalance)
+		payload.ColorMode = colorModeColorTemp
+		payload.ColorTemp = &mireds
+	case state.Effect != nil:
+		payload.Effect = effectName(*state.Effect)
+	}
+
+	return json.Marshal(payload)
+}
+
+// lightCommand is the command payload of the JSON schema
+type lightCommand struct {
+	State      string      `json:"state"`
+	Brightness *int        `json:"brightness"`
+	Color      *lightColor `json:"color"`
+	ColorTemp  *int        `json:"color_temp"`
+	Effect     *string     `json:"effect"`
+}
+
+// ParseCommand converts a JSON schema command into a state change
+func ParseCommand(payload []byte) (domain.StateChange, error) {
+	var cmd lightCommand
+	if err := json.Unmarshal(payload, &cmd); err != nil {
+		return domain.StateChange{}, fmt.Errorf("invalid command: %w", err)
+	}
+
+	var change domain.StateChange
+
+	switch strings.ToUpper(cmd.State) {
+	case "ON":
+		on := true
+		change.PowerOn = &on
+	case "OFF":
+		off := false
+		change.PowerOn = &off
+		return change, nil // Nothing else reaches a lamp turning off
+	case "":
+	default:
+		return domain.StateChange{}, fmt.Errorf("invalid state: %s", cmd.State)
+	}
+
+	if cmd.Brightness != nil {
+		level := uint8(max(0, min(255, *cmd.Brightness)))
+		change.Brightness = &level
+	}
+
+	switch {
+	case cmd.Color != nil:
+		change.RGB = &domain.RGB{R: cmd.Color.R, G: cmd.Color.G, B: cmd.Color.B}
+	case cmd.ColorTemp != nil:
+		wb := domain.MiredsToWhiteBalance(*cmd.ColorTemp)
+		change.WhiteBalance = &wb
+	case cmd.Effect != nil:
+		effect, err := domain.GetEffect(*cmd.Effect)
+		if err != nil {
+			return domain.StateChange{}, err
+		}
+		effectInt := int(effect)
+		change.Effect = &effectInt
+	}
+
+	if change.IsZero() {
+		return domain.StateChange{}, fmt.Errorf("empty command")
+	}
+	return change, nil
+}
@@ -0,0 +1,116 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopics(t *testing.T) {
+	topics := Topics{Base: "lampcontrol", Discovery: "homeassistant"}
+	nodeID := NodeID("BE:27:EB:00:12:34")
+
+	assert.Equal(t, "be27eb001234", nodeID)
+	assert.Equal(t, "lampcontrol/be27eb001234/set", topics.Command(nodeID))
+	assert.Equal(t, "homeassistant/light/be27eb001234/config", topics.Config(nodeID))
+
+	parsed, ok := topics.ParseCommandTopic("lampcontrol/be27eb001234/set")
+	assert.True(t, ok)
+	assert.Equal(t, nodeID, parsed)
+
+	for _, topic := range []string{"lampcontrol/be27eb001234/state", "other/be27eb001234/set", "lampcontrol/a/b/set", "lampcontrol//set"} {
+		_, ok := topics.ParseCommandTopic(topic)
+		assert.False(t, ok, topic)
+	}
+}
+
+func TestDiscoveryPayload(t *testing.T) {
+	topics := Topics{Base: "lampcontrol", Discovery: "homeassistant"}
+	device := domain.NewDevice("BE:27:EB:00:12:34", "ELK-BLEDOM", -60)
+
+	payload, err := topics.DiscoveryPayload(device)
+	require.NoError(t, err)
+
+	var config map[string]any
+	require.NoError(t, json.Unmarshal(payload, &config))
+	assert.Equal(t, "json", config["schema"])
+	assert.Equal(t, "lampcontrol_be27eb001234", config["unique_id"])
+	assert.Equal(t, "lampcontrol/be27eb001234/state", config["state_topic"])
+	assert.Equal(t, "lampcontrol/status", config["availability_topic"])
+	assert.Equal(t, []any{"rgb", "color_temp"}, config["supported_color_modes"])
+	assert.Equal(t, []any{"fade", "pulse", "rainbow", "strobe"}, config["effect_list"])
+}
+
+func TestParseCommand(t *testing.T) {
+	on := true
+	off := false
+	full := uint8(255)
+	rainbow := int(domain.EffectMap["rainbow"])
+
+	tests := []struct {
+		name    string
+		payload string
+		want    domain.StateChange
+		wantErr bool
+	}{
+		{name: "on", payload: `{"state":"ON"}`, want: domain.StateChange{PowerOn: &on}},
+		{name: "off ignores the rest", payload: `{"state":"OFF","brightness":10}`, want: domain.StateChange{PowerOn: &off}},
+		{name: "color", payload: `{"state":"ON","color":{"r":255,"g":0,"b":128}}`, want: domain.StateChange{PowerOn: &on, RGB: &domain.RGB{R: 255, B: 128}}},
+		{name: "brightness is clamped", payload: `{"brightness":300}`, want: domain.StateChange{Brightness: &full}},
+		{name: "warmest white", payload: `{"color_temp":500}`, want: domain.StateChange{WhiteBalance: &domain.WhiteBalance{Warm: 255}}},
+		{name: "effect", payload: `{"effect":"rainbow"}`, want: domain.StateChange{Effect: &rainbow}},
+		{name: "unknown effect", payload: `{"effect":"disco"}`, wantErr: true},
+		{name: "invalid state", payload: `{"state":"TOGGLE"}`, wantErr: true},
+		{name: "empty", payload: `{}`, wantErr: true},
+		{name: "not json", payload: `ON`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommand([]byte(tt.payload))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStatePayload(t *testing.T) {
+	strobe := int(domain.EffectMap["strobe"])
+
+	tests := []struct {
+		name  string
+		state domain.DeviceState
+		want  string
+	}{
+		{
+			name:  "color",
+			state: domain.DeviceState{PowerOn: true, Brightness: 200, RGB: &domain.RGB{R: 255}},
+			want:  `{"state":"ON","brightness":200,"color_mode":"rgb","color":{"r":255,"g":0,"b":0}}`,
+		},
+		{
+			name:  "white",
+			state: domain.DeviceState{PowerOn: true, Brightness: 255, WhiteBalance: &domain.WhiteBalance{Warm: 128, Cold: 128}},
+			want:  `{"state":"ON","brightness":255,"color_mode":"color_temp","color_temp":327}`,
+		},
+		{
+			name:  "effect",
+			state: domain.DeviceState{Brightness: 100, Effect: &strobe},
+			want:  `{"state":"OFF","brightness":100,"color_mode":"rgb","effect":"strobe"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StatePayload(tt.state)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MQTT 3.1.1 control packet types, in the upper nibble of the fixed header
+const (
+	packetConnect    byte = 1
+	packetConnack    byte = 2
+	packetPublish    byte = 3
+	packetPuback     byte = 4
+	packetSubscribe  byte = 8
+	packetSuback     byte = 9
+	packetPingreq    byte = 12
+	packetPingresp   byte = 13
+	packetDisconnect byte = 14
+	protocolLevel311 byte = 4
+)
+
+// CONNECT flags
+const (
+	connectCleanSession byte = 0x02
+	connectWill         byte = 0x04
+	connectWillRetain   byte = 0x20
+	connectPassword     byte = 0x40
+	connectUsername     byte = 0x80
+)
+
+// CONNACK return codes that mean the broker rejected the credentials
+const (
+	connackBadCredentials byte = 4
+	connackNotAuthorized  byte = 5
+)
+
+// subackFailure is the SUBACK return code of a rejected subscription
+const subackFailure byte = 0x80
+
+// packet is a decoded control packet: the fixed header and the rest of the packet
+type packet struct {
+	kind  byte
+	flags byte
+	body  []byte
+}
+
+// connectOptions holds the fields of a CONNECT packet
+type connectOptions struct {
+	clientID   string
+	username   string
+	password   string
+	keepAlive  uint16 // Seconds
+	willTopic  string
+	willBody   []byte
+	willRetain bool
+}
+
+// publishPacket is a decoded PUBLISH packet
+type publishPacket struct {
+	topic    string
+	payload  []byte
+	qos      byte
+	retain   bool
+	packetID uint16
+}
+
+// readPacket reads the next control packet
+func readPacket(r *bufio.Reader) (packet, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return packet{}, err
+	}
+
+	length, err := readRemainingLength(r)
+	if err != nil {
+		return packet{}, err
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return packet{}, err
+	}
+
+	return packet{kind: header >> 4, flags: header & 0x0f, body: body}, nil
+}
+
+// readRemainingLength decodes the variable length integer of the fixed header
+func readRemainingLength(r *bufio.Reader) (int, error) {
+	length := 0
+	multiplier := 1
+	for i := 0; i < 4; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		length += int(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			return length, nil
+		}
+		multiplier *= 128
+	}
+	return 0, errors.New("malformed MQTT remaining length")
+}
+
+// encodePacket builds a control packet from its fixed header and the rest of the packet
+func encodePacket(kind, flags byte, body []byte) []byte {
+	buf := make([]byte, 0, len(body)+5)
+	buf = append(buf, kind<<4|flags&0x0f)
+
+	length := len(body)
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 0x80
+		}
+		buf = append(buf, b)
+		if length == 0 {
+			break
+		}
+	}
+	return append(buf, body...)
+}
+
+// encodeConnect builds a CONNECT packet with a clean session
+func encodeConnect(opts connectOptions) []byte {
+	flags := connectCleanSession
+	if opts.willTopic != "" {
+		flags |= connectWill
+		if opts.willRetain {
+			flags |= connectWillRetain
+		}
+	}
+	if opts.username != "" {
+		flags |= connectUsername
+		if opts.password != "" {
+			flags |= connectPassword
+		}
+	}
+
+	body := appendString(nil, "MQTT")
+	body = append(body, protocolLevel311, flags)
+	body = binary.BigEndian.AppendUint16(body, opts.keepAlive)
+
+	body = appendString(body, opts.clientID)
+	if opts.willTopic != "" {
+		body = appendString(body, opts.willTopic)
+		body = appendBytes(body, opts.willBody)
+	}
+	if opts.username != "" {
+		body = appendString(body, opts.username)
+		if opts.password != "" {
+			body = appendString(body, opts.password)
+		}
+	}
+
+	return encodePacket(packetConnect, 0, body)
+}
+
+// encodePublish builds a QoS 0 PUBLISH packet
+func encodePublish(topic string, payload []byte, retain bool) []byte {
+	var flags byte
+	if retain {
+		flags = 0x01
+	}
+
+	body := appendString(nil, topic)
+	body = append(body, payload...)
+	return encodePacket(packetPublish, flags, body)
+}
+
+// encodeSubscribe builds a SUBSCRIBE packet for QoS 0 subscriptions
+func encodeSubscribe(packetID uint16, filters []string) []byte {
+	body := binary.BigEndian.AppendUint16(nil, packetID)
+	for _, filter := range filters {
+		body = appendString(body, filter)
+		body = append(body, 0)
+	}
+	return encodePacket(packetSubscribe, 0x02, body)
+}
+
+// encodePuback acknowledges a QoS 1 PUBLISH packet
+func encodePuback(packetID uint16) []byte {
+	return encodePacket(packetPuback, 0, binary.BigEndian.AppendUint16(nil, packetID))
+}
+
+// decodeConnack returns the return code of a CONNACK packet
+func decodeConnack(p packet) (byte, error) {
+	if p.kind != packetConnack {
+		return 0, fmt.Errorf("unexpected MQTT packet type %d, want CONNACK", p.kind)
+	}
+	if len(p.body) != 2 {
+		return 0, errors.New("malformed MQTT CONNACK")
+	}
+	return p.body[1], nil
+}
+
+// decodePublish decodes a PUBLISH packet
+func decodePublish(p packet) (publishPacket, error) {
+	msg := publishPacket{
+		qos:    (p.flags >> 1) & 0x03,
+		retain: p.flags&0x01 != 0,
+	}
+
+	topic, rest, err := readString(p.body)
+	if err != nil {
+		return msg, err
+	}
+	msg.topic = topic
+
+	if msg.qos > 0 {
+		if len(rest) < 2 {
+			return msg, errors.New("malformed MQTT PUBLISH")
+		}
+		msg.packetID = binary.BigEndian.Uint16(rest)
+		rest = rest[2:]
+	}
+
+	msg.payload = rest
+	return msg, nil
+}
+
+// appendString appends a length-prefixed UTF-8 string
+func appendString(buf []byte, s string) []byte {
+	return appendBytes(buf, []byte(s))
+}
+
+// appendBytes appends length-prefixed binary data
+func appendBytes(buf, data []byte) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(data)))
+	return append(buf, data...)
+}
+
+// readString reads a length-prefixed string and returns the remaining data
+func readString(data []byte) (string, []byte, error) {
+	if len(data) < 2 {
+		return "", nil, errors.New("malformed MQTT string")
+	}
+	length := int(binary.BigEndian.Uint16(data))
+	if len(data) < 2+length {
+		return "", nil, errors.New("malformed MQTT string")
+	}
+	return string(data[2 : 2+length]), data[2+length:], nil
+}
@@ -0,0 +1,446 @@
+package obs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/gorilla/websocket"
+)
+
+// obs-websocket v5 opcodes
+const (
+	opHello      = 0
+	opIdentify   = 1
+	opIdentified = 2
+	opEvent      = 5
+)
+
+// rpcVersion is the obs-websocket RPC version the client speaks
+const rpcVersion = 1
+
+// Event subscriptions: scene events and output (stream and record) events
+const (
+	subscriptionScenes  = 1 << 2
+	subscriptionOutputs = 1 << 6
+)
+
+// closeAuthenticationFailed is the close code OBS sends for a wrong password
+const closeAuthenticationFailed = 4009
+
+// Output states reported by stream and record events
+const (
+	outputStarted = "OBS_WEBSOCKET_OUTPUT_STARTED"
+	outputStopped = "OBS_WEBSOCKET_OUTPUT_STOPPED"
+)
+
+// Reconnect backoff and handshake defaults
+const (
+	defaultMinBackoff = 1 * time.Second
+	defaultMaxBackoff = 30 * time.Second
+	handshakeTimeout  = 10 * time.Second
+)
+
+// ErrAuthenticationFailed is returned when OBS rejects the password
+var ErrAuthenticationFailed = errors.New("OBS authentication failed")
+
+// message is the envelope of every obs-websocket message
+type message struct {
+	Op int             `json:"op"`
+	D  json.RawMessage `json:"d"`
+}
+
+// helloData is sent by OBS right after connecting
+type helloData struct {
+	RPCVersion     int `json:"rpcVersion"`
+	Authentication *struct {
+		Challenge string `json:"challenge"`
+		Salt      string `json:"salt"`
+	} `json:"authentication,omitempty"`
+}
+
+// identifyData answers the hello
+type identifyData struct {
+	RPCVersion         int    `json:"rpcVersion"`
+	Authentication     string `json:"authentication,omitempty"`
+	EventSubscriptions int    `json:"eventSubscriptions"`
+}
+
+// eventMessage carries an OBS event
+type eventMessage struct {
+	EventType string          `json:"eventType"`
+	EventData json.RawMessage `json:"eventData"`
+}
+
+// eventData holds the fields of the supported events
+type eventData struct {
+	SceneName    string `json:"sceneName"`
+	OutputActive bool   `json:"outputActive"`
+	OutputState  string `json:"outputState"`
+}
+
+// Client connects to obs-websocket v5 and reports scene, stream and record events.
+// Like the Twitch IRC client it reconnects with exponential backoff and stops
+// retrying when OBS rejects the password.
+type Client struct {
+	address      string
+	password     string
+	eventHandler domain.OBSEventHandler
+	stateHandler domain.ConnectionStateHandler
+	status       domain.ConnectionStatus
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	conn         *websocket.Conn
+	cancel       context.CancelFunc
+	done         chan struct{}
+	mu           sync.RWMutex
+	log          *slog.Logger
+}
+
+// NewClient creates a new OBS WebSocket client
+func NewClient(address, password string) *Client {
+	return &Client{
+		address:    address,
+		password:   password,
+		status:     domain.NewConnectionStatus(domain.ConnectionDisconnected),
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+		log:        logging.Source("obs"),
+	}
+}
+
+// SetBackoff sets the minimum and maximum delay between reconnect attempts
+func (c *Client) SetBackoff(min, max time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.minBackoff = min
+	c.maxBackoff = max
+}
+
+// SetEventHandler sets the handler for OBS events
+func (c *Client) SetEventHandler(handler domain.OBSEventHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.eventHandler = handler
+}
+
+// SetStateHandler sets the handler for connection state changes
+func (c *Client) SetStateHandler(handler domain.ConnectionStateHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stateHandler = handler
+}
+
+// Connect starts the managed connection in the background.
+// The connection outlives ctx; call Disconnect to stop it.
+func (c *Client) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	if c.cancel != nil {
+		c.mu.Unlock()
+		return nil // Already running
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	c.mu.Unlock()
+
+	go c.run(runCtx)
+
+	return nil
+}
+
+// run connects and reconnects until the context is canceled or authentication fails
+func (c *Client) run(ctx context.Context) {
+	defer close(c.done)
+
+	attempt := 0
+	for {
+		if attempt == 0 {
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionConnecting))
+		}
+
+		identified, err := c.session(ctx)
+
+		if ctx.Err() != nil {
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionDisconnected))
+			return
+		}
+
+		if errors.Is(err, ErrAuthenticationFailed) {
+			c.log.Error("Authentication failed, check the password")
+			status := domain.NewConnectionStatus(domain.ConnectionAuthFailed)
+			status.Error = err.Error()
+			c.setStatus(status)
+			c.clearRunning()
+			return
+		}
+
+		// A connection that got identified starts a fresh backoff
+		if identified {
+			attempt = 0
+		}
+		attempt++
+
+		delay := c.backoff(attempt)
+		c.log.Warn("Connection lost", "error", err, "retry_in", delay)
+
+		status := domain.NewConnectionStatus(domain.ConnectionReconnecting)
+		status.Attempt = attempt
+		status.RetryAt = time.Now().Add(delay)
+		if err != nil {
+			status.Error = err.Error()
+		}
+		c.setStatus(status)
+
+		select {
+		case <-ctx.Done():
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionDisconnected))
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// session runs a single connection until it drops.
+// It reports whether the connection got identified.
+func (c *Client) session(ctx context.Context) (bool, error) {
+	dialer := websocket.Dialer{HandshakeTimeout: handshakeTimeout}
+	conn, _, err := dialer.DialContext(ctx, c.address, nil)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.conn = nil
+		c.mu.Unlock()
+		conn.Close()
+	}()
+
+	// Disconnect raced with the dial
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	if err := c.identify(conn); err != nil {
+		return false, err
+	}
+
+	c.log.Info("Connected", "address", c.address)
+	c.setStatus(domain.NewConnectionStatus(domain.ConnectionConnected))
+
+	for {
+		var msg message
+		if err := conn.ReadJSON(&msg); err != nil {
+			return true, err
+		}
+		if msg.Op == opEvent {
+			c.handleEvent(msg.D)
+		}
+	}
+}
+
+// identify answers the hello and waits for OBS to confirm the session
+func (c *Client) identify(conn *websocket.Conn) error {
+	conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	var hello helloData
+	if err := readOp(conn, opHello, &hello); err != nil {
+		return err
+	}
+
+	identify := identifyData{
+		RPCVersion:         rpcVersion,
+		EventSubscriptions: subscriptionScenes | subscriptionOutputs,
+	}
+	if hello.Authentication != nil {
+		identify.Authentication = Authenticate(c.password, hello.Authentication.Salt, hello.Authentication.Challenge)
+	}
+
+	data, err := json.Marshal(identify)
+	if err != nil {
+		return err
+	}
+	if err := conn.WriteJSON(message{Op: opIdentify, D: data}); err != nil {
+		return err
+	}
+
+	return readOp(conn, opIdentified, nil)
+}
+
+// readOp reads the next message and decodes it if it has the expected opcode
+func readOp(conn *websocket.Conn, op int, v any) error {
+	var msg message
+	if err := conn.ReadJSON(&msg); err != nil {
+		if websocket.IsCloseError(err, closeAuthenticationFailed) {
+			return ErrAuthenticationFailed
+		}
+		return err
+	}
+	if msg.Op != op {
+		return fmt.Errorf("unexpected OBS message: op %d, want %d", msg.Op, op)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(msg.D, v)
+}
+
+// Authenticate computes the obs-websocket authentication string for a password
+func Authenticate(password, salt, challenge string) string {
+	secret := sha256.Sum256([]byte(password + salt))
+	secretB64 := base64.StdEncoding.EncodeToString(secret[:])
+
+	auth := sha256.Sum256([]byte(secretB64 + challenge))
+	return base64.StdEncoding.EncodeToString(auth[:])
+}
+
+// handleEvent converts a supported OBS event and passes it to the event handler
+func (c *Client) handleEvent(payload json.RawMessage) {
+	var msg eventMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		c.log.Warn("Invalid event", "error", err)
+		return
+	}
+
+	eventType := domain.OBSEventType(msg.EventType)
+	if !eventType.IsValid() {
+		return
+	}
+
+	var data eventData
+	if err := json.Unmarshal(msg.EventData, &data); err != nil {
+		c.log.Warn("Invalid event", "event", msg.EventType, "error", err)
+		return
+	}
+
+	event := &domain.OBSEvent{
+		Type:      eventType,
+		SceneName: data.SceneName,
+		Timestamp: time.Now(),
+	}
+
+	// Outputs also report their starting and stopping phases, only the final states count
+	if eventType != domain.OBSSceneChanged {
+		switch data.OutputState {
+		case outputStarted:
+			event.Active = true
+		case outputStopped:
+			event.Active = false
+		default:
+			return
+		}
+	}
+
+	c.mu.RLock()
+	handler := c.eventHandler
+	c.mu.RUnlock()
+
+	if handler != nil {
+		handler(event)
+	}
+}
+
+// backoff returns the delay before the given reconnect attempt
+func (c *Client) backoff(attempt int) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	delay := c.minBackoff
+	for i := 1; i < attempt && delay < c.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+	return delay
+}
+
+// Disconnect stops the managed connection and waits for it to shut down
+func (c *Client) Disconnect() error {
+	c.mu.Lock()
+	cancel := c.cancel
+	done := c.done
+	conn := c.conn
+	c.cancel = nil
+	if cancel != nil {
+		cancel() // Under the lock so a session either sees the cancel or stored its conn
+	}
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	if conn != nil {
+		conn.Close() // Unblocks the read loop
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		c.log.Warn("Timed out waiting for connection to close")
+	}
+
+	return nil
+}
+
+// clearRunning marks the managed connection as stopped without a Disconnect call
+func (c *Client) clearRunning() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
+
+// IsConnected returns whether the client is identified with OBS
+func (c *Client) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status.State == domain.ConnectionConnected
+}
+
+// Status returns the current connection status
+func (c *Client) Status() domain.ConnectionStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
+}
+
+// setStatus stores a new status and notifies the state handler
+func (c *Client) setStatus(status domain.ConnectionStatus) {
+	c.mu.Lock()
+	if c.status.State == status.State && status.State != domain.ConnectionReconnecting {
+		c.mu.Unlock()
+		return
+	}
+	c.status = status
+	handler := c.stateHandler
+	c.mu.Unlock()
+
+	c.log.Info("Connection state changed", "state", status.State)
+
+	if handler != nil {
+		handler(status)
+	}
+}
@@ -0,0 +1,202 @@
+package obs
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPassword  = "supersecret"
+	testSalt      = "lM1GncleQOaCu9lT1yeUZhFYnqhsLLP1G5lAGo3ixaI="
+	testChallenge = "+IxH4CnCiqpX1rM9scsNynZzbOe4KhDeYcTNS3PDaeY="
+)
+
+// fakeOBSServer speaks just enough obs-websocket v5 to identify clients and push events
+type fakeOBSServer struct {
+	listener net.Listener
+	server   *http.Server
+	mu       sync.Mutex
+	conns    []*websocket.Conn
+}
+
+func newFakeOBSServer(t *testing.T, address string) *fakeOBSServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", address)
+	require.NoError(t, err)
+
+	s := &fakeOBSServer{listener: listener}
+	s.server = &http.Server{Handler: http.HandlerFunc(s.handle)}
+	go s.server.Serve(listener)
+	t.Cleanup(s.close)
+
+	return s
+}
+
+func (s *fakeOBSServer) url() string {
+	return "ws://" + s.listener.Addr().String()
+}
+
+func (s *fakeOBSServer) handle(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	hello, _ := json.Marshal(map[string]any{
+		"obsWebSocketVersion": "5.5.0",
+		"rpcVersion":          1,
+		"authentication":      map[string]string{"challenge": testChallenge, "salt": testSalt},
+	})
+	conn.WriteJSON(message{Op: opHello, D: hello})
+
+	var msg message
+	if err := conn.ReadJSON(&msg); err != nil || msg.Op != opIdentify {
+		conn.Close()
+		return
+	}
+	var identify identifyData
+	json.Unmarshal(msg.D, &identify)
+
+	if identify.Authentication != Authenticate(testPassword, testSalt, testChallenge) {
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(closeAuthenticationFailed, "Authentication failed."))
+		conn.Close()
+		return
+	}
+
+	// Register before confirming so events emitted right after the client is identified reach it
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	identified, _ := json.Marshal(map[string]int{"negotiatedRpcVersion": 1})
+	conn.WriteJSON(message{Op: opIdentified, D: identified})
+	s.conns = append(s.conns, conn)
+}
+
+// emit sends an event to every identified client
+func (s *fakeOBSServer) emit(t *testing.T, eventType string, data map[string]any) {
+	t.Helper()
+
+	eventData, err := json.Marshal(data)
+	require.NoError(t, err)
+	payload, err := json.Marshal(eventMessage{EventType: eventType, EventData: eventData})
+	require.NoError(t, err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		require.NoError(t, conn.WriteJSON(message{Op: opEvent, D: payload}))
+	}
+}
+
+// close stops accepting connections and drops the open ones
+func (s *fakeOBSServer) close() {
+	s.server.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+// eventRecorder collects events reported by the client
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []domain.OBSEvent
+}
+
+func (r *eventRecorder) handle(event *domain.OBSEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, *event)
+}
+
+func (r *eventRecorder) get() []domain.OBSEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.OBSEvent(nil), r.events...)
+}
+
+func newTestClient(url, password string) (*Client, *eventRecorder) {
+	client := NewClient(url, password)
+	client.SetBackoff(10*time.Millisecond, 50*time.Millisecond)
+
+	recorder := &eventRecorder{}
+	client.SetEventHandler(recorder.handle)
+
+	return client, recorder
+}
+
+func TestClientReportsEvents(t *testing.T) {
+	server := newFakeOBSServer(t, "127.0.0.1:0")
+	client, recorder := newTestClient(server.url(), testPassword)
+
+	require.NoError(t, client.Connect(t.Context()))
+	require.Eventually(t, client.IsConnected, 2*time.Second, 10*time.Millisecond)
+
+	server.emit(t, "CurrentProgramSceneChanged", map[string]any{"sceneName": "BRB"})
+	server.emit(t, "RecordStateChanged", map[string]any{"outputActive": false, "outputState": "OBS_WEBSOCKET_OUTPUT_STARTING"})
+	server.emit(t, "RecordStateChanged", map[string]any{"outputActive": true, "outputState": "OBS_WEBSOCKET_OUTPUT_STARTED"})
+	server.emit(t, "StreamStateChanged", map[string]any{"outputActive": false, "outputState": "OBS_WEBSOCKET_OUTPUT_STOPPED"})
+	server.emit(t, "InputMuteStateChanged", map[string]any{"inputName": "Mic"})
+
+	require.Eventually(t, func() bool { return len(recorder.get()) == 3 }, 2*time.Second, 10*time.Millisecond)
+	events := recorder.get()
+	assert.Equal(t, domain.OBSSceneChanged, events[0].Type)
+	assert.Equal(t, "BRB", events[0].SceneName)
+	assert.Equal(t, domain.OBSRecordStateChanged, events[1].Type)
+	assert.True(t, events[1].Active, "starting phase is skipped")
+	assert.Equal(t, domain.OBSStreamStateChanged, events[2].Type)
+	assert.False(t, events[2].Active)
+
+	require.NoError(t, client.Disconnect())
+	assert.Equal(t, domain.ConnectionDisconnected, client.Status().State)
+}
+
+func TestClientLifecycle(t *testing.T) {
+	t.Run("stops on wrong password", func(t *testing.T) {
+		server := newFakeOBSServer(t, "127.0.0.1:0")
+		client, _ := newTestClient(server.url(), "wrong")
+
+		require.NoError(t, client.Connect(t.Context()))
+		assert.Eventually(t, func() bool {
+			return client.Status().State == domain.ConnectionAuthFailed
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.NotEmpty(t, client.Status().Error)
+
+		require.NoError(t, client.Disconnect())
+	})
+
+	t.Run("reconnects after OBS restart", func(t *testing.T) {
+		server := newFakeOBSServer(t, "127.0.0.1:0")
+		address := server.listener.Addr().String()
+		client, recorder := newTestClient(server.url(), testPassword)
+
+		require.NoError(t, client.Connect(t.Context()))
+		require.Eventually(t, client.IsConnected, 2*time.Second, 10*time.Millisecond)
+
+		server.close()
+		assert.Eventually(t, func() bool {
+			return client.Status().State == domain.ConnectionReconnecting
+		}, 2*time.Second, 10*time.Millisecond)
+
+		server = newFakeOBSServer(t, address)
+		require.Eventually(t, client.IsConnected, 2*time.Second, 10*time.Millisecond)
+
+		server.emit(t, "CurrentProgramSceneChanged", map[string]any{"sceneName": "Live"})
+		assert.Eventually(t, func() bool { return len(recorder.get()) == 1 }, 2*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, client.Disconnect())
+	})
+}
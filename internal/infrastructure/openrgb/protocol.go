@@ -0,0 +1,378 @@
+package openrgb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// ProtocolVersion is the highest SDK protocol version the server speaks.
+// Version 3 adds mode brightness, version 4 zone segments and plugins.
+const ProtocolVersion uint32 = 3
+
+// headerSize is the size of a packet header: magic, device index, packet ID and data size
+const headerSize = 16
+
+// maxPacketSize bounds the data of a packet, a single LED controller never needs more
+const maxPacketSize = 1 << 20
+
+// magic starts every packet
+var magic = [4]byte{'O', 'R', 'G', 'B'}
+
+// Packet IDs
+const (
+	PacketRequestControllerCount = 0
+	PacketRequestControllerData  = 1
+	PacketRequestProtocolVersion = 40
+	PacketSetClientName          = 50
+	PacketDeviceListUpdated      = 100
+	PacketRequestProfileList     = 150
+	PacketResizeZone             = 1000
+	PacketUpdateLEDs             = 1050
+	PacketUpdateZoneLEDs         = 1051
+	PacketUpdateSingleLED        = 1052
+	PacketSetCustomMode          = 1100
+	PacketUpdateMode             = 1101
+	PacketSaveMode               = 1102
+)
+
+// Device and zone types
+const (
+	DeviceTypeLEDStrip = 4
+	ZoneTypeSingle     = 0
+)
+
+// Mode flags
+const (
+	ModeFlagHasSpeed       = 1 << 0
+	ModeFlagHasPerLEDColor = 1 << 5
+)
+
+// Mode color modes
+const (
+	ColorModeNone   = 0
+	ColorModePerLED = 1
+)
+
+// ErrBadMagic is returned for data that is not an SDK packet
+var ErrBadMagic = errors.New("not an OpenRGB packet")
+
+// Header starts every packet
+type Header struct {
+	DeviceIndex uint32
+	PacketID    uint32
+	Size        uint32
+}
+
+// Packet is a packet read from a client
+type Packet struct {
+	Header
+	Data []byte
+}
+
+// ReadPacket reads the next packet
+func ReadPacket(r io.Reader) (Packet, error) {
+	var buf [headerSize]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return Packet{}, err
+	}
+	if !bytes.Equal(buf[:4], magic[:]) {
+		return Packet{}, ErrBadMagic
+	}
+
+	packet := Packet{Header: Header{
+		DeviceIndex: binary.LittleEndian.Uint32(buf[4:]),
+		PacketID:    binary.LittleEndian.Uint32(buf[8:]),
+		Size:        binary.LittleEndian.Uint32(buf[12:]),
+	}}
+	if packet.Size > maxPacketSize {
+		return Packet{}, fmt.Errorf("packet of %d bytes too large", packet.Size)
+	}
+
+	packet.Data = make([]byte, packet.Size)
+	if _, err := io.ReadFull(r, packet.Data); err != nil {
+		return Packet{}, err
+	}
+	return packet, nil
+}
+
+// EncodePacket encodes a packet with its header
+func EncodePacket(deviceIndex, packetID uint32, data []byte) []byte {
+	buf := make([]byte, headerSize, headerSize+len(data))
+	copy(buf, magic[:])
+	binary.LittleEndian.PutUint32(buf[4:], deviceIndex)
+	binary.LittleEndian.PutUint32(buf[8:], packetID)
+	binary.LittleEndian.PutUint32(buf[12:], uint32(len(data)))
+	return append(buf, data...)
+}
+
+// Mode is a mode of a controller
+type Mode struct {
+	Name      string
+	Value     int32 // Lamp effect index, unused for Direct
+	Flags     uint32
+	SpeedMin  uint32
+	SpeedMax  uint32
+	Speed     uint32
+	ColorMode uint32
+}
+
+// Controller is a lamp as an OpenRGB controller: an LED strip with a single
+// zone of a single LED, driven directly or by one of the lamp's effects
+type Controller struct {
+	Address    string
+	Name       string
+	Modes      []Mode // Direct first, then the effects
+	ActiveMode int
+	Color      domain.RGB
+}
+
+// NewController builds the controller of a lamp. A lamp that is off reports black.
+func NewController(device *domain.Device) Controller {
+	name := device.Name
+	if name == "" {
+		name = device.Address
+	}
+
+	state := device.State
+	speed := domain.DefaultEffectSpeed
+	if state.EffectSpeed != nil {
+		speed = *state.EffectSpeed
+	}
+
+	controller := Controller{
+		Address: device.Address,
+		Name:    name,
+		Modes: []Mode{{
+			Name:      "Direct",
+			Flags:     ModeFlagHasPerLEDColor,
+			ColorMode: ColorModePerLED,
+		}},
+	}
+
+	for _, effectName := range domain.EffectNames() {
+		effect := int32(domain.EffectMap[effectName])
+		if state.Effect != nil && int32(*state.Effect) == effect {
+			controller.ActiveMode = len(controller.Modes)
+		}
+		controller.Modes = append(controller.Modes, Mode{
+			Name:      strings.ToUpper(effectName[:1]) + effectName[1:],
+			Value:     effect,
+			Flags:     ModeFlagHasSpeed,
+			SpeedMin:  0,
+			SpeedMax:  255,
+			Speed:     uint32(speed),
+			ColorMode: ColorModeNone,
+		})
+	}
+
+	if state.PowerOn && state.RGB != nil {
+		controller.Color = *state.RGB
+	}
+	return controller
+}
+
+// SortControllers orders controllers by address, the order of the device indices
+func SortControllers(controllers []Controller) {
+	sort.Slice(controllers, func(i, j int) bool {
+		return controllers[i].Address < controllers[j].Address
+	})
+}
+
+// Encode encodes the controller data for a protocol version
+func (c Controller) Encode(protocol uint32) []byte {
+	var e encoder
+	e.u32(0) // Data size, filled in below
+	e.i32(DeviceTypeLEDStrip)
+	e.str(c.Name)
+	if protocol >= 1 {
+		e.str("ELK-BLEDOM")
+	}
+	e.str("Bluetooth LED strip controlled by LampControl")
+	e.str("")
+	e.str(c.Address)
+	e.str("Bluetooth: " + c.Address)
+
+	e.u16(uint16(len(c.Modes)))
+	e.i32(int32(c.ActiveMode))
+	for _, mode := range c.Modes {
+		e.str(mode.Name)
+		e.i32(mode.Value)
+		e.u32(mode.Flags)
+		e.u32(mode.SpeedMin)
+		e.u32(mode.SpeedMax)
+		if protocol >= 3 {
+			e.u32(0) // Brightness min
+			e.u32(0) // Brightness max
+		}
+		e.u32(0) // Colors min
+		e.u32(0) // Colors max
+		e.u32(mode.Speed)
+		if protocol >= 3 {
+			e.u32(0) // Brightness
+		}
+		e.u32(0) // Direction
+		e.u32(mode.ColorMode)
+		e.u16(0) // Mode colors
+	}
+
+	e.u16(1) // Zones
+	e.str("Strip")
+	e.i32(ZoneTypeSingle)
+	e.u32(1) // LEDs min
+	e.u32(1) // LEDs max
+	e.u32(1) // LEDs count
+	e.u16(0) // No matrix map
+
+	e.u16(1) // LEDs
+	e.str("LED")
+	e.u32(0)
+
+	e.u16(1) // Colors
+	e.color(c.Color)
+
+	data := e.Bytes()
+	binary.LittleEndian.PutUint32(data, uint32(len(data)))
+	return data
+}
+
+// ParseUpdateLEDs returns the colors of an UpdateLEDs packet
+func ParseUpdateLEDs(data []byte) ([]domain.RGB, error) {
+	d := decoder{data: data}
+	d.u32() // Data size
+	return d.colors(), d.err
+}
+
+// ParseUpdateZoneLEDs returns the zone and colors of an UpdateZoneLEDs packet
+func ParseUpdateZoneLEDs(data []byte) (uint32, []domain.RGB, error) {
+	d := decoder{data: data}
+	d.u32() // Data size
+	zone := d.u32()
+	colors := d.colors()
+	return zone, colors, d.err
+}
+
+// ParseUpdateSingleLED returns the LED and color of an UpdateSingleLED packet
+func ParseUpdateSingleLED(data []byte) (int32, domain.RGB, error) {
+	d := decoder{data: data}
+	led := d.i32()
+	color := d.color()
+	return led, color, d.err
+}
+
+// ParseUpdateMode returns the mode index and speed of an UpdateMode or SaveMode packet
+func ParseUpdateMode(data []byte, protocol uint32) (int32, uint32, error) {
+	d := decoder{data: data}
+	d.u32() // Data size
+	index := d.i32()
+	d.str() // Name
+	d.i32() // Value
+	d.u32() // Flags
+	d.u32() // Speed min
+	d.u32() // Speed max
+	if protocol >= 3 {
+		d.u32() // Brightness min
+		d.u32() // Brightness max
+	}
+	d.u32() // Colors min
+	d.u32() // Colors max
+	speed := d.u32()
+	return index, speed, d.err
+}
+
+// encoder writes little endian SDK fields
+type encoder struct {
+	bytes.Buffer
+}
+
+func (e *encoder) u16(v uint16) {
+	e.Write(binary.LittleEndian.AppendUint16(nil, v))
+}
+
+func (e *encoder) u32(v uint32) {
+	e.Write(binary.LittleEndian.AppendUint32(nil, v))
+}
+
+func (e *encoder) i32(v int32) {
+	e.u32(uint32(v))
+}
+
+// str writes a string with its length, which counts the terminating null
+func (e *encoder) str(s string) {
+	e.u16(uint16(len(s) + 1))
+	e.WriteString(s)
+	e.WriteByte(0)
+}
+
+// color writes a color padded to four bytes
+func (e *encoder) color(c domain.RGB) {
+	e.Write([]byte{c.R, c.G, c.B, 0})
+}
+
+// decoder reads little endian SDK fields, remembering the first error
+type decoder struct {
+	data []byte
+	err  error
+}
+
+// next returns the next n bytes, or nil once the data is exhausted
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.data) < n {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *decoder) u16() uint16 {
+	b := d.next(2)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(b)
+}
+
+func (d *decoder) u32() uint32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (d *decoder) i32() int32 {
+	return int32(d.u32())
+}
+
+func (d *decoder) str() string {
+	b := d.next(int(d.u16()))
+	return string(bytes.TrimRight(b, "\x00"))
+}
+
+func (d *decoder) color() domain.RGB {
+	b := d.next(4)
+	if b == nil {
+		return domain.RGB{}
+	}
+	return domain.RGB{R: b[0], G: b[1], B: b[2]}
+}
+
+func (d *decoder) colors() []domain.RGB {
+	count := int(d.u16())
+	colors := make([]domain.RGB, 0, min(count, len(d.data)/4))
+	for i := 0; i < count && d.err == nil; i++ {
+		colors = append(colors, d.color())
+	}
+	return colors
+}
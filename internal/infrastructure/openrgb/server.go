@@ -0,0 +1,275 @@
+package openrgb
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+)
+
+// Backend provides the controllers of the server and applies what clients send
+type Backend interface {
+	// Controllers returns the lamps ordered by device index
+	Controllers() []Controller
+	// SetColor shows a color on a lamp
+	SetColor(address string, color domain.RGB)
+	// SetEffect runs a built-in effect on a lamp
+	SetEffect(address string, effect, speed uint8)
+}
+
+// Server serves the OpenRGB network SDK, so OpenRGB and its effect plugins
+// see each lamp as an LED strip controller
+type Server struct {
+	backend         Backend
+	onClientsChange func()
+	listener        net.Listener
+	clients         map[*client]struct{}
+	closed          bool
+	serving         sync.WaitGroup
+	mu              sync.Mutex
+	log             *slog.Logger
+}
+
+// client is a connected SDK client
+type client struct {
+	conn     net.Conn
+	name     string
+	protocol uint32 // Negotiated protocol version
+	writeMu  sync.Mutex
+}
+
+// NewServer creates a server; onClientsChange, if set, is called when a client
+// connects, names itself or disconnects
+func NewServer(backend Backend, onClientsChange func()) *Server {
+	return &Server{
+		backend:         backend,
+		onClientsChange: onClientsChange,
+		clients:         make(map[*client]struct{}),
+		log:             logging.Source("openrgb"),
+	}
+}
+
+// Serve accepts clients until the server is closed
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		c := &client{conn: conn}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		s.clients[c] = struct{}{}
+		s.serving.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.serving.Done()
+			s.serve(c)
+		}()
+		s.clientsChanged()
+	}
+}
+
+// Close stops accepting clients and disconnects the connected ones
+func (s *Server) Close() error {
+	s.mu.Lock()
+	listener := s.listener
+	s.closed = true
+	for c := range s.clients {
+		c.conn.Close()
+	}
+	s.mu.Unlock()
+
+	var err error
+	if listener != nil {
+		err = listener.Close()
+	}
+	s.serving.Wait()
+	return err
+}
+
+// Clients returns the names of the connected clients, or their address until they named themselves
+func (s *Server) Clients() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.clients))
+	for c := range s.clients {
+		name := c.name
+		if name == "" {
+			name = c.conn.RemoteAddr().String()
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DeviceListUpdated tells the clients to fetch the controllers again
+func (s *Server) DeviceListUpdated() {
+	s.mu.Lock()
+	clients := make([]*client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range clients {
+		c.send(0, PacketDeviceListUpdated, nil)
+	}
+}
+
+// clientsChanged reports a change of the connected clients
+func (s *Server) clientsChanged() {
+	if s.onClientsChange != nil {
+		s.onClientsChange()
+	}
+}
+
+// serve answers the packets of a client until it disconnects
+func (s *Server) serve(c *client) {
+	defer func() {
+		c.conn.Close()
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+		s.clientsChanged()
+	}()
+
+	for {
+		packet, err := ReadPacket(c.conn)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				s.log.Warn("Dropping client", "remote", c.conn.RemoteAddr().String(), "error", err)
+			}
+			return
+		}
+		s.handle(c, packet)
+	}
+}
+
+// handle answers a packet. Like OpenRGB itself, packets for unknown devices
+// and malformed updates are ignored.
+func (s *Server) handle(c *client, packet Packet) {
+	switch packet.PacketID {
+	case PacketRequestControllerCount:
+		c.send(0, PacketRequestControllerCount, binary.LittleEndian.AppendUint32(nil, uint32(len(s.backend.Controllers()))))
+
+	case PacketRequestControllerData:
+		controller, ok := s.controller(packet.DeviceIndex)
+		if !ok {
+			return
+		}
+		protocol := uint32(0)
+		if len(packet.Data) >= 4 {
+			protocol = min(binary.LittleEndian.Uint32(packet.Data), ProtocolVersion)
+		}
+		c.send(packet.DeviceIndex, PacketRequestControllerData, controller.Encode(protocol))
+
+	case PacketRequestProtocolVersion:
+		protocol := uint32(0)
+		if len(packet.Data) >= 4 {
+			protocol = binary.LittleEndian.Uint32(packet.Data)
+		}
+		s.mu.Lock()
+		c.protocol = min(protocol, ProtocolVersion)
+		s.mu.Unlock()
+		c.send(0, PacketRequestProtocolVersion, binary.LittleEndian.AppendUint32(nil, ProtocolVersion))
+
+	case PacketSetClientName:
+		s.mu.Lock()
+		c.name = strings.TrimRight(string(packet.Data), "\x00")
+		s.mu.Unlock()
+		s.log.Info("Client connected", "client", c.name, "remote", c.conn.RemoteAddr().String())
+		s.clientsChanged()
+
+	case PacketRequestProfileList:
+		// Profiles live in OpenRGB, the server has none
+		c.send(0, PacketRequestProfileList, []byte{6, 0, 0, 0, 0, 0})
+
+	case PacketUpdateLEDs:
+		colors, err := ParseUpdateLEDs(packet.Data)
+		if err == nil && len(colors) > 0 {
+			s.setColor(packet.DeviceIndex, colors[0])
+		}
+
+	case PacketUpdateZoneLEDs:
+		zone, colors, err := ParseUpdateZoneLEDs(packet.Data)
+		if err == nil && zone == 0 && len(colors) > 0 {
+			s.setColor(packet.DeviceIndex, colors[0])
+		}
+
+	case PacketUpdateSingleLED:
+		led, color, err := ParseUpdateSingleLED(packet.Data)
+		if err == nil && led == 0 {
+			s.setColor(packet.DeviceIndex, color)
+		}
+
+	case PacketUpdateMode, PacketSaveMode:
+		s.mu.Lock()
+		protocol := c.protocol
+		s.mu.Unlock()
+
+		index, speed, err := ParseUpdateMode(packet.Data, protocol)
+		if err != nil {
+			return
+		}
+		controller, ok := s.controller(packet.DeviceIndex)
+		if !ok || index < 0 || int(index) >= len(controller.Modes) {
+			return
+		}
+
+		// Direct shows the colors sent next, effects start right away
+		if index > 0 {
+			mode := controller.Modes[index]
+			s.backend.SetEffect(controller.Address, uint8(mode.Value), uint8(min(speed, mode.SpeedMax)))
+		}
+
+	case PacketResizeZone, PacketSetCustomMode:
+		// The single LED can't be resized and Direct needs no switching
+	}
+}
+
+// controller returns the controller with a device index
+func (s *Server) controller(index uint32) (Controller, bool) {
+	controllers := s.backend.Controllers()
+	if int(index) >= len(controllers) {
+		return Controller{}, false
+	}
+	return controllers[index], true
+}
+
+// setColor shows a color on the lamp with a device index
+func (s *Server) setColor(index uint32, color domain.RGB) {
+	if controller, ok := s.controller(index); ok {
+		s.backend.SetColor(controller.Address, color)
+	}
+}
+
+// send writes a packet to the client, dropping it if the client is gone
+func (c *client) send(deviceIndex, packetID uint32, data []byte) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.conn.Write(EncodePacket(deviceIndex, packetID, data))
+}
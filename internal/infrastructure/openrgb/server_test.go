@@ -0,0 +1,223 @@
+package openrgb
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend records the colors and effects clients send
+type fakeBackend struct {
+	mu          sync.Mutex
+	controllers []Controller
+	colors      map[string]domain.RGB
+	effects     map[string][2]uint8
+}
+
+func newFakeBackend() *fakeBackend {
+	desk := domain.NewDevice("BE:27:EB:00:00:01", "Desk", -50)
+	desk.State = domain.DeviceState{PowerOn: true, Brightness: 255, RGB: &domain.RGB{R: 255, G: 64}}
+	shelf := domain.NewDevice("BE:27:EB:00:00:02", "", -60)
+
+	return &fakeBackend{
+		controllers: []Controller{NewController(desk), NewController(shelf)},
+		colors:      make(map[string]domain.RGB),
+		effects:     make(map[string][2]uint8),
+	}
+}
+
+func (f *fakeBackend) Controllers() []Controller {
+	return f.controllers
+}
+
+func (f *fakeBackend) SetColor(address string, color domain.RGB) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.colors[address] = color
+}
+
+func (f *fakeBackend) SetEffect(address string, effect, speed uint8) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.effects[address] = [2]uint8{effect, speed}
+}
+
+func (f *fakeBackend) color(address string) (domain.RGB, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	color, ok := f.colors[address]
+	return color, ok
+}
+
+func (f *fakeBackend) effect(address string) ([2]uint8, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	effect, ok := f.effects[address]
+	return effect, ok
+}
+
+// startServer serves the backend on a local port and connects a client
+func startServer(t *testing.T, backend Backend) (*Server, net.Conn) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := NewServer(backend, nil)
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(listener) }()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		assert.NoError(t, server.Close())
+		assert.NoError(t, <-done)
+	})
+	return server, conn
+}
+
+// request sends a packet and reads the answer
+func request(t *testing.T, conn net.Conn, deviceIndex, packetID uint32, data []byte) Packet {
+	_, err := conn.Write(EncodePacket(deviceIndex, packetID, data))
+	require.NoError(t, err)
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	packet, err := ReadPacket(conn)
+	require.NoError(t, err)
+	assert.Equal(t, packetID, packet.PacketID)
+	return packet
+}
+
+func u32(v uint32) []byte {
+	return binary.LittleEndian.AppendUint32(nil, v)
+}
+
+func TestServerDescribesLampsAsControllers(t *testing.T) {
+	_, conn := startServer(t, newFakeBackend())
+
+	version := request(t, conn, 0, PacketRequestProtocolVersion, u32(4))
+	assert.Equal(t, ProtocolVersion, binary.LittleEndian.Uint32(version.Data))
+
+	count := request(t, conn, 0, PacketRequestControllerCount, nil)
+	assert.Equal(t, uint32(2), binary.LittleEndian.Uint32(count.Data))
+
+	packet := request(t, conn, 0, PacketRequestControllerData, u32(ProtocolVersion))
+	d := decoder{data: packet.Data}
+	assert.Equal(t, uint32(len(packet.Data)), d.u32())
+	assert.Equal(t, int32(DeviceTypeLEDStrip), d.i32())
+	assert.Equal(t, "Desk", d.str())
+	assert.Equal(t, "ELK-BLEDOM", d.str())
+	d.str() // Description
+	d.str() // Version
+	assert.Equal(t, "BE:27:EB:00:00:01", d.str())
+	d.str() // Location
+
+	modes := int(d.u16())
+	assert.Equal(t, 1+len(domain.EffectMap), modes)
+	assert.Equal(t, int32(0), d.i32(), "Direct is active")
+	for i := 0; i < modes; i++ {
+		name := d.str()
+		if i == 0 {
+			assert.Equal(t, "Direct", name)
+		}
+		for j := 0; j < 12; j++ { // Value to color mode at protocol 3
+			d.u32()
+		}
+		assert.Equal(t, uint16(0), d.u16())
+	}
+
+	assert.Equal(t, uint16(1), d.u16(), "one zone")
+	d.str()
+	assert.Equal(t, int32(ZoneTypeSingle), d.i32())
+	assert.Equal(t, []uint32{1, 1, 1}, []uint32{d.u32(), d.u32(), d.u32()})
+	assert.Equal(t, uint16(0), d.u16())
+
+	assert.Equal(t, uint16(1), d.u16(), "one LED")
+	d.str()
+	d.u32()
+
+	assert.Equal(t, []domain.RGB{{R: 255, G: 64}}, d.colors())
+	require.NoError(t, d.err)
+	assert.Empty(t, d.data)
+
+	// Protocol 0 clients get no vendor
+	old := request(t, conn, 1, PacketRequestControllerData, nil)
+	d = decoder{data: old.Data}
+	d.u32()
+	d.i32()
+	assert.Equal(t, "BE:27:EB:00:00:02", d.str(), "unnamed lamps go by address")
+	assert.Equal(t, "Bluetooth LED strip controlled by LampControl", d.str())
+}
+
+func TestServerAppliesColorsAndModes(t *testing.T) {
+	backend := newFakeBackend()
+	_, conn := startServer(t, backend)
+	request(t, conn, 0, PacketRequestProtocolVersion, u32(ProtocolVersion))
+
+	send := func(deviceIndex, packetID uint32, data []byte) {
+		_, err := conn.Write(EncodePacket(deviceIndex, packetID, data))
+		require.NoError(t, err)
+	}
+
+	// UpdateLEDs: data size, color count, colors
+	send(1, PacketUpdateLEDs, []byte{10, 0, 0, 0, 1, 0, 0, 128, 255, 0})
+	require.Eventually(t, func() bool {
+		color, ok := backend.color("BE:27:EB:00:00:02")
+		return ok && color == domain.RGB{G: 128, B: 255}
+	}, time.Second, 5*time.Millisecond)
+
+	// UpdateSingleLED on another LED than the only one is ignored
+	send(0, PacketUpdateSingleLED, append(u32(1), 1, 2, 3, 0))
+	send(0, PacketUpdateSingleLED, append(u32(0), 9, 8, 7, 0))
+	require.Eventually(t, func() bool {
+		color, ok := backend.color("BE:27:EB:00:00:01")
+		return ok && color == domain.RGB{R: 9, G: 8, B: 7}
+	}, time.Second, 5*time.Millisecond)
+
+	// UpdateMode to the second effect at speed 200
+	var e encoder
+	e.u32(0)
+	e.i32(2)
+	mode := backend.controllers[0].Modes[2]
+	e.str(mode.Name)
+	e.i32(mode.Value)
+	e.u32(mode.Flags)
+	e.u32(mode.SpeedMin)
+	e.u32(mode.SpeedMax)
+	e.u32(0)
+	e.u32(0)
+	e.u32(0)
+	e.u32(0)
+	e.u32(200)
+	send(0, PacketUpdateMode, e.Bytes())
+	require.Eventually(t, func() bool {
+		effect, ok := backend.effect("BE:27:EB:00:00:01")
+		return ok && effect == [2]uint8{uint8(mode.Value), 200}
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestServerTracksClients(t *testing.T) {
+	server, conn := startServer(t, newFakeBackend())
+
+	_, err := conn.Write(EncodePacket(0, PacketSetClientName, []byte("OpenRGB Effects Plugin\x00")))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		clients := server.Clients()
+		return len(clients) == 1 && clients[0] == "OpenRGB Effects Plugin"
+	}, time.Second, 5*time.Millisecond)
+
+	server.DeviceListUpdated()
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	packet, err := ReadPacket(conn)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(PacketDeviceListUpdated), packet.PacketID)
+
+	conn.Close()
+	require.Eventually(t, func() bool { return len(server.Clients()) == 0 }, time.Second, 5*time.Millisecond)
+}
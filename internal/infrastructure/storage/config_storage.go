@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// Config is an integration configuration kept by a ConfigStorage
+type Config interface {
+	Validate() error
+}
+
+// cryptFunc encrypts or decrypts a secret
+type cryptFunc func(secret string) (string, error)
+
+// ConfigStorage handles persistent storage of an integration configuration
+// as a JSON file in the config directory, with its secrets encrypted
+type ConfigStorage[T Config] struct {
+	filePath string
+	name     string // Used in errors, e.g. "OBS"
+	encKey   []byte
+	secrets  func(config T, crypt cryptFunc) (T, error) // Copies the config with its secrets passed through crypt, nil if it has none
+	mu       sync.RWMutex
+	config   T
+}
+
+// Storages of the integration configurations
+type (
+	OBSStorage     = ConfigStorage[*domain.OBSConfig]
+	AlertStorage   = ConfigStorage[*domain.AlertConfig]
+	MQTTStorage    = ConfigStorage[*domain.MQTTConfig]
+	HueStorage     = ConfigStorage[*domain.HueConfig]
+	DMXStorage     = ConfigStorage[*domain.DMXConfig]
+	OpenRGBStorage = ConfigStorage[*domain.OpenRGBConfig]
+)
+
+// NewOBSStorage creates a new OBS storage instance
+func NewOBSStorage() (*OBSStorage, error) {
+	return newConfigStorage("obs_config.json", "OBS", domain.NewOBSConfig, func(config *domain.OBSConfig, crypt cryptFunc) (*domain.OBSConfig, error) {
+		result := *config
+		var err error
+		result.Password, err = crypt(config.Password)
+		return &result, err
+	})
+}
+
+// NewAlertStorage creates a new alert storage instance
+func NewAlertStorage() (*AlertStorage, error) {
+	return newConfigStorage("alert_config.json", "alert", domain.NewAlertConfig, cryptAlertSecrets)
+}
+
+// NewMQTTStorage creates a new MQTT storage instance
+func NewMQTTStorage() (*MQTTStorage, error) {
+	return newConfigStorage("mqtt_config.json", "MQTT", domain.NewMQTTConfig, func(config *domain.MQTTConfig, crypt cryptFunc) (*domain.MQTTConfig, error) {
+		result := *config
+		var err error
+		result.Password, err = crypt(config.Password)
+		return &result, err
+	})
+}
+
+// NewHueStorage creates a new Hue storage instance
+func NewHueStorage() (*HueStorage, error) {
+	return newConfigStorage("hue_config.json", "Hue", domain.NewHueConfig, func(config *domain.HueConfig, crypt cryptFunc) (*domain.HueConfig, error) {
+		result := config.Clone()
+		for i, user := range result.Users {
+			username, err := crypt(user.Username)
+			if err != nil {
+				return nil, err
+			}
+			result.Users[i].Username = username
+		}
+		return result, nil
+	})
+}
+
+// NewDMXStorage creates a new DMX storage instance
+func NewDMXStorage() (*DMXStorage, error) {
+	return newConfigStorage("dmx_config.json", "DMX", domain.NewDMXConfig, nil)
+}
+
+// NewOpenRGBStorage creates a new OpenRGB storage instance
+func NewOpenRGBStorage() (*OpenRGBStorage, error) {
+	return newConfigStorage("openrgb_config.json", "OpenRGB", domain.NewOpenRGBConfig, nil)
+}
+
+// newConfigStorage creates a storage for a config file in the config directory,
+// starting from the defaults until the file is saved
+func newConfigStorage[T Config](fileName, name string, defaults func() T, secrets func(T, cryptFunc) (T, error)) (*ConfigStorage[T], error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	configDir := filepath.Join(homeDir, ".lampcontrol")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	storage := &ConfigStorage[T]{
+		filePath: filepath.Join(configDir, fileName),
+		name:     name,
+		encKey:   generateEncryptionKey(),
+		secrets:  secrets,
+		config:   defaults(),
+	}
+
+	if err := storage.load(); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to load %s config: %w", name, err)
+		}
+	}
+
+	return storage, nil
+}
+
+// Get returns the current configuration
+func (s *ConfigStorage[T]) Get() T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.config
+}
+
+// Save validates and saves the configuration
+func (s *ConfigStorage[T]) Save(config T) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config = config
+	return s.persist()
+}
+
+// persist saves config to file with the secrets encrypted
+func (s *ConfigStorage[T]) persist() error {
+	encConfig := s.config
+	if s.secrets != nil {
+		var err error
+		encConfig, err = s.secrets(s.config, func(secret string) (string, error) {
+			return encryptSecret(s.encKey, secret)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s secrets: %w", s.name, err)
+		}
+	}
+
+	data, err := json.MarshalIndent(encConfig, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s config: %w", s.name, err)
+	}
+
+	if err := os.WriteFile(s.filePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s config file: %w", s.name, err)
+	}
+
+	return nil
+}
+
+// load loads config from file
+func (s *ConfigStorage[T]) load() error {
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return err
+	}
+
+	// Decoding onto the defaults would mix their list entries with the stored ones
+	config := reflect.New(reflect.TypeFor[T]().Elem()).Interface().(T)
+	if err := json.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("failed to unmarshal %s config: %w", s.name, err)
+	}
+
+	if s.secrets != nil {
+		config, err = s.secrets(config, func(secret string) (string, error) {
+			return decryptSecret(s.encKey, secret)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s secrets: %w", s.name, err)
+		}
+	}
+
+	s.config = config
+	return nil
+}
+
+// cryptAlertSecrets returns a copy of config with every secret passed through crypt
+func cryptAlertSecrets(config *domain.AlertConfig, crypt cryptFunc) (*domain.AlertConfig, error) {
+	result := *config
+	result.Webhooks = make([]domain.WebhookConfig, len(config.Webhooks))
+	copy(result.Webhooks, config.Webhooks)
+
+	var err error
+	for i := range result.Webhooks {
+		if result.Webhooks[i].Secret, err = crypt(result.Webhooks[i].Secret); err != nil {
+			return nil, err
+		}
+	}
+	if result.StreamElements.Token, err = crypt(result.StreamElements.Token); err != nil {
+		return nil, err
+	}
+	if result.Streamlabs.Token, err = crypt(result.Streamlabs.Token); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
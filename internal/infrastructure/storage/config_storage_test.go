@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigStorageEncryptsSecrets(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	obsStorage, err := NewOBSStorage()
+	require.NoError(t, err)
+	assert.Len(t, obsStorage.Get().Mappings, 4, "starts with the example mappings")
+
+	config := domain.NewOBSConfig()
+	config.Password = "hunter2"
+	config.Mappings = config.Mappings[:1]
+	require.NoError(t, obsStorage.Save(config))
+
+	data, err := os.ReadFile(filepath.Join(home, ".lampcontrol", "obs_config.json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hunter2")
+
+	reloaded, err := NewOBSStorage()
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", reloaded.Get().Password)
+	assert.Equal(t, config.Mappings, reloaded.Get().Mappings)
+
+	// Invalid configs are not saved
+	config.Address = "http://localhost"
+	assert.Error(t, obsStorage.Save(config))
+}
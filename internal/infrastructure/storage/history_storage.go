@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// DefaultHistoryLimit is how many command records the history keeps
+const DefaultHistoryLimit = 50000
+
+// HistoryStorage handles persistent storage of Twitch command history.
+// Records are appended to a JSON Lines file so a write usually doesn't rewrite the history.
+// Once the file holds a tenth more records than the limit, the oldest records are dropped
+// and the file is rewritten.
+type HistoryStorage struct {
+	filePath string
+	limit    int
+	mu       sync.RWMutex
+	records  []*domain.CommandRecord
+}
+
+// NewHistoryStorage creates a new history storage instance
+func NewHistoryStorage() (*HistoryStorage, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	configDir := filepath.Join(homeDir, ".lampcontrol")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	return NewHistoryStorageAt(filepath.Join(configDir, "twitch_history.jsonl"), DefaultHistoryLimit)
+}
+
+// NewHistoryStorageAt creates a history storage backed by the given file, keeping at most limit records
+func NewHistoryStorageAt(filePath string, limit int) (*HistoryStorage, error) {
+	storage := &HistoryStorage{
+		filePath: filePath,
+		limit:    limit,
+		records:  make([]*domain.CommandRecord, 0),
+	}
+
+	if err := storage.load(); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to load history: %w", err)
+		}
+	}
+
+	if len(storage.records) > limit {
+		if err := storage.compact(); err != nil {
+			return nil, err
+		}
+	}
+
+	return storage, nil
+}
+
+// Append persists a command record
+func (s *HistoryStorage) Append(record *domain.CommandRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	s.records = append(s.records, record)
+
+	// Rewriting on every append past the limit would be as costly as the unbounded file
+	if len(s.records) > s.limit+s.limit/10 {
+		return s.compact()
+	}
+	return nil
+}
+
+// Query returns matching records, newest first
+func (s *HistoryStorage) Query(filter domain.CommandHistoryFilter) []*domain.CommandRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]*domain.CommandRecord, 0)
+	for i := len(s.records) - 1; i >= 0; i-- {
+		if !filter.Matches(s.records[i]) {
+			continue
+		}
+		records = append(records, s.records[i])
+		if filter.Limit > 0 && len(records) >= filter.Limit {
+			break
+		}
+	}
+
+	return records
+}
+
+// All returns all records in the order they were written
+func (s *HistoryStorage) All() []*domain.CommandRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]*domain.CommandRecord(nil), s.records...)
+}
+
+// compact drops the oldest records beyond the limit and rewrites the file
+func (s *HistoryStorage) compact() error {
+	s.records = append([]*domain.CommandRecord(nil), s.records[len(s.records)-s.limit:]...)
+
+	var buf bytes.Buffer
+	for _, record := range s.records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal record: %w", err)
+		}
+		buf.Write(append(data, '\n'))
+	}
+
+	if err := replaceFile(s.filePath, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to compact history: %w", err)
+	}
+	return nil
+}
+
+// load loads records from file, skipping lines that cannot be parsed
+func (s *HistoryStorage) load() error {
+	file, err := os.Open(s.filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record domain.CommandRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// A crash mid-append leaves a partial last line
+			continue
+		}
+		s.records = append(s.records, &record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return nil
+}
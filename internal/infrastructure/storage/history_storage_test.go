@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRecord(username, command string, outcome domain.CommandOutcome, at time.Time) *domain.CommandRecord {
+	cmd := &domain.ChatCommand{Username: username, Command: command, Timestamp: at}
+	return domain.NewCommandRecord("stream-1", cmd, outcome, "")
+}
+
+func TestHistoryStorageAppendAndQuery(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+	storage, err := NewHistoryStorageAt(filePath, 100)
+	require.NoError(t, err)
+
+	start := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	require.NoError(t, storage.Append(newRecord("alice", "red", domain.OutcomeSuccess, start)))
+	require.NoError(t, storage.Append(newRecord("bob", "red", domain.OutcomeCooldown, start.Add(time.Minute))))
+	require.NoError(t, storage.Append(newRecord("alice", "rainbow", domain.OutcomeSuccess, start.Add(2*time.Minute))))
+
+	// A crash mid-append leaves a partial line that loading skips
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"id":"broken`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	reloaded, err := NewHistoryStorageAt(filePath, 100)
+	require.NoError(t, err)
+	assert.Len(t, reloaded.All(), 3)
+
+	tests := []struct {
+		name     string
+		filter   domain.CommandHistoryFilter
+		commands []string
+	}{
+		{name: "all, newest first", filter: domain.CommandHistoryFilter{}, commands: []string{"rainbow", "red", "red"}},
+		{name: "username ignores case", filter: domain.CommandHistoryFilter{Username: "Alice"}, commands: []string{"rainbow", "red"}},
+		{name: "outcome", filter: domain.CommandHistoryFilter{Outcome: domain.OutcomeCooldown}, commands: []string{"red"}},
+		{name: "time range", filter: domain.CommandHistoryFilter{Since: start.Add(time.Minute), Until: start.Add(time.Minute)}, commands: []string{"red"}},
+		{name: "limit", filter: domain.CommandHistoryFilter{Limit: 1}, commands: []string{"rainbow"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands := make([]string, 0)
+			for _, record := range reloaded.Query(tt.filter) {
+				commands = append(commands, record.Command)
+			}
+			assert.Equal(t, tt.commands, commands)
+		})
+	}
+}
+
+func TestHistoryStorageRetention(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+	storage, err := NewHistoryStorageAt(filePath, 10)
+	require.NoError(t, err)
+
+	start := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	for i := 0; i < 11; i++ {
+		require.NoError(t, storage.Append(newRecord("alice", "red", domain.OutcomeSuccess, start.Add(time.Duration(i)*time.Second))))
+	}
+	assert.Len(t, storage.All(), 11, "the file grows up to a tenth past the limit")
+
+	require.NoError(t, storage.Append(newRecord("alice", "blue", domain.OutcomeSuccess, start.Add(time.Minute))))
+	records := storage.All()
+	require.Len(t, records, 10)
+	assert.Equal(t, start.Add(2*time.Second), records[0].Timestamp)
+	assert.Equal(t, "blue", records[9].Command)
+
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, 10, strings.Count(string(data), "\n"))
+
+	// A smaller limit trims the history on load
+	reloaded, err := NewHistoryStorageAt(filePath, 5)
+	require.NoError(t, err)
+	assert.Len(t, reloaded.All(), 5)
+}
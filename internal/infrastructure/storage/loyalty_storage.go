@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// LoyaltyStorage handles persistent storage of the viewer loyalty ledger.
+// Every change is written to a temporary file that replaces the ledger atomically,
+// so a crash leaves either the old or the new ledger on disk.
+type LoyaltyStorage struct {
+	filePath string
+	mu       sync.RWMutex
+	ledger   *domain.LoyaltyLedger
+}
+
+// NewLoyaltyStorage creates a new loyalty storage instance
+func NewLoyaltyStorage() (*LoyaltyStorage, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	configDir := filepath.Join(homeDir, ".lampcontrol")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	return NewLoyaltyStorageAt(filepath.Join(configDir, "loyalty.json"))
+}
+
+// NewLoyaltyStorageAt creates a loyalty storage backed by the given file
+func NewLoyaltyStorageAt(filePath string) (*LoyaltyStorage, error) {
+	storage := &LoyaltyStorage{
+		filePath: filePath,
+		ledger:   domain.NewLoyaltyLedger(),
+	}
+
+	if err := storage.load(); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to load loyalty ledger: %w", err)
+		}
+	}
+
+	return storage, nil
+}
+
+// Update changes the ledger and persists it.
+// Nothing is written if fn returns an error, fn must leave the ledger untouched in that case.
+func (s *LoyaltyStorage) Update(fn func(ledger *domain.LoyaltyLedger) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := fn(s.ledger); err != nil {
+		return err
+	}
+
+	return s.persist()
+}
+
+// Balance returns the points of a viewer
+func (s *LoyaltyStorage) Balance(viewer string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.ledger.Balance(viewer)
+}
+
+// Account returns a copy of a viewer's account
+func (s *LoyaltyStorage) Account(viewer string) (domain.PointsAccount, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	account, exists := s.ledger.Accounts[viewer]
+	if !exists {
+		return domain.PointsAccount{}, false
+	}
+	return *account, true
+}
+
+// Leaderboard returns the accounts with the highest balances
+func (s *LoyaltyStorage) Leaderboard(limit int) []domain.PointsAccount {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.ledger.Leaderboard(limit)
+}
+
+// persist writes the ledger to a temporary file, syncs it and renames it over the ledger
+func (s *LoyaltyStorage) persist() error {
+	data, err := json.MarshalIndent(s.ledger, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal loyalty ledger: %w", err)
+	}
+
+	if err := replaceFile(s.filePath, data); err != nil {
+		return fmt.Errorf("failed to save loyalty ledger: %w", err)
+	}
+
+	return nil
+}
+
+// load loads the ledger from file
+func (s *LoyaltyStorage) load() error {
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return err
+	}
+
+	ledger := domain.NewLoyaltyLedger()
+	if err := json.Unmarshal(data, ledger); err != nil {
+		return fmt.Errorf("failed to unmarshal loyalty ledger: %w", err)
+	}
+	if ledger.Accounts == nil {
+		ledger.Accounts = make(map[string]*domain.PointsAccount)
+	}
+
+	s.ledger = ledger
+	return nil
+}
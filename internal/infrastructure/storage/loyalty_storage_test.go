@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoyaltyStoragePersists(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "loyalty.json")
+
+	storage, err := NewLoyaltyStorageAt(filePath)
+	require.NoError(t, err)
+
+	require.NoError(t, storage.Update(func(ledger *domain.LoyaltyLedger) error {
+		ledger.Earn("alice", "Alice", 40)
+		return nil
+	}))
+
+	// A failed update writes nothing
+	err = storage.Update(func(ledger *domain.LoyaltyLedger) error {
+		return ledger.Spend("alice", 100)
+	})
+	assert.ErrorIs(t, err, domain.ErrInsufficientPoints)
+
+	reloaded, err := NewLoyaltyStorageAt(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, 40, reloaded.Balance("alice"))
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwitchStorageLoadsOldConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// A config saved before polls and the panic action existed
+	dir := filepath.Join(home, ".lampcontrol")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "twitch_config.json"), []byte(`{
+  "enabled": false,
+  "channel": "streamer",
+  "effect_duration": 20000000000,
+  "vip_bypass_cooldown": false
+}`), 0600))
+
+	storage, err := NewTwitchStorage()
+	require.NoError(t, err)
+
+	config := storage.Get()
+	assert.Equal(t, "streamer", config.Channel)
+	assert.Equal(t, 20*time.Second, config.EffectDuration)
+	assert.False(t, config.VIPBypassCooldown)
+	assert.Equal(t, domain.DefaultVoteDuration, config.VoteDuration)
+	// Panic must not turn the lamp off for configs without a safe scene
+	assert.Equal(t, domain.DefaultSafeScene(), config.SafeScene)
+}
@@ -78,3 +78,45 @@ func (c *APIClient) ValidateToken(accessToken string) (bool, error) {
 
 	return resp.StatusCode == http.StatusOK, nil
 }
+
+// followersResponse represents the Helix channel followers response
+type followersResponse struct {
+	Total int `json:"total"`
+	Data  []struct {
+		UserID string `json:"user_id"`
+	} `json:"data"`
+}
+
+// IsFollower checks whether a user follows a channel.
+// The access token needs the moderator:read:followers scope.
+func (c *APIClient) IsFollower(accessToken, broadcasterID, userID string) (bool, error) {
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+	query.Set("user_id", userID)
+
+	req, err := http.NewRequest("GET", "https://api.twitch.tv/helix/channels/followers?"+query.Encode(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Client-Id", c.clientID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to check follower: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("follower check failed: %s - %s", resp.Status, string(body))
+	}
+
+	var followers followersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&followers); err != nil {
+		return false, fmt.Errorf("failed to decode followers response: %w", err)
+	}
+
+	return len(followers.Data) > 0, nil
+}
@@ -2,81 +2,263 @@ package twitch
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
 	"github.com/gempir/go-twitch-irc/v4"
 )
 
-// MessageHandler is called when a chat message is received
-type MessageHandler func(cmd *domain.TwitchCommand)
+// Reconnect backoff defaults
+const (
+	defaultMinBackoff = 1 * time.Second
+	defaultMaxBackoff = 60 * time.Second
+)
 
-// IRCClient wraps Twitch IRC functionality
+// IRCClient wraps Twitch IRC functionality and manages the connection lifecycle:
+// it reconnects with exponential backoff, waits for the channel join to be
+// confirmed and stops retrying when Twitch rejects the token.
 type IRCClient struct {
-	client         *twitch.Client
-	channel        string
-	messageHandler MessageHandler
-	connected      bool
-	mu             sync.RWMutex
+	client          *twitch.Client
+	channel         string
+	messageHandler  domain.ChatCommandHandler
+	stateHandler    domain.ConnectionStateHandler
+	presenceHandler domain.ChatPresenceHandler
+	alertHandler    domain.AlertHandler
+	status          domain.ConnectionStatus
+	minBackoff      time.Duration
+	maxBackoff      time.Duration
+	joined          bool // Whether the current connection confirmed the join
+	cancel          context.CancelFunc
+	done            chan struct{}
+	mu              sync.RWMutex
+	log             *slog.Logger
 }
 
 // NewIRCClient creates a new Twitch IRC client
-func NewIRCClient(username, token, channel string, handler MessageHandler) *IRCClient {
+func NewIRCClient(username, token, channel string) *IRCClient {
 	client := twitch.NewClient(username, token)
+	// Membership reports viewer joins and parts for loyalty points
+	client.Capabilities = []string{twitch.TagsCapability, twitch.CommandsCapability, twitch.MembershipCapability}
 
 	ircClient := &IRCClient{
-		client:         client,
-		channel:        channel,
-		messageHandler: handler,
-		connected:      false,
+		client:     client,
+		channel:    strings.ToLower(strings.TrimPrefix(channel, "#")),
+		status:     domain.NewConnectionStatus(domain.ConnectionDisconnected),
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+		log:        logging.Source("twitch"),
 	}
 
 	// Set up message handler
 	client.OnPrivateMessage(ircClient.onMessage)
+	client.OnUserJoinMessage(ircClient.onUserJoin)
+	client.OnUserPartMessage(ircClient.onUserPart)
+	client.OnUserNoticeMessage(ircClient.onUserNotice)
 
 	// Set up connection handlers
-	client.OnConnect(func() {
-		log.Printf("[Twitch] Connected to channel: %s", channel)
-		ircClient.mu.Lock()
-		ircClient.connected = true
-		ircClient.mu.Unlock()
-	})
+	client.OnConnect(ircClient.onConnect)
+	client.OnSelfJoinMessage(ircClient.onSelfJoin)
+	client.OnNoticeMessage(ircClient.onNotice)
+	client.OnReconnectMessage(ircClient.onReconnect)
 
 	return ircClient
 }
 
-// Connect connects to Twitch IRC
+// SetServer points the client at a different IRC server, e.g. a local test server
+func (c *IRCClient) SetServer(address string, useTLS bool) {
+	c.client.IrcAddress = address
+	c.client.TLS = useTLS
+}
+
+// SetBackoff sets the minimum and maximum delay between reconnect attempts
+func (c *IRCClient) SetBackoff(min, max time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.minBackoff = min
+	c.maxBackoff = max
+}
+
+// SetMessageHandler sets the handler for lamp commands
+func (c *IRCClient) SetMessageHandler(handler domain.ChatCommandHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messageHandler = handler
+}
+
+// SetStateHandler sets the handler for connection state changes
+func (c *IRCClient) SetStateHandler(handler domain.ConnectionStateHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stateHandler = handler
+}
+
+// SetPresenceHandler sets the handler for viewer activity
+func (c *IRCClient) SetPresenceHandler(handler domain.ChatPresenceHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.presenceHandler = handler
+}
+
+// SetAlertHandler sets the handler for subs, gifted subs and raids
+func (c *IRCClient) SetAlertHandler(handler domain.AlertHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.alertHandler = handler
+}
+
+// Connect starts the managed connection in the background.
+// The connection outlives ctx; call Disconnect to stop it.
 func (c *IRCClient) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	if c.cancel != nil {
+		c.mu.Unlock()
+		return nil // Already running
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	c.mu.Unlock()
+
 	c.client.Join(c.channel)
 
-	go func() {
-		if err := c.client.Connect(); err != nil {
-			log.Printf("[Twitch] Connection error: %v", err)
-		}
-	}()
+	go c.run(runCtx)
 
 	return nil
 }
 
-// Disconnect disconnects from Twitch IRC
+// run connects and reconnects until the context is canceled or authentication fails.
+// The underlying client redials once by itself when a connection drops and only
+// returns once that fails, from then on the backoff here takes over.
+func (c *IRCClient) run(ctx context.Context) {
+	defer close(c.done)
+
+	attempt := 0
+	for {
+		if attempt == 0 {
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionConnecting))
+		}
+
+		err := c.client.Connect()
+
+		c.mu.Lock()
+		wasJoined := c.joined
+		c.joined = false
+		c.mu.Unlock()
+
+		if ctx.Err() != nil || errors.Is(err, twitch.ErrClientDisconnected) {
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionDisconnected))
+			return
+		}
+
+		if errors.Is(err, twitch.ErrLoginAuthenticationFailed) {
+			c.log.Error("Authentication failed, check the access token")
+			status := domain.NewConnectionStatus(domain.ConnectionAuthFailed)
+			status.Error = err.Error()
+			c.setStatus(status)
+			c.clearRunning()
+			return
+		}
+
+		// A connection that made it into the channel starts a fresh backoff
+		if wasJoined {
+			attempt = 0
+		}
+		attempt++
+
+		delay := c.backoff(attempt)
+		c.log.Warn("Connection lost", "error", err, "retry_in", delay)
+
+		status := domain.NewConnectionStatus(domain.ConnectionReconnecting)
+		status.Attempt = attempt
+		status.RetryAt = time.Now().Add(delay)
+		if err != nil {
+			status.Error = err.Error()
+		}
+		c.setStatus(status)
+
+		select {
+		case <-ctx.Done():
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionDisconnected))
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// backoff returns the delay before the given reconnect attempt
+func (c *IRCClient) backoff(attempt int) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	delay := c.minBackoff
+	for i := 1; i < attempt && delay < c.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+	return delay
+}
+
+// Disconnect stops the managed connection and waits for it to shut down
 func (c *IRCClient) Disconnect() error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	cancel := c.cancel
+	done := c.done
+	c.cancel = nil
+	c.mu.Unlock()
 
-	if c.connected {
-		c.client.Disconnect()
-		c.connected = false
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	c.client.Disconnect()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		c.log.Warn("Timed out waiting for IRC connection to close")
 	}
 
 	return nil
 }
 
-// IsConnected returns connection status
+// clearRunning marks the managed connection as stopped without a Disconnect call
+func (c *IRCClient) clearRunning() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
+
+// IsConnected returns whether the client is in the channel
 func (c *IRCClient) IsConnected() bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.connected
+	return c.status.State == domain.ConnectionJoined
+}
+
+// Status returns the current connection status
+func (c *IRCClient) Status() domain.ConnectionStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
 }
 
 // SendMessage sends a message to chat
@@ -84,8 +266,100 @@ func (c *IRCClient) SendMessage(message string) {
 	c.client.Say(c.channel, message)
 }
 
+// setStatus stores a new status and notifies the state handler
+func (c *IRCClient) setStatus(status domain.ConnectionStatus) {
+	c.mu.Lock()
+	if c.status.State == status.State && status.State != domain.ConnectionReconnecting {
+		c.mu.Unlock()
+		return
+	}
+	c.status = status
+	handler := c.stateHandler
+	c.mu.Unlock()
+
+	c.log.Info("Connection state changed", "state", status.State)
+
+	if handler != nil {
+		handler(status)
+	}
+}
+
+// onConnect handles a successful login
+func (c *IRCClient) onConnect() {
+	c.mu.RLock()
+	stopped := c.cancel == nil
+	c.mu.RUnlock()
+
+	// Disconnect raced with the login, drop the connection now
+	if stopped {
+		c.client.Disconnect()
+		return
+	}
+
+	c.mu.Lock()
+	c.joined = false
+	c.mu.Unlock()
+
+	c.setStatus(domain.NewConnectionStatus(domain.ConnectionConnected))
+}
+
+// onReconnect handles a server request to reconnect, the client redials right away
+func (c *IRCClient) onReconnect(message twitch.ReconnectMessage) {
+	c.log.Info("Server requested reconnect")
+	c.setStatus(domain.NewConnectionStatus(domain.ConnectionReconnecting))
+}
+
+// onSelfJoin handles the join confirmation for our channel
+func (c *IRCClient) onSelfJoin(message twitch.UserJoinMessage) {
+	if !strings.EqualFold(message.Channel, c.channel) {
+		return
+	}
+
+	c.mu.Lock()
+	c.joined = true
+	c.mu.Unlock()
+
+	c.log.Info("Joined channel", "channel", c.channel)
+	c.setStatus(domain.NewConnectionStatus(domain.ConnectionJoined))
+}
+
+// onNotice logs server notices, e.g. bans or rate limits
+func (c *IRCClient) onNotice(message twitch.NoticeMessage) {
+	c.log.Info("Server notice", "msg_id", message.MsgID, "message", message.Message)
+}
+
+// onUserJoin reports a viewer entering the channel
+func (c *IRCClient) onUserJoin(message twitch.UserJoinMessage) {
+	c.reportPresence(message.User, "", domain.PresenceJoin)
+}
+
+// onUserPart reports a viewer leaving the channel
+func (c *IRCClient) onUserPart(message twitch.UserPartMessage) {
+	c.reportPresence(message.User, "", domain.PresencePart)
+}
+
+// reportPresence passes viewer activity to the presence handler
+func (c *IRCClient) reportPresence(username, displayName string, event domain.PresenceEvent) {
+	c.mu.RLock()
+	handler := c.presenceHandler
+	c.mu.RUnlock()
+
+	if handler != nil {
+		handler(&domain.ChatPresence{
+			Platform:    domain.PlatformTwitch,
+			Username:    username,
+			DisplayName: displayName,
+			Event:       event,
+			Timestamp:   time.Now(),
+		})
+	}
+}
+
 // onMessage handles incoming chat messages
 func (c *IRCClient) onMessage(message twitch.PrivateMessage) {
+	// Any message shows the viewer is watching
+	c.reportPresence(message.User.Name, message.User.DisplayName, domain.PresenceMessage)
+
 	// Parse command
 	command, err := domain.ParseTwitchCommand(message.Message)
 	if err != nil {
@@ -96,38 +370,134 @@ func (c *IRCClient) onMessage(message twitch.PrivateMessage) {
 	badges := extractBadges(message)
 
 	// Create command
-	cmd := &domain.TwitchCommand{
-		Username:    message.User.Name,
-		DisplayName: message.User.DisplayName,
-		Command:     command,
-		IsVIP:       badges.IsVIP,
-		IsSub:       badges.IsSub,
-		IsMod:       badges.IsMod,
-		Timestamp:   message.Time,
+	cmd := &domain.ChatCommand{
+		Platform:      domain.PlatformTwitch,
+		Username:      message.User.Name,
+		DisplayName:   message.User.DisplayName,
+		UserID:        message.User.ID,
+		ChannelID:     message.RoomID,
+		Command:       command,
+		IsVIP:         badges.IsVIP,
+		IsSub:         badges.IsSub,
+		IsMod:         badges.IsMod,
+		IsFounder:     badges.IsFounder,
+		IsBroadcaster: badges.IsBroadcaster,
+		SubTier:       badges.SubTier,
+		Timestamp:     message.Time,
 	}
 
 	// Call handler
-	if c.messageHandler != nil {
-		c.messageHandler(cmd)
+	c.mu.RLock()
+	handler := c.messageHandler
+	c.mu.RUnlock()
+
+	if handler != nil {
+		handler(cmd)
+	}
+}
+
+// onUserNotice reports subs, gifted subs and raids announced in the channel
+func (c *IRCClient) onUserNotice(message twitch.UserNoticeMessage) {
+	alert := alertFromUserNotice(message)
+	if alert == nil {
+		return
+	}
+
+	c.mu.RLock()
+	handler := c.alertHandler
+	c.mu.RUnlock()
+
+	if handler != nil {
+		handler(alert)
+	}
+}
+
+// alertFromUserNotice converts a USERNOTICE into an alert, or returns nil for other notices
+func alertFromUserNotice(message twitch.UserNoticeMessage) *domain.Alert {
+	alert := &domain.Alert{
+		Source:    string(domain.PlatformTwitch),
+		User:      message.User.DisplayName,
+		Message:   message.Message,
+		Timestamp: message.Time,
+	}
+	if alert.User == "" {
+		alert.User = message.User.Name
 	}
+
+	switch message.MsgID {
+	case "sub", "resub":
+		alert.Type = domain.AlertSub
+		alert.Amount = msgParam(message, "msg-param-cumulative-months", 1)
+	case "subgift":
+		// Gifts of a gift bomb follow the submysterygift notice that already counted them
+		if message.MsgParams["msg-param-community-gift-id"] != "" {
+			return nil
+		}
+		alert.Type = domain.AlertGift
+		alert.Amount = 1
+	case "submysterygift":
+		alert.Type = domain.AlertGift
+		alert.Amount = msgParam(message, "msg-param-mass-gift-count", 1)
+	case "raid":
+		alert.Type = domain.AlertRaid
+		alert.Amount = msgParam(message, "msg-param-viewerCount", 0)
+		if name := message.MsgParams["msg-param-displayName"]; name != "" {
+			alert.User = name
+		}
+	default:
+		return nil
+	}
+
+	return alert
+}
+
+// msgParam reads a numeric USERNOTICE parameter
+func msgParam(message twitch.UserNoticeMessage, name string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(message.MsgParams[name], 64)
+	if err != nil {
+		return fallback
+	}
+	return value
 }
 
 // extractBadges extracts user privilege information
 func extractBadges(message twitch.PrivateMessage) domain.UserBadges {
 	badges := domain.UserBadges{}
 
-	for badge := range message.User.Badges {
+	for badge, version := range message.User.Badges {
 		switch badge {
 		case "vip":
 			badges.IsVIP = true
 		case "subscriber":
 			badges.IsSub = true
+			badges.SubTier = subTierFromBadge(version)
+		case "founder":
+			badges.IsFounder = true
+			badges.IsSub = true
 		case "moderator":
 			badges.IsMod = true
 		case "broadcaster":
+			badges.IsBroadcaster = true
 			badges.IsMod = true // Broadcaster has mod privileges
 		}
 	}
 
+	if badges.IsSub && badges.SubTier == 0 {
+		badges.SubTier = 1
+	}
+
 	return badges
 }
+
+// subTierFromBadge derives the subscription tier from the subscriber badge version.
+// Twitch encodes tier 2 and 3 badges as 2000+ and 3000+, tier 1 badges use the month count.
+func subTierFromBadge(version int) int {
+	switch {
+	case version >= 3000:
+		return 3
+	case version >= 2000:
+		return 2
+	default:
+		return 1
+	}
+}
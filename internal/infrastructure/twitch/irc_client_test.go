@@ -0,0 +1,242 @@
+package twitch
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/gempir/go-twitch-irc/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIRCServer speaks just enough of the Twitch IRC protocol to log in and join
+type fakeIRCServer struct {
+	listener   net.Listener
+	rejectAuth bool
+	mu         sync.Mutex
+	conns      []net.Conn
+}
+
+func newFakeIRCServer(t *testing.T, address string, rejectAuth bool) *fakeIRCServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", address)
+	require.NoError(t, err)
+
+	server := &fakeIRCServer{listener: listener, rejectAuth: rejectAuth}
+	go server.serve()
+	t.Cleanup(server.close)
+
+	return server
+}
+
+func (s *fakeIRCServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeIRCServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeIRCServer) handle(conn net.Conn) {
+	nick := ""
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "NICK "):
+			nick = strings.TrimPrefix(line, "NICK ")
+			if s.rejectAuth {
+				fmt.Fprintf(conn, ":tmi.twitch.tv NOTICE * :Login authentication failed\r\n")
+				continue
+			}
+			fmt.Fprintf(conn, ":tmi.twitch.tv 001 %s :Welcome, GLHF!\r\n", nick)
+		case strings.HasPrefix(line, "JOIN "):
+			for _, channel := range strings.Split(strings.TrimPrefix(line, "JOIN "), ",") {
+				fmt.Fprintf(conn, ":%s!%s@%s.tmi.twitch.tv JOIN %s\r\n", nick, nick, nick, channel)
+			}
+		case strings.HasPrefix(line, "PING"):
+			fmt.Fprintf(conn, ":tmi.twitch.tv PONG tmi.twitch.tv\r\n")
+		}
+	}
+}
+
+// close stops accepting connections and drops the open ones
+func (s *fakeIRCServer) close() {
+	s.listener.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+// stateRecorder collects connection states reported by the client
+type stateRecorder struct {
+	mu     sync.Mutex
+	states []domain.ConnectionState
+}
+
+func (r *stateRecorder) handle(status domain.ConnectionStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states = append(r.states, status.State)
+}
+
+func (r *stateRecorder) seen(state domain.ConnectionState) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+func newTestClient(address string) (*IRCClient, *stateRecorder) {
+	client := NewIRCClient("lampbot", "oauth:test", "#Streamer")
+	client.SetServer(address, false)
+	client.SetBackoff(10*time.Millisecond, 50*time.Millisecond)
+
+	recorder := &stateRecorder{}
+	client.SetStateHandler(recorder.handle)
+
+	return client, recorder
+}
+
+func TestIRCClientLifecycle(t *testing.T) {
+	t.Run("joins channel", func(t *testing.T) {
+		server := newFakeIRCServer(t, "127.0.0.1:0", false)
+		client, recorder := newTestClient(server.addr())
+
+		require.NoError(t, client.Connect(t.Context()))
+		assert.Eventually(t, client.IsConnected, 2*time.Second, 10*time.Millisecond)
+		assert.True(t, recorder.seen(domain.ConnectionConnecting))
+		assert.True(t, recorder.seen(domain.ConnectionConnected))
+
+		require.NoError(t, client.Disconnect())
+		assert.Equal(t, domain.ConnectionDisconnected, client.Status().State)
+	})
+
+	t.Run("stops on rejected token", func(t *testing.T) {
+		server := newFakeIRCServer(t, "127.0.0.1:0", true)
+		client, _ := newTestClient(server.addr())
+
+		require.NoError(t, client.Connect(t.Context()))
+		assert.Eventually(t, func() bool {
+			return client.Status().State == domain.ConnectionAuthFailed
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.NotEmpty(t, client.Status().Error)
+		assert.False(t, client.IsConnected())
+
+		require.NoError(t, client.Disconnect())
+	})
+
+	t.Run("reconnects after server restart", func(t *testing.T) {
+		server := newFakeIRCServer(t, "127.0.0.1:0", false)
+		address := server.addr()
+		client, recorder := newTestClient(address)
+
+		require.NoError(t, client.Connect(t.Context()))
+		require.Eventually(t, client.IsConnected, 2*time.Second, 10*time.Millisecond)
+
+		server.close()
+		assert.Eventually(t, func() bool {
+			return recorder.seen(domain.ConnectionReconnecting)
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.False(t, client.IsConnected())
+
+		newFakeIRCServer(t, address, false)
+		assert.Eventually(t, client.IsConnected, 2*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, client.Disconnect())
+	})
+}
+
+func TestAlertFromUserNotice(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		wantType   domain.AlertType
+		wantUser   string
+		wantAmount float64
+		wantNone   bool
+	}{
+		{
+			name:       "resub",
+			line:       `@display-name=Alice;login=alice;msg-id=resub;msg-param-cumulative-months=14;room-id=1;tmi-sent-ts=1700000000000;user-id=2 :tmi.twitch.tv USERNOTICE #streamer :still here`,
+			wantType:   domain.AlertSub,
+			wantUser:   "Alice",
+			wantAmount: 14,
+		},
+		{
+			name:       "raid",
+			line:       `@display-name=raider;login=raider;msg-id=raid;msg-param-displayName=Raider;msg-param-viewerCount=42;room-id=1;tmi-sent-ts=1700000000000;user-id=3 :tmi.twitch.tv USERNOTICE #streamer`,
+			wantType:   domain.AlertRaid,
+			wantUser:   "Raider",
+			wantAmount: 42,
+		},
+		{
+			name:       "gift bomb",
+			line:       `@display-name=Bob;login=bob;msg-id=submysterygift;msg-param-mass-gift-count=20;room-id=1;tmi-sent-ts=1700000000000;user-id=4 :tmi.twitch.tv USERNOTICE #streamer`,
+			wantType:   domain.AlertGift,
+			wantUser:   "Bob",
+			wantAmount: 20,
+		},
+		{
+			name:     "gifts of a gift bomb",
+			line:     `@display-name=Bob;login=bob;msg-id=subgift;msg-param-community-gift-id=123;msg-param-recipient-user-name=carol;room-id=1;tmi-sent-ts=1700000000000;user-id=4 :tmi.twitch.tv USERNOTICE #streamer`,
+			wantNone: true,
+		},
+		{
+			name:       "single gift",
+			line:       `@display-name=Bob;login=bob;msg-id=subgift;msg-param-recipient-user-name=carol;room-id=1;tmi-sent-ts=1700000000000;user-id=4 :tmi.twitch.tv USERNOTICE #streamer`,
+			wantType:   domain.AlertGift,
+			wantUser:   "Bob",
+			wantAmount: 1,
+		},
+		{
+			name:     "announcement",
+			line:     `@display-name=Mod;login=mod;msg-id=announcement;room-id=1;tmi-sent-ts=1700000000000;user-id=5 :tmi.twitch.tv USERNOTICE #streamer :hello`,
+			wantNone: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, ok := twitch.ParseMessage(tt.line).(*twitch.UserNoticeMessage)
+			require.True(t, ok)
+
+			alert := alertFromUserNotice(*message)
+			if tt.wantNone {
+				assert.Nil(t, alert)
+				return
+			}
+			require.NotNil(t, alert)
+			assert.Equal(t, tt.wantType, alert.Type)
+			assert.Equal(t, tt.wantUser, alert.User)
+			assert.Equal(t, tt.wantAmount, alert.Amount)
+			assert.Equal(t, "twitch", alert.Source)
+		})
+	}
+}
@@ -0,0 +1,522 @@
+package wled
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// WLED release the API mimics
+const (
+	Version   = "0.14.4"
+	VersionID = 2405180
+	UDPPort   = 21324
+)
+
+// Light capabilities of a segment: RGB, white channel and adjustable white balance
+const lightCapabilities = 1 | 2 | 4
+
+// defaultCCT is the white balance reported for lamps without one, halfway between warm and cold
+const defaultCCT = 127
+
+// Kelvin range accepted for the cct of a segment, warm to cold white
+const (
+	minKelvin = 2000
+	maxKelvin = 6500
+)
+
+// Effect is an entry of the WLED effect list
+type Effect struct {
+	Name   string
+	Effect *int        // Built-in lamp effect, nil for a solid color
+	Speed  *uint8      // Speed of the built-in effect, nil for the current speed
+	Color  *domain.RGB // Color of a custom effect without a matching built-in effect
+}
+
+// Effects returns the effect list: Solid first like in WLED, then the built-in
+// effects, then the custom effects. Custom effects run the built-in effect of
+// their pattern, or show their first color if the lamps have no such effect.
+func Effects(custom []*domain.CustomEffect) []Effect {
+	effects := []Effect{{Name: "Solid"}}
+
+	for _, name := range domain.EffectNames() {
+		index := int(domain.EffectMap[name])
+		effects = append(effects, Effect{Name: name, Effect: &index})
+	}
+
+	sorted := append([]*domain.CustomEffect(nil), custom...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	for _, c := range sorted {
+		effect := Effect{Name: c.Name}
+		if index, exists := domain.EffectMap[strings.ToLower(c.Pattern)]; exists {
+			effectInt := int(index)
+			speed := c.Speed
+			effect.Effect = &effectInt
+			effect.Speed = &speed
+		} else if len(c.Colors) > 0 {
+			effect.Color = &domain.RGB{R: c.Colors[0].R, G: c.Colors[0].G, B: c.Colors[0].B}
+		}
+		effects = append(effects, effect)
+	}
+
+	return effects
+}
+
+// Names returns the names of an effect list
+func Names(effects []Effect) []string {
+	names := make([]string, len(effects))
+	for i, effect := range effects {
+		names[i] = effect.Name
+	}
+	return names
+}
+
+// State is the WLED state object. Every lamp is a segment one LED long,
+// so WLED apps, LedFx and the Home Assistant WLED integration control it unchanged.
+type State struct {
+	On         bool      `json:"on"`
+	Bri        uint8     `json:"bri"`
+	Transition int       `json:"transition"`
+	PS         int       `json:"ps"`
+	PL         int       `json:"pl"`
+	LOR        int       `json:"lor"`
+	MainSeg    int       `json:"mainseg"`
+	Seg        []Segment `json:"seg"`
+}
+
+// Segment is a lamp in the WLED state object
+type Segment struct {
+	ID    int       `json:"id"`
+	Start int       `json:"start"`
+	Stop  int       `json:"stop"`
+	Len   int       `json:"len"`
+	On    bool      `json:"on"`
+	Bri   uint8     `json:"bri"`
+	CCT   int       `json:"cct"`
+	Col   [][]uint8 `json:"col"` // Primary, secondary and tertiary color as [r,g,b,w]
+	FX    int       `json:"fx"`
+	SX    uint8     `json:"sx"`
+	IX    uint8     `json:"ix"`
+	Pal   int       `json:"pal"`
+	Sel   bool      `json:"sel"`
+	Name  string    `json:"n"`
+}
+
+// sortDevices returns the lamps in segment order
+func sortDevices(devices []*domain.Device) []*domain.Device {
+	sorted := append([]*domain.Device(nil), devices...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Address < sorted[j].Address
+	})
+	return sorted
+}
+
+// NewState builds the WLED state of the lamps. The master switch is on while any
+// lamp is on, and the master brightness is that of the brightest lamp.
+func NewState(devices []*domain.Device, effects []Effect) State {
+	state := State{Bri: 255, Transition: 7, PS: -1, PL: -1, Seg: []Segment{}}
+
+	var brightest uint8
+	for i, device := range sortDevices(devices) {
+		segment := newSegment(i, device, effects)
+		state.Seg = append(state.Seg, segment)
+
+		state.On = state.On || segment.On
+		brightest = max(brightest, segment.Bri)
+	}
+	if len(state.Seg) > 0 {
+		state.Bri = brightest
+	}
+
+	return state
+}
+
+// newSegment builds the segment of a lamp
+func newSegment(id int, device *domain.Device, effects []Effect) Segment {
+	name := device.Name
+	if name == "" {
+		name = device.Address
+	}
+
+	s := device.State
+	segment := Segment{
+		ID:    id,
+		Start: id,
+		Stop:  id + 1,
+		Len:   1,
+		On:    s.PowerOn,
+		Bri:   s.Brightness,
+		CCT:   defaultCCT,
+		Col:   [][]uint8{{0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}},
+		SX:    domain.DefaultEffectSpeed,
+		IX:    128,
+		Sel:   true,
+		Name:  name,
+	}
+
+	switch {
+	case s.RGB != nil:
+		segment.Col[0] = []uint8{s.RGB.R, s.RGB.G, s.RGB.B, 0}
+	case s.WhiteBalance != nil:
+		segment.Col[0] = []uint8{0, 0, 0, 255}
+		segment.CCT = WhiteBalanceToCCT(*s.WhiteBalance)
+	case s.Effect != nil:
+		for i, effect := range effects {
+			if effect.Effect != nil && *effect.Effect == *s.Effect {
+				segment.FX = i
+				break
+			}
+		}
+	}
+	if s.EffectSpeed != nil {
+		segment.SX = *s.EffectSpeed
+	}
+
+	return segment
+}
+
+// Info is the WLED info object
+type Info struct {
+	Ver      string `json:"ver"`
+	VID      int    `json:"vid"`
+	Leds     Leds   `json:"leds"`
+	Str      bool   `json:"str"`
+	Name     string `json:"name"`
+	UDPPort  int    `json:"udpport"`
+	Live     bool   `json:"live"`
+	FxCount  int    `json:"fxcount"`
+	PalCount int    `json:"palcount"`
+	Wifi     Wifi   `json:"wifi"`
+	Arch     string `json:"arch"`
+	Core     string `json:"core"`
+	FreeHeap int    `json:"freeheap"`
+	Uptime   int    `json:"uptime"` // Seconds
+	Brand    string `json:"brand"`
+	Product  string `json:"product"`
+	MAC      string `json:"mac"`
+	IP       string `json:"ip"`
+}
+
+// Leds describes the LEDs, one per lamp
+type Leds struct {
+	Count  int   `json:"count"`
+	Pwr    int   `json:"pwr"`
+	FPS    int   `json:"fps"`
+	MaxPwr int   `json:"maxpwr"`
+	MaxSeg int   `json:"maxseg"`
+	LC     int   `json:"lc"`
+	SegLC  []int `json:"seglc"`
+	RGBW   bool  `json:"rgbw"`
+	WV     int   `json:"wv"`
+	CCT    bool  `json:"cct"`
+}
+
+// Wifi describes the network connection, fixed since the server isn't a WiFi module
+type Wifi struct {
+	BSSID   string `json:"bssid"`
+	RSSI    int    `json:"rssi"`
+	Signal  int    `json:"signal"`
+	Channel int    `json:"channel"`
+}
+
+// NewInfo builds the WLED info object
+func NewInfo(name, mac, ip string, devices []*domain.Device, effects []Effect, uptime time.Duration) Info {
+	segLC := make([]int, len(devices))
+	for i := range segLC {
+		segLC[i] = lightCapabilities
+	}
+
+	return Info{
+		Ver: Version,
+		VID: VersionID,
+		Leds: Leds{
+			Count:  len(devices),
+			MaxSeg: max(len(devices), 1),
+			LC:     lightCapabilities,
+			SegLC:  segLC,
+			RGBW:   true,
+			WV:     1,
+			CCT:    true,
+		},
+		Name:     name,
+		UDPPort:  UDPPort,
+		FxCount:  len(effects),
+		PalCount: 1,
+		Wifi:     Wifi{Signal: 100},
+		Arch:     "lampcontrol",
+		Core:     Version,
+		Uptime:   int(uptime / time.Second),
+		Brand:    "WLED",
+		Product:  "LampControl",
+		MAC:      mac,
+		IP:       ip,
+	}
+}
+
+// StateUpdate is a request to change the WLED state
+type StateUpdate struct {
+	On  json.RawMessage `json:"on"`  // true, false or "t" to toggle
+	Bri *int            `json:"bri"` // Master brightness, sets all lamps
+	Seg json.RawMessage `json:"seg"` // A list of segment updates, or one update for all segments
+	V   bool            `json:"v"`   // Respond with the new state
+}
+
+// SegmentUpdate is a request to change a segment
+type SegmentUpdate struct {
+	ID  *int              `json:"id"`
+	On  json.RawMessage   `json:"on"`
+	Bri *int              `json:"bri"`
+	Col []json.RawMessage `json:"col"` // [r,g,b(,w)] arrays or hex strings, only the primary color is used
+	CCT *int              `json:"cct"` // 0 (warm) to 255 (cold), or Kelvin
+	FX  *int              `json:"fx"`
+	SX  *int              `json:"sx"`
+}
+
+// Changes converts a state update into a change per lamp address
+func (u StateUpdate) Changes(devices []*domain.Device, effects []Effect) (map[string]domain.StateChange, error) {
+	sorted := sortDevices(devices)
+	changes := make(map[string]domain.StateChange)
+
+	// The master switch toggles all lamps together, off if any is on
+	anyOn := false
+	for _, device := range sorted {
+		anyOn = anyOn || device.State.PowerOn
+	}
+	on, err := parseSwitch(u.On, anyOn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, device := range sorted {
+		var change domain.StateChange
+		change.PowerOn = on
+		if u.Bri != nil {
+			level := clampByte(*u.Bri)
+			change.Brightness = &level
+		}
+		changes[device.Address] = change
+	}
+
+	segments, all, err := parseSegments(u.Seg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, seg := range segments {
+		targets := sorted
+		if !all {
+			id := i
+			if seg.ID != nil {
+				id = *seg.ID
+			}
+			// WLED ignores segments that don't exist
+			if id < 0 || id >= len(sorted) {
+				continue
+			}
+			targets = sorted[id : id+1]
+		}
+
+		for _, device := range targets {
+			segChange, err := seg.change(device.State, effects)
+			if err != nil {
+				return nil, err
+			}
+			changes[device.Address] = changes[device.Address].Then(segChange)
+		}
+	}
+
+	for addr, change := range changes {
+		if change.IsZero() {
+			delete(changes, addr)
+		}
+	}
+	return changes, nil
+}
+
+// parseSegments decodes a list of segment updates, or a single update that applies to all segments
+func parseSegments(data json.RawMessage) ([]SegmentUpdate, bool, error) {
+	trimmed := strings.TrimSpace(string(data))
+	switch {
+	case trimmed == "" || trimmed == "null":
+		return nil, false, nil
+	case strings.HasPrefix(trimmed, "["):
+		var segments []SegmentUpdate
+		if err := json.Unmarshal(data, &segments); err != nil {
+			return nil, false, fmt.Errorf("invalid seg: %w", err)
+		}
+		return segments, false, nil
+	default:
+		var segment SegmentUpdate
+		if err := json.Unmarshal(data, &segment); err != nil {
+			return nil, false, fmt.Errorf("invalid seg: %w", err)
+		}
+		// An update with an ID only targets that segment
+		return []SegmentUpdate{segment}, segment.ID == nil, nil
+	}
+}
+
+// change converts a segment update into a change of the lamp state
+func (seg SegmentUpdate) change(state domain.DeviceState, effects []Effect) (domain.StateChange, error) {
+	var change domain.StateChange
+
+	on, err := parseSwitch(seg.On, state.PowerOn)
+	if err != nil {
+		return change, err
+	}
+	change.PowerOn = on
+
+	if seg.Bri != nil {
+		level := clampByte(*seg.Bri)
+		change.Brightness = &level
+	}
+
+	cct := defaultCCT
+	if state.WhiteBalance != nil {
+		cct = WhiteBalanceToCCT(*state.WhiteBalance)
+	}
+	if seg.CCT != nil {
+		cct = normalizeCCT(*seg.CCT)
+	}
+
+	var color *domain.StateChange
+	if len(seg.Col) > 0 {
+		c, err := parseColor(seg.Col[0], cct)
+		if err != nil {
+			return change, err
+		}
+		color = &c
+	}
+
+	var speed *uint8
+	if seg.SX != nil {
+		s := clampByte(*seg.SX)
+		speed = &s
+	}
+
+	switch {
+	case seg.FX != nil:
+		if *seg.FX < 0 || *seg.FX >= len(effects) {
+			return change, fmt.Errorf("unknown effect: %d", *seg.FX)
+		}
+		effect := effects[*seg.FX]
+		switch {
+		case effect.Effect != nil:
+			effectInt := *effect.Effect
+			change.Effect = &effectInt
+			change.EffectSpeed = effect.Speed
+			if speed != nil {
+				change.EffectSpeed = speed
+			}
+		case color != nil:
+			change = change.Then(*color)
+		case effect.Color != nil:
+			rgb := *effect.Color
+			change.RGB = &rgb
+		case state.Effect != nil:
+			// Solid after an effect needs a color, the lamp doesn't remember one
+			change.RGB = &domain.RGB{R: 255, G: 255, B: 255}
+		}
+	case color != nil:
+		change = change.Then(*color)
+	case seg.CCT != nil:
+		wb := CCTToWhiteBalance(cct)
+		change.WhiteBalance = &wb
+	case speed != nil && state.Effect != nil:
+		effect := *state.Effect
+		change.Effect = &effect
+		change.EffectSpeed = speed
+	}
+
+	return change, nil
+}
+
+// parseSwitch decodes an on field: true, false or "t" to toggle the current state
+func parseSwitch(data json.RawMessage, current bool) (*bool, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" || trimmed == "null" {
+		return nil, nil
+	}
+
+	if trimmed == `"t"` {
+		toggled := !current
+		return &toggled, nil
+	}
+
+	var on bool
+	if err := json.Unmarshal(data, &on); err != nil {
+		return nil, fmt.Errorf("invalid on: %s", trimmed)
+	}
+	return &on, nil
+}
+
+// parseColor decodes a segment color. A color with only the white channel set
+// selects white with the balance of the segment.
+func parseColor(data json.RawMessage, cct int) (domain.StateChange, error) {
+	var channels []int
+
+	var hexColor string
+	if err := json.Unmarshal(data, &hexColor); err == nil {
+		raw, err := hex.DecodeString(hexColor)
+		if err != nil || (len(raw) != 3 && len(raw) != 4) {
+			return domain.StateChange{}, fmt.Errorf("invalid color: %s", hexColor)
+		}
+		// Hex colors are RRGGBB or WWRRGGBB
+		if len(raw) == 4 {
+			raw = append(raw[1:], raw[0])
+		}
+		for _, b := range raw {
+			channels = append(channels, int(b))
+		}
+	} else if err := json.Unmarshal(data, &channels); err != nil || len(channels) < 3 {
+		return domain.StateChange{}, fmt.Errorf("invalid color: %s", string(data))
+	}
+
+	rgb := domain.RGB{R: clampByte(channels[0]), G: clampByte(channels[1]), B: clampByte(channels[2])}
+	white := len(channels) > 3 && channels[3] > 0
+	if white && rgb == (domain.RGB{}) {
+		wb := CCTToWhiteBalance(cct)
+		return domain.StateChange{WhiteBalance: &wb}, nil
+	}
+	return domain.StateChange{RGB: &rgb}, nil
+}
+
+// normalizeCCT converts a cct given in Kelvin to the 0-255 range
+func normalizeCCT(cct int) int {
+	if cct <= 255 {
+		return max(0, cct)
+	}
+	kelvin := max(minKelvin, min(maxKelvin, cct))
+	return int(math.Round(255 * float64(kelvin-minKelvin) / float64(maxKelvin-minKelvin)))
+}
+
+// CCTToWhiteBalance mixes the warm and cold white channels for a cct from 0 (warm) to 255 (cold)
+func CCTToWhiteBalance(cct int) domain.WhiteBalance {
+	cold := clampByte(cct)
+	return domain.WhiteBalance{Warm: 255 - cold, Cold: cold}
+}
+
+// WhiteBalanceToCCT returns the cct of a warm and cold white mix
+func WhiteBalanceToCCT(wb domain.WhiteBalance) int {
+	total := int(wb.Warm) + int(wb.Cold)
+	if total == 0 {
+		return defaultCCT
+	}
+	return int(math.Round(255 * float64(wb.Cold) / float64(total)))
+}
+
+// clampByte limits a value to 0-255
+func clampByte(v int) uint8 {
+	return uint8(max(0, min(255, v)))
+}
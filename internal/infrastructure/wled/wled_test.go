@@ -0,0 +1,157 @@
+package wled
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDevices() []*domain.Device {
+	rainbow := int(domain.EffectMap["rainbow"])
+	speed := uint8(200)
+
+	kitchen := domain.NewDevice("BE:27:EB:00:00:02", "Kitchen", -60)
+	kitchen.State = domain.DeviceState{PowerOn: true, Brightness: 100, Effect: &rainbow, EffectSpeed: &speed}
+
+	desk := domain.NewDevice("BE:27:EB:00:00:01", "Desk", -50)
+	desk.State = domain.DeviceState{PowerOn: false, Brightness: 200, RGB: &domain.RGB{R: 255, G: 128}}
+
+	return []*domain.Device{kitchen, desk}
+}
+
+func TestEffects(t *testing.T) {
+	now := time.Now()
+	custom := []*domain.CustomEffect{
+		{ID: "2", Name: "Sunset", Colors: []domain.RGBColor{{R: 255, G: 80}}, Pattern: "jump", CreatedAt: now.Add(time.Minute)},
+		{ID: "1", Name: "Police", Colors: []domain.RGBColor{{R: 255}, {B: 255}}, Pattern: "strobe", Speed: 240, CreatedAt: now},
+	}
+
+	effects := Effects(custom)
+	assert.Equal(t, []string{"Solid", "fade", "pulse", "rainbow", "strobe", "Police", "Sunset"}, Names(effects))
+
+	police := effects[5]
+	assert.Equal(t, int(domain.EffectMap["strobe"]), *police.Effect, "custom pattern runs the built-in effect")
+	assert.Equal(t, uint8(240), *police.Speed)
+
+	sunset := effects[6]
+	assert.Nil(t, sunset.Effect)
+	assert.Equal(t, &domain.RGB{R: 255, G: 80}, sunset.Color, "no built-in jump effect, first color shown")
+}
+
+func TestNewState(t *testing.T) {
+	state := NewState(testDevices(), Effects(nil))
+
+	assert.True(t, state.On, "on while any lamp is on")
+	assert.Equal(t, uint8(200), state.Bri)
+	require.Len(t, state.Seg, 2)
+
+	desk := state.Seg[0]
+	assert.Equal(t, "Desk", desk.Name, "segments ordered by address")
+	assert.False(t, desk.On)
+	assert.Equal(t, []uint8{255, 128, 0, 0}, desk.Col[0])
+	assert.Equal(t, 0, desk.FX)
+
+	kitchen := state.Seg[1]
+	assert.Equal(t, 1, kitchen.Start)
+	assert.Equal(t, 3, kitchen.FX)
+	assert.Equal(t, uint8(200), kitchen.SX)
+}
+
+func TestStateUpdateChanges(t *testing.T) {
+	on := true
+	off := false
+	dim := uint8(50)
+	pulse := int(domain.EffectMap["pulse"])
+	rainbow := int(domain.EffectMap["rainbow"])
+	fast := uint8(250)
+
+	const desk = "BE:27:EB:00:00:01"
+	const kitchen = "BE:27:EB:00:00:02"
+
+	tests := []struct {
+		name    string
+		body    string
+		want    map[string]domain.StateChange
+		wantErr bool
+	}{
+		{
+			name: "master switch toggles all off",
+			body: `{"on":"t"}`,
+			want: map[string]domain.StateChange{desk: {PowerOn: &off}, kitchen: {PowerOn: &off}},
+		},
+		{
+			name: "master brightness",
+			body: `{"on":true,"bri":50}`,
+			want: map[string]domain.StateChange{desk: {PowerOn: &on, Brightness: &dim}, kitchen: {PowerOn: &on, Brightness: &dim}},
+		},
+		{
+			name: "segment color by position",
+			body: `{"seg":[{"col":[[0,0,255]]}]}`,
+			want: map[string]domain.StateChange{desk: {RGB: &domain.RGB{B: 255}}},
+		},
+		{
+			name: "segment by id with hex color",
+			body: `{"seg":[{"id":1,"on":true,"col":["00FF00"]}]}`,
+			want: map[string]domain.StateChange{kitchen: {PowerOn: &on, RGB: &domain.RGB{G: 255}}},
+		},
+		{
+			name: "white channel selects white",
+			body: `{"seg":{"id":0,"col":[[0,0,0,255]],"cct":255}}`,
+			want: map[string]domain.StateChange{desk: {WhiteBalance: &domain.WhiteBalance{Cold: 255}}},
+		},
+		{
+			name: "kelvin",
+			body: `{"seg":[{"cct":2000}]}`,
+			want: map[string]domain.StateChange{desk: {WhiteBalance: &domain.WhiteBalance{Warm: 255}}},
+		},
+		{
+			name: "effect for all segments",
+			body: `{"seg":{"fx":2}}`,
+			want: map[string]domain.StateChange{desk: {Effect: &pulse}, kitchen: {Effect: &pulse}},
+		},
+		{
+			name: "speed of the running effect",
+			body: `{"seg":[{"id":1,"sx":250}]}`,
+			want: map[string]domain.StateChange{kitchen: {Effect: &rainbow, EffectSpeed: &fast}},
+		},
+		{
+			name: "solid after an effect",
+			body: `{"seg":[{"id":1,"fx":0}]}`,
+			want: map[string]domain.StateChange{kitchen: {RGB: &domain.RGB{R: 255, G: 255, B: 255}}},
+		},
+		{
+			name: "missing segment is ignored",
+			body: `{"seg":[{"id":7,"on":true}]}`,
+			want: map[string]domain.StateChange{},
+		},
+		{name: "unknown effect", body: `{"seg":[{"fx":99}]}`, wantErr: true},
+		{name: "invalid color", body: `{"seg":[{"col":["nothex"]}]}`, wantErr: true},
+		{name: "invalid switch", body: `{"on":"maybe"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var update StateUpdate
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &update))
+
+			got, err := update.Changes(testDevices(), Effects(nil))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCCT(t *testing.T) {
+	assert.Equal(t, domain.WhiteBalance{Warm: 255}, CCTToWhiteBalance(0))
+	assert.Equal(t, 255, WhiteBalanceToCCT(domain.WhiteBalance{Cold: 200}))
+	assert.Equal(t, defaultCCT, WhiteBalanceToCCT(domain.WhiteBalance{}))
+	assert.Equal(t, 255, normalizeCCT(10000), "kelvin clamped to the coldest white")
+}
@@ -0,0 +1,395 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+)
+
+const (
+	apiBaseURL = "https://www.googleapis.com/youtube/v3"
+
+	defaultPollInterval = 5 * time.Second
+	maxRetryDelay       = 60 * time.Second
+)
+
+// ChatClient reads YouTube Live chat through the YouTube Data API.
+// It only reads chat, an API key can't post messages.
+type ChatClient struct {
+	apiKey          string
+	videoID         string
+	baseURL         string
+	httpClient      *http.Client
+	messageHandler  domain.ChatCommandHandler
+	stateHandler    domain.ConnectionStateHandler
+	presenceHandler domain.ChatPresenceHandler
+	status          domain.ConnectionStatus
+	cancel          context.CancelFunc
+	mu              sync.RWMutex
+	log             *slog.Logger
+}
+
+// NewChatClient creates a chat client for the live stream with the given video ID
+func NewChatClient(apiKey, videoID string) *ChatClient {
+	return &ChatClient{
+		apiKey:     apiKey,
+		videoID:    videoID,
+		baseURL:    apiBaseURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		status:     domain.NewConnectionStatus(domain.ConnectionDisconnected),
+		log:        logging.Source("youtube"),
+	}
+}
+
+// SetBaseURL points the client at a different API server, e.g. a local test server
+func (c *ChatClient) SetBaseURL(baseURL string) {
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// SetMessageHandler sets the handler for lamp commands
+func (c *ChatClient) SetMessageHandler(handler domain.ChatCommandHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messageHandler = handler
+}
+
+// SetStateHandler sets the handler for connection state changes
+func (c *ChatClient) SetStateHandler(handler domain.ConnectionStateHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stateHandler = handler
+}
+
+// SetPresenceHandler sets the handler for viewer activity.
+// YouTube doesn't report joins, only messages show a viewer is watching.
+func (c *ChatClient) SetPresenceHandler(handler domain.ChatPresenceHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.presenceHandler = handler
+}
+
+// Connect starts polling the live chat in the background
+func (c *ChatClient) Connect(ctx context.Context) error {
+	if c.apiKey == "" {
+		return fmt.Errorf("YouTube API key is not configured")
+	}
+
+	c.mu.Lock()
+	if c.cancel != nil {
+		c.mu.Unlock()
+		return nil // Already running
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.mu.Unlock()
+
+	go c.run(runCtx)
+
+	return nil
+}
+
+// Disconnect stops polling
+func (c *ChatClient) Disconnect() error {
+	c.mu.Lock()
+	cancel := c.cancel
+	c.cancel = nil
+	c.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	return nil
+}
+
+// Status returns the current connection status
+func (c *ChatClient) Status() domain.ConnectionStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
+}
+
+// SendMessage is not supported with an API key, replies are only logged
+func (c *ChatClient) SendMessage(message string) {
+	c.log.Info("Reply not sent, chat is read-only", "message", message)
+}
+
+// run resolves the live chat and polls it until the context is canceled
+func (c *ChatClient) run(ctx context.Context) {
+	attempt := 0
+	c.setStatus(domain.NewConnectionStatus(domain.ConnectionConnecting))
+
+	for {
+		err := c.poll(ctx)
+		if ctx.Err() != nil {
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionDisconnected))
+			return
+		}
+
+		if err == errInvalidKey {
+			status := domain.NewConnectionStatus(domain.ConnectionAuthFailed)
+			status.Error = err.Error()
+			c.setStatus(status)
+			return
+		}
+
+		// A chat that was polled successfully starts a fresh backoff
+		if c.Status().State == domain.ConnectionJoined {
+			attempt = 0
+		}
+		attempt++
+		delay := defaultPollInterval << min(attempt-1, 4)
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+		c.log.Warn("Chat polling failed", "error", err, "retry_in", delay)
+
+		status := domain.NewConnectionStatus(domain.ConnectionReconnecting)
+		status.Attempt = attempt
+		status.RetryAt = time.Now().Add(delay)
+		status.Error = err.Error()
+		c.setStatus(status)
+
+		select {
+		case <-ctx.Done():
+			c.setStatus(domain.NewConnectionStatus(domain.ConnectionDisconnected))
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// poll reads the chat until an error occurs.
+// Messages sent before the first poll are skipped so old commands don't replay.
+func (c *ChatClient) poll(ctx context.Context) error {
+	chatID, err := c.liveChatID(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.setStatus(domain.NewConnectionStatus(domain.ConnectionJoined))
+
+	pageToken := ""
+	first := true
+	for {
+		page, err := c.messages(ctx, chatID, pageToken)
+		if err != nil {
+			return err
+		}
+
+		if !first {
+			for _, item := range page.Items {
+				c.handleMessage(item)
+			}
+		}
+		first = false
+		pageToken = page.NextPageToken
+
+		interval := time.Duration(page.PollingIntervalMillis) * time.Millisecond
+		if interval <= 0 {
+			interval = defaultPollInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// handleMessage reports the author as active and passes lamp commands on to the message handler
+func (c *ChatClient) handleMessage(item chatMessage) {
+	if item.Snippet.Type != "textMessageEvent" {
+		return
+	}
+
+	author := item.AuthorDetails
+	username := strings.ToLower(strings.TrimPrefix(author.DisplayName, "@"))
+	timestamp, _ := time.Parse(time.RFC3339, item.Snippet.PublishedAt)
+
+	c.mu.RLock()
+	presenceHandler := c.presenceHandler
+	c.mu.RUnlock()
+
+	if presenceHandler != nil {
+		presenceHandler(&domain.ChatPresence{
+			Platform:    domain.PlatformYouTube,
+			Username:    username,
+			DisplayName: author.DisplayName,
+			Event:       domain.PresenceMessage,
+			Timestamp:   timestamp,
+		})
+	}
+
+	command, err := domain.ParseTwitchCommand(item.Snippet.DisplayMessage)
+	if err != nil {
+		return // Not a lamp command
+	}
+
+	cmd := &domain.ChatCommand{
+		Platform:      domain.PlatformYouTube,
+		Username:      username,
+		DisplayName:   author.DisplayName,
+		UserID:        author.ChannelID,
+		Command:       command,
+		IsMod:         author.IsChatModerator || author.IsChatOwner,
+		IsSub:         author.IsChatSponsor, // Channel members
+		IsBroadcaster: author.IsChatOwner,
+		Timestamp:     timestamp,
+	}
+	if cmd.IsSub {
+		cmd.SubTier = 1
+	}
+
+	c.mu.RLock()
+	handler := c.messageHandler
+	c.mu.RUnlock()
+
+	if handler != nil {
+		handler(cmd)
+	}
+}
+
+// liveChatID looks up the chat of the live stream
+func (c *ChatClient) liveChatID(ctx context.Context) (string, error) {
+	params := url.Values{}
+	params.Set("part", "liveStreamingDetails")
+	params.Set("id", c.videoID)
+
+	var response struct {
+		Items []struct {
+			LiveStreamingDetails struct {
+				ActiveLiveChatID string `json:"activeLiveChatId"`
+			} `json:"liveStreamingDetails"`
+		} `json:"items"`
+	}
+	if err := c.get(ctx, "/videos", params, &response); err != nil {
+		return "", err
+	}
+
+	if len(response.Items) == 0 {
+		return "", fmt.Errorf("video %s not found", c.videoID)
+	}
+
+	chatID := response.Items[0].LiveStreamingDetails.ActiveLiveChatID
+	if chatID == "" {
+		return "", fmt.Errorf("video %s is not live", c.videoID)
+	}
+	return chatID, nil
+}
+
+// messages fetches the next page of chat messages
+func (c *ChatClient) messages(ctx context.Context, chatID, pageToken string) (*chatPage, error) {
+	params := url.Values{}
+	params.Set("liveChatId", chatID)
+	params.Set("part", "snippet,authorDetails")
+	if pageToken != "" {
+		params.Set("pageToken", pageToken)
+	}
+
+	var page chatPage
+	if err := c.get(ctx, "/liveChat/messages", params, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// get performs an API request and decodes the JSON response
+func (c *ChatClient) get(ctx context.Context, path string, params url.Values, target interface{}) error {
+	params.Set("key", c.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusForbidden {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+				Errors  []struct {
+					Reason string `json:"reason"`
+				} `json:"errors"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		for _, e := range apiErr.Error.Errors {
+			if e.Reason == "keyInvalid" {
+				return errInvalidKey
+			}
+		}
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error (status %d)", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// setStatus stores a new status and notifies the state handler
+func (c *ChatClient) setStatus(status domain.ConnectionStatus) {
+	c.mu.Lock()
+	if c.status.State == status.State && status.State != domain.ConnectionReconnecting {
+		c.mu.Unlock()
+		return
+	}
+	c.status = status
+	handler := c.stateHandler
+	c.mu.Unlock()
+
+	c.log.Info("Connection state changed", "state", status.State)
+
+	if handler != nil {
+		handler(status)
+	}
+}
+
+// errInvalidKey is returned when YouTube rejects the API key
+var errInvalidKey = fmt.Errorf("YouTube rejected the API key")
+
+// chatPage is a page of the liveChatMessages.list response
+type chatPage struct {
+	NextPageToken         string        `json:"nextPageToken"`
+	PollingIntervalMillis int           `json:"pollingIntervalMillis"`
+	Items                 []chatMessage `json:"items"`
+}
+
+// chatMessage is a single live chat message
+type chatMessage struct {
+	Snippet struct {
+		Type           string `json:"type"`
+		DisplayMessage string `json:"displayMessage"`
+		PublishedAt    string `json:"publishedAt"`
+	} `json:"snippet"`
+	AuthorDetails struct {
+		ChannelID       string `json:"channelId"`
+		DisplayName     string `json:"displayName"`
+		IsChatOwner     bool   `json:"isChatOwner"`
+		IsChatModerator bool   `json:"isChatModerator"`
+		IsChatSponsor   bool   `json:"isChatSponsor"`
+	} `json:"authorDetails"`
+}
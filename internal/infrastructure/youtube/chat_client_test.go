@@ -0,0 +1,153 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// message builds a chat message as the liveChatMessages.list response carries it
+func message(author, text string, owner, moderator bool) map[string]any {
+	return map[string]any{
+		"snippet": map[string]any{
+			"type":           "textMessageEvent",
+			"displayMessage": text,
+			"publishedAt":    "2026-01-01T20:00:00Z",
+		},
+		"authorDetails": map[string]any{
+			"channelId":       "UC" + author,
+			"displayName":     "@" + author,
+			"isChatOwner":     owner,
+			"isChatModerator": moderator,
+		},
+	}
+}
+
+// newFakeAPI serves a live video whose chat pages are chained by page tokens
+func newFakeAPI(t *testing.T, pages map[string]map[string]any) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	requested := make([]string, 0)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/videos", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-key", r.URL.Query().Get("key"))
+		assert.Equal(t, "video-1", r.URL.Query().Get("id"))
+		json.NewEncoder(w).Encode(map[string]any{
+			"items": []any{map[string]any{"liveStreamingDetails": map[string]any{"activeLiveChatId": "chat-1"}}},
+		})
+	})
+	mux.HandleFunc("/liveChat/messages", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "chat-1", r.URL.Query().Get("liveChatId"))
+		token := r.URL.Query().Get("pageToken")
+
+		mu.Lock()
+		requested = append(requested, token)
+		mu.Unlock()
+
+		page, ok := pages[token]
+		if !ok {
+			// The last page keeps pointing at itself until new messages arrive
+			page = map[string]any{"nextPageToken": token, "pollingIntervalMillis": 10, "items": []any{}}
+		}
+		json.NewEncoder(w).Encode(page)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requested...)
+	}
+}
+
+func TestChatClientPollsPages(t *testing.T) {
+	server, requested := newFakeAPI(t, map[string]map[string]any{
+		"": {
+			"nextPageToken":         "page-2",
+			"pollingIntervalMillis": 10,
+			"items":                 []any{message("early", "!lamp red", false, false)},
+		},
+		"page-2": {
+			"nextPageToken":         "page-3",
+			"pollingIntervalMillis": 10,
+			"items": []any{
+				message("Streamer", "!lamp blue", true, false),
+				message("viewer", "hello", false, false),
+			},
+		},
+		"page-3": {
+			"nextPageToken":         "page-4",
+			"pollingIntervalMillis": 10,
+			"items":                 []any{message("helper", "!lamp green", false, true)},
+		},
+	})
+
+	var mu sync.Mutex
+	commands := make([]*domain.ChatCommand, 0)
+	presences := make([]string, 0)
+
+	client := NewChatClient("test-key", "video-1")
+	client.SetBaseURL(server.URL + "/")
+	client.SetMessageHandler(func(cmd *domain.ChatCommand) {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, cmd)
+	})
+	client.SetPresenceHandler(func(presence *domain.ChatPresence) {
+		mu.Lock()
+		defer mu.Unlock()
+		presences = append(presences, presence.Username)
+	})
+
+	require.NoError(t, client.Connect(context.Background()))
+	defer client.Disconnect()
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(commands) == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, domain.ConnectionJoined, client.Status().State)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Messages from before the client connected don't replay
+	assert.Equal(t, "blue", commands[0].Command)
+	assert.Equal(t, "streamer", commands[0].Username)
+	assert.True(t, commands[0].IsBroadcaster)
+	assert.Equal(t, "green", commands[1].Command)
+	assert.True(t, commands[1].IsMod)
+	assert.False(t, commands[1].IsBroadcaster)
+	assert.Equal(t, []string{"streamer", "viewer", "helper"}, presences)
+
+	assert.Equal(t, []string{"", "page-2", "page-3"}, requested()[:3])
+}
+
+func TestChatClientInvalidKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"API key not valid","errors":[{"reason":"keyInvalid"}]}}`))
+	}))
+	defer server.Close()
+
+	client := NewChatClient("bad-key", "video-1")
+	client.SetBaseURL(server.URL)
+	require.NoError(t, client.Connect(context.Background()))
+	defer client.Disconnect()
+
+	// An invalid key stops polling instead of retrying
+	assert.Eventually(t, func() bool {
+		return client.Status().State == domain.ConnectionAuthFailed
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,246 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// AlertConfigDTO represents the alert ingest configuration for API
+type AlertConfigDTO struct {
+	Webhooks       []WebhookDTO       `json:"webhooks"`
+	StreamElements AlertSocketDTO     `json:"streamelements"`
+	Streamlabs     AlertSocketDTO     `json:"streamlabs"`
+	Reactions      []AlertReactionDTO `json:"reactions"`
+	Types          []string           `json:"types"` // Supported alert types
+}
+
+// AlertConfigUpdateDTO represents an alert configuration update request
+type AlertConfigUpdateDTO struct {
+	Webhooks       *[]WebhookDTO         `json:"webhooks,omitempty"`
+	StreamElements *AlertSocketUpdateDTO `json:"streamelements,omitempty"`
+	Streamlabs     *AlertSocketUpdateDTO `json:"streamlabs,omitempty"`
+	Reactions      *[]AlertReactionDTO   `json:"reactions,omitempty"`
+}
+
+// WebhookDTO represents an inbound alert webhook
+type WebhookDTO struct {
+	Name            string                      `json:"name"`
+	Secret          *string                     `json:"secret,omitempty"` // Only for updates, omitted keeps the secret of the webhook with the same name
+	HasSecret       bool                        `json:"has_secret"`       // Don't expose the actual secret
+	SignatureHeader string                      `json:"signature_header"`
+	Type            string                      `json:"type,omitempty"`
+	TypePath        string                      `json:"type_path,omitempty"`
+	TypeValues      map[string]domain.AlertType `json:"type_values,omitempty"`
+	UserPath        string                      `json:"user_path,omitempty"`
+	AmountPath      string                      `json:"amount_path,omitempty"`
+	MessagePath     string                      `json:"message_path,omitempty"`
+}
+
+// AlertSocketDTO represents the socket settings of an alert provider
+type AlertSocketDTO struct {
+	Enabled  bool `json:"enabled"`
+	HasToken bool `json:"has_token"` // Don't expose the actual token
+}
+
+// AlertSocketUpdateDTO represents an alert provider socket update
+type AlertSocketUpdateDTO struct {
+	Enabled *bool   `json:"enabled,omitempty"`
+	Token   *string `json:"token,omitempty"` // Only for updates
+}
+
+// AlertReactionDTO represents the lamp reaction to an alert type
+type AlertReactionDTO struct {
+	Type                string      `json:"type"`
+	MinAmount           float64     `json:"min_amount"`
+	Color               *domain.RGB `json:"color,omitempty"`
+	Effect              *int        `json:"effect,omitempty"`
+	EffectSpeed         *uint8      `json:"effect_speed,omitempty"`
+	DurationSec         int         `json:"duration_sec"`
+	DurationPerAmountMs int         `json:"duration_per_amount_ms"` // Extra duration per raider, gift or currency unit
+	Blend               string      `json:"blend,omitempty"`        // How the reaction combines with the lamp state, default replace
+	Opacity             float64     `json:"opacity,omitempty"`      // 0 to 1, empty is fully opaque
+	FadeMs              int         `json:"fade_ms,omitempty"`      // Crossfade duration
+}
+
+// AlertDTO represents a received alert
+type AlertDTO struct {
+	Type      string    `json:"type"`
+	Source    string    `json:"source"`
+	User      string    `json:"user,omitempty"`
+	Amount    float64   `json:"amount,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AlertTestDTO represents a request to simulate an alert
+type AlertTestDTO struct {
+	Type    string  `json:"type" validate:"required"`
+	User    string  `json:"user"`
+	Amount  float64 `json:"amount"`
+	Message string  `json:"message"`
+}
+
+// AlertStatusDTO represents the alert provider connections
+type AlertStatusDTO struct {
+	Providers map[string]AlertProviderStatusDTO `json:"providers"`
+}
+
+// AlertProviderStatusDTO represents the connection status of an alert provider
+type AlertProviderStatusDTO struct {
+	Connected        bool   `json:"connected"`
+	State            string `json:"state"`
+	Error            string `json:"error,omitempty"`
+	ReconnectAttempt int    `json:"reconnect_attempt,omitempty"`
+	RetryAt          string `json:"retry_at,omitempty"`
+}
+
+// FromDomainAlertConfig converts the domain config to DTO
+func FromDomainAlertConfig(config *domain.AlertConfig) AlertConfigDTO {
+	webhooks := make([]WebhookDTO, len(config.Webhooks))
+	for i, h := range config.Webhooks {
+		webhooks[i] = WebhookDTO{
+			Name:            h.Name,
+			HasSecret:       h.Secret != "",
+			SignatureHeader: h.SignatureHeaderOrDefault(),
+			Type:            string(h.Type),
+			TypePath:        h.TypePath,
+			TypeValues:      h.TypeValues,
+			UserPath:        h.UserPath,
+			AmountPath:      h.AmountPath,
+			MessagePath:     h.MessagePath,
+		}
+	}
+
+	reactions := make([]AlertReactionDTO, len(config.Reactions))
+	for i, r := range config.Reactions {
+		reactions[i] = AlertReactionDTO{
+			Type:                string(r.Type),
+			MinAmount:           r.MinAmount,
+			Color:               r.Color,
+			Effect:              r.Effect,
+			EffectSpeed:         r.EffectSpeed,
+			DurationSec:         int(r.Duration.Seconds()),
+			DurationPerAmountMs: int(r.DurationPerAmount.Milliseconds()),
+			Blend:               string(r.Blend),
+			Opacity:             r.Opacity,
+			FadeMs:              int(r.Fade.Milliseconds()),
+		}
+	}
+
+	types := make([]string, len(domain.AlertTypes))
+	for i, alertType := range domain.AlertTypes {
+		types[i] = string(alertType)
+	}
+
+	return AlertConfigDTO{
+		Webhooks:       webhooks,
+		StreamElements: AlertSocketDTO{Enabled: config.StreamElements.Enabled, HasToken: config.StreamElements.Token != ""},
+		Streamlabs:     AlertSocketDTO{Enabled: config.Streamlabs.Enabled, HasToken: config.Streamlabs.Token != ""},
+		Reactions:      reactions,
+		Types:          types,
+	}
+}
+
+// ApplyUpdate applies the update DTO to the domain config
+func (dto *AlertConfigUpdateDTO) ApplyUpdate(config *domain.AlertConfig) {
+	if dto.Webhooks != nil {
+		webhooks := make([]domain.WebhookConfig, len(*dto.Webhooks))
+		for i, h := range *dto.Webhooks {
+			webhooks[i] = domain.WebhookConfig{
+				Name:            h.Name,
+				SignatureHeader: h.SignatureHeader,
+				Type:            domain.AlertType(h.Type),
+				TypePath:        h.TypePath,
+				TypeValues:      h.TypeValues,
+				UserPath:        h.UserPath,
+				AmountPath:      h.AmountPath,
+				MessagePath:     h.MessagePath,
+			}
+			if h.Secret != nil {
+				webhooks[i].Secret = *h.Secret
+			} else if existing := config.GetWebhook(h.Name); existing != nil {
+				webhooks[i].Secret = existing.Secret
+			}
+		}
+		config.Webhooks = webhooks
+	}
+	if dto.StreamElements != nil {
+		dto.StreamElements.apply(&config.StreamElements)
+	}
+	if dto.Streamlabs != nil {
+		dto.Streamlabs.apply(&config.Streamlabs)
+	}
+	if dto.Reactions != nil {
+		reactions := make([]domain.AlertReaction, len(*dto.Reactions))
+		for i, r := range *dto.Reactions {
+			reactions[i] = domain.AlertReaction{
+				Type:              domain.AlertType(r.Type),
+				MinAmount:         r.MinAmount,
+				Color:             r.Color,
+				Effect:            r.Effect,
+				EffectSpeed:       r.EffectSpeed,
+				Duration:          time.Duration(r.DurationSec) * time.Second,
+				DurationPerAmount: time.Duration(r.DurationPerAmountMs) * time.Millisecond,
+				Blend:             domain.BlendMode(r.Blend),
+				Opacity:           r.Opacity,
+				Fade:              time.Duration(r.FadeMs) * time.Millisecond,
+			}
+		}
+		config.Reactions = reactions
+	}
+	config.UpdatedAt = time.Now()
+}
+
+// apply applies the socket update to the provider settings
+func (dto *AlertSocketUpdateDTO) apply(socket *domain.AlertSocketConfig) {
+	if dto.Enabled != nil {
+		socket.Enabled = *dto.Enabled
+	}
+	if dto.Token != nil {
+		socket.Token = *dto.Token
+	}
+}
+
+// ToDomain converts the test request to an alert
+func (dto *AlertTestDTO) ToDomain() *domain.Alert {
+	return &domain.Alert{
+		Type:      domain.AlertType(dto.Type),
+		Source:    "test",
+		User:      dto.User,
+		Amount:    dto.Amount,
+		Message:   dto.Message,
+		Timestamp: time.Now(),
+	}
+}
+
+// FromDomainAlert converts a domain alert to DTO
+func FromDomainAlert(alert *domain.Alert) AlertDTO {
+	return AlertDTO{
+		Type:      string(alert.Type),
+		Source:    alert.Source,
+		User:      alert.User,
+		Amount:    alert.Amount,
+		Message:   alert.Message,
+		Timestamp: alert.Timestamp,
+	}
+}
+
+// FromAlertStatus converts the alert service status to DTO
+func FromAlertStatus(status application.AlertStatus) AlertStatusDTO {
+	dto := AlertStatusDTO{Providers: make(map[string]AlertProviderStatusDTO)}
+	for provider, connection := range status.Providers {
+		providerStatus := AlertProviderStatusDTO{
+			Connected:        connection.State == domain.ConnectionConnected,
+			State:            string(connection.State),
+			Error:            connection.Error,
+			ReconnectAttempt: connection.Attempt,
+		}
+		if connection.State == domain.ConnectionReconnecting && !connection.RetryAt.IsZero() {
+			providerStatus.RetryAt = connection.RetryAt.Format(time.RFC3339)
+		}
+		dto.Providers[provider] = providerStatus
+	}
+	return dto
+}
@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// ChatChannelDTO represents an additional chat the integration listens to
+type ChatChannelDTO struct {
+	ID            string                 `json:"id"`
+	Platform      string                 `json:"platform" validate:"required"`
+	Channel       string                 `json:"channel" validate:"required,min=1"`
+	Enabled       bool                   `json:"enabled"`
+	DeviceAddress string                 `json:"device_address"`
+	Commands      []string               `json:"commands"`
+	Permissions   []CommandPermissionDTO `json:"permissions"`
+}
+
+// ChatChannelUpdateDTO represents a partial chat channel update
+type ChatChannelUpdateDTO struct {
+	Enabled       *bool                   `json:"enabled,omitempty"`
+	DeviceAddress *string                 `json:"device_address,omitempty"`
+	Commands      *[]string               `json:"commands,omitempty"`
+	Permissions   *[]CommandPermissionDTO `json:"permissions,omitempty"`
+}
+
+// ChatChannelStatusDTO represents the connection state of a running chat
+type ChatChannelStatusDTO struct {
+	ID       string `json:"id"`
+	Platform string `json:"platform"`
+	Channel  string `json:"channel"`
+	State    string `json:"state"`
+	Error    string `json:"error,omitempty"`
+}
+
+// FromDomainChatChannel converts a domain chat channel to DTO
+func FromDomainChatChannel(channel domain.ChatChannel) ChatChannelDTO {
+	permissions := make([]CommandPermissionDTO, len(channel.Permissions))
+	for i, p := range channel.Permissions {
+		permissions[i] = FromDomainCommandPermission(p)
+	}
+
+	commands := channel.Commands
+	if commands == nil {
+		commands = []string{}
+	}
+
+	return ChatChannelDTO{
+		ID:            channel.ID,
+		Platform:      string(channel.Platform),
+		Channel:       channel.Channel,
+		Enabled:       channel.Enabled,
+		DeviceAddress: channel.DeviceAddress,
+		Commands:      commands,
+		Permissions:   permissions,
+	}
+}
+
+// FromDomainChatChannels converts the additional chat channels of a config to DTOs
+func FromDomainChatChannels(config *domain.TwitchConfig) []ChatChannelDTO {
+	channels := make([]ChatChannelDTO, len(config.Channels))
+	for i, channel := range config.Channels {
+		channels[i] = FromDomainChatChannel(channel)
+	}
+	return channels
+}
+
+// ToDomain converts DTO to a domain chat channel
+func (d *ChatChannelDTO) ToDomain() domain.ChatChannel {
+	channel := domain.NewChatChannel(domain.ChatPlatform(strings.ToLower(d.Platform)), d.Channel)
+	channel.Enabled = d.Enabled
+	channel.DeviceAddress = d.DeviceAddress
+	channel.Commands = normalizeCommands(d.Commands)
+
+	for _, p := range d.Permissions {
+		channel.Permissions = append(channel.Permissions, p.ToDomain())
+	}
+
+	return channel
+}
+
+// ApplyUpdate applies the update to a domain chat channel
+func (u *ChatChannelUpdateDTO) ApplyUpdate(channel *domain.ChatChannel) {
+	if u.Enabled != nil {
+		channel.Enabled = *u.Enabled
+	}
+	if u.DeviceAddress != nil {
+		channel.DeviceAddress = *u.DeviceAddress
+	}
+	if u.Commands != nil {
+		channel.Commands = normalizeCommands(*u.Commands)
+	}
+	if u.Permissions != nil {
+		channel.Permissions = make([]domain.CommandPermission, 0, len(*u.Permissions))
+		for _, p := range *u.Permissions {
+			channel.Permissions = append(channel.Permissions, p.ToDomain())
+		}
+	}
+}
+
+// FromChannelStatuses converts the running chats to DTOs
+func FromChannelStatuses(statuses []application.ChannelStatus) []ChatChannelStatusDTO {
+	dtos := make([]ChatChannelStatusDTO, len(statuses))
+	for i, s := range statuses {
+		dtos[i] = ChatChannelStatusDTO{
+			ID:       s.Channel.ID,
+			Platform: string(s.Channel.Platform),
+			Channel:  s.Channel.Channel,
+			State:    string(s.Status.State),
+			Error:    s.Status.Error,
+		}
+	}
+	return dtos
+}
+
+// normalizeCommands lowercases a command list and drops empty entries
+func normalizeCommands(commands []string) []string {
+	normalized := make([]string, 0, len(commands))
+	for _, command := range commands {
+		if command = strings.ToLower(strings.TrimSpace(command)); command != "" {
+			normalized = append(normalized, command)
+		}
+	}
+	return normalized
+}
@@ -35,7 +35,13 @@ type ScanRequestDTO struct {
 
 // SelectDeviceRequestDTO represents a request to select a device
 type SelectDeviceRequestDTO struct {
-	Address string `json:"address"` // Device MAC address
+	Address string `json:"address" validate:"required,min=1"` // Device MAC address
+}
+
+// SelectDeviceResponseDTO represents the response to a device selection
+type SelectDeviceResponseDTO struct {
+	Success bool      `json:"success"`
+	Device  DeviceDTO `json:"device"`
 }
 
 // HealthResponseDTO represents health check response
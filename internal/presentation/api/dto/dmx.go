@@ -0,0 +1,114 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// DMXConfigDTO represents the DMX input configuration for API
+type DMXConfigDTO struct {
+	Enabled         bool                `json:"enabled"`
+	ArtNet          bool                `json:"artnet"`
+	SACN            bool                `json:"sacn"`
+	MaxRate         int                 `json:"max_rate"`
+	InputTimeoutSec int                 `json:"input_timeout_sec"`
+	OnInputLoss     string              `json:"on_input_loss"`
+	Fixtures        []domain.DMXFixture `json:"fixtures"`
+}
+
+// DMXConfigUpdateDTO represents a DMX configuration update request
+type DMXConfigUpdateDTO struct {
+	Enabled         *bool                `json:"enabled,omitempty"`
+	ArtNet          *bool                `json:"artnet,omitempty"`
+	SACN            *bool                `json:"sacn,omitempty"`
+	MaxRate         *int                 `json:"max_rate,omitempty" validate:"min=0,max=30"`
+	InputTimeoutSec *int                 `json:"input_timeout_sec,omitempty"`
+	OnInputLoss     *string              `json:"on_input_loss,omitempty"`
+	Fixtures        *[]domain.DMXFixture `json:"fixtures,omitempty"` // Replaces the whole patch
+}
+
+// DMXStatusDTO represents the DMX input status
+type DMXStatusDTO struct {
+	Running   bool             `json:"running"`
+	Universes []DMXUniverseDTO `json:"universes"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// DMXUniverseDTO represents the input state of one universe
+type DMXUniverseDTO struct {
+	Universe  int    `json:"universe"`
+	Active    bool   `json:"active"`
+	Protocol  string `json:"protocol,omitempty"`
+	Source    string `json:"source,omitempty"`
+	LastFrame string `json:"last_frame,omitempty"`
+	Frames    uint64 `json:"frames"`
+}
+
+// FromDomainDMXConfig converts the domain config to DTO
+func FromDomainDMXConfig(config *domain.DMXConfig) DMXConfigDTO {
+	fixtures := config.Fixtures
+	if fixtures == nil {
+		fixtures = []domain.DMXFixture{}
+	}
+
+	return DMXConfigDTO{
+		Enabled:         config.Enabled,
+		ArtNet:          config.ArtNet,
+		SACN:            config.SACN,
+		MaxRate:         config.MaxRateOrDefault(),
+		InputTimeoutSec: int(config.InputTimeout() / time.Second),
+		OnInputLoss:     string(config.OnInputLossOrDefault()),
+		Fixtures:        fixtures,
+	}
+}
+
+// ApplyUpdate applies the update DTO to the domain config
+func (dto *DMXConfigUpdateDTO) ApplyUpdate(config *domain.DMXConfig) {
+	if dto.Enabled != nil {
+		config.Enabled = *dto.Enabled
+	}
+	if dto.ArtNet != nil {
+		config.ArtNet = *dto.ArtNet
+	}
+	if dto.SACN != nil {
+		config.SACN = *dto.SACN
+	}
+	if dto.MaxRate != nil {
+		config.MaxRate = *dto.MaxRate
+	}
+	if dto.InputTimeoutSec != nil {
+		config.InputTimeoutSec = *dto.InputTimeoutSec
+	}
+	if dto.OnInputLoss != nil {
+		config.OnInputLoss = domain.DMXLossBehavior(*dto.OnInputLoss)
+	}
+	if dto.Fixtures != nil {
+		config.Fixtures = append([]domain.DMXFixture{}, *dto.Fixtures...)
+	}
+	config.UpdatedAt = time.Now()
+}
+
+// FromDMXStatus converts the DMX input status to DTO
+func FromDMXStatus(status domain.DMXStatus) DMXStatusDTO {
+	universes := make([]DMXUniverseDTO, 0, len(status.Universes))
+	for _, universe := range status.Universes {
+		dto := DMXUniverseDTO{
+			Universe: universe.Universe,
+			Active:   universe.Active,
+			Protocol: universe.Protocol,
+			Source:   universe.Source,
+			Frames:   universe.Frames,
+		}
+		if !universe.LastFrame.IsZero() {
+			dto.LastFrame = universe.LastFrame.Format(time.RFC3339)
+		}
+		universes = append(universes, dto)
+	}
+
+	return DMXStatusDTO{
+		Running:   status.Running,
+		Universes: universes,
+		Error:     status.Error,
+	}
+}
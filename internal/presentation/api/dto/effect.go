@@ -25,12 +25,20 @@ type RGBColorDTO struct {
 
 // CreateEffectRequestDTO represents a request to create a custom effect
 type CreateEffectRequestDTO struct {
-	Name    string         `json:"name"`
-	Colors  []RGBColorDTO  `json:"colors"`
-	Pattern string         `json:"pattern"`
+	Name    string         `json:"name" validate:"required,min=1"`
+	Colors  []RGBColorDTO  `json:"colors" validate:"required,min=1"`
+	Pattern EffectPattern  `json:"pattern" validate:"required"`
 	Speed   uint8          `json:"speed"`
 }
 
+// EffectPattern is the pattern of a custom effect, one of domain.EffectPatterns
+type EffectPattern string
+
+// EnumValues lists the patterns for the API schema
+func (EffectPattern) EnumValues() []string {
+	return domain.EffectPatterns
+}
+
 // CustomEffectFromDomain converts a domain CustomEffect to DTO
 func CustomEffectFromDomain(effect *domain.CustomEffect) CustomEffectDTO {
 	colors := make([]RGBColorDTO, len(effect.Colors))
@@ -64,5 +72,5 @@ func (r *CreateEffectRequestDTO) ToDomain() *domain.CustomEffect {
 		colors[i] = domain.RGBColor{R: c.R, G: c.G, B: c.B}
 	}
 
-	return domain.NewCustomEffect(r.Name, colors, r.Pattern, r.Speed)
+	return domain.NewCustomEffect(r.Name, colors, string(r.Pattern), r.Speed)
 }
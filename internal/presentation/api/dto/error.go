@@ -0,0 +1,37 @@
+package dto
+
+import "net/http"
+
+// Error codes of ErrorDTO
+const (
+	ErrorCodeInvalidBody  = "invalid_body"      // The body isn't JSON
+	ErrorCodeValidation   = "validation_failed" // A value breaks the schema, Field names it
+	ErrorCodeBadRequest   = "bad_request"
+	ErrorCodeUnauthorized = "unauthorized"
+	ErrorCodeNotFound     = "not_found"
+	ErrorCodeConflict     = "conflict"
+	ErrorCodeInternal     = "internal_error"
+)
+
+// ErrorDTO is the body of every REST error response
+type ErrorDTO struct {
+	Code    string `json:"code" validate:"required"`
+	Message string `json:"message" validate:"required"`
+	Field   string `json:"field,omitempty"` // Offending request field, e.g. colors[0].r
+}
+
+// ErrorCodeForStatus returns the error code matching an HTTP status
+func ErrorCodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrorCodeBadRequest
+	case http.StatusUnauthorized:
+		return ErrorCodeUnauthorized
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusConflict:
+		return ErrorCodeConflict
+	default:
+		return ErrorCodeInternal
+	}
+}
@@ -0,0 +1,54 @@
+package dto
+
+// Message types only sent on the event stream
+const (
+	MessageTypeDeviceState      MessageType = "device_state"
+	MessageTypeDeviceConnection MessageType = "device_connection"
+	MessageTypeScanStarted      MessageType = "scan_started"
+)
+
+// DeviceStateMessage reports the new state of any lamp, selected or not
+type DeviceStateMessage struct {
+	Type    MessageType    `json:"type"`
+	Address string         `json:"address"`
+	State   DeviceStateDTO `json:"state"`
+}
+
+// DeviceConnectionMessage reports a lamp connecting or disconnecting
+type DeviceConnectionMessage struct {
+	Type      MessageType `json:"type"`
+	Address   string      `json:"address"`
+	Connected bool        `json:"connected"`
+}
+
+// ScanStartedMessage reports a device scan being started
+type ScanStartedMessage struct {
+	Type    MessageType `json:"type"`
+	Timeout string      `json:"timeout"`
+}
+
+// NewDeviceStateMessage creates a device state message
+func NewDeviceStateMessage(address string, state DeviceStateDTO) DeviceStateMessage {
+	return DeviceStateMessage{
+		Type:    MessageTypeDeviceState,
+		Address: address,
+		State:   state,
+	}
+}
+
+// NewDeviceConnectionMessage creates a device connection message
+func NewDeviceConnectionMessage(address string, connected bool) DeviceConnectionMessage {
+	return DeviceConnectionMessage{
+		Type:      MessageTypeDeviceConnection,
+		Address:   address,
+		Connected: connected,
+	}
+}
+
+// NewScanStartedMessage creates a scan started message
+func NewScanStartedMessage(timeout string) ScanStartedMessage {
+	return ScanStartedMessage{
+		Type:    MessageTypeScanStarted,
+		Timeout: timeout,
+	}
+}
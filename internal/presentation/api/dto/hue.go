@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// HueConfigDTO represents the Hue bridge configuration for API
+type HueConfigDTO struct {
+	Enabled bool         `json:"enabled"`
+	Port    int          `json:"port"`
+	Users   []HueUserDTO `json:"users"`
+}
+
+// HueUserDTO represents an app paired with the bridge
+type HueUserDTO struct {
+	Username   string `json:"username"`
+	DeviceType string `json:"device_type"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// HueConfigUpdateDTO represents a Hue configuration update request
+type HueConfigUpdateDTO struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	Port    *int  `json:"port,omitempty" validate:"min=0,max=65535"`
+}
+
+// HueStatusDTO represents the Hue bridge status
+type HueStatusDTO struct {
+	Running       bool   `json:"running"`
+	Port          int    `json:"port,omitempty"`
+	Discovery     bool   `json:"discovery"`
+	LinkButton    bool   `json:"link_button"`              // Whether apps can pair right now
+	LinkRemaining int    `json:"link_remaining,omitempty"` // Seconds left to pair
+	Error         string `json:"error,omitempty"`
+}
+
+// FromDomainHueConfig converts the domain config to DTO
+func FromDomainHueConfig(config *domain.HueConfig) HueConfigDTO {
+	users := make([]HueUserDTO, 0, len(config.Users))
+	for _, user := range config.Users {
+		users = append(users, HueUserDTO{
+			Username:   user.Username,
+			DeviceType: user.DeviceType,
+			CreatedAt:  user.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return HueConfigDTO{
+		Enabled: config.Enabled,
+		Port:    config.PortOrDefault(),
+		Users:   users,
+	}
+}
+
+// ApplyUpdate applies the update DTO to the domain config
+func (dto *HueConfigUpdateDTO) ApplyUpdate(config *domain.HueConfig) {
+	if dto.Enabled != nil {
+		config.Enabled = *dto.Enabled
+	}
+	if dto.Port != nil {
+		config.Port = *dto.Port
+	}
+	config.UpdatedAt = time.Now()
+}
+
+// FromHueStatus converts the Hue bridge status to DTO
+func FromHueStatus(status domain.HueStatus) HueStatusDTO {
+	dto := HueStatusDTO{
+		Running:   status.Running,
+		Discovery: status.Discovery,
+		Error:     status.Error,
+	}
+	if status.Running {
+		dto.Port = status.Port
+	}
+	if remaining := time.Until(status.LinkUntil); remaining > 0 {
+		dto.LinkButton = true
+		dto.LinkRemaining = int(remaining.Round(time.Second).Seconds())
+	}
+	return dto
+}
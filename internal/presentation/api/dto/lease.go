@@ -0,0 +1,112 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// LeaseStackDTO represents the sources holding a lamp, the owner last
+type LeaseStackDTO struct {
+	DeviceAddress string         `json:"device_address"`
+	Owner         string         `json:"owner"`
+	Base          DeviceStateDTO `json:"base"`  // State restored when all leases end
+	State         DeviceStateDTO `json:"state"` // State the leases add up to
+	Leases        []LeaseDTO     `json:"leases"`
+}
+
+// LeaseDTO represents the claim of a source on a lamp
+type LeaseDTO struct {
+	Source       string  `json:"source"`
+	Holder       string  `json:"holder,omitempty"`
+	Priority     int     `json:"priority"`
+	Blend        string  `json:"blend"`
+	Opacity      float64 `json:"opacity"` // Current opacity, ramping up during a crossfade
+	AcquiredAt   string  `json:"acquired_at"`
+	ExpiresAt    string  `json:"expires_at,omitempty"`
+	RemainingSec int     `json:"remaining_sec,omitempty"`
+}
+
+// FromLeaseStacks converts the lease stacks of all lamps to DTOs
+func FromLeaseStacks(stacks []application.DeviceLeases) []LeaseStackDTO {
+	now := time.Now()
+
+	dtos := make([]LeaseStackDTO, 0, len(stacks))
+	for _, device := range stacks {
+		stack := device.Stack
+
+		dto := LeaseStackDTO{
+			DeviceAddress: device.DeviceAddress,
+			Base:          FromDomainState(stack.Base),
+			State:         FromDomainState(stack.Render(now)),
+			Leases:        make([]LeaseDTO, 0, len(stack.Leases)),
+		}
+		if top := stack.Top(); top != nil {
+			dto.Owner = top.Source
+		}
+		for _, lease := range stack.Leases {
+			dto.Leases = append(dto.Leases, FromDomainLease(lease, now))
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos
+}
+
+// FromDomainLease converts a domain lease to DTO
+func FromDomainLease(lease domain.Lease, now time.Time) LeaseDTO {
+	blend := lease.Blend
+	if blend == "" {
+		blend = domain.BlendReplace
+	}
+
+	dto := LeaseDTO{
+		Source:     lease.Source,
+		Holder:     lease.Holder,
+		Priority:   int(lease.Priority),
+		Blend:      string(blend),
+		Opacity:    lease.OpacityAt(now),
+		AcquiredAt: lease.AcquiredAt.Format(time.RFC3339),
+	}
+
+	if !lease.ExpiresAt.IsZero() {
+		dto.ExpiresAt = lease.ExpiresAt.Format(time.RFC3339)
+		dto.RemainingSec = int(lease.Remaining(now).Seconds())
+	}
+
+	return dto
+}
+
+// OverlayRequestDTO lays a state over the base scene of a lamp until it is cleared
+type OverlayRequestDTO struct {
+	DeviceAddress string               `json:"device_address,omitempty"` // Empty uses the selected device
+	Power         *bool                `json:"power,omitempty"`
+	Color         *domain.RGB          `json:"color,omitempty"`
+	Brightness    *uint8               `json:"brightness,omitempty"`
+	WhiteBalance  *domain.WhiteBalance `json:"white_balance,omitempty"`
+	Blend         string               `json:"blend,omitempty"`   // How the overlay combines with the base scene, default replace
+	Opacity       float64              `json:"opacity,omitempty"` // 0 to 1, empty is fully opaque
+	FadeMs        int                  `json:"fade_ms,omitempty" validate:"min=0"`
+}
+
+// OverlayClearDTO removes the overlay of a lamp
+type OverlayClearDTO struct {
+	DeviceAddress string `json:"device_address,omitempty"` // Empty uses the selected device
+}
+
+// ToDomain converts an overlay request to the overlay lease
+func (dto *OverlayRequestDTO) ToDomain() domain.Lease {
+	return domain.Lease{
+		Source: domain.LeaseSourceOverlay,
+		Holder: "streamer",
+		Change: domain.StateChange{
+			PowerOn:      dto.Power,
+			Brightness:   dto.Brightness,
+			RGB:          dto.Color,
+			WhiteBalance: dto.WhiteBalance,
+		},
+		Blend:   domain.BlendMode(dto.Blend),
+		Opacity: dto.Opacity,
+		Fade:    time.Duration(dto.FadeMs) * time.Millisecond,
+	}
+}
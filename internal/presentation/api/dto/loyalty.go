@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// defaultLeaderboardLimit caps leaderboard responses when no limit is given
+const defaultLeaderboardLimit = 10
+
+// PointsAccountDTO represents the loyalty points of a viewer
+type PointsAccountDTO struct {
+	Viewer      string `json:"viewer"`
+	DisplayName string `json:"display_name"`
+	Balance     int    `json:"balance"`
+	Earned      int    `json:"earned"`
+	Spent       int    `json:"spent"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
+
+// PointsAdjustDTO represents an admin change of a viewer's balance
+type PointsAdjustDTO struct {
+	Delta int `json:"delta" validate:"required"` // Points to add, negative to remove
+}
+
+// FromDomainPointsAccount converts a domain points account to DTO
+func FromDomainPointsAccount(account domain.PointsAccount) PointsAccountDTO {
+	dto := PointsAccountDTO{
+		Viewer:      account.Viewer,
+		DisplayName: account.DisplayName,
+		Balance:     account.Balance,
+		Earned:      account.Earned,
+		Spent:       account.Spent,
+	}
+	if !account.UpdatedAt.IsZero() {
+		dto.UpdatedAt = account.UpdatedAt.Format(time.RFC3339)
+	}
+	return dto
+}
+
+// FromDomainPointsAccounts converts points accounts to DTOs
+func FromDomainPointsAccounts(accounts []domain.PointsAccount) []PointsAccountDTO {
+	dtos := make([]PointsAccountDTO, len(accounts))
+	for i, account := range accounts {
+		dtos[i] = FromDomainPointsAccount(account)
+	}
+	return dtos
+}
+
+// ParseLeaderboardLimit reads the limit query parameter of the leaderboard
+func ParseLeaderboardLimit(query url.Values) (int, error) {
+	limit := query.Get("limit")
+	if limit == "" {
+		return defaultLeaderboardLimit, nil
+	}
+
+	n, err := strconv.Atoi(limit)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid limit: %s", limit)
+	}
+	return n, nil
+}
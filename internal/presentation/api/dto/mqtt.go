@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// MQTTConfigDTO represents the MQTT bridge configuration for API
+type MQTTConfigDTO struct {
+	Enabled         bool   `json:"enabled"`
+	Broker          string `json:"broker"`
+	Username        string `json:"username,omitempty"`
+	HasPassword     bool   `json:"has_password"` // Don't expose the actual password
+	ClientID        string `json:"client_id"`
+	BaseTopic       string `json:"base_topic"`
+	DiscoveryPrefix string `json:"discovery_prefix"`
+}
+
+// MQTTConfigUpdateDTO represents an MQTT configuration update request
+type MQTTConfigUpdateDTO struct {
+	Enabled         *bool   `json:"enabled,omitempty"`
+	Broker          *string `json:"broker,omitempty"`
+	Username        *string `json:"username,omitempty"`
+	Password        *string `json:"password,omitempty"` // Only for updates
+	ClientID        *string `json:"client_id,omitempty"`
+	BaseTopic       *string `json:"base_topic,omitempty"`
+	DiscoveryPrefix *string `json:"discovery_prefix,omitempty"`
+}
+
+// MQTTStatusDTO represents the MQTT broker connection status
+type MQTTStatusDTO struct {
+	Connected        bool   `json:"connected"`
+	State            string `json:"state"`
+	Error            string `json:"error,omitempty"`
+	ReconnectAttempt int    `json:"reconnect_attempt,omitempty"`
+	RetryAt          string `json:"retry_at,omitempty"`
+}
+
+// FromDomainMQTTConfig converts the domain config to DTO
+func FromDomainMQTTConfig(config *domain.MQTTConfig) MQTTConfigDTO {
+	broker := config.Broker
+	if broker == "" {
+		broker = domain.DefaultMQTTBroker
+	}
+
+	return MQTTConfigDTO{
+		Enabled:         config.Enabled,
+		Broker:          broker,
+		Username:        config.Username,
+		HasPassword:     config.Password != "",
+		ClientID:        config.ClientIDOrDefault(),
+		BaseTopic:       config.BaseTopicOrDefault(),
+		DiscoveryPrefix: config.DiscoveryPrefixOrDefault(),
+	}
+}
+
+// ApplyUpdate applies the update DTO to the domain config
+func (dto *MQTTConfigUpdateDTO) ApplyUpdate(config *domain.MQTTConfig) {
+	if dto.Enabled != nil {
+		config.Enabled = *dto.Enabled
+	}
+	if dto.Broker != nil {
+		config.Broker = *dto.Broker
+	}
+	if dto.Username != nil {
+		config.Username = *dto.Username
+	}
+	if dto.Password != nil {
+		config.Password = *dto.Password
+	}
+	if dto.ClientID != nil {
+		config.ClientID = *dto.ClientID
+	}
+	if dto.BaseTopic != nil {
+		config.BaseTopic = *dto.BaseTopic
+	}
+	if dto.DiscoveryPrefix != nil {
+		config.DiscoveryPrefix = *dto.DiscoveryPrefix
+	}
+	config.UpdatedAt = time.Now()
+}
+
+// FromMQTTStatus converts the MQTT connection status to DTO
+func FromMQTTStatus(status domain.ConnectionStatus) MQTTStatusDTO {
+	dto := MQTTStatusDTO{
+		Connected:        status.State == domain.ConnectionConnected,
+		State:            string(status.State),
+		Error:            status.Error,
+		ReconnectAttempt: status.Attempt,
+	}
+	if status.State == domain.ConnectionReconnecting && !status.RetryAt.IsZero() {
+		dto.RetryAt = status.RetryAt.Format(time.RFC3339)
+	}
+	return dto
+}
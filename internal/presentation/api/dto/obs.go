@@ -0,0 +1,125 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// OBSConfigDTO represents the OBS integration configuration for API
+type OBSConfigDTO struct {
+	Enabled     bool            `json:"enabled"`
+	Address     string          `json:"address"`
+	HasPassword bool            `json:"has_password"` // Don't expose the actual password
+	Mappings    []OBSMappingDTO `json:"mappings"`
+	Events      []string        `json:"events"` // Supported OBS events
+}
+
+// OBSConfigUpdateDTO represents an OBS configuration update request
+type OBSConfigUpdateDTO struct {
+	Enabled  *bool            `json:"enabled,omitempty"`
+	Address  *string          `json:"address,omitempty"`
+	Password *string          `json:"password,omitempty"` // Only for updates
+	Mappings *[]OBSMappingDTO `json:"mappings,omitempty"`
+}
+
+// OBSMappingDTO represents a mapping from an OBS event to a lamp action
+type OBSMappingDTO struct {
+	Event         string           `json:"event"`
+	Scene         string           `json:"scene,omitempty"`
+	Active        *bool            `json:"active,omitempty"`
+	DeviceAddress string           `json:"device_address,omitempty"`
+	Action        domain.OBSAction `json:"action"`
+	Blend         string           `json:"blend,omitempty"`   // How the action combines with the lamp state, default replace
+	Opacity       float64          `json:"opacity,omitempty"` // 0 to 1, empty is fully opaque
+	FadeMs        int              `json:"fade_ms,omitempty"` // Crossfade duration
+}
+
+// OBSStatusDTO represents the OBS connection status and OBS state
+type OBSStatusDTO struct {
+	Connected        bool   `json:"connected"`
+	State            string `json:"state"`
+	Error            string `json:"error,omitempty"`
+	ReconnectAttempt int    `json:"reconnect_attempt,omitempty"`
+	RetryAt          string `json:"retry_at,omitempty"`
+	Scene            string `json:"scene,omitempty"`
+	Streaming        bool   `json:"streaming"`
+	Recording        bool   `json:"recording"`
+}
+
+// FromDomainOBSConfig converts the domain config to DTO
+func FromDomainOBSConfig(config *domain.OBSConfig) OBSConfigDTO {
+	mappings := make([]OBSMappingDTO, len(config.Mappings))
+	for i, m := range config.Mappings {
+		mappings[i] = OBSMappingDTO{
+			Event:         string(m.Event),
+			Scene:         m.Scene,
+			Active:        m.Active,
+			DeviceAddress: m.DeviceAddress,
+			Action:        m.Action,
+			Blend:         string(m.Blend),
+			Opacity:       m.Opacity,
+			FadeMs:        int(m.Fade.Milliseconds()),
+		}
+	}
+
+	return OBSConfigDTO{
+		Enabled:     config.Enabled,
+		Address:     config.AddressOrDefault(),
+		HasPassword: config.Password != "",
+		Mappings:    mappings,
+		Events: []string{
+			string(domain.OBSSceneChanged),
+			string(domain.OBSStreamStateChanged),
+			string(domain.OBSRecordStateChanged),
+		},
+	}
+}
+
+// ApplyUpdate applies the update DTO to the domain config
+func (dto *OBSConfigUpdateDTO) ApplyUpdate(config *domain.OBSConfig) {
+	if dto.Enabled != nil {
+		config.Enabled = *dto.Enabled
+	}
+	if dto.Address != nil {
+		config.Address = *dto.Address
+	}
+	if dto.Password != nil {
+		config.Password = *dto.Password
+	}
+	if dto.Mappings != nil {
+		mappings := make([]domain.OBSMapping, len(*dto.Mappings))
+		for i, m := range *dto.Mappings {
+			mappings[i] = domain.OBSMapping{
+				Event:         domain.OBSEventType(m.Event),
+				Scene:         m.Scene,
+				Active:        m.Active,
+				DeviceAddress: m.DeviceAddress,
+				Action:        m.Action,
+				Blend:         domain.BlendMode(m.Blend),
+				Opacity:       m.Opacity,
+				Fade:          time.Duration(m.FadeMs) * time.Millisecond,
+			}
+		}
+		config.Mappings = mappings
+	}
+	config.UpdatedAt = time.Now()
+}
+
+// FromOBSStatus converts the OBS service status to DTO
+func FromOBSStatus(status application.OBSStatus) OBSStatusDTO {
+	dto := OBSStatusDTO{
+		Connected:        status.Connection.State == domain.ConnectionConnected,
+		State:            string(status.Connection.State),
+		Error:            status.Connection.Error,
+		ReconnectAttempt: status.Connection.Attempt,
+		Scene:            status.Scene,
+		Streaming:        status.Streaming,
+		Recording:        status.Recording,
+	}
+	if status.Connection.State == domain.ConnectionReconnecting && !status.Connection.RetryAt.IsZero() {
+		dto.RetryAt = status.Connection.RetryAt.Format(time.RFC3339)
+	}
+	return dto
+}
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// OpenRGBConfigDTO represents the OpenRGB SDK server configuration for API
+type OpenRGBConfigDTO struct {
+	Enabled bool `json:"enabled"`
+	Port    int  `json:"port"`
+	MaxRate int  `json:"max_rate"`
+}
+
+// OpenRGBConfigUpdateDTO represents an OpenRGB configuration update request
+type OpenRGBConfigUpdateDTO struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	Port    *int  `json:"port,omitempty" validate:"min=0,max=65535"`
+	MaxRate *int  `json:"max_rate,omitempty" validate:"min=0,max=30"`
+}
+
+// OpenRGBStatusDTO represents the OpenRGB SDK server status
+type OpenRGBStatusDTO struct {
+	Running bool     `json:"running"`
+	Port    int      `json:"port,omitempty"`
+	Clients []string `json:"clients"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// FromDomainOpenRGBConfig converts the domain config to DTO
+func FromDomainOpenRGBConfig(config *domain.OpenRGBConfig) OpenRGBConfigDTO {
+	return OpenRGBConfigDTO{
+		Enabled: config.Enabled,
+		Port:    config.PortOrDefault(),
+		MaxRate: config.MaxRateOrDefault(),
+	}
+}
+
+// ApplyUpdate applies the update DTO to the domain config
+func (dto *OpenRGBConfigUpdateDTO) ApplyUpdate(config *domain.OpenRGBConfig) {
+	if dto.Enabled != nil {
+		config.Enabled = *dto.Enabled
+	}
+	if dto.Port != nil {
+		config.Port = *dto.Port
+	}
+	if dto.MaxRate != nil {
+		config.MaxRate = *dto.MaxRate
+	}
+	config.UpdatedAt = time.Now()
+}
+
+// FromOpenRGBStatus converts the OpenRGB server status to DTO
+func FromOpenRGBStatus(status domain.OpenRGBStatus) OpenRGBStatusDTO {
+	clients := status.Clients
+	if clients == nil {
+		clients = []string{}
+	}
+
+	return OpenRGBStatusDTO{
+		Running: status.Running,
+		Port:    status.Port,
+		Clients: clients,
+		Error:   status.Error,
+	}
+}
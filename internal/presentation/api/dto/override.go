@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// OverrideStatusDTO represents the streamer override state
+type OverrideStatusDTO struct {
+	Locked      bool   `json:"locked"`
+	LockedBy    string `json:"locked_by,omitempty"`
+	LockedAt    string `json:"locked_at,omitempty"`
+	LastPanicAt string `json:"last_panic_at,omitempty"`
+}
+
+// OverrideRequestDTO represents a lock or panic request
+type OverrideRequestDTO struct {
+	By string `json:"by"` // Who triggered the action, defaults to "streamer"
+}
+
+// FromDomainOverride converts the domain override state to DTO
+func FromDomainOverride(override domain.OverrideState) OverrideStatusDTO {
+	status := OverrideStatusDTO{
+		Locked:   override.Locked,
+		LockedBy: override.LockedBy,
+	}
+
+	if !override.LockedAt.IsZero() {
+		status.LockedAt = override.LockedAt.Format(time.RFC3339)
+	}
+	if !override.LastPanicAt.IsZero() {
+		status.LastPanicAt = override.LastPanicAt.Format(time.RFC3339)
+	}
+
+	return status
+}
@@ -18,6 +18,19 @@ type TwitchConfigDTO struct {
 	GlobalCooldownSec int `json:"global_cooldown_sec"`
 	UserCooldownSec   int `json:"user_cooldown_sec"`
 
+	VoteMode        bool `json:"vote_mode"`
+	VoteDurationSec int  `json:"vote_duration_sec"`
+
+	LoyaltyEnabled     bool `json:"loyalty_enabled"`
+	LoyaltyIntervalSec int  `json:"loyalty_interval_sec"`
+	LoyaltyWatchPoints int  `json:"loyalty_watch_points"`
+	LoyaltyChatPoints  int  `json:"loyalty_chat_points"`
+
+	MaxFlashesPerSecond int  `json:"max_flashes_per_second"`
+	MinHueChangeMs      int  `json:"min_hue_change_ms"`
+	BlockViewerStrobe   bool `json:"block_viewer_strobe"`
+	EpilepsySafe        bool `json:"epilepsy_safe"`
+
 	VIPBypassCooldown bool `json:"vip_bypass_cooldown"`
 	SubBypassCooldown bool `json:"sub_bypass_cooldown"`
 	ModBypassCooldown bool `json:"mod_bypass_cooldown"`
@@ -34,6 +47,19 @@ type TwitchConfigUpdateDTO struct {
 	GlobalCooldownSec *int `json:"global_cooldown_sec,omitempty"`
 	UserCooldownSec   *int `json:"user_cooldown_sec,omitempty"`
 
+	VoteMode        *bool `json:"vote_mode,omitempty"`
+	VoteDurationSec *int  `json:"vote_duration_sec,omitempty"`
+
+	LoyaltyEnabled     *bool `json:"loyalty_enabled,omitempty"`
+	LoyaltyIntervalSec *int  `json:"loyalty_interval_sec,omitempty"`
+	LoyaltyWatchPoints *int  `json:"loyalty_watch_points,omitempty"`
+	LoyaltyChatPoints  *int  `json:"loyalty_chat_points,omitempty"`
+
+	MaxFlashesPerSecond *int  `json:"max_flashes_per_second,omitempty"`
+	MinHueChangeMs      *int  `json:"min_hue_change_ms,omitempty"`
+	BlockViewerStrobe   *bool `json:"block_viewer_strobe,omitempty"`
+	EpilepsySafe        *bool `json:"epilepsy_safe,omitempty"`
+
 	VIPBypassCooldown *bool `json:"vip_bypass_cooldown,omitempty"`
 	SubBypassCooldown *bool `json:"sub_bypass_cooldown,omitempty"`
 	ModBypassCooldown *bool `json:"mod_bypass_cooldown,omitempty"`
@@ -42,15 +68,27 @@ type TwitchConfigUpdateDTO struct {
 // TwitchStatusDTO represents Twitch connection status
 type TwitchStatusDTO struct {
 	Connected    bool             `json:"connected"`
+	State        string           `json:"state"`
+	Error        string           `json:"error,omitempty"`
+	ReconnectAttempt int          `json:"reconnect_attempt,omitempty"`
+	RetryAt      string           `json:"retry_at,omitempty"`
 	Channel      string           `json:"channel,omitempty"`
 	ActiveEffect *ActiveEffectDTO `json:"active_effect,omitempty"`
+	ActiveEffects []*ActiveEffectDTO `json:"active_effects"` // Effects on all devices
+	Channels     []ChatChannelStatusDTO `json:"channels"`
+	ActiveVote   *VoteDTO         `json:"active_vote,omitempty"`
+	Override     OverrideStatusDTO `json:"override"`
+	Cooldowns    []CooldownStatusDTO `json:"cooldowns"` // Running cooldowns per device
 }
 
 // ActiveEffectDTO represents currently active viewer effect
 type ActiveEffectDTO struct {
 	Username         string `json:"username"`
 	Command          string `json:"command"`
+	Channel          string `json:"channel,omitempty"`
+	DeviceAddress    string `json:"device_address"`
 	StartedAt        string `json:"started_at"`
+	DurationSec      int    `json:"duration_sec"`
 	RemainingTimeSec int    `json:"remaining_time_sec"`
 }
 
@@ -58,21 +96,32 @@ type ActiveEffectDTO struct {
 type TwitchCommandListDTO struct {
 	Colors  []string `json:"colors"`
 	Effects []string `json:"effects"`
+	Power   []string `json:"power"`
 }
 
 // FromDomainTwitchConfig converts domain config to DTO
 func FromDomainTwitchConfig(config *domain.TwitchConfig) TwitchConfigDTO {
 	return TwitchConfigDTO{
-		Enabled:           config.Enabled,
-		Channel:           config.Channel,
-		BotUsername:       config.BotUsername,
-		HasToken:          config.AccessToken != "",
-		EffectDurationSec: int(config.EffectDuration.Seconds()),
-		GlobalCooldownSec: int(config.GlobalCooldown.Seconds()),
-		UserCooldownSec:   int(config.UserCooldown.Seconds()),
-		VIPBypassCooldown: config.VIPBypassCooldown,
-		SubBypassCooldown: config.SubBypassCooldown,
-		ModBypassCooldown: config.ModBypassCooldown,
+		Enabled:             config.Enabled,
+		Channel:             config.Channel,
+		BotUsername:         config.BotUsername,
+		HasToken:            config.AccessToken != "",
+		EffectDurationSec:   int(config.EffectDuration.Seconds()),
+		GlobalCooldownSec:   int(config.GlobalCooldown.Seconds()),
+		UserCooldownSec:     int(config.UserCooldown.Seconds()),
+		VoteMode:            config.VoteMode,
+		VoteDurationSec:     int(config.VoteDurationOrDefault().Seconds()),
+		LoyaltyEnabled:      config.Loyalty.Enabled,
+		LoyaltyIntervalSec:  int(config.Loyalty.IntervalOrDefault().Seconds()),
+		LoyaltyWatchPoints:  config.Loyalty.WatchPoints,
+		LoyaltyChatPoints:   config.Loyalty.ChatPoints,
+		MaxFlashesPerSecond: config.Safety.MaxFlashesOrDefault(),
+		MinHueChangeMs:      int(config.Safety.MinHueChangeInterval.Milliseconds()),
+		BlockViewerStrobe:   config.Safety.BlockViewerStrobe,
+		EpilepsySafe:        config.Safety.EpilepsySafe,
+		VIPBypassCooldown:   config.VIPBypassCooldown,
+		SubBypassCooldown:   config.SubBypassCooldown,
+		ModBypassCooldown:   config.ModBypassCooldown,
 	}
 }
 
@@ -99,6 +148,36 @@ func (dto *TwitchConfigUpdateDTO) ApplyUpdate(config *domain.TwitchConfig) {
 	if dto.UserCooldownSec != nil {
 		config.UserCooldown = time.Duration(*dto.UserCooldownSec) * time.Second
 	}
+	if dto.VoteMode != nil {
+		config.VoteMode = *dto.VoteMode
+	}
+	if dto.VoteDurationSec != nil {
+		config.VoteDuration = time.Duration(*dto.VoteDurationSec) * time.Second
+	}
+	if dto.LoyaltyEnabled != nil {
+		config.Loyalty.Enabled = *dto.LoyaltyEnabled
+	}
+	if dto.LoyaltyIntervalSec != nil {
+		config.Loyalty.Interval = time.Duration(*dto.LoyaltyIntervalSec) * time.Second
+	}
+	if dto.LoyaltyWatchPoints != nil {
+		config.Loyalty.WatchPoints = *dto.LoyaltyWatchPoints
+	}
+	if dto.LoyaltyChatPoints != nil {
+		config.Loyalty.ChatPoints = *dto.LoyaltyChatPoints
+	}
+	if dto.MaxFlashesPerSecond != nil {
+		config.Safety.MaxFlashesPerSecond = *dto.MaxFlashesPerSecond
+	}
+	if dto.MinHueChangeMs != nil {
+		config.Safety.MinHueChangeInterval = time.Duration(*dto.MinHueChangeMs) * time.Millisecond
+	}
+	if dto.BlockViewerStrobe != nil {
+		config.Safety.BlockViewerStrobe = *dto.BlockViewerStrobe
+	}
+	if dto.EpilepsySafe != nil {
+		config.Safety.EpilepsySafe = *dto.EpilepsySafe
+	}
 	if dto.VIPBypassCooldown != nil {
 		config.VIPBypassCooldown = *dto.VIPBypassCooldown
 	}
@@ -113,21 +192,56 @@ func (dto *TwitchConfigUpdateDTO) ApplyUpdate(config *domain.TwitchConfig) {
 }
 
 // FromActiveEffect converts active effect to DTO
-func FromActiveEffect(effect *application.ActiveEffect, duration time.Duration) *ActiveEffectDTO {
+func FromActiveEffect(effect *application.ActiveEffect) *ActiveEffectDTO {
 	if effect == nil {
 		return nil
 	}
 
-	elapsed := time.Since(effect.StartedAt)
-	remaining := duration - elapsed
-	if remaining < 0 {
-		remaining = 0
-	}
-
 	return &ActiveEffectDTO{
 		Username:         effect.Username,
 		Command:          effect.Command,
+		Channel:          effect.Channel,
+		DeviceAddress:    effect.DeviceAddress,
 		StartedAt:        effect.StartedAt.Format(time.RFC3339),
-		RemainingTimeSec: int(remaining.Seconds()),
+		DurationSec:      int(effect.Duration.Seconds()),
+		RemainingTimeSec: int(effect.Remaining().Seconds()),
+	}
+}
+
+// FromTwitchService builds the current status of the Twitch integration
+func FromTwitchService(service *application.TwitchService) TwitchStatusDTO {
+	config := service.GetConfig()
+	connection := service.GetConnectionStatus()
+
+	status := TwitchStatusDTO{
+		Connected:        connection.State == domain.ConnectionJoined,
+		State:            string(connection.State),
+		Error:            connection.Error,
+		ReconnectAttempt: connection.Attempt,
+		Channel:          config.Channel,
+		Channels:         FromChannelStatuses(service.GetChannelStatuses()),
+		Override:         FromDomainOverride(service.GetOverride()),
+		Cooldowns:        FromCooldownStatuses(service.GetCooldowns()),
+	}
+
+	if connection.State == domain.ConnectionReconnecting && !connection.RetryAt.IsZero() {
+		status.RetryAt = connection.RetryAt.Format(time.RFC3339)
 	}
+
+	// Add active effect if any
+	if activeEffect := service.GetActiveEffect(); activeEffect != nil {
+		status.ActiveEffect = FromActiveEffect(activeEffect)
+	}
+
+	status.ActiveEffects = make([]*ActiveEffectDTO, 0)
+	for _, effect := range service.GetActiveEffects() {
+		status.ActiveEffects = append(status.ActiveEffects, FromActiveEffect(effect))
+	}
+
+	// Add running poll if any
+	if vote := service.GetVote(); vote != nil && !vote.Closed {
+		status.ActiveVote = FromDomainVote(vote)
+	}
+
+	return status
 }
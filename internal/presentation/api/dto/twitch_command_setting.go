@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// CommandSettingDTO represents the per-command settings of a chat command
+type CommandSettingDTO struct {
+	Command     string `json:"command"`
+	DurationSec int    `json:"duration_sec" validate:"min=0"` // 0 uses the global effect duration
+	Speed       *uint8 `json:"speed,omitempty"`
+	CooldownSec int    `json:"cooldown_sec" validate:"min=0"`
+	Cost        int    `json:"cost" validate:"min=0"`
+
+	Blend   string  `json:"blend,omitempty"`   // How the effect combines with the lamp state, default replace
+	Opacity float64 `json:"opacity,omitempty"` // 0 to 1, empty is fully opaque
+	FadeMs  int     `json:"fade_ms,omitempty"` // Crossfade duration
+}
+
+// CooldownStatusDTO represents the running cooldowns of a device
+type CooldownStatusDTO struct {
+	DeviceAddress      string         `json:"device_address"`
+	GlobalRemainingSec int            `json:"global_remaining_sec"`
+	Commands           map[string]int `json:"commands"` // command -> remaining seconds
+	Users              map[string]int `json:"users"`    // username -> remaining seconds
+}
+
+// FromDomainCommandSettings converts the command settings of a config to DTOs
+func FromDomainCommandSettings(config *domain.TwitchConfig) []CommandSettingDTO {
+	settings := make([]CommandSettingDTO, len(config.CommandSettings))
+	for i, s := range config.CommandSettings {
+		settings[i] = FromDomainCommandSetting(s)
+	}
+	return settings
+}
+
+// FromDomainCommandSetting converts a domain command setting to DTO
+func FromDomainCommandSetting(s domain.CommandSetting) CommandSettingDTO {
+	return CommandSettingDTO{
+		Command:     s.Command,
+		DurationSec: int(s.Duration.Seconds()),
+		Speed:       s.Speed,
+		CooldownSec: int(s.Cooldown.Seconds()),
+		Cost:        s.Cost,
+		Blend:       string(s.Blend),
+		Opacity:     s.Opacity,
+		FadeMs:      int(s.Fade.Milliseconds()),
+	}
+}
+
+// ToDomain converts a command setting DTO to the domain model
+func (dto *CommandSettingDTO) ToDomain() domain.CommandSetting {
+	return domain.CommandSetting{
+		Command:  strings.ToLower(strings.TrimSpace(dto.Command)),
+		Duration: time.Duration(dto.DurationSec) * time.Second,
+		Speed:    dto.Speed,
+		Cooldown: time.Duration(dto.CooldownSec) * time.Second,
+		Cost:     dto.Cost,
+		Blend:    domain.BlendMode(dto.Blend),
+		Opacity:  dto.Opacity,
+		Fade:     time.Duration(dto.FadeMs) * time.Millisecond,
+	}
+}
+
+// FromCooldownStatuses converts the running cooldowns to DTOs
+func FromCooldownStatuses(statuses []application.CooldownStatus) []CooldownStatusDTO {
+	dtos := make([]CooldownStatusDTO, len(statuses))
+	for i, s := range statuses {
+		dtos[i] = CooldownStatusDTO{
+			DeviceAddress:      s.DeviceAddress,
+			GlobalRemainingSec: ceilSeconds(s.Global),
+			Commands:           make(map[string]int, len(s.Commands)),
+			Users:              make(map[string]int, len(s.Users)),
+		}
+		for command, remaining := range s.Commands {
+			dtos[i].Commands[command] = ceilSeconds(remaining)
+		}
+		for username, remaining := range s.Users {
+			dtos[i].Users[username] = ceilSeconds(remaining)
+		}
+	}
+	return dtos
+}
+
+// ceilSeconds rounds a remaining time up, so a running cooldown never shows as 0
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
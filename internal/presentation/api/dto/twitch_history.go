@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// CommandRecordDTO represents a recorded chat command attempt
+type CommandRecordDTO struct {
+	ID          string `json:"id"`
+	StreamID    string `json:"stream_id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Command     string `json:"command"`
+	Outcome     string `json:"outcome"`
+	Reason      string `json:"reason,omitempty"`
+	Timestamp   string `json:"timestamp"`
+}
+
+// TwitchHistoryDTO represents a page of command history
+type TwitchHistoryDTO struct {
+	Records []CommandRecordDTO `json:"records"`
+	Count   int                `json:"count"`
+}
+
+// defaultHistoryLimit caps history responses when no limit is given
+const defaultHistoryLimit = 100
+
+// FromDomainCommandRecords converts command records to DTO
+func FromDomainCommandRecords(records []*domain.CommandRecord) TwitchHistoryDTO {
+	dtos := make([]CommandRecordDTO, len(records))
+	for i, record := range records {
+		dtos[i] = CommandRecordDTO{
+			ID:          record.ID,
+			StreamID:    record.StreamID,
+			Username:    record.Username,
+			DisplayName: record.DisplayName,
+			Command:     record.Command,
+			Outcome:     string(record.Outcome),
+			Reason:      record.Reason,
+			Timestamp:   record.Timestamp.Format(time.RFC3339),
+		}
+	}
+
+	return TwitchHistoryDTO{
+		Records: dtos,
+		Count:   len(dtos),
+	}
+}
+
+// ParseHistoryFilter builds a history filter from query parameters
+// (user, command, outcome, stream, since, until, limit)
+func ParseHistoryFilter(query url.Values) (domain.CommandHistoryFilter, error) {
+	filter := domain.CommandHistoryFilter{
+		Username: NormalizeUsername(query.Get("user")),
+		Command:  query.Get("command"),
+		Outcome:  domain.CommandOutcome(query.Get("outcome")),
+		StreamID: query.Get("stream"),
+		Limit:    defaultHistoryLimit,
+	}
+
+	if since := query.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return filter, fmt.Errorf("invalid since timestamp (expected RFC3339): %s", since)
+		}
+		filter.Since = t
+	}
+
+	if until := query.Get("until"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return filter, fmt.Errorf("invalid until timestamp (expected RFC3339): %s", until)
+		}
+		filter.Until = t
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("invalid limit: %s", limit)
+		}
+		filter.Limit = n
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,117 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// CommandPermissionDTO represents a per-command permission rule
+type CommandPermissionDTO struct {
+	Command    string   `json:"command"`
+	MinRole    string   `json:"min_role"`
+	MinSubTier int      `json:"min_sub_tier" validate:"min=0,max=3"` // Lowest tier a subscriber needs, 0 for any
+	AllowUsers []string `json:"allow_users"`
+	DenyUsers  []string `json:"deny_users"`
+}
+
+// TwitchPermissionsDTO represents all permission settings
+type TwitchPermissionsDTO struct {
+	Permissions []CommandPermissionDTO `json:"permissions"`
+	BannedUsers []string               `json:"banned_users"`
+	Roles       []string               `json:"roles"` // Available roles, ignored on update
+}
+
+// BanUserRequestDTO represents a request to ban a user
+type BanUserRequestDTO struct {
+	Username string `json:"username" validate:"required,min=1"`
+}
+
+// AvailableRoles lists all roles from least to most privileged
+var AvailableRoles = []string{
+	string(domain.RoleEveryone),
+	string(domain.RoleFollower),
+	string(domain.RoleSubscriber),
+	string(domain.RoleVIP),
+	string(domain.RoleModerator),
+	string(domain.RoleBroadcaster),
+}
+
+// FromDomainPermissions converts the permission settings of a config to DTO
+func FromDomainPermissions(config *domain.TwitchConfig) TwitchPermissionsDTO {
+	permissions := make([]CommandPermissionDTO, len(config.Permissions))
+	for i, p := range config.Permissions {
+		permissions[i] = FromDomainCommandPermission(p)
+	}
+
+	banned := config.BannedUsers
+	if banned == nil {
+		banned = []string{}
+	}
+
+	return TwitchPermissionsDTO{
+		Permissions: permissions,
+		BannedUsers: banned,
+		Roles:       AvailableRoles,
+	}
+}
+
+// FromDomainCommandPermission converts a domain permission rule to DTO
+func FromDomainCommandPermission(p domain.CommandPermission) CommandPermissionDTO {
+	return CommandPermissionDTO{
+		Command:    p.Command,
+		MinRole:    string(p.MinRole),
+		MinSubTier: p.MinSubTier,
+		AllowUsers: normalizeUsers(p.AllowUsers),
+		DenyUsers:  normalizeUsers(p.DenyUsers),
+	}
+}
+
+// ToDomain converts a permission DTO to the domain model
+func (dto *CommandPermissionDTO) ToDomain() domain.CommandPermission {
+	role := domain.Role(strings.ToLower(dto.MinRole))
+	if role == "" {
+		role = domain.RoleEveryone
+	}
+
+	return domain.CommandPermission{
+		Command:    strings.ToLower(strings.TrimSpace(dto.Command)),
+		MinRole:    role,
+		MinSubTier: dto.MinSubTier,
+		AllowUsers: normalizeUsers(dto.AllowUsers),
+		DenyUsers:  normalizeUsers(dto.DenyUsers),
+	}
+}
+
+// ApplyUpdate replaces the permission settings of a config
+func (dto *TwitchPermissionsDTO) ApplyUpdate(config *domain.TwitchConfig) error {
+	permissions := make([]domain.CommandPermission, 0, len(dto.Permissions))
+	for _, p := range dto.Permissions {
+		permission := p.ToDomain()
+		if err := permission.Validate(); err != nil {
+			return err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	config.Permissions = permissions
+	config.BannedUsers = normalizeUsers(dto.BannedUsers)
+	return nil
+}
+
+// normalizeUsers lowercases usernames, strips "@" and drops empty entries
+func normalizeUsers(users []string) []string {
+	normalized := make([]string, 0, len(users))
+	for _, user := range users {
+		user = NormalizeUsername(user)
+		if user != "" {
+			normalized = append(normalized, user)
+		}
+	}
+	return normalized
+}
+
+// NormalizeUsername normalizes a single username the same way user lists are
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// TwitchRepliesDTO represents the bot reply settings
+type TwitchRepliesDTO struct {
+	Language        string            `json:"language"`
+	Templates       map[string]string `json:"templates"` // Custom templates by event
+	SilencedReplies []string          `json:"silenced_replies"`
+	RateLimit       int               `json:"rate_limit" validate:"min=0,max=100"` // Max messages per chat and 30 seconds
+
+	Languages []string                     `json:"languages"` // Available language packs, ignored on update
+	Events    []string                     `json:"events"`    // Available reply events, ignored on update
+	Defaults  map[string]map[string]string `json:"defaults"`  // Built-in templates by language and event, ignored on update
+}
+
+// FromDomainReplies converts the reply settings of a config to DTO
+func FromDomainReplies(config *domain.TwitchConfig) TwitchRepliesDTO {
+	templates := make(map[string]string, len(config.ReplyTemplates))
+	for event, text := range config.ReplyTemplates {
+		templates[string(event)] = text
+	}
+
+	silenced := make([]string, len(config.SilencedReplies))
+	for i, event := range config.SilencedReplies {
+		silenced[i] = string(event)
+	}
+
+	events := make([]string, 0, len(domain.ReplyLanguages[domain.DefaultReplyLanguage]))
+	for _, event := range domain.ReplyEvents() {
+		events = append(events, string(event))
+	}
+
+	defaults := make(map[string]map[string]string, len(domain.ReplyLanguages))
+	for language, pack := range domain.ReplyLanguages {
+		defaults[language] = make(map[string]string, len(pack))
+		for event, text := range pack {
+			defaults[language][string(event)] = text
+		}
+	}
+
+	return TwitchRepliesDTO{
+		Language:        config.ReplyLanguageOrDefault(),
+		Templates:       templates,
+		SilencedReplies: silenced,
+		RateLimit:       config.ReplyRateLimitOrDefault(),
+		Languages:       domain.ReplyLanguageNames(),
+		Events:          events,
+		Defaults:        defaults,
+	}
+}
+
+// ApplyUpdate replaces the reply settings of a config.
+// Empty templates are dropped so the language pack applies again.
+func (dto *TwitchRepliesDTO) ApplyUpdate(config *domain.TwitchConfig) {
+	config.ReplyLanguage = dto.Language
+
+	config.ReplyTemplates = make(map[domain.ReplyEvent]string, len(dto.Templates))
+	for event, text := range dto.Templates {
+		if text != "" {
+			config.ReplyTemplates[domain.ReplyEvent(event)] = text
+		}
+	}
+
+	config.SilencedReplies = make([]domain.ReplyEvent, 0, len(dto.SilencedReplies))
+	for _, event := range dto.SilencedReplies {
+		config.SilencedReplies = append(config.SilencedReplies, domain.ReplyEvent(event))
+	}
+
+	config.ReplyRateLimit = dto.RateLimit
+}
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+)
+
+// VoteDTO represents a chat poll and its live tally
+type VoteDTO struct {
+	ID           string          `json:"id"`
+	Open         bool            `json:"open"`
+	Canceled     bool            `json:"canceled"`
+	StartedAt    string          `json:"started_at"`
+	EndsAt       string          `json:"ends_at"`
+	RemainingSec int             `json:"remaining_sec"`
+	TotalVotes   int             `json:"total_votes"`
+	Tally        []VoteResultDTO `json:"tally"`
+	Winner       string          `json:"winner,omitempty"` // Only set once the poll closed
+}
+
+// VoteResultDTO represents the votes for a single option
+type VoteResultDTO struct {
+	Option string `json:"option"`
+	Count  int    `json:"count"`
+}
+
+// FromDomainVote converts a domain vote session to DTO
+func FromDomainVote(session *domain.VoteSession) *VoteDTO {
+	if session == nil {
+		return nil
+	}
+
+	tally := session.Tally()
+	results := make([]VoteResultDTO, len(tally))
+	for i, result := range tally {
+		results[i] = VoteResultDTO{Option: result.Option, Count: result.Count}
+	}
+
+	vote := &VoteDTO{
+		ID:           session.ID,
+		Open:         !session.Closed,
+		Canceled:     session.Canceled,
+		StartedAt:    session.StartedAt.Format(time.RFC3339),
+		EndsAt:       session.EndsAt.Format(time.RFC3339),
+		RemainingSec: int(session.Remaining().Seconds()),
+		TotalVotes:   len(session.Votes),
+		Tally:        results,
+	}
+
+	if session.Closed && !session.Canceled && len(results) > 0 {
+		vote.Winner = results[0].Option
+	}
+
+	return vote
+}
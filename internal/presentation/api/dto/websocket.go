@@ -12,6 +12,15 @@ const (
 	MessageTypeScanResult   MessageType = "scan_result"
 	MessageTypeTwitchStatus MessageType = "twitch_status"
 	MessageTypeTwitchCommand MessageType = "twitch_command"
+	MessageTypeTwitchVote    MessageType = "twitch_vote"
+	MessageTypeOverride      MessageType = "override_status"
+	MessageTypeOBSStatus     MessageType = "obs_status"
+	MessageTypeAlert         MessageType = "alert"
+	MessageTypeAlertStatus   MessageType = "alert_status"
+	MessageTypeMQTTStatus    MessageType = "mqtt_status"
+	MessageTypeHueStatus     MessageType = "hue_status"
+	MessageTypeDMXStatus     MessageType = "dmx_status"
+	MessageTypeOpenRGBStatus MessageType = "openrgb_status"
 )
 
 // CommandAction represents the action to perform
@@ -23,6 +32,9 @@ const (
 	CommandActionBrightness   CommandAction = "brightness"
 	CommandActionWhiteBalance CommandAction = "white_balance"
 	CommandActionEffect       CommandAction = "effect"
+	CommandActionLock         CommandAction = "lock"
+	CommandActionUnlock       CommandAction = "unlock"
+	CommandActionPanic        CommandAction = "panic"
 )
 
 // CommandMessage represents a command from client to server
@@ -134,3 +146,129 @@ func NewTwitchCommandMessage(username, command string) TwitchCommandMessage {
 		Command:  command,
 	}
 }
+
+// TwitchVoteMessage represents a live poll tally update
+type TwitchVoteMessage struct {
+	Type MessageType `json:"type"`
+	Vote *VoteDTO    `json:"vote"`
+}
+
+// NewTwitchVoteMessage creates a Twitch vote message
+func NewTwitchVoteMessage(vote *VoteDTO) TwitchVoteMessage {
+	return TwitchVoteMessage{
+		Type: MessageTypeTwitchVote,
+		Vote: vote,
+	}
+}
+
+// OverrideStatusMessage represents a streamer override change
+type OverrideStatusMessage struct {
+	Type     MessageType       `json:"type"`
+	Override OverrideStatusDTO `json:"override"`
+}
+
+// NewOverrideStatusMessage creates an override status message
+func NewOverrideStatusMessage(override OverrideStatusDTO) OverrideStatusMessage {
+	return OverrideStatusMessage{
+		Type:     MessageTypeOverride,
+		Override: override,
+	}
+}
+
+// OBSStatusMessage represents an OBS connection or state change
+type OBSStatusMessage struct {
+	Type   MessageType  `json:"type"`
+	Status OBSStatusDTO `json:"status"`
+}
+
+// NewOBSStatusMessage creates an OBS status message
+func NewOBSStatusMessage(status OBSStatusDTO) OBSStatusMessage {
+	return OBSStatusMessage{
+		Type:   MessageTypeOBSStatus,
+		Status: status,
+	}
+}
+
+// AlertMessage represents a received stream alert
+type AlertMessage struct {
+	Type  MessageType `json:"type"`
+	Alert AlertDTO    `json:"alert"`
+}
+
+// NewAlertMessage creates an alert message
+func NewAlertMessage(alert AlertDTO) AlertMessage {
+	return AlertMessage{
+		Type:  MessageTypeAlert,
+		Alert: alert,
+	}
+}
+
+// AlertStatusMessage represents an alert provider connection change
+type AlertStatusMessage struct {
+	Type   MessageType    `json:"type"`
+	Status AlertStatusDTO `json:"status"`
+}
+
+// NewAlertStatusMessage creates an alert status message
+func NewAlertStatusMessage(status AlertStatusDTO) AlertStatusMessage {
+	return AlertStatusMessage{
+		Type:   MessageTypeAlertStatus,
+		Status: status,
+	}
+}
+
+// MQTTStatusMessage represents an MQTT broker connection change
+type MQTTStatusMessage struct {
+	Type   MessageType   `json:"type"`
+	Status MQTTStatusDTO `json:"status"`
+}
+
+// NewMQTTStatusMessage creates an MQTT status message
+func NewMQTTStatusMessage(status MQTTStatusDTO) MQTTStatusMessage {
+	return MQTTStatusMessage{
+		Type:   MessageTypeMQTTStatus,
+		Status: status,
+	}
+}
+
+// HueStatusMessage represents a Hue bridge status change
+type HueStatusMessage struct {
+	Type   MessageType  `json:"type"`
+	Status HueStatusDTO `json:"status"`
+}
+
+// NewHueStatusMessage creates a Hue status message
+func NewHueStatusMessage(status HueStatusDTO) HueStatusMessage {
+	return HueStatusMessage{
+		Type:   MessageTypeHueStatus,
+		Status: status,
+	}
+}
+
+// DMXStatusMessage represents a DMX input change
+type DMXStatusMessage struct {
+	Type   MessageType  `json:"type"`
+	Status DMXStatusDTO `json:"status"`
+}
+
+// NewDMXStatusMessage creates a DMX status message
+func NewDMXStatusMessage(status DMXStatusDTO) DMXStatusMessage {
+	return DMXStatusMessage{
+		Type:   MessageTypeDMXStatus,
+		Status: status,
+	}
+}
+
+// OpenRGBStatusMessage represents an OpenRGB server status change
+type OpenRGBStatusMessage struct {
+	Type   MessageType      `json:"type"`
+	Status OpenRGBStatusDTO `json:"status"`
+}
+
+// NewOpenRGBStatusMessage creates an OpenRGB status message
+func NewOpenRGBStatusMessage(status OpenRGBStatusDTO) OpenRGBStatusMessage {
+	return OpenRGBStatusMessage{
+		Type:   MessageTypeOpenRGBStatus,
+		Status: status,
+	}
+}
@@ -0,0 +1,160 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Topics events are published under, clients filter the stream by them
+const (
+	TopicState      = "state"      // Lamp state and streamer override changes
+	TopicConnection = "connection" // Lamp connections and integration status
+	TopicScan       = "scan"       // Device scans
+	TopicTwitch     = "twitch"     // Chat commands and polls
+	TopicAlert      = "alert"      // Stream alerts
+)
+
+// Topics lists every topic
+var Topics = []string{TopicState, TopicConnection, TopicScan, TopicTwitch, TopicAlert}
+
+// DefaultBufferSize is how many past events are kept for resuming clients
+const DefaultBufferSize = 256
+
+// subscriberBuffer is how many events a subscriber may fall behind before it is dropped
+const subscriberBuffer = 64
+
+// Event is a published event. IDs increase by one per event.
+type Event struct {
+	ID    uint64
+	Topic string
+	Type  string
+	Data  json.RawMessage
+}
+
+// Broker fans events out to subscribers and keeps the latest ones in a ring
+// buffer, so clients reconnecting with the last event ID they saw miss nothing
+type Broker struct {
+	ring        []Event // Oldest event at ring[start], unless not full yet
+	start       int
+	nextID      uint64
+	subscribers map[*Subscription]struct{}
+	closed      bool
+	mu          sync.Mutex
+}
+
+// NewBroker creates a broker keeping the last size events
+func NewBroker(size int) *Broker {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	return &Broker{
+		ring:        make([]Event, 0, size),
+		nextID:      1,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives the events of its topics until closed. Events is
+// closed when the subscriber falls too far behind; it should reconnect with
+// the last event ID it got.
+type Subscription struct {
+	Events <-chan Event
+	events chan Event
+	topics []string // Empty means all topics
+	broker *Broker
+}
+
+// Publish marshals data and sends it to the subscribers of the topic
+func (b *Broker) Publish(topic, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	event := Event{ID: b.nextID, Topic: topic, Type: eventType, Data: payload}
+	b.nextID++
+
+	if len(b.ring) < cap(b.ring) {
+		b.ring = append(b.ring, event)
+	} else {
+		b.ring[b.start] = event
+		b.start = (b.start + 1) % len(b.ring)
+	}
+
+	for sub := range b.subscribers {
+		if !sub.wants(topic) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// Slow subscriber, it catches up from the buffer after reconnecting
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+	return nil
+}
+
+// Subscribe returns the buffered events after lastID and a subscription to
+// the topics, all topics if none are given. Pass 0 as lastID to start with
+// new events only. If lastID fell out of the buffer, all buffered events are
+// returned.
+func (b *Broker) Subscribe(lastID uint64, topics []string) ([]Event, *Subscription) {
+	events := make(chan Event, subscriberBuffer)
+	sub := &Subscription{Events: events, events: events, topics: topics, broker: b}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var backlog []Event
+	if lastID > 0 {
+		for i := range b.ring {
+			event := b.ring[(b.start+i)%len(b.ring)]
+			if event.ID > lastID && sub.wants(event.Topic) {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+
+	if b.closed {
+		close(events)
+		return backlog, sub
+	}
+	b.subscribers[sub] = struct{}{}
+	return backlog, sub
+}
+
+// Close ends all subscriptions, e.g. so the HTTP server can shut down while
+// clients are streaming. Later subscriptions end right away.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	if _, ok := s.broker.subscribers[s]; ok {
+		delete(s.broker.subscribers, s)
+		close(s.events)
+	}
+}
+
+// wants reports whether the subscription covers the topic
+func (s *Subscription) wants(topic string) bool {
+	return len(s.topics) == 0 || slices.Contains(s.topics, topic)
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ids(events []Event) []uint64 {
+	result := make([]uint64, len(events))
+	for i, event := range events {
+		result[i] = event.ID
+	}
+	return result
+}
+
+func TestBrokerResumesFromBuffer(t *testing.T) {
+	b := NewBroker(3)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, b.Publish(TopicState, "device_state", map[string]int{"n": i}))
+	}
+
+	tests := []struct {
+		name   string
+		lastID uint64
+		topics []string
+		want   []uint64
+	}{
+		{name: "new events only", lastID: 0, want: []uint64{}},
+		{name: "resume inside buffer", lastID: 3, want: []uint64{4, 5}},
+		{name: "resume before buffer", lastID: 1, want: []uint64{3, 4, 5}},
+		{name: "up to date", lastID: 5, want: []uint64{}},
+		{name: "other topic", lastID: 1, topics: []string{TopicScan}, want: []uint64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backlog, sub := b.Subscribe(tt.lastID, tt.topics)
+			defer sub.Close()
+			assert.Equal(t, tt.want, ids(backlog))
+		})
+	}
+
+	backlog, sub := b.Subscribe(4, nil)
+	defer sub.Close()
+	assert.JSONEq(t, `{"n":4}`, string(backlog[0].Data))
+}
+
+func TestBrokerFiltersTopics(t *testing.T) {
+	b := NewBroker(8)
+	_, sub := b.Subscribe(0, []string{TopicScan, TopicTwitch})
+	defer sub.Close()
+
+	b.Publish(TopicState, "device_state", nil)
+	b.Publish(TopicScan, "scan_started", nil)
+	b.Publish(TopicTwitch, "twitch_command", nil)
+
+	assert.Equal(t, Event{ID: 2, Topic: TopicScan, Type: "scan_started", Data: []byte("null")}, <-sub.Events)
+	assert.Equal(t, uint64(3), (<-sub.Events).ID)
+}
+
+func TestBrokerDropsSlowSubscribers(t *testing.T) {
+	b := NewBroker(subscriberBuffer * 2)
+	_, slow := b.Subscribe(0, nil)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.Publish(TopicState, "device_state", i)
+	}
+
+	received := 0
+	for range slow.Events {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+
+	// Reconnecting with the last ID catches up on the dropped event
+	backlog, sub := b.Subscribe(subscriberBuffer, nil)
+	defer sub.Close()
+	assert.Equal(t, []uint64{subscriberBuffer + 1}, ids(backlog))
+	slow.Close()
+}
+
+func TestBrokerClose(t *testing.T) {
+	b := NewBroker(8)
+	_, sub := b.Subscribe(0, nil)
+
+	b.Close()
+	_, ok := <-sub.Events
+	assert.False(t, ok)
+
+	_, late := b.Subscribe(0, nil)
+	_, ok = <-late.Events
+	assert.False(t, ok)
+	late.Close()
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/alerts"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxWebhookBody limits the size of webhook payloads
+const maxWebhookBody = 1 << 20
+
+// AlertHandler handles alert webhooks and test alerts
+type AlertHandler struct {
+	alertService *application.AlertService
+	storage      *storage.AlertStorage
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(alertService *application.AlertService, storage *storage.AlertStorage) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+		storage:      storage,
+	}
+}
+
+// Webhook handles POST /api/hooks/{name}
+func (h *AlertHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	hook := h.storage.Get().GetWebhook(name)
+	if hook == nil {
+		writeError(w, http.StatusNotFound, "Webhook not found")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	if err := alerts.VerifySignature(hook.Secret, body, r.Header.Get(hook.SignatureHeaderOrDefault())); err != nil {
+		logging.FromContext(r.Context()).Warn("Rejected alert webhook", "provider", name, "error", err)
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	alert, err := alerts.ParseWebhook(hook, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Unmapped event types are accepted so the sender doesn't retry them
+	if alert != nil {
+		h.alertService.HandleAlert(alert)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// TestAlert handles POST /api/alerts/test
+func (h *AlertHandler) TestAlert(w http.ResponseWriter, r *http.Request) {
+	var testDTO dto.AlertTestDTO
+	if err := json.NewDecoder(r.Body).Decode(&testDTO); err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	alert := testDTO.ToDomain()
+	if !alert.Type.IsValid() {
+		writeFieldError(w, "type", "Unknown alert type")
+		return
+	}
+
+	h.alertService.HandleAlert(alert)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainAlert(alert))
+}
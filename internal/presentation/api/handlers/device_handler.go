@@ -3,10 +3,10 @@ package handlers
 import (
 	"context"
 	"encoding/json"
-	"log"
 	"net/http"
 	"time"
 
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api/state"
 )
@@ -60,21 +60,19 @@ func (h *DeviceHandler) ScanDevices(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Perform scan
+	h.state.BroadcastScanStarted(timeout)
 	ctx := context.Background()
 	devices, err := h.state.GetDeviceService().Scan(ctx, timeout)
 	if err != nil {
-		log.Printf("Scan failed: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Scan failed",
-		})
+		logging.FromContext(r.Context()).Error("Scan failed", "error", err)
+		writeError(w, http.StatusInternalServerError, "Scan failed")
 		return
 	}
 
 	deviceDTOs := dto.FromDomainList(devices)
 
-	// Broadcast scan results to WebSocket clients
-	h.state.GetWebSocketHub().BroadcastMessage(dto.NewScanResultMessage(deviceDTOs))
+	// Broadcast scan results to WebSocket and event stream clients
+	h.state.BroadcastScanResult(deviceDTOs)
 
 	json.NewEncoder(w).Encode(deviceDTOs)
 }
@@ -85,42 +83,21 @@ func (h *DeviceHandler) SelectDevice(w http.ResponseWriter, r *http.Request) {
 
 	var req dto.SelectDeviceRequestDTO
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   "Invalid request body",
-		})
-		return
-	}
-
-	if req.Address == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   "Device address is required",
-		})
+		writeInvalidBody(w)
 		return
 	}
 
 	// Select the device
 	if err := h.state.SelectDevice(req.Address); err != nil {
-		log.Printf("Failed to select device: %v", err)
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   "Device not found",
-		})
+		logging.FromContext(r.Context()).Error("Failed to select device", "error", err)
+		writeError(w, http.StatusNotFound, "Device not found")
 		return
 	}
 
 	// Get the selected device
 	device, err := h.state.GetSelectedDevice()
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   "Failed to get device",
-		})
+		writeError(w, http.StatusInternalServerError, "Failed to get device")
 		return
 	}
 
@@ -129,9 +106,9 @@ func (h *DeviceHandler) SelectDevice(w http.ResponseWriter, r *http.Request) {
 	// Broadcast state update to WebSocket clients
 	h.state.BroadcastState()
 
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-		"device":  deviceDTO,
+	json.NewEncoder(w).Encode(dto.SelectDeviceResponseDTO{
+		Success: true,
+		Device:  deviceDTO,
 	})
 }
 
@@ -141,10 +118,7 @@ func (h *DeviceHandler) GetCurrentDevice(w http.ResponseWriter, r *http.Request)
 
 	device, err := h.state.GetSelectedDevice()
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "No device selected",
-		})
+		writeError(w, http.StatusNotFound, "No device selected")
 		return
 	}
 
@@ -2,9 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
 	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
 	"github.com/go-chi/chi/v5"
@@ -35,28 +35,20 @@ func (h *EffectHandler) ListEffects(w http.ResponseWriter, r *http.Request) {
 func (h *EffectHandler) CreateEffect(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateEffectRequestDTO
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeInvalidBody(w)
 		return
 	}
 
-	// Validate request
-	if req.Name == "" {
-		http.Error(w, "Effect name is required", http.StatusBadRequest)
-		return
-	}
-
-	if len(req.Colors) == 0 {
-		http.Error(w, "At least one color is required", http.StatusBadRequest)
+	effect := req.ToDomain()
+	if err := effect.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Create effect
-	effect := req.ToDomain()
-
 	// Save to storage
 	if err := h.storage.Save(effect); err != nil {
-		log.Printf("Failed to save effect: %v", err)
-		http.Error(w, "Failed to save effect", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("Failed to save effect", "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to save effect")
 		return
 	}
 
@@ -72,13 +64,13 @@ func (h *EffectHandler) CreateEffect(w http.ResponseWriter, r *http.Request) {
 func (h *EffectHandler) DeleteEffect(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "Effect ID is required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Effect ID is required")
 		return
 	}
 
 	if err := h.storage.Delete(id); err != nil {
-		log.Printf("Failed to delete effect: %v", err)
-		http.Error(w, "Effect not found", http.StatusNotFound)
+		logging.FromContext(r.Context()).Error("Failed to delete effect", "error", err)
+		writeError(w, http.StatusNotFound, "Effect not found")
 		return
 	}
 
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+)
+
+// writeError sends a JSON error body, with the code derived from the status
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeErrorDTO(w, status, dto.ErrorDTO{Code: dto.ErrorCodeForStatus(status), Message: message})
+}
+
+// writeFieldError rejects a request because of one of its fields
+func writeFieldError(w http.ResponseWriter, field, message string) {
+	writeErrorDTO(w, http.StatusBadRequest, dto.ErrorDTO{Code: dto.ErrorCodeValidation, Message: message, Field: field})
+}
+
+// writeInvalidBody rejects a body that doesn't decode into the request DTO
+func writeInvalidBody(w http.ResponseWriter) {
+	writeErrorDTO(w, http.StatusBadRequest, dto.ErrorDTO{Code: dto.ErrorCodeInvalidBody, Message: "Invalid request body"})
+}
+
+func writeErrorDTO(w http.ResponseWriter, status int, body dto.ErrorDTO) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
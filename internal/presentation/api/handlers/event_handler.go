@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/events"
+)
+
+const (
+	// eventHeartbeatInterval keeps proxies from closing quiet streams
+	eventHeartbeatInterval = 15 * time.Second
+
+	// eventRetry is how long browsers wait before reconnecting, in milliseconds
+	eventRetry = 3000
+)
+
+// EventHandler streams lamp and system events as Server-Sent Events
+type EventHandler struct {
+	broker *events.Broker
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(broker *events.Broker) *EventHandler {
+	return &EventHandler{
+		broker: broker,
+	}
+}
+
+// Stream handles GET /api/events. Clients pick topics with ?topic=state,scan
+// (or repeated topic parameters) and resume after the Last-Event-ID header.
+func (h *EventHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	topics, err := parseTopics(r.URL.Query()["topic"])
+	if err != nil {
+		writeFieldError(w, "topic", err.Error())
+		return
+	}
+
+	// A malformed ID starts the stream with new events, like a first connect
+	lastID, _ := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+
+	// Streams outlive the write timeout of the server
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		writeError(w, http.StatusInternalServerError, "Failed to start event stream")
+		return
+	}
+
+	backlog, sub := h.broker.Subscribe(lastID, topics)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", eventRetry)
+	for _, event := range backlog {
+		writeEvent(w, event)
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Fell behind or shutting down, the client resumes from the buffer
+				return
+			}
+			writeEvent(w, event)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes an event in the SSE wire format. The data is compact
+// JSON, so it always fits on a single data line.
+func writeEvent(w http.ResponseWriter, event events.Event) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
+
+// parseTopics splits comma separated topic parameters, rejecting unknown topics
+func parseTopics(params []string) ([]string, error) {
+	var topics []string
+	for _, param := range params {
+		for _, topic := range strings.Split(param, ",") {
+			topic = strings.TrimSpace(topic)
+			if topic == "" {
+				continue
+			}
+			if !slices.Contains(events.Topics, topic) {
+				return nil, fmt.Errorf("unknown topic %q (use %s)", topic, strings.Join(events.Topics, ", "))
+			}
+			topics = append(topics, topic)
+		}
+	}
+	return topics, nil
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+)
+
+// HueHandler handles the pairing endpoints of the Hue bridge
+type HueHandler struct {
+	hueService *application.HueService
+}
+
+// NewHueHandler creates a new Hue handler
+func NewHueHandler(hueService *application.HueService) *HueHandler {
+	return &HueHandler{
+		hueService: hueService,
+	}
+}
+
+// PressLinkButton handles POST /api/hue/link
+func (h *HueHandler) PressLinkButton(w http.ResponseWriter, r *http.Request) {
+	status := h.hueService.PressLinkButton()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromHueStatus(status))
+}
+
+// DeleteUser handles DELETE /api/hue/users/{username}
+func (h *HueHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, "username")
+
+	if err := h.hueService.DeleteUser(username); err != nil {
+		if errors.Is(err, domain.ErrHueUserNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("Failed to remove Hue app", "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to remove app")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+)
+
+// integrationService is a service started with its stored config
+type integrationService interface {
+	Start(ctx context.Context) error
+	Stop() error
+}
+
+// IntegrationHandler handles the config and status endpoints of an integration.
+// Saving the config restarts the service with it.
+type IntegrationHandler[T storage.Config] struct {
+	name    string // Used in errors, e.g. "OBS"
+	service integrationService
+	storage *storage.ConfigStorage[T]
+	clone   func(config T) T                     // Copy an update works on, so an invalid update leaves the running config untouched
+	update  func(body io.Reader, config T) error // Decodes an update DTO onto the config
+	enabled func(config T) bool                  // Whether the service runs with the config, nil if Start reloads it
+	config  func(config T) any                   // Config DTO
+	status  func() any                           // Status DTO
+}
+
+// NewOBSHandler creates the handler of the OBS config and status endpoints
+func NewOBSHandler(obsService *application.OBSService, storage *storage.OBSStorage) *IntegrationHandler[*domain.OBSConfig] {
+	return &IntegrationHandler[*domain.OBSConfig]{
+		name:    "OBS",
+		service: obsService,
+		storage: storage,
+		clone:   func(config *domain.OBSConfig) *domain.OBSConfig { copied := *config; return &copied },
+		update:  decodeUpdate[dto.OBSConfigUpdateDTO, *domain.OBSConfig],
+		enabled: func(config *domain.OBSConfig) bool { return config.Enabled },
+		config:  func(config *domain.OBSConfig) any { return dto.FromDomainOBSConfig(config) },
+		status:  func() any { return dto.FromOBSStatus(obsService.GetStatus()) },
+	}
+}
+
+// NewAlertConfigHandler creates the handler of the alert config and status endpoints
+func NewAlertConfigHandler(alertService *application.AlertService, storage *storage.AlertStorage) *IntegrationHandler[*domain.AlertConfig] {
+	return &IntegrationHandler[*domain.AlertConfig]{
+		name:    "alert providers",
+		service: alertService,
+		storage: storage,
+		clone:   func(config *domain.AlertConfig) *domain.AlertConfig { copied := *config; return &copied },
+		update:  decodeUpdate[dto.AlertConfigUpdateDTO, *domain.AlertConfig],
+		config:  func(config *domain.AlertConfig) any { return dto.FromDomainAlertConfig(config) },
+		status:  func() any { return dto.FromAlertStatus(alertService.GetStatus()) },
+	}
+}
+
+// NewMQTTHandler creates the handler of the MQTT config and status endpoints
+func NewMQTTHandler(mqttService *application.MQTTService, storage *storage.MQTTStorage) *IntegrationHandler[*domain.MQTTConfig] {
+	return &IntegrationHandler[*domain.MQTTConfig]{
+		name:    "MQTT bridge",
+		service: mqttService,
+		storage: storage,
+		clone:   func(config *domain.MQTTConfig) *domain.MQTTConfig { copied := *config; return &copied },
+		update:  decodeUpdate[dto.MQTTConfigUpdateDTO, *domain.MQTTConfig],
+		enabled: func(config *domain.MQTTConfig) bool { return config.Enabled },
+		config:  func(config *domain.MQTTConfig) any { return dto.FromDomainMQTTConfig(config) },
+		status:  func() any { return dto.FromMQTTStatus(mqttService.GetStatus()) },
+	}
+}
+
+// NewHueConfigHandler creates the handler of the Hue config and status endpoints
+func NewHueConfigHandler(hueService *application.HueService, storage *storage.HueStorage) *IntegrationHandler[*domain.HueConfig] {
+	return &IntegrationHandler[*domain.HueConfig]{
+		name:    "Hue bridge",
+		service: hueService,
+		storage: storage,
+		clone:   (*domain.HueConfig).Clone,
+		update:  decodeUpdate[dto.HueConfigUpdateDTO, *domain.HueConfig],
+		enabled: func(config *domain.HueConfig) bool { return config.Enabled },
+		config:  func(config *domain.HueConfig) any { return dto.FromDomainHueConfig(config) },
+		status:  func() any { return dto.FromHueStatus(hueService.GetStatus()) },
+	}
+}
+
+// NewDMXHandler creates the handler of the DMX config and status endpoints
+func NewDMXHandler(dmxService *application.DMXService, storage *storage.DMXStorage) *IntegrationHandler[*domain.DMXConfig] {
+	return &IntegrationHandler[*domain.DMXConfig]{
+		name:    "DMX input",
+		service: dmxService,
+		storage: storage,
+		clone:   func(config *domain.DMXConfig) *domain.DMXConfig { copied := *config; return &copied },
+		update:  decodeUpdate[dto.DMXConfigUpdateDTO, *domain.DMXConfig],
+		enabled: func(config *domain.DMXConfig) bool { return config.Enabled },
+		config:  func(config *domain.DMXConfig) any { return dto.FromDomainDMXConfig(config) },
+		status:  func() any { return dto.FromDMXStatus(dmxService.GetStatus()) },
+	}
+}
+
+// NewOpenRGBHandler creates the handler of the OpenRGB config and status endpoints
+func NewOpenRGBHandler(openRGBService *application.OpenRGBService, storage *storage.OpenRGBStorage) *IntegrationHandler[*domain.OpenRGBConfig] {
+	return &IntegrationHandler[*domain.OpenRGBConfig]{
+		name:    "OpenRGB server",
+		service: openRGBService,
+		storage: storage,
+		clone:   func(config *domain.OpenRGBConfig) *domain.OpenRGBConfig { copied := *config; return &copied },
+		update:  decodeUpdate[dto.OpenRGBConfigUpdateDTO, *domain.OpenRGBConfig],
+		enabled: func(config *domain.OpenRGBConfig) bool { return config.Enabled },
+		config:  func(config *domain.OpenRGBConfig) any { return dto.FromDomainOpenRGBConfig(config) },
+		status:  func() any { return dto.FromOpenRGBStatus(openRGBService.GetStatus()) },
+	}
+}
+
+// decodeUpdate decodes an update DTO and applies it to a config
+func decodeUpdate[U any, T any, PU interface {
+	*U
+	ApplyUpdate(config T)
+}](body io.Reader, config T) error {
+	var update U
+	if err := json.NewDecoder(body).Decode(&update); err != nil {
+		return err
+	}
+	PU(&update).ApplyUpdate(config)
+	return nil
+}
+
+// GetConfig handles GET /api/{integration}/config
+func (h *IntegrationHandler[T]) GetConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.config(h.storage.Get()))
+}
+
+// UpdateConfig handles PUT /api/{integration}/config
+func (h *IntegrationHandler[T]) UpdateConfig(w http.ResponseWriter, r *http.Request) {
+	config := h.clone(h.storage.Get())
+	if err := h.update(r.Body, config); err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	if err := h.storage.Save(config); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to save config", "integration", h.name, "error", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.restart(r.Context(), config); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to start", "integration", h.name, "error", err)
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to start %s: %v", h.name, err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.config(config))
+}
+
+// GetStatus handles GET /api/{integration}/status
+func (h *IntegrationHandler[T]) GetStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.status())
+}
+
+// restart applies a saved config to the running service
+func (h *IntegrationHandler[T]) restart(ctx context.Context, config T) error {
+	if h.enabled == nil {
+		return h.service.Start(ctx)
+	}
+
+	h.service.Stop()
+	if !h.enabled(config) {
+		return nil
+	}
+	return h.service.Start(ctx)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/state"
+)
+
+// LeaseHandler handles the lamp arbitration endpoints
+type LeaseHandler struct {
+	state *state.ServerState
+}
+
+// NewLeaseHandler creates a new lease handler
+func NewLeaseHandler(state *state.ServerState) *LeaseHandler {
+	return &LeaseHandler{
+		state: state,
+	}
+}
+
+// GetLeases handles GET /api/leases
+func (h *LeaseHandler) GetLeases(w http.ResponseWriter, r *http.Request) {
+	arbiter := h.state.GetTwitchService().Arbiter()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromLeaseStacks(arbiter.Stacks()))
+}
+
+// SetOverlay handles PUT /api/leases/overlay
+func (h *LeaseHandler) SetOverlay(w http.ResponseWriter, r *http.Request) {
+	var req dto.OverlayRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	overlay := req.ToDomain()
+	if err := overlay.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deviceAddr, ok := h.deviceFor(w, req.DeviceAddress)
+	if !ok {
+		return
+	}
+
+	twitchService := h.state.GetTwitchService()
+	if err := twitchService.SetOverlay(r.Context(), deviceAddr, overlay); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to set overlay", "device", deviceAddr, "error", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.respondLeases(w)
+}
+
+// ClearOverlay handles DELETE /api/leases/overlay
+func (h *LeaseHandler) ClearOverlay(w http.ResponseWriter, r *http.Request) {
+	var req dto.OverlayClearDTO
+	// The body is optional
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeInvalidBody(w)
+		return
+	}
+
+	deviceAddr, ok := h.deviceFor(w, req.DeviceAddress)
+	if !ok {
+		return
+	}
+
+	twitchService := h.state.GetTwitchService()
+	if err := twitchService.ClearOverlay(r.Context(), deviceAddr); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to clear overlay", "device", deviceAddr, "error", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.respondLeases(w)
+}
+
+// deviceFor returns the requested device, falling back to the selected device
+func (h *LeaseHandler) deviceFor(w http.ResponseWriter, deviceAddr string) (string, bool) {
+	if deviceAddr != "" {
+		return deviceAddr, true
+	}
+
+	deviceAddr, err := h.state.GetSelectedDeviceAddress()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "No device selected")
+		return "", false
+	}
+	return deviceAddr, true
+}
+
+// respondLeases broadcasts the new lamp state and responds with the lease stacks
+func (h *LeaseHandler) respondLeases(w http.ResponseWriter) {
+	h.state.BroadcastState()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromLeaseStacks(h.state.GetTwitchService().Arbiter().Stacks()))
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+	"github.com/go-chi/chi/v5"
+)
+
+// LoyaltyHandler handles viewer loyalty points endpoints
+type LoyaltyHandler struct {
+	loyaltyService *application.LoyaltyService
+}
+
+// NewLoyaltyHandler creates a new loyalty handler
+func NewLoyaltyHandler(loyaltyService *application.LoyaltyService) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		loyaltyService: loyaltyService,
+	}
+}
+
+// GetLeaderboard handles GET /api/twitch/points
+func (h *LoyaltyHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	limit, err := dto.ParseLeaderboardLimit(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	accounts := h.loyaltyService.GetLeaderboard(limit)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainPointsAccounts(accounts))
+}
+
+// GetAccount handles GET /api/twitch/points/{viewer}
+func (h *LoyaltyHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
+	account, exists := h.loyaltyService.GetAccount(viewerParam(r))
+	if !exists {
+		writeError(w, http.StatusNotFound, "Viewer has no points account")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainPointsAccount(account))
+}
+
+// AdjustPoints handles POST /api/twitch/points/{viewer}
+func (h *LoyaltyHandler) AdjustPoints(w http.ResponseWriter, r *http.Request) {
+	var req dto.PointsAdjustDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	viewer := viewerParam(r)
+	if viewer == "" {
+		writeError(w, http.StatusBadRequest, "Viewer is required")
+		return
+	}
+
+	account, err := h.loyaltyService.Adjust(viewer, req.Delta)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Failed to adjust points", "viewer", viewer, "error", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainPointsAccount(account))
+}
+
+// viewerParam returns the normalized viewer key from the URL, e.g. "alice" or "youtube:bob"
+func viewerParam(r *http.Request) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(chi.URLParam(r, "viewer")), "@"))
+}
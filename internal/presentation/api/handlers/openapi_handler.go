@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/openapi"
+)
+
+// OpenAPIHandler serves the OpenAPI document of the REST API
+type OpenAPIHandler struct {
+	doc *openapi.Document
+}
+
+// NewOpenAPIHandler creates a new OpenAPI handler
+func NewOpenAPIHandler(doc *openapi.Document) *OpenAPIHandler {
+	return &OpenAPIHandler{
+		doc: doc,
+	}
+}
+
+// GetDocument handles GET /api/openapi.json
+func (h *OpenAPIHandler) GetDocument(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.doc)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/state"
+)
+
+// OverrideHandler handles streamer override endpoints (lock and panic)
+type OverrideHandler struct {
+	state *state.ServerState
+}
+
+// NewOverrideHandler creates a new override handler
+func NewOverrideHandler(state *state.ServerState) *OverrideHandler {
+	return &OverrideHandler{
+		state: state,
+	}
+}
+
+// GetOverride handles GET /api/override
+func (h *OverrideHandler) GetOverride(w http.ResponseWriter, r *http.Request) {
+	override := h.state.GetTwitchService().GetOverride()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainOverride(override))
+}
+
+// Lock handles POST /api/override/lock
+func (h *OverrideHandler) Lock(w http.ResponseWriter, r *http.Request) {
+	twitchService := h.state.GetTwitchService()
+	twitchService.Lock(requestedBy(r))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainOverride(twitchService.GetOverride()))
+}
+
+// Unlock handles DELETE /api/override/lock
+func (h *OverrideHandler) Unlock(w http.ResponseWriter, r *http.Request) {
+	twitchService := h.state.GetTwitchService()
+	twitchService.Unlock(requestedBy(r))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainOverride(twitchService.GetOverride()))
+}
+
+// Panic handles POST /api/override/panic
+func (h *OverrideHandler) Panic(w http.ResponseWriter, r *http.Request) {
+	twitchService := h.state.GetTwitchService()
+
+	if err := twitchService.Panic(r.Context(), requestedBy(r)); err != nil {
+		logging.FromContext(r.Context()).Error("Panic failed", "error", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.state.BroadcastState()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainOverride(twitchService.GetOverride()))
+}
+
+// requestedBy reads the optional "by" field of an override request
+func requestedBy(r *http.Request) string {
+	var req dto.OverrideRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.By == "" {
+		return "streamer"
+	}
+	return req.By
+}
@@ -1,16 +1,19 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/codeneuss/lampcontrol/internal/application"
 	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
 	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+	"github.com/go-chi/chi/v5"
 	"github.com/joho/godotenv"
 )
 
@@ -41,20 +44,18 @@ func (h *TwitchHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
 func (h *TwitchHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 	var updateDTO dto.TwitchConfigUpdateDTO
 	if err := json.NewDecoder(r.Body).Decode(&updateDTO); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeInvalidBody(w)
 		return
 	}
 
-	// Get current config
-	config := h.storage.Get()
-
-	// Apply updates
+	// Apply updates to a copy, the stored config stays untouched if validation fails
+	config := h.storage.Get().Clone()
 	updateDTO.ApplyUpdate(config)
 
 	// Validate and save
 	if err := h.storage.Save(config); err != nil {
-		log.Printf("Failed to save Twitch config: %v", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		logging.FromContext(r.Context()).Error("Failed to save Twitch config", "error", err)
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -62,8 +63,8 @@ func (h *TwitchHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 	if config.Enabled {
 		h.twitchService.Stop()
 		if err := h.twitchService.Start(r.Context()); err != nil {
-			log.Printf("Failed to start Twitch service: %v", err)
-			http.Error(w, "Failed to connect to Twitch", http.StatusInternalServerError)
+			logging.FromContext(r.Context()).Error("Failed to start Twitch service", "error", err)
+			writeError(w, http.StatusInternalServerError, "Failed to connect to Twitch")
 			return
 		}
 	} else {
@@ -78,17 +79,7 @@ func (h *TwitchHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 
 // GetStatus returns Twitch connection status
 func (h *TwitchHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
-	config := h.storage.Get()
-
-	status := dto.TwitchStatusDTO{
-		Connected: h.twitchService.IsConnected(),
-		Channel:   config.Channel,
-	}
-
-	// Add active effect if any
-	if activeEffect := h.twitchService.GetActiveEffect(); activeEffect != nil {
-		status.ActiveEffect = dto.FromActiveEffect(activeEffect, config.EffectDuration)
-	}
+	status := dto.FromTwitchService(h.twitchService)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(status)
@@ -106,9 +97,15 @@ func (h *TwitchHandler) GetAvailableCommands(w http.ResponseWriter, r *http.Requ
 		effects = append(effects, effect)
 	}
 
+	power := make([]string, 0, len(domain.PowerMap))
+	for command := range domain.PowerMap {
+		power = append(power, command)
+	}
+
 	commandList := dto.TwitchCommandListDTO{
 		Colors:  colors,
 		Effects: effects,
+		Power:   power,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -117,9 +114,9 @@ func (h *TwitchHandler) GetAvailableCommands(w http.ResponseWriter, r *http.Requ
 
 // GetOAuthURL returns the Twitch OAuth URL for token generation
 func (h *TwitchHandler) GetOAuthURL(w http.ResponseWriter, r *http.Request) {
-	// .env laden
+	// The client ID may come from a .env file
 	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file")
+		logging.FromContext(r.Context()).Debug("No .env file loaded", "error", err)
 	}
 
 	clientID := os.Getenv("TWITCH_CLIENT_ID")
@@ -128,7 +125,7 @@ func (h *TwitchHandler) GetOAuthURL(w http.ResponseWriter, r *http.Request) {
 	}
 	// Twitch OAuth URL for chat scope
 	// Note: You'll need to register a Twitch app and replace YOUR_CLIENT_ID
-	oauthURL := fmt.Sprintf("https://id.twitch.tv/oauth2/authorize?client_id=%s&redirect_uri=http://localhost:8080&response_type=token&scope=chat:read+chat:edit", clientID)
+	oauthURL := fmt.Sprintf("https://id.twitch.tv/oauth2/authorize?client_id=%s&redirect_uri=http://localhost:8080&response_type=token&scope=chat:read+chat:edit+moderator:read:followers", clientID)
 
 	response := map[string]string{
 		"oauth_url":    oauthURL,
@@ -138,3 +135,375 @@ func (h *TwitchHandler) GetOAuthURL(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// GetPermissions handles GET /api/twitch/permissions
+func (h *TwitchHandler) GetPermissions(w http.ResponseWriter, r *http.Request) {
+	config := h.storage.Get()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainPermissions(config))
+}
+
+// UpdatePermissions handles PUT /api/twitch/permissions
+func (h *TwitchHandler) UpdatePermissions(w http.ResponseWriter, r *http.Request) {
+	var req dto.TwitchPermissionsDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	config := h.storage.Get().Clone()
+	if err := req.ApplyUpdate(config); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.savePermissions(w, r, config)
+}
+
+// SetCommandPermission handles PUT /api/twitch/permissions/{command}
+func (h *TwitchHandler) SetCommandPermission(w http.ResponseWriter, r *http.Request) {
+	var req dto.CommandPermissionDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	req.Command = chi.URLParam(r, "command")
+	permission := req.ToDomain()
+	if err := permission.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	config := h.storage.Get().Clone()
+	config.SetPermission(permission)
+
+	h.savePermissions(w, r, config)
+}
+
+// DeleteCommandPermission handles DELETE /api/twitch/permissions/{command}
+func (h *TwitchHandler) DeleteCommandPermission(w http.ResponseWriter, r *http.Request) {
+	config := h.storage.Get().Clone()
+	if !config.RemovePermission(chi.URLParam(r, "command")) {
+		writeError(w, http.StatusNotFound, "Permission rule not found")
+		return
+	}
+
+	h.savePermissions(w, r, config)
+}
+
+// BanUser handles POST /api/twitch/bans
+func (h *TwitchHandler) BanUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.BanUserRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	username := dto.NormalizeUsername(req.Username)
+	if username == "" {
+		writeFieldError(w, "username", "Username is required")
+		return
+	}
+
+	config := h.storage.Get().Clone()
+	if !config.IsBanned(username) {
+		config.BannedUsers = append(config.BannedUsers, username)
+	}
+
+	h.savePermissions(w, r, config)
+}
+
+// UnbanUser handles DELETE /api/twitch/bans/{username}
+func (h *TwitchHandler) UnbanUser(w http.ResponseWriter, r *http.Request) {
+	username := dto.NormalizeUsername(chi.URLParam(r, "username"))
+
+	config := h.storage.Get().Clone()
+	banned := make([]string, 0, len(config.BannedUsers))
+	for _, user := range config.BannedUsers {
+		if user != username {
+			banned = append(banned, user)
+		}
+	}
+
+	if len(banned) == len(config.BannedUsers) {
+		writeError(w, http.StatusNotFound, "User is not banned")
+		return
+	}
+	config.BannedUsers = banned
+
+	h.savePermissions(w, r, config)
+}
+
+// savePermissions persists the config and responds with the permission settings
+func (h *TwitchHandler) savePermissions(w http.ResponseWriter, r *http.Request, config *domain.TwitchConfig) {
+	config.UpdatedAt = time.Now()
+
+	if err := h.storage.Save(config); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to save Twitch permissions", "error", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainPermissions(config))
+}
+
+// GetCommandSettings handles GET /api/twitch/command-settings
+func (h *TwitchHandler) GetCommandSettings(w http.ResponseWriter, r *http.Request) {
+	config := h.storage.Get()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainCommandSettings(config))
+}
+
+// SetCommandSetting handles PUT /api/twitch/command-settings/{command}
+func (h *TwitchHandler) SetCommandSetting(w http.ResponseWriter, r *http.Request) {
+	var req dto.CommandSettingDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	req.Command = chi.URLParam(r, "command")
+	setting := req.ToDomain()
+	if err := setting.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	config := h.storage.Get().Clone()
+	config.SetCommandSetting(setting)
+
+	h.saveCommandSettings(w, r, config)
+}
+
+// DeleteCommandSetting handles DELETE /api/twitch/command-settings/{command}
+func (h *TwitchHandler) DeleteCommandSetting(w http.ResponseWriter, r *http.Request) {
+	config := h.storage.Get().Clone()
+	if !config.RemoveCommandSetting(chi.URLParam(r, "command")) {
+		writeError(w, http.StatusNotFound, "Command setting not found")
+		return
+	}
+
+	h.saveCommandSettings(w, r, config)
+}
+
+// saveCommandSettings persists the config and responds with the command settings
+func (h *TwitchHandler) saveCommandSettings(w http.ResponseWriter, r *http.Request, config *domain.TwitchConfig) {
+	config.UpdatedAt = time.Now()
+
+	if err := h.storage.Save(config); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to save Twitch command settings", "error", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainCommandSettings(config))
+}
+
+// GetReplies handles GET /api/twitch/replies
+func (h *TwitchHandler) GetReplies(w http.ResponseWriter, r *http.Request) {
+	config := h.storage.Get()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainReplies(config))
+}
+
+// UpdateReplies handles PUT /api/twitch/replies
+func (h *TwitchHandler) UpdateReplies(w http.ResponseWriter, r *http.Request) {
+	var req dto.TwitchRepliesDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	config := h.storage.Get().Clone()
+	req.ApplyUpdate(config)
+	config.UpdatedAt = time.Now()
+
+	if err := h.storage.Save(config); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to save Twitch replies", "error", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainReplies(config))
+}
+
+// GetVote handles GET /api/twitch/vote
+func (h *TwitchHandler) GetVote(w http.ResponseWriter, r *http.Request) {
+	vote := h.twitchService.GetVote()
+	if vote == nil {
+		writeError(w, http.StatusNotFound, "No poll has been held yet")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainVote(vote))
+}
+
+// StartVote handles POST /api/twitch/vote
+func (h *TwitchHandler) StartVote(w http.ResponseWriter, r *http.Request) {
+	if !h.twitchService.StartVote() {
+		writeError(w, http.StatusConflict, "A poll is already running")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.FromDomainVote(h.twitchService.GetVote()))
+}
+
+// CancelVote handles DELETE /api/twitch/vote
+func (h *TwitchHandler) CancelVote(w http.ResponseWriter, r *http.Request) {
+	if !h.twitchService.CancelVote() {
+		writeError(w, http.StatusNotFound, "No poll is running")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetHistory handles GET /api/twitch/history
+func (h *TwitchHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	filter, err := dto.ParseHistoryFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	records := h.twitchService.GetHistory(filter)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainCommandRecords(records))
+}
+
+// GetStats handles GET /api/twitch/stats
+func (h *TwitchHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	filter, err := dto.ParseHistoryFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Stats cover the whole filtered history, limit only caps the rankings
+	top := 10
+	if r.URL.Query().Get("limit") != "" {
+		top = filter.Limit
+	}
+	filter.Limit = 0
+
+	if user := r.URL.Query().Get("user"); user != "" {
+		stats := domain.ComputeViewerStats(h.twitchService.GetHistory(filter), dto.NormalizeUsername(user))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stats)
+		return
+	}
+
+	stats := h.twitchService.GetStats(filter, top)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GetChannels handles GET /api/twitch/channels
+func (h *TwitchHandler) GetChannels(w http.ResponseWriter, r *http.Request) {
+	config := h.storage.Get()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainChatChannels(config))
+}
+
+// AddChannel handles POST /api/twitch/channels
+func (h *TwitchHandler) AddChannel(w http.ResponseWriter, r *http.Request) {
+	var req dto.ChatChannelDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	channel := req.ToDomain()
+	if err := channel.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	config := h.storage.Get().Clone()
+	if config.FindChannel(channel.ID) != nil {
+		writeError(w, http.StatusConflict, "Chat channel already exists")
+		return
+	}
+	config.Channels = append(config.Channels, channel)
+
+	if !h.saveChannels(w, r, config) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.FromDomainChatChannel(channel))
+}
+
+// UpdateChannel handles PUT /api/twitch/channels/{id}
+func (h *TwitchHandler) UpdateChannel(w http.ResponseWriter, r *http.Request) {
+	var req dto.ChatChannelUpdateDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	config := h.storage.Get().Clone()
+	channel := config.FindChannel(chi.URLParam(r, "id"))
+	if channel == nil {
+		writeError(w, http.StatusNotFound, "Chat channel not found")
+		return
+	}
+
+	req.ApplyUpdate(channel)
+	if !h.saveChannels(w, r, config) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.FromDomainChatChannel(*channel))
+}
+
+// DeleteChannel handles DELETE /api/twitch/channels/{id}
+func (h *TwitchHandler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
+	config := h.storage.Get().Clone()
+	if !config.RemoveChannel(chi.URLParam(r, "id")) {
+		writeError(w, http.StatusNotFound, "Chat channel not found")
+		return
+	}
+
+	if !h.saveChannels(w, r, config) {
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// saveChannels persists the chat channels and restarts a running integration to pick them up.
+// It writes the error response and returns false on failure.
+func (h *TwitchHandler) saveChannels(w http.ResponseWriter, r *http.Request, config *domain.TwitchConfig) bool {
+	config.UpdatedAt = time.Now()
+
+	if err := h.storage.Save(config); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to save chat channels", "error", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	if config.Enabled {
+		h.twitchService.Stop()
+		if err := h.twitchService.Start(context.Background()); err != nil {
+			logging.FromContext(r.Context()).Error("Failed to restart Twitch service", "error", err)
+		}
+	}
+
+	return true
+}
@@ -1,9 +1,9 @@
 package handlers
 
 import (
-	"log"
 	"net/http"
 
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api/state"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api/websocket"
 	gorillaws "github.com/gorilla/websocket"
@@ -35,7 +35,7 @@ func NewWebSocketHandler(state *state.ServerState) *WebSocketHandler {
 func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("Failed to upgrade connection: %v", err)
+		logging.FromContext(r.Context()).Warn("Failed to upgrade connection", "error", err)
 		return
 	}
 
@@ -49,5 +49,5 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 	go client.WritePump()
 	go client.ReadPump()
 
-	log.Printf("WebSocket client connected from %s", r.RemoteAddr)
+	logging.FromContext(r.Context()).Info("WebSocket client connected", "remote", r.RemoteAddr)
 }
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/wled"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/state"
+)
+
+// wledName is the device name WLED apps show
+const wledName = "LampControl"
+
+// WLEDHandler emulates the WLED JSON API
+type WLEDHandler struct {
+	state         *state.ServerState
+	effectStorage *storage.EffectStorage
+	started       time.Time
+	mac           string
+}
+
+// NewWLEDHandler creates a new WLED handler
+func NewWLEDHandler(state *state.ServerState, effectStorage *storage.EffectStorage) *WLEDHandler {
+	return &WLEDHandler{
+		state:         state,
+		effectStorage: effectStorage,
+		started:       time.Now(),
+		mac:           wledMAC(),
+	}
+}
+
+// wledMAC derives a stable MAC address from the host name, WLED apps use it to tell devices apart
+func wledMAC() string {
+	hostname, _ := os.Hostname()
+	h := fnv.New64a()
+	h.Write([]byte("lampcontrol:" + hostname))
+	return fmt.Sprintf("%012x", h.Sum64()&0xffffffffffff)
+}
+
+// GetAll handles GET /json
+func (h *WLEDHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	effects := h.effects()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"state":    wled.NewState(h.state.GetDeviceService().ListDevices(), effects),
+		"info":     h.info(r, effects),
+		"effects":  wled.Names(effects),
+		"palettes": []string{"Default"},
+	})
+}
+
+// GetState handles GET /json/state
+func (h *WLEDHandler) GetState(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wled.NewState(h.state.GetDeviceService().ListDevices(), h.effects()))
+}
+
+// UpdateState handles POST /json/state
+func (h *WLEDHandler) UpdateState(w http.ResponseWriter, r *http.Request) {
+	var update wled.StateUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeInvalidBody(w)
+		return
+	}
+
+	effects := h.effects()
+	changes, err := update.Changes(h.state.GetDeviceService().ListDevices(), effects)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	addresses := make([]string, 0, len(changes))
+	for addr := range changes {
+		addresses = append(addresses, addr)
+	}
+	sort.Strings(addresses)
+
+	for _, addr := range addresses {
+		if err := h.state.ApplyChange(r.Context(), addr, changes[addr]); err != nil {
+			logging.FromContext(r.Context()).Error("WLED command failed", "device", addr, "error", err)
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Command failed: %v", err))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if update.V {
+		json.NewEncoder(w).Encode(wled.NewState(h.state.GetDeviceService().ListDevices(), effects))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// GetInfo handles GET /json/info
+func (h *WLEDHandler) GetInfo(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.info(r, h.effects()))
+}
+
+// GetEffects handles GET /json/effects
+func (h *WLEDHandler) GetEffects(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wled.Names(h.effects()))
+}
+
+// effects returns the built-in and custom effects
+func (h *WLEDHandler) effects() []wled.Effect {
+	return wled.Effects(h.effectStorage.GetAll())
+}
+
+// info builds the info object, reporting the address the client reached us at
+func (h *WLEDHandler) info(r *http.Request, effects []wled.Effect) wled.Info {
+	ip := r.Host
+	if host, _, err := net.SplitHostPort(r.Host); err == nil {
+		ip = host
+	}
+	return wled.NewInfo(wledName, h.mac, ip, h.state.GetDeviceService().ListDevices(), effects, time.Since(h.started))
+}
@@ -3,10 +3,11 @@ package middleware
 import (
 	"bufio"
 	"fmt"
-	"log"
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
 )
 
 // responseWriter wraps http.ResponseWriter to capture status code
@@ -34,6 +35,11 @@ func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return h.Hijack()
 }
 
+// Unwrap lets http.ResponseController reach the flusher and deadlines of the connection
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Logging middleware logs HTTP requests
 func Logging(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -46,13 +52,11 @@ func Logging(next http.Handler) http.Handler {
 		next.ServeHTTP(wrapped, r)
 
 		// Log request details
-		duration := time.Since(start)
-		log.Printf(
-			"%s %s %d %s",
-			r.Method,
-			r.RequestURI,
-			wrapped.statusCode,
-			duration,
+		logging.FromContext(r.Context()).Info("HTTP request",
+			"method", r.Method,
+			"path", r.RequestURI,
+			"status", wrapped.statusCode,
+			"duration", time.Since(start),
 		)
 	})
 }
@@ -1,9 +1,11 @@
 package middleware
 
 import (
-	"log"
 	"net/http"
 	"runtime/debug"
+
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
 )
 
 // Recovery middleware recovers from panics and logs the error
@@ -11,8 +13,8 @@ func Recovery(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				log.Printf("Panic recovered: %v\n%s", err, debug.Stack())
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				logging.FromContext(r.Context()).Error("Panic recovered", "error", err, "stack", string(debug.Stack()))
+				writeError(w, http.StatusInternalServerError, dto.ErrorDTO{Code: dto.ErrorCodeInternal, Message: "Internal Server Error"})
 			}
 		}()
 
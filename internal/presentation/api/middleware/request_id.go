@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+)
+
+// RequestIDHeader carries the ID of a request, set by a proxy or by RequestID
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID middleware tags each request with an ID, taken from the request
+// header or generated, and puts a logger carrying it in the request context
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > 64 {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		logger := slog.Default().With(logging.KeyRequestID, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithLogger(r.Context(), logger)))
+	})
+}
+
+// newRequestID returns 8 random bytes as hex
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
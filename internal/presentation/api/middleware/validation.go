@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/openapi"
+)
+
+// maxRequestBody caps JSON bodies read for validation
+const maxRequestBody = 1 << 20
+
+// Validation checks JSON request bodies against the OpenAPI document, so
+// handlers only see requests that match their DTO's schema
+func Validation(doc *openapi.Document) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			op := doc.Find(r.Method, r.URL.Path)
+			if op == nil || op.RequestBody == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBody))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, dto.ErrorDTO{Code: dto.ErrorCodeInvalidBody, Message: "Invalid request body"})
+				return
+			}
+			// Handlers decode the body again
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if err := doc.ValidateBody(op, body); err != nil {
+				var validationErr *openapi.ValidationError
+				switch {
+				case errors.As(err, &validationErr):
+					writeError(w, http.StatusBadRequest, dto.ErrorDTO{Code: dto.ErrorCodeValidation, Message: validationErr.Message, Field: validationErr.Field})
+				default:
+					writeError(w, http.StatusBadRequest, dto.ErrorDTO{Code: dto.ErrorCodeInvalidBody, Message: err.Error()})
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeError sends a JSON error body
+func writeError(w http.ResponseWriter, status int, body dto.ErrorDTO) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/openapi"
+)
+
+// apiVersion is the version reported in the OpenAPI document
+const apiVersion = "1.0.0"
+
+// historyQuery lists the filters of the command history endpoints
+var historyQuery = []string{"user", "command", "outcome", "stream", "since", "until", "limit"}
+
+// routes documents every route under /api. setupRouter registers the
+// handlers, TestRoutesMatchRouter keeps the two in sync
+var routes = []openapi.Route{
+	// Device routes
+	{Method: http.MethodGet, Path: "/api/health", Tag: "devices", Summary: "Health check", Response: dto.HealthResponseDTO{}},
+	{Method: http.MethodGet, Path: "/api/devices", Tag: "devices", Summary: "List known devices", Response: []dto.DeviceDTO{}},
+	{Method: http.MethodPost, Path: "/api/scan", Tag: "devices", Summary: "Scan for devices", Request: dto.ScanRequestDTO{}, OptionalBody: true, Response: []dto.DeviceDTO{}},
+	{Method: http.MethodPost, Path: "/api/device/select", Tag: "devices", Summary: "Select the device to control", Request: dto.SelectDeviceRequestDTO{}, Response: dto.SelectDeviceResponseDTO{}},
+	{Method: http.MethodGet, Path: "/api/device/current", Tag: "devices", Summary: "Get the selected device", Response: dto.DeviceDTO{}},
+
+	// Server-sent events
+	{Method: http.MethodGet, Path: "/api/events", Tag: "events", Summary: "Stream lamp and system events as text/event-stream", Query: []string{"topic"}},
+
+	// Custom effects routes
+	{Method: http.MethodGet, Path: "/api/effects", Tag: "effects", Summary: "List custom effects", Response: []dto.CustomEffectDTO{}},
+	{Method: http.MethodPost, Path: "/api/effects", Tag: "effects", Summary: "Create a custom effect", Request: dto.CreateEffectRequestDTO{}, Response: dto.CustomEffectDTO{}, Status: http.StatusCreated},
+	{Method: http.MethodDelete, Path: "/api/effects/{id}", Tag: "effects", Summary: "Delete a custom effect", Status: http.StatusNoContent},
+
+	// Twitch routes
+	{Method: http.MethodGet, Path: "/api/twitch/config", Tag: "twitch", Summary: "Get the Twitch configuration", Response: dto.TwitchConfigDTO{}},
+	{Method: http.MethodPut, Path: "/api/twitch/config", Tag: "twitch", Summary: "Update the Twitch configuration", Request: dto.TwitchConfigUpdateDTO{}, Response: dto.TwitchConfigDTO{}},
+	{Method: http.MethodGet, Path: "/api/twitch/status", Tag: "twitch", Summary: "Get the chat status", Response: dto.TwitchStatusDTO{}},
+	{Method: http.MethodGet, Path: "/api/twitch/commands", Tag: "twitch", Summary: "List chat commands", Response: dto.TwitchCommandListDTO{}},
+	{Method: http.MethodGet, Path: "/api/twitch/oauth", Tag: "twitch", Summary: "Get the Twitch OAuth URL", Response: map[string]string{}},
+	{Method: http.MethodGet, Path: "/api/twitch/permissions", Tag: "twitch", Summary: "Get command permissions and bans", Response: dto.TwitchPermissionsDTO{}},
+	{Method: http.MethodPut, Path: "/api/twitch/permissions", Tag: "twitch", Summary: "Replace command permissions and bans", Request: dto.TwitchPermissionsDTO{}, Response: dto.TwitchPermissionsDTO{}},
+	{Method: http.MethodPut, Path: "/api/twitch/permissions/{command}", Tag: "twitch", Summary: "Set the permission of a command", Request: dto.CommandPermissionDTO{}, Response: dto.TwitchPermissionsDTO{}},
+	{Method: http.MethodDelete, Path: "/api/twitch/permissions/{command}", Tag: "twitch", Summary: "Remove the permission of a command", Response: dto.TwitchPermissionsDTO{}},
+	{Method: http.MethodPost, Path: "/api/twitch/bans", Tag: "twitch", Summary: "Ban a viewer", Request: dto.BanUserRequestDTO{}, Response: dto.TwitchPermissionsDTO{}},
+	{Method: http.MethodDelete, Path: "/api/twitch/bans/{username}", Tag: "twitch", Summary: "Unban a viewer", Response: dto.TwitchPermissionsDTO{}},
+	{Method: http.MethodGet, Path: "/api/twitch/command-settings", Tag: "twitch", Summary: "List command settings", Response: []dto.CommandSettingDTO{}},
+	{Method: http.MethodPut, Path: "/api/twitch/command-settings/{command}", Tag: "twitch", Summary: "Set the duration, speed, cooldown and cost of a command", Request: dto.CommandSettingDTO{}, Response: []dto.CommandSettingDTO{}},
+	{Method: http.MethodDelete, Path: "/api/twitch/command-settings/{command}", Tag: "twitch", Summary: "Reset a command setting", Response: []dto.CommandSettingDTO{}},
+	{Method: http.MethodGet, Path: "/api/twitch/points", Tag: "twitch", Summary: "Get the points leaderboard", Query: []string{"limit"}, Response: []dto.PointsAccountDTO{}},
+	{Method: http.MethodGet, Path: "/api/twitch/points/{viewer}", Tag: "twitch", Summary: "Get a viewer's points", Response: dto.PointsAccountDTO{}},
+	{Method: http.MethodPost, Path: "/api/twitch/points/{viewer}", Tag: "twitch", Summary: "Add or remove a viewer's points", Request: dto.PointsAdjustDTO{}, Response: dto.PointsAccountDTO{}},
+	{Method: http.MethodGet, Path: "/api/twitch/replies", Tag: "twitch", Summary: "Get the chat reply settings", Response: dto.TwitchRepliesDTO{}},
+	{Method: http.MethodPut, Path: "/api/twitch/replies", Tag: "twitch", Summary: "Update the chat reply settings", Request: dto.TwitchRepliesDTO{}, Response: dto.TwitchRepliesDTO{}},
+	{Method: http.MethodGet, Path: "/api/twitch/vote", Tag: "twitch", Summary: "Get the running or last poll", Response: dto.VoteDTO{}},
+	{Method: http.MethodPost, Path: "/api/twitch/vote", Tag: "twitch", Summary: "Start a poll", Response: dto.VoteDTO{}, Status: http.StatusCreated},
+	{Method: http.MethodDelete, Path: "/api/twitch/vote", Tag: "twitch", Summary: "Cancel the running poll", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/api/twitch/history", Tag: "twitch", Summary: "Get the command history", Query: historyQuery, Response: dto.TwitchHistoryDTO{}},
+	{Method: http.MethodGet, Path: "/api/twitch/stats", Tag: "twitch", Summary: "Get command statistics, or a viewer's with ?user", Query: historyQuery, Response: domain.CommandStats{}},
+	{Method: http.MethodGet, Path: "/api/twitch/channels", Tag: "twitch", Summary: "List chat channels", Response: []dto.ChatChannelDTO{}},
+	{Method: http.MethodPost, Path: "/api/twitch/channels", Tag: "twitch", Summary: "Add a chat channel", Request: dto.ChatChannelDTO{}, Response: dto.ChatChannelDTO{}, Status: http.StatusCreated},
+	{Method: http.MethodPut, Path: "/api/twitch/channels/{id}", Tag: "twitch", Summary: "Update a chat channel", Request: dto.ChatChannelUpdateDTO{}, Response: dto.ChatChannelDTO{}},
+	{Method: http.MethodDelete, Path: "/api/twitch/channels/{id}", Tag: "twitch", Summary: "Remove a chat channel", Status: http.StatusNoContent},
+
+	// OBS routes
+	{Method: http.MethodGet, Path: "/api/obs/config", Tag: "obs", Summary: "Get the OBS configuration", Response: dto.OBSConfigDTO{}},
+	{Method: http.MethodPut, Path: "/api/obs/config", Tag: "obs", Summary: "Update the OBS configuration", Request: dto.OBSConfigUpdateDTO{}, Response: dto.OBSConfigDTO{}},
+	{Method: http.MethodGet, Path: "/api/obs/status", Tag: "obs", Summary: "Get the OBS connection status", Response: dto.OBSStatusDTO{}},
+
+	// Alert routes, the webhook body is provider specific and signed, so it has no schema
+	{Method: http.MethodPost, Path: "/api/hooks/{name}", Tag: "alerts", Summary: "Receive a donation or subscription webhook", Status: http.StatusAccepted},
+	{Method: http.MethodGet, Path: "/api/alerts/config", Tag: "alerts", Summary: "Get the alert configuration", Response: dto.AlertConfigDTO{}},
+	{Method: http.MethodPut, Path: "/api/alerts/config", Tag: "alerts", Summary: "Update the alert configuration", Request: dto.AlertConfigUpdateDTO{}, Response: dto.AlertConfigDTO{}},
+	{Method: http.MethodGet, Path: "/api/alerts/status", Tag: "alerts", Summary: "Get the alert provider status", Response: dto.AlertStatusDTO{}},
+	{Method: http.MethodPost, Path: "/api/alerts/test", Tag: "alerts", Summary: "Play a test alert", Request: dto.AlertTestDTO{}, Response: dto.AlertDTO{}},
+
+	// MQTT routes
+	{Method: http.MethodGet, Path: "/api/mqtt/config", Tag: "mqtt", Summary: "Get the MQTT configuration", Response: dto.MQTTConfigDTO{}},
+	{Method: http.MethodPut, Path: "/api/mqtt/config", Tag: "mqtt", Summary: "Update the MQTT configuration", Request: dto.MQTTConfigUpdateDTO{}, Response: dto.MQTTConfigDTO{}},
+	{Method: http.MethodGet, Path: "/api/mqtt/status", Tag: "mqtt", Summary: "Get the MQTT connection status", Response: dto.MQTTStatusDTO{}},
+
+	// Hue bridge emulation routes
+	{Method: http.MethodGet, Path: "/api/hue/config", Tag: "hue", Summary: "Get the Hue bridge configuration", Response: dto.HueConfigDTO{}},
+	{Method: http.MethodPut, Path: "/api/hue/config", Tag: "hue", Summary: "Update the Hue bridge configuration", Request: dto.HueConfigUpdateDTO{}, Response: dto.HueConfigDTO{}},
+	{Method: http.MethodGet, Path: "/api/hue/status", Tag: "hue", Summary: "Get the Hue bridge status", Response: dto.HueStatusDTO{}},
+	{Method: http.MethodPost, Path: "/api/hue/link", Tag: "hue", Summary: "Press the virtual link button", Response: dto.HueStatusDTO{}},
+	{Method: http.MethodDelete, Path: "/api/hue/users/{username}", Tag: "hue", Summary: "Remove a paired Hue app", Status: http.StatusNoContent},
+
+	// DMX routes
+	{Method: http.MethodGet, Path: "/api/dmx/config", Tag: "dmx", Summary: "Get the Art-Net and sACN configuration", Response: dto.DMXConfigDTO{}},
+	{Method: http.MethodPut, Path: "/api/dmx/config", Tag: "dmx", Summary: "Update the Art-Net and sACN configuration", Request: dto.DMXConfigUpdateDTO{}, Response: dto.DMXConfigDTO{}},
+	{Method: http.MethodGet, Path: "/api/dmx/status", Tag: "dmx", Summary: "Get the DMX input status", Response: dto.DMXStatusDTO{}},
+
+	// OpenRGB routes
+	{Method: http.MethodGet, Path: "/api/openrgb/config", Tag: "openrgb", Summary: "Get the OpenRGB server configuration", Response: dto.OpenRGBConfigDTO{}},
+	{Method: http.MethodPut, Path: "/api/openrgb/config", Tag: "openrgb", Summary: "Update the OpenRGB server configuration", Request: dto.OpenRGBConfigUpdateDTO{}, Response: dto.OpenRGBConfigDTO{}},
+	{Method: http.MethodGet, Path: "/api/openrgb/status", Tag: "openrgb", Summary: "Get the OpenRGB server status", Response: dto.OpenRGBStatusDTO{}},
+
+	// Streamer override routes
+	{Method: http.MethodGet, Path: "/api/override", Tag: "override", Summary: "Get the override state", Response: dto.OverrideStatusDTO{}},
+	{Method: http.MethodPost, Path: "/api/override/lock", Tag: "override", Summary: "Lock the lamps against chat commands", Request: dto.OverrideRequestDTO{}, OptionalBody: true, Response: dto.OverrideStatusDTO{}},
+	{Method: http.MethodDelete, Path: "/api/override/lock", Tag: "override", Summary: "Unlock the lamps", Request: dto.OverrideRequestDTO{}, OptionalBody: true, Response: dto.OverrideStatusDTO{}},
+	{Method: http.MethodPost, Path: "/api/override/panic", Tag: "override", Summary: "Stop all effects and lock the lamps", Request: dto.OverrideRequestDTO{}, OptionalBody: true, Response: dto.OverrideStatusDTO{}},
+
+	// Lease routes
+	{Method: http.MethodGet, Path: "/api/leases", Tag: "leases", Summary: "List the lamp leases by priority", Response: []dto.LeaseStackDTO{}},
+	{Method: http.MethodPut, Path: "/api/leases/overlay", Tag: "leases", Summary: "Lay an overlay over the base scene of a lamp", Request: dto.OverlayRequestDTO{}, Response: []dto.LeaseStackDTO{}},
+	{Method: http.MethodDelete, Path: "/api/leases/overlay", Tag: "leases", Summary: "Remove the overlay of a lamp", Request: dto.OverlayClearDTO{}, OptionalBody: true, Response: []dto.LeaseStackDTO{}},
+
+	// This document
+	{Method: http.MethodGet, Path: "/api/openapi.json", Tag: "meta", Summary: "Get this OpenAPI document"},
+}
+
+// newDocument builds the OpenAPI document of the REST API
+func newDocument() *openapi.Document {
+	return openapi.New(openapi.Info{Title: "LampControl API", Version: apiVersion}, dto.ErrorDTO{}, routes)
+}
@@ -0,0 +1,218 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI version of generated documents
+const Version = "3.0.3"
+
+const contentTypeJSON = "application/json"
+
+// Route describes one REST endpoint. Request and response schemas are
+// generated from the Go types, so the document follows the DTOs
+type Route struct {
+	Method       string
+	Path         string // chi pattern, e.g. /api/effects/{id}
+	Summary      string
+	Tag          string
+	Query        []string // Documented query parameters
+	Request      any      // Zero value of the JSON body, nil if the route takes none
+	OptionalBody bool     // The handler falls back to defaults without a body
+	Response     any      // Zero value of the JSON response, nil if it has none
+	Status       int      // Success status, http.StatusOK if zero
+}
+
+// Document is an OpenAPI 3 document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+
+	routes []matcher
+}
+
+// Info holds the API title and version
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem maps lower-case HTTP methods to operations
+type PathItem map[string]*Operation
+
+// Operation is a single method on a path
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes the JSON body of an operation
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes one response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the named schemas references point to
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// matcher finds the operation of a request path
+type matcher struct {
+	method    string
+	segments  []string
+	operation *Operation
+}
+
+// New builds the document for routes, with errorBody as the schema of every
+// error response
+func New(info Info, errorBody any, routes []Route) *Document {
+	g := newGenerator()
+	errorSchema := g.schemaFor(reflect.TypeOf(errorBody))
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+	}
+
+	for _, route := range routes {
+		op := &Operation{
+			OperationID: operationID(route),
+			Summary:     route.Summary,
+			Responses:   make(map[string]Response),
+		}
+		if route.Tag != "" {
+			op.Tags = []string{route.Tag}
+		}
+
+		segments := strings.Split(strings.Trim(route.Path, "/"), "/")
+		for _, segment := range segments {
+			if name, ok := pathParam(segment); ok {
+				op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+			}
+		}
+		for _, name := range route.Query {
+			op.Parameters = append(op.Parameters, Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}})
+		}
+
+		if route.Request != nil {
+			op.RequestBody = &RequestBody{
+				Required: !route.OptionalBody,
+				Content:  jsonContent(g.schemaFor(reflect.TypeOf(route.Request))),
+			}
+		}
+
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		response := Response{Description: http.StatusText(status)}
+		if route.Response != nil {
+			response.Content = jsonContent(g.schemaFor(reflect.TypeOf(route.Response)))
+		}
+		op.Responses[strconv.Itoa(status)] = response
+		op.Responses["default"] = Response{Description: "Error", Content: jsonContent(errorSchema)}
+
+		method := strings.ToLower(route.Method)
+		if doc.Paths[route.Path] == nil {
+			doc.Paths[route.Path] = make(PathItem)
+		}
+		doc.Paths[route.Path][method] = op
+		doc.routes = append(doc.routes, matcher{method: method, segments: segments, operation: op})
+	}
+
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+// Find returns the operation matching a request, preferring literal path
+// segments over parameters like chi does, or nil if none matches
+func (d *Document) Find(method, path string) *Operation {
+	method = strings.ToLower(method)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var best *Operation
+	bestLiterals := -1
+	for _, route := range d.routes {
+		if route.method != method || len(route.segments) != len(segments) {
+			continue
+		}
+
+		literals := 0
+		matched := true
+		for i, segment := range route.segments {
+			if _, ok := pathParam(segment); ok {
+				continue
+			}
+			if segment != segments[i] {
+				matched = false
+				break
+			}
+			literals++
+		}
+		if matched && literals > bestLiterals {
+			best, bestLiterals = route.operation, literals
+		}
+	}
+	return best
+}
+
+// resolve follows a component reference
+func (d *Document) resolve(schema *Schema) *Schema {
+	for schema.Ref != "" {
+		schema = d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+// operationID derives an ID like postApiEffects from the method and path
+func operationID(route Route) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(route.Method))
+	for _, part := range strings.FieldsFunc(route.Path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '{' || r == '}' || r == '.'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func pathParam(segment string) (string, bool) {
+	if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		return segment[1 : len(segment)-1], true
+	}
+	return "", false
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{contentTypeJSON: {Schema: schema}}
+}
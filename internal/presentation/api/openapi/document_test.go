@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testColor struct {
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+}
+
+type testPattern string
+
+func (testPattern) EnumValues() []string {
+	return []string{"fade", "strobe"}
+}
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testScene struct {
+	testBase
+	Name      string      `json:"name" validate:"required,min=1"`
+	Colors    []testColor `json:"colors" validate:"min=1"`
+	Accent    *testColor  `json:"accent,omitempty"`
+	Primary   *testColor  `json:"primary" validate:"required"`
+	Mode      string      `json:"mode,omitempty" validate:"enum=fade|jump"`
+	Pattern   testPattern `json:"pattern,omitempty"`
+	Speed     *int        `json:"speed,omitempty" validate:"min=0,max=100"`
+	Secret    string      `json:"-"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+func newTestDocument() *Document {
+	return New(Info{Title: "Test", Version: "1"}, struct {
+		Message string `json:"message"`
+	}{}, []Route{
+		{Method: http.MethodPost, Path: "/api/scenes", Request: testScene{}, Response: testScene{}, Status: http.StatusCreated},
+		{Method: http.MethodPut, Path: "/api/scenes/{id}", Request: testScene{}, OptionalBody: true},
+		{Method: http.MethodPut, Path: "/api/scenes/default", Request: testColor{}},
+	})
+}
+
+func TestSchemaFromStruct(t *testing.T) {
+	doc := newTestDocument()
+
+	scene := doc.Components.Schemas["testScene"]
+	require.NotNil(t, scene)
+	assert.ElementsMatch(t, []string{"name", "primary"}, scene.Required)
+
+	// Embedded fields are flattened, skipped fields left out
+	assert.Contains(t, scene.Properties, "id")
+	assert.NotContains(t, scene.Properties, "Secret")
+	assert.Equal(t, "date-time", scene.Properties["created_at"].Format)
+
+	// Optional pointers may be null, required ones may not
+	assert.True(t, scene.Properties["accent"].Nullable)
+	assert.Equal(t, "#/components/schemas/testColor", scene.Properties["accent"].AllOf[0].Ref)
+	assert.Equal(t, "#/components/schemas/testColor", scene.Properties["primary"].Ref)
+
+	assert.Equal(t, 1, *scene.Properties["colors"].MinItems)
+	assert.Equal(t, []string{"fade", "jump"}, scene.Properties["mode"].Enum)
+	assert.Equal(t, []string{"fade", "strobe"}, scene.Properties["pattern"].Enum)
+	assert.Equal(t, 100.0, *scene.Properties["speed"].Maximum)
+
+	// uint8 is bounded by its range
+	color := doc.Components.Schemas["testColor"]
+	assert.Equal(t, 255.0, *color.Properties["r"].Maximum)
+}
+
+func TestFindPrefersLiteralSegments(t *testing.T) {
+	doc := newTestDocument()
+
+	assert.Same(t, doc.Paths["/api/scenes/default"]["put"], doc.Find(http.MethodPut, "/api/scenes/default"))
+	assert.Same(t, doc.Paths["/api/scenes/{id}"]["put"], doc.Find(http.MethodPut, "/api/scenes/42"))
+	assert.Nil(t, doc.Find(http.MethodGet, "/api/scenes/42"))
+	assert.Nil(t, doc.Find(http.MethodPut, "/api/scenes/42/colors"))
+}
+
+func TestValidateBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		body      string
+		wantErr   bool
+		wantField string
+		wantJSON  bool
+	}{
+		{name: "valid", method: http.MethodPost, path: "/api/scenes", body: `{"name":"Sunset","primary":{"r":1,"g":2,"b":3},"speed":null}`},
+		{name: "unknown fields are ignored", method: http.MethodPost, path: "/api/scenes", body: `{"name":"Sunset","primary":{},"extra":true}`},
+		{name: "null optional", method: http.MethodPost, path: "/api/scenes", body: `{"name":"Sunset","primary":{},"accent":null}`},
+		{name: "missing required", method: http.MethodPost, path: "/api/scenes", body: `{"name":"Sunset"}`, wantErr: true, wantField: "primary"},
+		{name: "null required", method: http.MethodPost, path: "/api/scenes", body: `{"name":"Sunset","primary":null}`, wantErr: true, wantField: "primary"},
+		{name: "wrong type", method: http.MethodPost, path: "/api/scenes", body: `{"name":5,"primary":{}}`, wantErr: true, wantField: "name"},
+		{name: "fraction for integer", method: http.MethodPost, path: "/api/scenes", body: `{"name":"Sunset","primary":{"r":1.5}}`, wantErr: true, wantField: "primary.r"},
+		{name: "empty array", method: http.MethodPost, path: "/api/scenes", body: `{"name":"Sunset","primary":{},"colors":[]}`, wantErr: true, wantField: "colors"},
+		{name: "item out of range", method: http.MethodPost, path: "/api/scenes", body: `{"name":"Sunset","primary":{},"colors":[{"b":-1}]}`, wantErr: true, wantField: "colors[0].b"},
+		{name: "bad enum", method: http.MethodPost, path: "/api/scenes", body: `{"name":"Sunset","primary":{},"mode":"spin"}`, wantErr: true, wantField: "mode"},
+		{name: "missing required body", method: http.MethodPost, path: "/api/scenes", body: " ", wantErr: true},
+		{name: "missing optional body", method: http.MethodPut, path: "/api/scenes/42"},
+		{name: "not JSON", method: http.MethodPost, path: "/api/scenes", body: `{"name"`, wantJSON: true},
+		{name: "trailing data", method: http.MethodPost, path: "/api/scenes", body: `{} {}`, wantJSON: true},
+	}
+
+	doc := newTestDocument()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := doc.ValidateBody(doc.Find(tt.method, tt.path), []byte(tt.body))
+
+			switch {
+			case tt.wantJSON:
+				assert.ErrorIs(t, err, ErrInvalidJSON)
+			case tt.wantErr:
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDocumentMarshals(t *testing.T) {
+	data, err := json.Marshal(newTestDocument())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"operationId":"putApiScenesId"`)
+	assert.Contains(t, string(data), `"201":{"description":"Created"`)
+}
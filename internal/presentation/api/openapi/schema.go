@@ -0,0 +1,287 @@
+package openapi
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Schema is the subset of an OpenAPI 3.0 schema object the REST API uses
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Enumer is implemented by string types limited to a fixed set of values,
+// so the schema lists them without an enum rule repeating them
+type Enumer interface {
+	EnumValues() []string
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	enumerType        = reflect.TypeFor[Enumer]()
+)
+
+// generator builds schemas from Go types, collecting named structs as components
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schemaFor returns the schema of t, a reference for named structs
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		return nullable(g.schemaFor(t.Elem()))
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Int64:
+		return intSchema(true, t.Bits())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64:
+		return intSchema(false, t.Bits())
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		if t.Implements(enumerType) {
+			return &Schema{Type: "string", Enum: reflect.Zero(t).Interface().(Enumer).EnumValues()}
+		}
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: true}
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem()), Nullable: true}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem()), MinItems: ptr(t.Len()), MaxItems: ptr(t.Len())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.objectSchema(t)
+		}
+		return g.ref(t)
+	default:
+		// Interfaces and anything else accept any JSON value
+		return &Schema{}
+	}
+}
+
+// ref registers t as a component on first use and returns a reference to it
+func (g *generator) ref(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = g.componentName(t)
+		g.names[t] = name
+		// Reserve the name first, so recursive types end in a reference
+		g.schemas[name] = &Schema{}
+		*g.schemas[name] = *g.objectSchema(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName drops the DTO suffix, prefixing the package on a clash
+func (g *generator) componentName(t reflect.Type) string {
+	name := strings.TrimSuffix(t.Name(), "DTO")
+	if _, taken := g.schemas[name]; !taken {
+		return name
+	}
+
+	pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+	return string(unicode.ToUpper(rune(pkg[0]))) + pkg[1:] + name
+}
+
+// objectSchema maps struct fields to properties the way encoding/json does
+func (g *generator) objectSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// Untagged embedded structs are flattened into the parent
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inner := g.objectSchema(embedded)
+				for key, prop := range inner.Properties {
+					schema.Properties[key] = prop
+				}
+				schema.Required = append(schema.Required, inner.Required...)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := g.schemaFor(field.Type)
+		if strings.Contains(opts, "string") {
+			prop = &Schema{Type: "string", Nullable: prop.Nullable}
+		}
+
+		required, err := applyRules(prop, field.Tag.Get("validate"))
+		if err != nil {
+			panic(fmt.Sprintf("openapi: %s.%s: %v", t.Name(), field.Name, err))
+		}
+		if required {
+			prop = notNull(prop)
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = prop
+	}
+
+	return schema
+}
+
+// applyRules adds the constraints of a validate tag such as
+// `validate:"required,min=1,enum=fade|strobe"` and reports whether the field is required
+func applyRules(schema *Schema, tag string) (bool, error) {
+	if tag == "" {
+		return false, nil
+	}
+
+	required := false
+	for rule := range strings.SplitSeq(tag, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "min", "max":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return false, fmt.Errorf("invalid %s rule %q", key, value)
+			}
+			if err := applyBound(schema, key, n); err != nil {
+				return false, err
+			}
+		case "enum":
+			if schema.Type != "string" {
+				return false, fmt.Errorf("enum rule on %s", schema.Type)
+			}
+			schema.Enum = strings.Split(value, "|")
+		default:
+			return false, fmt.Errorf("unknown rule %q", key)
+		}
+	}
+	return required, nil
+}
+
+// applyBound sets a min or max rule as the length, item count or value bound
+func applyBound(schema *Schema, key string, n float64) error {
+	isMin := key == "min"
+	switch schema.Type {
+	case "string":
+		if isMin {
+			schema.MinLength = ptr(int(n))
+		} else {
+			schema.MaxLength = ptr(int(n))
+		}
+	case "array":
+		if isMin {
+			schema.MinItems = ptr(int(n))
+		} else {
+			schema.MaxItems = ptr(int(n))
+		}
+	case "integer", "number":
+		if isMin {
+			schema.Minimum = ptr(n)
+		} else {
+			schema.Maximum = ptr(n)
+		}
+	default:
+		return fmt.Errorf("%s rule on %s", key, schema.Type)
+	}
+	return nil
+}
+
+// intSchema bounds integers to their Go type, so a brightness of 300 is
+// rejected instead of failing to decode into a uint8
+func intSchema(signed bool, bits int) *Schema {
+	schema := &Schema{Type: "integer", Format: "int64"}
+	if bits <= 32 {
+		schema.Format = "int32"
+	}
+
+	switch {
+	case signed && bits <= 32:
+		schema.Minimum = ptr(-math.Ldexp(1, bits-1))
+		schema.Maximum = ptr(math.Ldexp(1, bits-1) - 1)
+	case !signed:
+		schema.Minimum = ptr(0.0)
+		if bits <= 32 {
+			schema.Maximum = ptr(math.Ldexp(1, bits) - 1)
+		}
+	}
+	return schema
+}
+
+// nullable allows null, wrapping references since siblings of $ref are ignored
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+	}
+	copied := *schema
+	copied.Nullable = true
+	return &copied
+}
+
+// notNull undoes nullable for required fields
+func notNull(schema *Schema) *Schema {
+	if len(schema.AllOf) == 1 && schema.Type == "" {
+		return schema.AllOf[0]
+	}
+	schema.Nullable = false
+	return schema
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,228 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrInvalidJSON is returned for bodies that aren't valid JSON
+var ErrInvalidJSON = errors.New("invalid JSON")
+
+// ValidationError reports the first value that doesn't match its schema
+type ValidationError struct {
+	Field   string // Path of the value, e.g. colors[1].r, empty for the body itself
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// ValidateBody checks a request body against the operation's schema
+func (d *Document) ValidateBody(op *Operation, body []byte) error {
+	if op.RequestBody == nil {
+		return nil
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			return &ValidationError{Message: "request body is required"}
+		}
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if decoder.More() {
+		return fmt.Errorf("%w: unexpected data after the body", ErrInvalidJSON)
+	}
+
+	return d.Validate(op.RequestBody.Content[contentTypeJSON].Schema, value)
+}
+
+// Validate checks a decoded JSON value against a schema. Numbers must be
+// decoded as json.Number
+func (d *Document) Validate(schema *Schema, value any) error {
+	return d.validate(schema, value, "")
+}
+
+func (d *Document) validate(schema *Schema, value any, field string) error {
+	schema = d.resolve(schema)
+
+	// Checked before allOf, so a nullable wrapper lets null through
+	if value == nil && (schema.Nullable || schema.Type == "" && len(schema.AllOf) == 0) {
+		return nil
+	}
+
+	for _, sub := range schema.AllOf {
+		if err := d.validate(sub, value, field); err != nil {
+			return err
+		}
+	}
+
+	if value == nil {
+		if schema.Type == "" {
+			return nil
+		}
+		return fail(field, "must not be null")
+	}
+
+	switch schema.Type {
+	case "object":
+		return d.validateObject(schema, value, field)
+	case "array":
+		return d.validateArray(schema, value, field)
+	case "string":
+		return validateString(schema, value, field)
+	case "integer", "number":
+		return validateNumber(schema, value, field)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fail(field, "must be a boolean")
+		}
+	}
+	return nil
+}
+
+func (d *Document) validateObject(schema *Schema, value any, field string) error {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return fail(field, "must be an object")
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			return fail(join(field, name), "is required")
+		}
+	}
+
+	// Sorted, so the same body always reports the same field first
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		prop, ok := schema.Properties[key]
+		if !ok {
+			prop = schema.AdditionalProperties
+		}
+		// Unknown fields are ignored, like encoding/json does
+		if prop == nil {
+			continue
+		}
+		if err := d.validate(prop, object[key], join(field, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Document) validateArray(schema *Schema, value any, field string) error {
+	items, ok := value.([]any)
+	if !ok {
+		return fail(field, "must be an array")
+	}
+
+	if schema.MinItems != nil && len(items) < *schema.MinItems {
+		return fail(field, fmt.Sprintf("must have at least %d item(s)", *schema.MinItems))
+	}
+	if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+		return fail(field, fmt.Sprintf("must have at most %d item(s)", *schema.MaxItems))
+	}
+
+	if schema.Items != nil {
+		for i, item := range items {
+			if err := d.validate(schema.Items, item, fmt.Sprintf("%s[%d]", field, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateString(schema *Schema, value any, field string) error {
+	s, ok := value.(string)
+	if !ok {
+		return fail(field, "must be a string")
+	}
+
+	length := utf8.RuneCountInString(s)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		if *schema.MinLength == 1 {
+			return fail(field, "must not be empty")
+		}
+		return fail(field, fmt.Sprintf("must be at least %d characters", *schema.MinLength))
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		return fail(field, fmt.Sprintf("must be at most %d characters", *schema.MaxLength))
+	}
+
+	if len(schema.Enum) > 0 && !slices.Contains(schema.Enum, s) {
+		return fail(field, "must be one of "+strings.Join(schema.Enum, ", "))
+	}
+
+	if schema.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return fail(field, "must be an RFC 3339 date-time")
+		}
+	}
+	return nil
+}
+
+func validateNumber(schema *Schema, value any, field string) error {
+	message := "must be a number"
+	if schema.Type == "integer" {
+		message = "must be an integer"
+	}
+
+	number, ok := value.(json.Number)
+	if !ok {
+		return fail(field, message)
+	}
+
+	n, err := strconv.ParseFloat(number.String(), 64)
+	if err != nil || schema.Type == "integer" && n != math.Trunc(n) {
+		return fail(field, message)
+	}
+
+	if schema.Minimum != nil && n < *schema.Minimum {
+		return fail(field, "must be at least "+formatBound(*schema.Minimum))
+	}
+	if schema.Maximum != nil && n > *schema.Maximum {
+		return fail(field, "must be at most "+formatBound(*schema.Maximum))
+	}
+	return nil
+}
+
+func formatBound(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func fail(field, message string) error {
+	return &ValidationError{Field: field, Message: message}
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRouter builds the router with only the state and effect storage set,
+// enough for the routes these tests call
+func newTestRouter(t *testing.T) http.Handler {
+	t.Setenv("HOME", t.TempDir())
+	effectStorage, err := storage.NewEffectStorage()
+	require.NoError(t, err)
+
+	server := &Server{
+		state:    state.NewServerState(application.NewDeviceService(nil), nil),
+		services: Services{EffectStorage: effectStorage},
+	}
+	return server.setupRouter()
+}
+
+func TestRoutesMatchRouter(t *testing.T) {
+	router := newTestRouter(t).(chi.Routes)
+
+	var registered []string
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if strings.HasPrefix(route, "/api/") {
+			registered = append(registered, method+" "+route)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	documented := make([]string, len(routes))
+	for i, route := range routes {
+		documented[i] = route.Method + " " + route.Path
+	}
+
+	assert.ElementsMatch(t, registered, documented, "every /api route needs an entry in routes")
+}
+
+func TestServeDocument(t *testing.T) {
+	router := newTestRouter(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var doc struct {
+		OpenAPI    string                    `json:"openapi"`
+		Paths      map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Contains(t, doc.Paths["/api/effects"], "post")
+
+	// Every reference points at a schema in the document
+	for _, ref := range strings.Split(rec.Body.String(), `"$ref":"#/components/schemas/`)[1:] {
+		name := ref[:strings.Index(ref, `"`)]
+		assert.Contains(t, doc.Components.Schemas, name)
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   string
+		wantField  string
+	}{
+		{
+			name:       "malformed JSON",
+			method:     http.MethodPost,
+			path:       "/api/effects",
+			body:       `{"name":`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   dto.ErrorCodeInvalidBody,
+		},
+		{
+			name:       "missing body",
+			method:     http.MethodPost,
+			path:       "/api/effects",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   dto.ErrorCodeValidation,
+		},
+		{
+			name:       "missing name",
+			method:     http.MethodPost,
+			path:       "/api/effects",
+			body:       `{"colors":[{"r":255,"g":0,"b":0}],"pattern":"fade"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   dto.ErrorCodeValidation,
+			wantField:  "name",
+		},
+		{
+			name:       "unknown pattern",
+			method:     http.MethodPost,
+			path:       "/api/effects",
+			body:       `{"name":"Sunset","colors":[{"r":255,"g":0,"b":0}],"pattern":"wobble"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   dto.ErrorCodeValidation,
+			wantField:  "pattern",
+		},
+		{
+			name:       "color out of range",
+			method:     http.MethodPost,
+			path:       "/api/effects",
+			body:       `{"name":"Sunset","colors":[{"r":255,"g":300,"b":0}],"pattern":"fade"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   dto.ErrorCodeValidation,
+			wantField:  "colors[0].g",
+		},
+		{
+			name:       "empty address",
+			method:     http.MethodPost,
+			path:       "/api/device/select",
+			body:       `{"address":""}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   dto.ErrorCodeValidation,
+			wantField:  "address",
+		},
+		{
+			name:       "valid effect",
+			method:     http.MethodPost,
+			path:       "/api/effects",
+			body:       `{"name":"Sunset","colors":[{"r":255,"g":80,"b":0}],"pattern":"fade","speed":50}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "handler error",
+			method:     http.MethodGet,
+			path:       "/api/device/current",
+			wantStatus: http.StatusNotFound,
+			wantCode:   dto.ErrorCodeNotFound,
+		},
+	}
+
+	router := newTestRouter(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+
+			if tt.wantCode == "" {
+				return
+			}
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var body dto.ErrorDTO
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantCode, body.Code)
+			assert.Equal(t, tt.wantField, body.Field)
+			assert.NotEmpty(t, body.Message)
+		})
+	}
+}
@@ -3,7 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/metrics"
 	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api/handlers"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api/middleware"
@@ -20,18 +22,37 @@ import (
 
 // Server represents the HTTP server
 type Server struct {
-	httpServer    *http.Server
-	state         *state.ServerState
-	effectStorage *storage.EffectStorage
-	twitchStorage *storage.TwitchStorage
+	httpServer  *http.Server
+	state       *state.ServerState
+	services    Services
+	wledEnabled bool
+}
+
+// Services are the services and storages behind the API routes
+type Services struct {
+	EffectStorage  *storage.EffectStorage
+	TwitchStorage  *storage.TwitchStorage
+	LoyaltyService *application.LoyaltyService
+	OBSService     *application.OBSService
+	OBSStorage     *storage.OBSStorage
+	AlertService   *application.AlertService
+	AlertStorage   *storage.AlertStorage
+	MQTTService    *application.MQTTService
+	MQTTStorage    *storage.MQTTStorage
+	HueService     *application.HueService
+	HueStorage     *storage.HueStorage
+	DMXService     *application.DMXService
+	DMXStorage     *storage.DMXStorage
+	OpenRGBService *application.OpenRGBService
+	OpenRGBStorage *storage.OpenRGBStorage
 }
 
 // NewServer creates a new HTTP server
-func NewServer(host string, port int, serverState *state.ServerState, effectStorage *storage.EffectStorage, twitchStorage *storage.TwitchStorage) *Server {
+func NewServer(host string, port int, serverState *state.ServerState, services Services, wledEnabled bool) *Server {
 	server := &Server{
-		state:         serverState,
-		effectStorage: effectStorage,
-		twitchStorage: twitchStorage,
+		state:       serverState,
+		services:    services,
+		wledEnabled: wledEnabled,
 	}
 
 	// Create router
@@ -54,6 +75,7 @@ func (s *Server) setupRouter() http.Handler {
 	r := chi.NewRouter()
 
 	// Apply middleware
+	r.Use(middleware.RequestID)
 	r.Use(middleware.Recovery)
 	r.Use(middleware.Logging)
 	r.Use(middleware.CORS)
@@ -61,17 +83,38 @@ func (s *Server) setupRouter() http.Handler {
 	// Create handlers
 	deviceHandler := handlers.NewDeviceHandler(s.state)
 	wsHandler := handlers.NewWebSocketHandler(s.state)
-	effectHandler := handlers.NewEffectHandler(s.effectStorage)
-	twitchHandler := handlers.NewTwitchHandler(s.state.GetTwitchService(), s.twitchStorage)
+	effectHandler := handlers.NewEffectHandler(s.services.EffectStorage)
+	twitchHandler := handlers.NewTwitchHandler(s.state.GetTwitchService(), s.services.TwitchStorage)
+	overrideHandler := handlers.NewOverrideHandler(s.state)
+	leaseHandler := handlers.NewLeaseHandler(s.state)
+	loyaltyHandler := handlers.NewLoyaltyHandler(s.services.LoyaltyService)
+	obsHandler := handlers.NewOBSHandler(s.services.OBSService, s.services.OBSStorage)
+	alertHandler := handlers.NewAlertHandler(s.services.AlertService, s.services.AlertStorage)
+	alertConfigHandler := handlers.NewAlertConfigHandler(s.services.AlertService, s.services.AlertStorage)
+	mqttHandler := handlers.NewMQTTHandler(s.services.MQTTService, s.services.MQTTStorage)
+	hueHandler := handlers.NewHueHandler(s.services.HueService)
+	hueConfigHandler := handlers.NewHueConfigHandler(s.services.HueService, s.services.HueStorage)
+	dmxHandler := handlers.NewDMXHandler(s.services.DMXService, s.services.DMXStorage)
+	openRGBHandler := handlers.NewOpenRGBHandler(s.services.OpenRGBService, s.services.OpenRGBStorage)
+	eventHandler := handlers.NewEventHandler(s.state.GetEventBroker())
+	doc := newDocument()
+	openAPIHandler := handlers.NewOpenAPIHandler(doc)
 
 	// API routes
 	r.Route("/api", func(r chi.Router) {
+		// Bodies are checked against the document before any handler decodes them
+		r.Use(middleware.Validation(doc))
+		r.Get("/openapi.json", openAPIHandler.GetDocument)
+
 		r.Get("/health", deviceHandler.Health)
 		r.Get("/devices", deviceHandler.ListDevices)
 		r.Post("/scan", deviceHandler.ScanDevices)
 		r.Post("/device/select", deviceHandler.SelectDevice)
 		r.Get("/device/current", deviceHandler.GetCurrentDevice)
 
+		// Server-Sent Events stream
+		r.Get("/events", eventHandler.Stream)
+
 		// Effect routes
 		r.Get("/effects", effectHandler.ListEffects)
 		r.Post("/effects", effectHandler.CreateEffect)
@@ -83,11 +126,92 @@ func (s *Server) setupRouter() http.Handler {
 		r.Get("/twitch/status", twitchHandler.GetStatus)
 		r.Get("/twitch/commands", twitchHandler.GetAvailableCommands)
 		r.Get("/twitch/oauth", twitchHandler.GetOAuthURL)
+		r.Get("/twitch/permissions", twitchHandler.GetPermissions)
+		r.Put("/twitch/permissions", twitchHandler.UpdatePermissions)
+		r.Put("/twitch/permissions/{command}", twitchHandler.SetCommandPermission)
+		r.Delete("/twitch/permissions/{command}", twitchHandler.DeleteCommandPermission)
+		r.Post("/twitch/bans", twitchHandler.BanUser)
+		r.Delete("/twitch/bans/{username}", twitchHandler.UnbanUser)
+		r.Get("/twitch/command-settings", twitchHandler.GetCommandSettings)
+		r.Put("/twitch/command-settings/{command}", twitchHandler.SetCommandSetting)
+		r.Delete("/twitch/command-settings/{command}", twitchHandler.DeleteCommandSetting)
+		r.Get("/twitch/points", loyaltyHandler.GetLeaderboard)
+		r.Get("/twitch/points/{viewer}", loyaltyHandler.GetAccount)
+		r.Post("/twitch/points/{viewer}", loyaltyHandler.AdjustPoints)
+		r.Get("/twitch/replies", twitchHandler.GetReplies)
+		r.Put("/twitch/replies", twitchHandler.UpdateReplies)
+		r.Get("/twitch/vote", twitchHandler.GetVote)
+		r.Post("/twitch/vote", twitchHandler.StartVote)
+		r.Delete("/twitch/vote", twitchHandler.CancelVote)
+		r.Get("/twitch/history", twitchHandler.GetHistory)
+		r.Get("/twitch/stats", twitchHandler.GetStats)
+		r.Get("/twitch/channels", twitchHandler.GetChannels)
+		r.Post("/twitch/channels", twitchHandler.AddChannel)
+		r.Put("/twitch/channels/{id}", twitchHandler.UpdateChannel)
+		r.Delete("/twitch/channels/{id}", twitchHandler.DeleteChannel)
+
+		// OBS routes
+		r.Get("/obs/config", obsHandler.GetConfig)
+		r.Put("/obs/config", obsHandler.UpdateConfig)
+		r.Get("/obs/status", obsHandler.GetStatus)
+
+		// Alert routes
+		r.Post("/hooks/{name}", alertHandler.Webhook)
+		r.Get("/alerts/config", alertConfigHandler.GetConfig)
+		r.Put("/alerts/config", alertConfigHandler.UpdateConfig)
+		r.Get("/alerts/status", alertConfigHandler.GetStatus)
+		r.Post("/alerts/test", alertHandler.TestAlert)
+
+		// MQTT routes
+		r.Get("/mqtt/config", mqttHandler.GetConfig)
+		r.Put("/mqtt/config", mqttHandler.UpdateConfig)
+		r.Get("/mqtt/status", mqttHandler.GetStatus)
+
+		// Hue bridge routes
+		r.Get("/hue/config", hueConfigHandler.GetConfig)
+		r.Put("/hue/config", hueConfigHandler.UpdateConfig)
+		r.Get("/hue/status", hueConfigHandler.GetStatus)
+		r.Post("/hue/link", hueHandler.PressLinkButton)
+		r.Delete("/hue/users/{username}", hueHandler.DeleteUser)
+
+		// DMX input routes
+		r.Get("/dmx/config", dmxHandler.GetConfig)
+		r.Put("/dmx/config", dmxHandler.UpdateConfig)
+		r.Get("/dmx/status", dmxHandler.GetStatus)
+
+		// OpenRGB SDK server routes
+		r.Get("/openrgb/config", openRGBHandler.GetConfig)
+		r.Put("/openrgb/config", openRGBHandler.UpdateConfig)
+		r.Get("/openrgb/status", openRGBHandler.GetStatus)
+
+		// Override routes
+		r.Get("/override", overrideHandler.GetOverride)
+		r.Post("/override/lock", overrideHandler.Lock)
+		r.Delete("/override/lock", overrideHandler.Unlock)
+		r.Post("/override/panic", overrideHandler.Panic)
+
+		// Lamp arbitration routes
+		r.Get("/leases", leaseHandler.GetLeases)
+		r.Put("/leases/overlay", leaseHandler.SetOverlay)
+		r.Delete("/leases/overlay", leaseHandler.ClearOverlay)
 	})
 
+	// WLED JSON API routes, for WLED apps, LedFx and Home Assistant
+	if s.wledEnabled {
+		wledHandler := handlers.NewWLEDHandler(s.state, s.services.EffectStorage)
+		r.Get("/json", wledHandler.GetAll)
+		r.Get("/json/state", wledHandler.GetState)
+		r.Post("/json/state", wledHandler.UpdateState)
+		r.Get("/json/info", wledHandler.GetInfo)
+		r.Get("/json/effects", wledHandler.GetEffects)
+	}
+
 	// WebSocket route
 	r.Get("/ws", wsHandler.HandleWebSocket)
 
+	// Prometheus metrics
+	r.Handle("/metrics", metrics.Default)
+
 	// Static file serving
 	staticDir := "./web/static"
 	if absPath, err := filepath.Abs(staticDir); err == nil {
@@ -112,13 +236,13 @@ func (s *Server) Start() error {
 	go s.state.GetWebSocketHub().Run()
 
 	// Start HTTP server
-	log.Printf("Starting web server on %s", s.httpServer.Addr)
-	log.Printf("Web UI available at http://%s", s.httpServer.Addr)
+	slog.Info("Starting web server", "address", s.httpServer.Addr, "ui", "http://"+s.httpServer.Addr)
 
 	// Start server in a goroutine
 	go func() {
 		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("HTTP server error: %v", err)
+			slog.Error("HTTP server error", "error", err)
+			os.Exit(1)
 		}
 	}()
 
@@ -127,18 +251,21 @@ func (s *Server) Start() error {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server")
 
 	// Create shutdown context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// End the event streams, Shutdown waits for open requests
+	s.state.GetEventBroker().Close()
+
 	// Shutdown HTTP server
 	if err := s.httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("server shutdown error: %w", err)
 	}
 
-	log.Println("Server stopped")
+	slog.Info("Server stopped")
 	return nil
 }
 
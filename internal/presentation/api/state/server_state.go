@@ -1,12 +1,15 @@
 package state
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/codeneuss/lampcontrol/internal/application"
 	"github.com/codeneuss/lampcontrol/internal/domain"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/events"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api/websocket"
 )
 
@@ -17,6 +20,10 @@ type ServerState struct {
 	deviceService  *application.DeviceService
 	twitchService  *application.TwitchService
 	wsHub          *websocket.Hub
+	eventBroker    *events.Broker
+
+	// Where lamp state changes are forwarded besides the event stream, e.g. the MQTT bridge
+	onDeviceState func(address string, state domain.DeviceState)
 }
 
 // NewServerState creates a new server state
@@ -28,10 +35,15 @@ func NewServerState(deviceService *application.DeviceService, twitchService *app
 
 	// Create WebSocket hub with reference to state
 	state.wsHub = websocket.NewHub(deviceService, state.GetSelectedDeviceAddress)
+	state.eventBroker = events.NewBroker(events.DefaultBufferSize)
+
+	// Every lamp change from any source goes to the event stream
+	deviceService.SetStateChangeCallback(state.publishDeviceState)
+	deviceService.SetConnectionChangeCallback(state.publishDeviceConnection)
 
 	// Set Twitch callbacks if Twitch service is provided
 	if twitchService != nil {
-		twitchService.SetStatusChangeCallback(func(connected bool) {
+		twitchService.SetStatusChangeCallback(func(status domain.ConnectionStatus) {
 			state.BroadcastTwitchStatus()
 		})
 
@@ -39,7 +51,25 @@ func NewServerState(deviceService *application.DeviceService, twitchService *app
 			state.BroadcastTwitchCommand(username, command)
 		})
 
+		twitchService.SetVoteUpdateCallback(func(session *domain.VoteSession) {
+			state.BroadcastTwitchVote(session)
+		})
+
+		twitchService.SetOverrideChangeCallback(func(override domain.OverrideState) {
+			state.BroadcastOverrideStatus(override)
+		})
+
 		twitchService.SetGetSelectedDeviceFunc(state.GetSelectedDeviceAddress)
+		state.wsHub.SetTwitchService(twitchService)
+		// Epilepsy-safe mode also limits the streamer's own changes
+		state.routeThroughSafetyFilter(state.wsHub)
+
+		// Restores act for the streamer and show up in the UI
+		arbiter := twitchService.Arbiter()
+		state.routeThroughSafetyFilter(arbiter)
+		arbiter.SetChangeCallback(func(deviceAddr string) {
+			state.BroadcastState()
+		})
 	}
 
 	return state
@@ -87,6 +117,23 @@ func (s *ServerState) GetDeviceService() *application.DeviceService {
 	return s.deviceService
 }
 
+// ApplyChange sends a change made on behalf of the streamer, e.g. from a WLED app,
+// through the safety filter to the base layer below viewer effects and alerts
+func (s *ServerState) ApplyChange(ctx context.Context, deviceAddr string, change domain.StateChange) error {
+	var err error
+	if s.twitchService != nil {
+		err = s.twitchService.ApplyManualChange(ctx, deviceAddr, change)
+	} else {
+		err = application.ApplyChange(ctx, s.deviceService, deviceAddr, change)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.BroadcastState()
+	return nil
+}
+
 // GetWebSocketHub returns the WebSocket hub
 func (s *ServerState) GetWebSocketHub() *websocket.Hub {
 	return s.wsHub
@@ -97,29 +144,243 @@ func (s *ServerState) BroadcastState() {
 	s.wsHub.BroadcastDeviceState()
 }
 
+// GetEventBroker returns the broker of the event stream
+func (s *ServerState) GetEventBroker() *events.Broker {
+	return s.eventBroker
+}
+
+// broadcast sends a message to all WebSocket clients and publishes it on the event stream
+func (s *ServerState) broadcast(topic string, messageType dto.MessageType, message interface{}) {
+	s.wsHub.BroadcastMessage(message)
+	s.eventBroker.Publish(topic, string(messageType), message)
+}
+
+// publishDeviceState publishes the new state of a lamp and forwards it
+func (s *ServerState) publishDeviceState(address string, state domain.DeviceState) {
+	s.eventBroker.Publish(events.TopicState, string(dto.MessageTypeDeviceState),
+		dto.NewDeviceStateMessage(address, dto.FromDomainState(state)))
+
+	s.mu.RLock()
+	forward := s.onDeviceState
+	s.mu.RUnlock()
+
+	if forward != nil {
+		forward(address, state)
+	}
+}
+
+// publishDeviceConnection publishes a lamp connecting or disconnecting
+func (s *ServerState) publishDeviceConnection(address string, connected bool) {
+	s.eventBroker.Publish(events.TopicConnection, string(dto.MessageTypeDeviceConnection),
+		dto.NewDeviceConnectionMessage(address, connected))
+}
+
+// BroadcastScanStarted publishes a device scan being started on the event stream
+func (s *ServerState) BroadcastScanStarted(timeout time.Duration) {
+	s.eventBroker.Publish(events.TopicScan, string(dto.MessageTypeScanStarted),
+		dto.NewScanStartedMessage(timeout.String()))
+}
+
+// BroadcastScanResult broadcasts the devices found by a scan to all WebSocket and event stream clients
+func (s *ServerState) BroadcastScanResult(devices []dto.DeviceDTO) {
+	s.broadcast(events.TopicScan, dto.MessageTypeScanResult, dto.NewScanResultMessage(devices))
+}
+
 // GetTwitchService returns the Twitch service
 func (s *ServerState) GetTwitchService() *application.TwitchService {
 	return s.twitchService
 }
 
-// BroadcastTwitchStatus broadcasts Twitch connection status to all WebSocket clients
+// BroadcastTwitchStatus broadcasts Twitch connection status to all WebSocket and event stream clients
 func (s *ServerState) BroadcastTwitchStatus() {
-	if s.twitchService == nil {
+	if s.twitchService == nil || s.wsHub == nil {
 		return
 	}
 
-	// This will be called by the status change callback
-	// The actual status will be fetched by the frontend via API
-	// For now, just signal that status changed
-	// We could enhance this to include the status in the message
+	message := dto.NewTwitchStatusMessage(dto.FromTwitchService(s.twitchService))
+	s.broadcast(events.TopicConnection, dto.MessageTypeTwitchStatus, message)
 }
 
-// BroadcastTwitchCommand broadcasts a Twitch command execution to all WebSocket clients
+// BroadcastTwitchCommand broadcasts a Twitch command execution to all WebSocket and event stream clients
 func (s *ServerState) BroadcastTwitchCommand(username, command string) {
 	if s.wsHub == nil {
 		return
 	}
 
 	message := dto.NewTwitchCommandMessage(username, command)
-	s.wsHub.BroadcastMessage(message)
+	s.broadcast(events.TopicTwitch, dto.MessageTypeTwitchCommand, message)
+}
+
+// BroadcastTwitchVote broadcasts a live poll tally to all WebSocket and event stream clients
+func (s *ServerState) BroadcastTwitchVote(session *domain.VoteSession) {
+	if s.wsHub == nil {
+		return
+	}
+
+	message := dto.NewTwitchVoteMessage(dto.FromDomainVote(session))
+	s.broadcast(events.TopicTwitch, dto.MessageTypeTwitchVote, message)
+}
+
+// BroadcastOverrideStatus broadcasts the streamer override state to all WebSocket and event stream clients
+func (s *ServerState) BroadcastOverrideStatus(override domain.OverrideState) {
+	if s.wsHub == nil {
+		return
+	}
+
+	message := dto.NewOverrideStatusMessage(dto.FromDomainOverride(override))
+	s.broadcast(events.TopicState, dto.MessageTypeOverride, message)
+}
+
+// lampChanged tells the clients about a lamp change a service made through a lease
+func (s *ServerState) lampChanged(deviceAddr string, change domain.StateChange) {
+	s.BroadcastState()
+}
+
+// routeThroughSafetyFilter sends the lamp changes of a service through the safety filter
+// of the streamer, if Twitch is set up. This is the one place the filter is wired in:
+// the arbiter, the WebSocket hub, OBS, alerts, DMX and OpenRGB all pass through it,
+// so flash limits and epilepsy-safe mode hold no matter where a change comes from.
+func (s *ServerState) routeThroughSafetyFilter(service interface {
+	SetDeviceController(devices application.DeviceController)
+}) {
+	if s.twitchService != nil {
+		service.SetDeviceController(s.twitchService.SafetyFilter().Streamer())
+	}
+}
+
+// SetOBSService connects the OBS service to the lamps and the WebSocket clients
+func (s *ServerState) SetOBSService(obsService *application.OBSService) {
+	obsService.SetGetSelectedDeviceFunc(s.GetSelectedDeviceAddress)
+	obsService.SetStatusChangeCallback(s.BroadcastOBSStatus)
+
+	// OBS changes are a lease below viewer effects, so only the UI needs to know
+	obsService.SetLampChangeCallback(s.lampChanged)
+	s.routeThroughSafetyFilter(obsService)
+}
+
+// BroadcastOBSStatus broadcasts the OBS status to all WebSocket and event stream clients
+func (s *ServerState) BroadcastOBSStatus(status application.OBSStatus) {
+	if s.wsHub == nil {
+		return
+	}
+
+	message := dto.NewOBSStatusMessage(dto.FromOBSStatus(status))
+	s.broadcast(events.TopicConnection, dto.MessageTypeOBSStatus, message)
+}
+
+// SetAlertService connects the alert service to the lamps and the WebSocket clients
+func (s *ServerState) SetAlertService(alertService *application.AlertService) {
+	alertService.SetGetSelectedDeviceFunc(s.GetSelectedDeviceAddress)
+	alertService.SetAlertCallback(s.BroadcastAlert)
+	alertService.SetStatusChangeCallback(s.BroadcastAlertStatus)
+
+	// Alert effects are a lease the arbiter restores, so only the UI needs to know
+	alertService.SetLampChangeCallback(func(deviceAddr string) {
+		s.BroadcastState()
+	})
+
+	s.routeThroughSafetyFilter(alertService)
+	if s.twitchService != nil {
+		// Subs, gifted subs and raids from Twitch chat preempt viewer commands
+		s.twitchService.SetAlertCallback(alertService.HandleAlert)
+	}
+}
+
+// BroadcastAlert broadcasts a received alert to all WebSocket and event stream clients
+func (s *ServerState) BroadcastAlert(alert *domain.Alert) {
+	if s.wsHub == nil {
+		return
+	}
+
+	message := dto.NewAlertMessage(dto.FromDomainAlert(alert))
+	s.broadcast(events.TopicAlert, dto.MessageTypeAlert, message)
+}
+
+// BroadcastAlertStatus broadcasts the alert provider status to all WebSocket and event stream clients
+func (s *ServerState) BroadcastAlertStatus(status application.AlertStatus) {
+	if s.wsHub == nil {
+		return
+	}
+
+	message := dto.NewAlertStatusMessage(dto.FromAlertStatus(status))
+	s.broadcast(events.TopicConnection, dto.MessageTypeAlertStatus, message)
+}
+
+// SetMQTTService connects the MQTT bridge to the lamps and the WebSocket clients
+func (s *ServerState) SetMQTTService(mqttService *application.MQTTService) {
+	mqttService.SetStatusChangeCallback(s.BroadcastMQTTStatus)
+
+	// Home Assistant acts for the streamer, its changes go to the base layer
+	mqttService.SetChangeFunc(s.ApplyChange)
+
+	// Every lamp change from any source shows up in Home Assistant
+	s.mu.Lock()
+	s.onDeviceState = mqttService.PublishState
+	s.mu.Unlock()
+}
+
+// BroadcastMQTTStatus broadcasts the MQTT connection status to all WebSocket and event stream clients
+func (s *ServerState) BroadcastMQTTStatus(status domain.ConnectionStatus) {
+	if s.wsHub == nil {
+		return
+	}
+
+	message := dto.NewMQTTStatusMessage(dto.FromMQTTStatus(status))
+	s.broadcast(events.TopicConnection, dto.MessageTypeMQTTStatus, message)
+}
+
+// SetHueService connects the Hue bridge to the lamps and the WebSocket clients
+func (s *ServerState) SetHueService(hueService *application.HueService) {
+	hueService.SetStatusChangeCallback(s.BroadcastHueStatus)
+
+	// Hue apps act for the streamer, their changes go to the base layer
+	hueService.SetChangeFunc(s.ApplyChange)
+}
+
+// BroadcastHueStatus broadcasts the Hue bridge status to all WebSocket and event stream clients
+func (s *ServerState) BroadcastHueStatus(status domain.HueStatus) {
+	if s.wsHub == nil {
+		return
+	}
+
+	message := dto.NewHueStatusMessage(dto.FromHueStatus(status))
+	s.broadcast(events.TopicConnection, dto.MessageTypeHueStatus, message)
+}
+
+// SetDMXService connects the lighting console input to the lamps and the WebSocket clients
+func (s *ServerState) SetDMXService(dmxService *application.DMXService) {
+	dmxService.SetStatusChangeCallback(s.BroadcastDMXStatus)
+
+	// The console holds a lease, viewer effects and alerts show on top of it
+	dmxService.SetLampChangeCallback(s.lampChanged)
+	s.routeThroughSafetyFilter(dmxService)
+}
+
+// BroadcastDMXStatus broadcasts the DMX input status to all WebSocket and event stream clients
+func (s *ServerState) BroadcastDMXStatus(status domain.DMXStatus) {
+	if s.wsHub == nil {
+		return
+	}
+
+	message := dto.NewDMXStatusMessage(dto.FromDMXStatus(status))
+	s.broadcast(events.TopicConnection, dto.MessageTypeDMXStatus, message)
+}
+
+// SetOpenRGBService connects the OpenRGB SDK server to the lamps and the WebSocket clients
+func (s *ServerState) SetOpenRGBService(openRGBService *application.OpenRGBService) {
+	openRGBService.SetStatusChangeCallback(s.BroadcastOpenRGBStatus)
+
+	// OpenRGB holds a lease, viewer effects and alerts show on top of it
+	openRGBService.SetLampChangeCallback(s.lampChanged)
+	s.routeThroughSafetyFilter(openRGBService)
+}
+
+// BroadcastOpenRGBStatus broadcasts the OpenRGB server status to all WebSocket and event stream clients
+func (s *ServerState) BroadcastOpenRGBStatus(status domain.OpenRGBStatus) {
+	if s.wsHub == nil {
+		return
+	}
+
+	message := dto.NewOpenRGBStatusMessage(dto.FromOpenRGBStatus(status))
+	s.broadcast(events.TopicConnection, dto.MessageTypeOpenRGBStatus, message)
 }
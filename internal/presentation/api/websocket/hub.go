@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/logging"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/metrics"
 	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
 )
 
@@ -36,8 +39,17 @@ type Hub struct {
 	// Device service for handling commands
 	deviceService *application.DeviceService
 
+	// Where lamp changes are sent
+	application.DeviceRoute
+
 	// Function to get selected device address
 	getSelectedDevice func() (string, error)
+
+	// Twitch service for streamer overrides (optional)
+	twitchService *application.TwitchService
+
+	// Logger for client and command errors
+	log *slog.Logger
 }
 
 // NewHub creates a new WebSocket hub
@@ -49,7 +61,9 @@ func NewHub(deviceService *application.DeviceService, getSelectedDevice func() (
 		unregister:        make(chan *Client),
 		broadcast:         make(chan []byte, 256),
 		deviceService:     deviceService,
+		DeviceRoute:       application.NewDeviceRoute(deviceService),
 		getSelectedDevice: getSelectedDevice,
+		log:               logging.Source("websocket"),
 	}
 }
 
@@ -59,17 +73,20 @@ func (h *Hub) Run() {
 		select {
 		case client := <-h.register:
 			h.clients[client] = true
-			log.Printf("Client connected. Total clients: %d", len(h.clients))
+			metrics.WSClients.With().Set(float64(len(h.clients)))
+			h.log.Debug("Client connected", "clients", len(h.clients))
 
 		case client := <-h.unregister:
 			if _, ok := h.clients[client]; ok {
 				delete(h.clients, client)
 				close(client.send)
-				log.Printf("Client disconnected. Total clients: %d", len(h.clients))
+				metrics.WSClients.With().Set(float64(len(h.clients)))
+				h.log.Debug("Client disconnected", "clients", len(h.clients))
 			}
 
 		case message := <-h.broadcast:
 			// Broadcast to all clients
+			metrics.WSBroadcasts.With().Inc()
 			for client := range h.clients {
 				select {
 				case client.send <- message:
@@ -77,6 +94,9 @@ func (h *Hub) Run() {
 					// Client buffer full, disconnect
 					close(client.send)
 					delete(h.clients, client)
+					metrics.WSBroadcastDrops.With().Inc()
+					metrics.WSClients.With().Set(float64(len(h.clients)))
+					h.log.Warn("Dropped slow client", "clients", len(h.clients))
 				}
 			}
 
@@ -91,11 +111,16 @@ func (h *Hub) Run() {
 func (h *Hub) handleCommand(client *Client, message []byte) {
 	var cmd dto.CommandMessage
 	if err := json.Unmarshal(message, &cmd); err != nil {
-		log.Printf("Failed to unmarshal command: %v", err)
+		h.log.Warn("Failed to unmarshal command", "error", err)
 		client.SendJSON(dto.NewErrorMessage("Invalid command format", "INVALID_FORMAT"))
 		return
 	}
 
+	// Override actions don't need a device
+	if h.handleOverrideAction(client, cmd.Action) {
+		return
+	}
+
 	// Get selected device address
 	deviceAddr, err := h.getSelectedDevice()
 	if err != nil {
@@ -104,6 +129,7 @@ func (h *Hub) handleCommand(client *Client, message []byte) {
 	}
 
 	ctx := context.Background()
+	var change domain.StateChange
 
 	// Process command based on action
 	switch cmd.Action {
@@ -113,7 +139,7 @@ func (h *Hub) handleCommand(client *Client, message []byte) {
 			client.SendJSON(dto.NewErrorMessage("Invalid power payload", "INVALID_PAYLOAD"))
 			return
 		}
-		err = h.deviceService.SetPower(ctx, deviceAddr, payload.On)
+		change.PowerOn = &payload.On
 
 	case dto.CommandActionColor:
 		var payload dto.ColorPayload
@@ -121,7 +147,7 @@ func (h *Hub) handleCommand(client *Client, message []byte) {
 			client.SendJSON(dto.NewErrorMessage("Invalid color payload", "INVALID_PAYLOAD"))
 			return
 		}
-		err = h.deviceService.SetColor(ctx, deviceAddr, payload.R, payload.G, payload.B)
+		change.RGB = &domain.RGB{R: payload.R, G: payload.G, B: payload.B}
 
 	case dto.CommandActionBrightness:
 		var payload dto.BrightnessPayload
@@ -129,7 +155,7 @@ func (h *Hub) handleCommand(client *Client, message []byte) {
 			client.SendJSON(dto.NewErrorMessage("Invalid brightness payload", "INVALID_PAYLOAD"))
 			return
 		}
-		err = h.deviceService.SetBrightness(ctx, deviceAddr, payload.Level)
+		change.Brightness = &payload.Level
 
 	case dto.CommandActionWhiteBalance:
 		var payload dto.WhiteBalancePayload
@@ -137,7 +163,7 @@ func (h *Hub) handleCommand(client *Client, message []byte) {
 			client.SendJSON(dto.NewErrorMessage("Invalid white balance payload", "INVALID_PAYLOAD"))
 			return
 		}
-		err = h.deviceService.SetWhiteBalance(ctx, deviceAddr, payload.Warm, payload.Cold)
+		change.WhiteBalance = &domain.WhiteBalance{Warm: payload.Warm, Cold: payload.Cold}
 
 	case dto.CommandActionEffect:
 		var payload dto.EffectPayload
@@ -145,15 +171,23 @@ func (h *Hub) handleCommand(client *Client, message []byte) {
 			client.SendJSON(dto.NewErrorMessage("Invalid effect payload", "INVALID_PAYLOAD"))
 			return
 		}
-		err = h.deviceService.SetEffect(ctx, deviceAddr, payload.Effect, payload.Speed)
+		effect := int(payload.Effect)
+		change.Effect = &effect
+		change.EffectSpeed = &payload.Speed
 
 	default:
 		client.SendJSON(dto.NewErrorMessage("Unknown command action", "UNKNOWN_ACTION"))
 		return
 	}
 
+	// Manual changes go to the base layer below viewer effects and alerts
+	if h.twitchService != nil {
+		err = h.twitchService.ApplyManualChange(ctx, deviceAddr, change)
+	} else {
+		err = application.ApplyChange(ctx, h.Devices(), deviceAddr, change)
+	}
 	if err != nil {
-		log.Printf("Command failed: %v", err)
+		h.log.Error("Command failed", "device", deviceAddr, "action", cmd.Action, "error", err)
 		client.SendJSON(dto.NewErrorMessage(fmt.Sprintf("Command failed: %v", err), "COMMAND_FAILED"))
 		return
 	}
@@ -162,6 +196,42 @@ func (h *Hub) handleCommand(client *Client, message []byte) {
 	h.BroadcastDeviceState()
 }
 
+// handleOverrideAction handles lock, unlock and panic actions.
+// It returns false if the action is not an override action.
+func (h *Hub) handleOverrideAction(client *Client, action dto.CommandAction) bool {
+	switch action {
+	case dto.CommandActionLock, dto.CommandActionUnlock, dto.CommandActionPanic:
+	default:
+		return false
+	}
+
+	if h.twitchService == nil {
+		client.SendJSON(dto.NewErrorMessage("Override is not available", "OVERRIDE_UNAVAILABLE"))
+		return true
+	}
+
+	switch action {
+	case dto.CommandActionLock:
+		h.twitchService.Lock("streamer")
+	case dto.CommandActionUnlock:
+		h.twitchService.Unlock("streamer")
+	case dto.CommandActionPanic:
+		if err := h.twitchService.Panic(context.Background(), "streamer"); err != nil {
+			h.log.Error("Panic failed", "error", err)
+			client.SendJSON(dto.NewErrorMessage(fmt.Sprintf("Panic failed: %v", err), "COMMAND_FAILED"))
+			return true
+		}
+		h.BroadcastDeviceState()
+	}
+
+	return true
+}
+
+// SetTwitchService sets the Twitch service used for streamer overrides
+func (h *Hub) SetTwitchService(twitchService *application.TwitchService) {
+	h.twitchService = twitchService
+}
+
 // BroadcastDeviceState sends the current device state to all clients
 func (h *Hub) BroadcastDeviceState() {
 	deviceAddr, err := h.getSelectedDevice()
@@ -179,7 +249,7 @@ func (h *Hub) BroadcastDeviceState() {
 
 	data, err := json.Marshal(message)
 	if err != nil {
-		log.Printf("Failed to marshal state update: %v", err)
+		h.log.Error("Failed to marshal state update", "error", err)
 		return
 	}
 
@@ -0,0 +1,118 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/pkg/lampcontrolv1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fromDomainDevice converts a device to its message
+func fromDomainDevice(device *domain.Device) *lampcontrolv1.Device {
+	return &lampcontrolv1.Device{
+		Address:   device.Address,
+		Name:      device.Name,
+		Rssi:      int32(device.RSSI),
+		Connected: device.Connected,
+		State:     fromDomainState(device.State),
+		LastSeen:  timestamp(device.LastSeen),
+	}
+}
+
+// fromDomainDevices converts a device list to messages
+func fromDomainDevices(devices []*domain.Device) []*lampcontrolv1.Device {
+	messages := make([]*lampcontrolv1.Device, len(devices))
+	for i, device := range devices {
+		messages[i] = fromDomainDevice(device)
+	}
+	return messages
+}
+
+// fromDomainState converts a device state to its message
+func fromDomainState(state domain.DeviceState) *lampcontrolv1.DeviceState {
+	message := &lampcontrolv1.DeviceState{
+		PowerOn:     state.PowerOn,
+		Brightness:  uint32(state.Brightness),
+		LastUpdated: timestamp(state.LastUpdated),
+	}
+	if state.RGB != nil {
+		message.Color = &lampcontrolv1.Color{R: uint32(state.RGB.R), G: uint32(state.RGB.G), B: uint32(state.RGB.B)}
+	}
+	if state.WhiteBalance != nil {
+		message.WhiteBalance = &lampcontrolv1.WhiteBalance{Warm: uint32(state.WhiteBalance.Warm), Cold: uint32(state.WhiteBalance.Cold)}
+	}
+	if state.Effect != nil {
+		effect := uint32(*state.Effect)
+		message.Effect = &effect
+	}
+	if state.EffectSpeed != nil {
+		speed := uint32(*state.EffectSpeed)
+		message.EffectSpeed = &speed
+	}
+	return message
+}
+
+// fromDomainOverride converts the override state to its message
+func fromDomainOverride(override domain.OverrideState) *lampcontrolv1.Override {
+	return &lampcontrolv1.Override{
+		Locked:   override.Locked,
+		LockedBy: override.LockedBy,
+		LockedAt: timestamp(override.LockedAt),
+	}
+}
+
+// fromDomainScene converts a custom effect to a scene
+func fromDomainScene(effect *domain.CustomEffect) *lampcontrolv1.Scene {
+	colors := make([]*lampcontrolv1.Color, len(effect.Colors))
+	for i, c := range effect.Colors {
+		colors[i] = &lampcontrolv1.Color{R: uint32(c.R), G: uint32(c.G), B: uint32(c.B)}
+	}
+
+	return &lampcontrolv1.Scene{
+		Id:        effect.ID,
+		Name:      effect.Name,
+		Colors:    colors,
+		Pattern:   effect.Pattern,
+		Speed:     uint32(effect.Speed),
+		CreatedAt: timestamp(effect.CreatedAt),
+	}
+}
+
+// toRGB validates a color message
+func toRGB(color *lampcontrolv1.Color) (domain.RGB, error) {
+	if color == nil {
+		return domain.RGB{}, status.Error(codes.InvalidArgument, "color is required")
+	}
+	r, err := toUint8("color.r", color.R)
+	if err != nil {
+		return domain.RGB{}, err
+	}
+	g, err := toUint8("color.g", color.G)
+	if err != nil {
+		return domain.RGB{}, err
+	}
+	b, err := toUint8("color.b", color.B)
+	if err != nil {
+		return domain.RGB{}, err
+	}
+	return domain.RGB{R: r, G: g, B: b}, nil
+}
+
+// toUint8 checks that a field fits the byte the lamp protocol sends
+func toUint8(field string, value uint32) (uint8, error) {
+	if value > 255 {
+		return 0, status.Errorf(codes.InvalidArgument, "%s must be 0-255, got %d", field, value)
+	}
+	return uint8(value), nil
+}
+
+// timestamp converts a time, leaving zero times unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,308 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/codeneuss/lampcontrol/internal/domain"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/dto"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/events"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/state"
+	"github.com/codeneuss/lampcontrol/pkg/lampcontrolv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// defaultScanTimeout is used when a scan request sets no timeout
+const defaultScanTimeout = 10 * time.Second
+
+// Server implements the gRPC LampControl service on the same state and
+// services as the HTTP handlers, so changes show up in the web UI
+type Server struct {
+	lampcontrolv1.UnimplementedLampControlServer
+
+	state         *state.ServerState
+	effectStorage *storage.EffectStorage
+}
+
+// NewServer creates a new gRPC server implementation
+func NewServer(state *state.ServerState, effectStorage *storage.EffectStorage) *Server {
+	return &Server{
+		state:         state,
+		effectStorage: effectStorage,
+	}
+}
+
+// ListDevices returns the devices found so far
+func (s *Server) ListDevices(ctx context.Context, req *lampcontrolv1.ListDevicesRequest) (*lampcontrolv1.ListDevicesResponse, error) {
+	devices := s.state.GetDeviceService().ListDevices()
+	return &lampcontrolv1.ListDevicesResponse{Devices: fromDomainDevices(devices)}, nil
+}
+
+// Scan scans for devices like POST /api/scan
+func (s *Server) Scan(ctx context.Context, req *lampcontrolv1.ScanRequest) (*lampcontrolv1.ScanResponse, error) {
+	timeout := defaultScanTimeout
+	if req.TimeoutSeconds > 0 {
+		timeout = time.Duration(req.TimeoutSeconds) * time.Second
+	}
+
+	s.state.BroadcastScanStarted(timeout)
+	devices, err := s.state.GetDeviceService().Scan(ctx, timeout)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "scan failed: %v", err)
+	}
+
+	messages := fromDomainDevices(devices)
+	s.state.BroadcastScanResult(dto.FromDomainList(devices))
+	return &lampcontrolv1.ScanResponse{Devices: messages}, nil
+}
+
+// SelectDevice selects the device used when requests give no address
+func (s *Server) SelectDevice(ctx context.Context, req *lampcontrolv1.SelectDeviceRequest) (*lampcontrolv1.Device, error) {
+	if err := s.state.SelectDevice(req.Address); err != nil {
+		return nil, toStatus(err)
+	}
+	s.state.BroadcastState()
+	return s.device(req.Address)
+}
+
+// SetPower turns a lamp on or off
+func (s *Server) SetPower(ctx context.Context, req *lampcontrolv1.SetPowerRequest) (*lampcontrolv1.Device, error) {
+	on := req.On
+	return s.apply(ctx, req.Address, domain.StateChange{PowerOn: &on})
+}
+
+// SetColor shows a color
+func (s *Server) SetColor(ctx context.Context, req *lampcontrolv1.SetColorRequest) (*lampcontrolv1.Device, error) {
+	color, err := toRGB(req.Color)
+	if err != nil {
+		return nil, err
+	}
+	return s.apply(ctx, req.Address, domain.StateChange{RGB: &color})
+}
+
+// SetBrightness sets the brightness
+func (s *Server) SetBrightness(ctx context.Context, req *lampcontrolv1.SetBrightnessRequest) (*lampcontrolv1.Device, error) {
+	level, err := toUint8("level", req.Level)
+	if err != nil {
+		return nil, err
+	}
+	return s.apply(ctx, req.Address, domain.StateChange{Brightness: &level})
+}
+
+// SetWhiteBalance shows white light
+func (s *Server) SetWhiteBalance(ctx context.Context, req *lampcontrolv1.SetWhiteBalanceRequest) (*lampcontrolv1.Device, error) {
+	warm, err := toUint8("warm", req.Warm)
+	if err != nil {
+		return nil, err
+	}
+	cold, err := toUint8("cold", req.Cold)
+	if err != nil {
+		return nil, err
+	}
+	return s.apply(ctx, req.Address, domain.StateChange{WhiteBalance: &domain.WhiteBalance{Warm: warm, Cold: cold}})
+}
+
+// SetEffect runs a built-in effect
+func (s *Server) SetEffect(ctx context.Context, req *lampcontrolv1.SetEffectRequest) (*lampcontrolv1.Device, error) {
+	effect, err := toUint8("effect", req.Effect)
+	if err != nil {
+		return nil, err
+	}
+	speed, err := toUint8("speed", req.Speed)
+	if err != nil {
+		return nil, err
+	}
+	effectInt := int(effect)
+	return s.apply(ctx, req.Address, domain.StateChange{Effect: &effectInt, EffectSpeed: &speed})
+}
+
+// Lock blocks viewer commands
+func (s *Server) Lock(ctx context.Context, req *lampcontrolv1.OverrideRequest) (*lampcontrolv1.Override, error) {
+	twitchService := s.state.GetTwitchService()
+	if twitchService == nil {
+		return nil, status.Error(codes.Unavailable, "override is not available")
+	}
+
+	twitchService.Lock(requestedBy(req))
+	return fromDomainOverride(twitchService.GetOverride()), nil
+}
+
+// Unlock allows viewer commands again
+func (s *Server) Unlock(ctx context.Context, req *lampcontrolv1.OverrideRequest) (*lampcontrolv1.Override, error) {
+	twitchService := s.state.GetTwitchService()
+	if twitchService == nil {
+		return nil, status.Error(codes.Unavailable, "override is not available")
+	}
+
+	twitchService.Unlock(requestedBy(req))
+	return fromDomainOverride(twitchService.GetOverride()), nil
+}
+
+// Panic stops everything viewers started and applies the safe scene
+func (s *Server) Panic(ctx context.Context, req *lampcontrolv1.OverrideRequest) (*lampcontrolv1.Override, error) {
+	twitchService := s.state.GetTwitchService()
+	if twitchService == nil {
+		return nil, status.Error(codes.Unavailable, "override is not available")
+	}
+
+	if err := twitchService.Panic(ctx, requestedBy(req)); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "panic failed: %v", err)
+	}
+	s.state.BroadcastState()
+	return fromDomainOverride(twitchService.GetOverride()), nil
+}
+
+// ListScenes returns the saved custom effects
+func (s *Server) ListScenes(ctx context.Context, req *lampcontrolv1.ListScenesRequest) (*lampcontrolv1.ListScenesResponse, error) {
+	effects := s.effectStorage.GetAll()
+	scenes := make([]*lampcontrolv1.Scene, len(effects))
+	for i, effect := range effects {
+		scenes[i] = fromDomainScene(effect)
+	}
+	return &lampcontrolv1.ListScenesResponse{Scenes: scenes}, nil
+}
+
+// CreateScene saves a custom effect
+func (s *Server) CreateScene(ctx context.Context, req *lampcontrolv1.CreateSceneRequest) (*lampcontrolv1.Scene, error) {
+	colors := make([]domain.RGBColor, len(req.Colors))
+	for i, c := range req.Colors {
+		rgb, err := toRGB(c)
+		if err != nil {
+			return nil, err
+		}
+		colors[i] = domain.RGBColor{R: rgb.R, G: rgb.G, B: rgb.B}
+	}
+	speed, err := toUint8("speed", req.Speed)
+	if err != nil {
+		return nil, err
+	}
+
+	effect := domain.NewCustomEffect(req.Name, colors, req.Pattern, speed)
+	if err := effect.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.effectStorage.Save(effect); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save scene: %v", err)
+	}
+	return fromDomainScene(effect), nil
+}
+
+// DeleteScene deletes a custom effect
+func (s *Server) DeleteScene(ctx context.Context, req *lampcontrolv1.DeleteSceneRequest) (*lampcontrolv1.DeleteSceneResponse, error) {
+	if _, err := s.effectStorage.Get(req.Id); err != nil {
+		return nil, status.Errorf(codes.NotFound, "scene %q not found", req.Id)
+	}
+	if err := s.effectStorage.Delete(req.Id); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete scene: %v", err)
+	}
+	return &lampcontrolv1.DeleteSceneResponse{}, nil
+}
+
+// WatchState sends the watched devices, then each one again when the event
+// stream reports a state or connection change for it
+func (s *Server) WatchState(req *lampcontrolv1.WatchStateRequest, stream grpc.ServerStreamingServer[lampcontrolv1.Device]) error {
+	// Subscribe first, so no change between the snapshot and the stream is lost
+	_, sub := s.state.GetEventBroker().Subscribe(0, []string{events.TopicState, events.TopicConnection})
+	defer sub.Close()
+
+	// Headers tell the client the watch is in place, even with no devices yet
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	watched := func(address string) bool {
+		return len(req.Addresses) == 0 || slices.Contains(req.Addresses, address)
+	}
+
+	for _, device := range s.state.GetDeviceService().ListDevices() {
+		if !watched(device.Address) {
+			continue
+		}
+		if err := stream.Send(fromDomainDevice(device)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-sub.Events:
+			if !ok {
+				return status.Error(codes.Unavailable, "state stream ended, watch again")
+			}
+
+			// Device events carry the address, override changes have none
+			var payload struct {
+				Address string `json:"address"`
+			}
+			if err := json.Unmarshal(event.Data, &payload); err != nil || payload.Address == "" || !watched(payload.Address) {
+				continue
+			}
+
+			device, err := s.state.GetDeviceService().GetDevice(payload.Address)
+			if err != nil {
+				continue
+			}
+			if err := stream.Send(fromDomainDevice(device)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// apply sends a change to a lamp, the selected one if no address is given
+func (s *Server) apply(ctx context.Context, address string, change domain.StateChange) (*lampcontrolv1.Device, error) {
+	if address == "" {
+		selected, err := s.state.GetSelectedDeviceAddress()
+		if err != nil {
+			return nil, status.Error(codes.FailedPrecondition, "no address given and no device selected")
+		}
+		address = selected
+	}
+
+	if _, err := s.state.GetDeviceService().GetDevice(address); err != nil {
+		return nil, toStatus(err)
+	}
+	if err := s.state.ApplyChange(ctx, address, change); err != nil {
+		return nil, toStatus(err)
+	}
+	return s.device(address)
+}
+
+// device returns the current device message
+func (s *Server) device(address string) (*lampcontrolv1.Device, error) {
+	device, err := s.state.GetDeviceService().GetDevice(address)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return fromDomainDevice(device), nil
+}
+
+// requestedBy reads the optional requester of an override
+func requestedBy(req *lampcontrolv1.OverrideRequest) string {
+	if req.By == "" {
+		return "streamer"
+	}
+	return req.By
+}
+
+// toStatus maps domain errors to gRPC status codes
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrDeviceNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrFlashRateExceeded), errors.Is(err, domain.ErrHueChangeTooSoon):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, domain.ErrStrobeBlocked):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
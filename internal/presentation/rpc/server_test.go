@@ -0,0 +1,158 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/codeneuss/lampcontrol/internal/application"
+	"github.com/codeneuss/lampcontrol/internal/infrastructure/storage"
+	"github.com/codeneuss/lampcontrol/internal/presentation/api/state"
+	"github.com/codeneuss/lampcontrol/pkg/lampcontrolv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient serves the API over an in-memory connection. The device
+// service has no adapter, so only requests failing before BLE work.
+func newTestClient(t *testing.T) (lampcontrolv1.LampControlClient, *state.ServerState) {
+	t.Setenv("HOME", t.TempDir())
+	effectStorage, err := storage.NewEffectStorage()
+	require.NoError(t, err)
+
+	serverState := state.NewServerState(application.NewDeviceService(nil), nil)
+
+	listener := bufconn.Listen(1 << 16)
+	server := grpc.NewServer()
+	lampcontrolv1.RegisterLampControlServer(server, NewServer(serverState, effectStorage))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return lampcontrolv1.NewLampControlClient(conn), serverState
+}
+
+func TestControlErrors(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{
+			name: "no device selected",
+			call: func() error {
+				_, err := client.SetPower(ctx, &lampcontrolv1.SetPowerRequest{On: true})
+				return err
+			},
+			want: codes.FailedPrecondition,
+		},
+		{
+			name: "unknown device",
+			call: func() error {
+				_, err := client.SetBrightness(ctx, &lampcontrolv1.SetBrightnessRequest{Address: "BE:27:EB:00:00:01", Level: 10})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			name: "color out of range",
+			call: func() error {
+				_, err := client.SetColor(ctx, &lampcontrolv1.SetColorRequest{Color: &lampcontrolv1.Color{R: 256}})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "missing color",
+			call: func() error {
+				_, err := client.SetColor(ctx, &lampcontrolv1.SetColorRequest{})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "select unknown device",
+			call: func() error {
+				_, err := client.SelectDevice(ctx, &lampcontrolv1.SelectDeviceRequest{Address: "BE:27:EB:00:00:01"})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			name: "override without Twitch service",
+			call: func() error {
+				_, err := client.Lock(ctx, &lampcontrolv1.OverrideRequest{})
+				return err
+			},
+			want: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, status.Code(tt.call()))
+		})
+	}
+}
+
+func TestScenes(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.CreateScene(ctx, &lampcontrolv1.CreateSceneRequest{Name: "Sunset"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.CreateScene(ctx, &lampcontrolv1.CreateSceneRequest{
+		Name:    "Sunset",
+		Colors:  []*lampcontrolv1.Color{{R: 255}},
+		Pattern: "spin",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	scene, err := client.CreateScene(ctx, &lampcontrolv1.CreateSceneRequest{
+		Name:    "Sunset",
+		Colors:  []*lampcontrolv1.Color{{R: 255, G: 80}, {R: 120, B: 40}},
+		Pattern: "fade",
+		Speed:   30,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, scene.Id)
+
+	list, err := client.ListScenes(ctx, &lampcontrolv1.ListScenesRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Scenes, 1)
+	assert.Equal(t, "Sunset", list.Scenes[0].Name)
+	assert.Equal(t, uint32(80), list.Scenes[0].Colors[0].G)
+
+	_, err = client.DeleteScene(ctx, &lampcontrolv1.DeleteSceneRequest{Id: scene.Id})
+	require.NoError(t, err)
+	_, err = client.DeleteScene(ctx, &lampcontrolv1.DeleteSceneRequest{Id: scene.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestWatchStateEndsOnShutdown(t *testing.T) {
+	client, serverState := newTestClient(t)
+
+	stream, err := client.WatchState(context.Background(), &lampcontrolv1.WatchStateRequest{})
+	require.NoError(t, err)
+
+	// The stream is open once the headers arrived
+	_, err = stream.Header()
+	require.NoError(t, err)
+
+	serverState.GetEventBroker().Close()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -0,0 +1,1484 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: lampcontrol/v1/lampcontrol.proto
+
+package lampcontrolv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Device struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Rssi          int32                  `protobuf:"varint,3,opt,name=rssi,proto3" json:"rssi,omitempty"`
+	Connected     bool                   `protobuf:"varint,4,opt,name=connected,proto3" json:"connected,omitempty"`
+	State         *DeviceState           `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	LastSeen      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Device) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Device) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Device) GetRssi() int32 {
+	if x != nil {
+		return x.Rssi
+	}
+	return 0
+}
+
+func (x *Device) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+func (x *Device) GetState() *DeviceState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *Device) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+type DeviceState struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	PowerOn    bool                   `protobuf:"varint,1,opt,name=power_on,json=powerOn,proto3" json:"power_on,omitempty"`
+	Brightness uint32                 `protobuf:"varint,2,opt,name=brightness,proto3" json:"brightness,omitempty"`
+	// Only the active color mode is set
+	Color         *Color                 `protobuf:"bytes,3,opt,name=color,proto3,oneof" json:"color,omitempty"`
+	WhiteBalance  *WhiteBalance          `protobuf:"bytes,4,opt,name=white_balance,json=whiteBalance,proto3,oneof" json:"white_balance,omitempty"`
+	Effect        *uint32                `protobuf:"varint,5,opt,name=effect,proto3,oneof" json:"effect,omitempty"`
+	EffectSpeed   *uint32                `protobuf:"varint,6,opt,name=effect_speed,json=effectSpeed,proto3,oneof" json:"effect_speed,omitempty"`
+	LastUpdated   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceState) Reset() {
+	*x = DeviceState{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceState) ProtoMessage() {}
+
+func (x *DeviceState) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceState.ProtoReflect.Descriptor instead.
+func (*DeviceState) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DeviceState) GetPowerOn() bool {
+	if x != nil {
+		return x.PowerOn
+	}
+	return false
+}
+
+func (x *DeviceState) GetBrightness() uint32 {
+	if x != nil {
+		return x.Brightness
+	}
+	return 0
+}
+
+func (x *DeviceState) GetColor() *Color {
+	if x != nil {
+		return x.Color
+	}
+	return nil
+}
+
+func (x *DeviceState) GetWhiteBalance() *WhiteBalance {
+	if x != nil {
+		return x.WhiteBalance
+	}
+	return nil
+}
+
+func (x *DeviceState) GetEffect() uint32 {
+	if x != nil && x.Effect != nil {
+		return *x.Effect
+	}
+	return 0
+}
+
+func (x *DeviceState) GetEffectSpeed() uint32 {
+	if x != nil && x.EffectSpeed != nil {
+		return *x.EffectSpeed
+	}
+	return 0
+}
+
+func (x *DeviceState) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+type Color struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	R             uint32                 `protobuf:"varint,1,opt,name=r,proto3" json:"r,omitempty"`
+	G             uint32                 `protobuf:"varint,2,opt,name=g,proto3" json:"g,omitempty"`
+	B             uint32                 `protobuf:"varint,3,opt,name=b,proto3" json:"b,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Color) Reset() {
+	*x = Color{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Color) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Color) ProtoMessage() {}
+
+func (x *Color) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Color.ProtoReflect.Descriptor instead.
+func (*Color) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Color) GetR() uint32 {
+	if x != nil {
+		return x.R
+	}
+	return 0
+}
+
+func (x *Color) GetG() uint32 {
+	if x != nil {
+		return x.G
+	}
+	return 0
+}
+
+func (x *Color) GetB() uint32 {
+	if x != nil {
+		return x.B
+	}
+	return 0
+}
+
+type WhiteBalance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Warm          uint32                 `protobuf:"varint,1,opt,name=warm,proto3" json:"warm,omitempty"`
+	Cold          uint32                 `protobuf:"varint,2,opt,name=cold,proto3" json:"cold,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WhiteBalance) Reset() {
+	*x = WhiteBalance{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WhiteBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WhiteBalance) ProtoMessage() {}
+
+func (x *WhiteBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WhiteBalance.ProtoReflect.Descriptor instead.
+func (*WhiteBalance) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WhiteBalance) GetWarm() uint32 {
+	if x != nil {
+		return x.Warm
+	}
+	return 0
+}
+
+func (x *WhiteBalance) GetCold() uint32 {
+	if x != nil {
+		return x.Cold
+	}
+	return 0
+}
+
+type ListDevicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesRequest) Reset() {
+	*x = ListDevicesRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesRequest) ProtoMessage() {}
+
+func (x *ListDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{4}
+}
+
+type ListDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesResponse) Reset() {
+	*x = ListDevicesResponse{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesResponse) ProtoMessage() {}
+
+func (x *ListDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListDevicesResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+type ScanRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to 10 seconds
+	TimeoutSeconds uint32 `protobuf:"varint,1,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ScanRequest) Reset() {
+	*x = ScanRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanRequest) ProtoMessage() {}
+
+func (x *ScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanRequest.ProtoReflect.Descriptor instead.
+func (*ScanRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ScanRequest) GetTimeoutSeconds() uint32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+type ScanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanResponse) Reset() {
+	*x = ScanResponse{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanResponse) ProtoMessage() {}
+
+func (x *ScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanResponse.ProtoReflect.Descriptor instead.
+func (*ScanResponse) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ScanResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+type SelectDeviceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectDeviceRequest) Reset() {
+	*x = SelectDeviceRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectDeviceRequest) ProtoMessage() {}
+
+func (x *SelectDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectDeviceRequest.ProtoReflect.Descriptor instead.
+func (*SelectDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SelectDeviceRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type SetPowerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	On            bool                   `protobuf:"varint,2,opt,name=on,proto3" json:"on,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPowerRequest) Reset() {
+	*x = SetPowerRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPowerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPowerRequest) ProtoMessage() {}
+
+func (x *SetPowerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPowerRequest.ProtoReflect.Descriptor instead.
+func (*SetPowerRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SetPowerRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *SetPowerRequest) GetOn() bool {
+	if x != nil {
+		return x.On
+	}
+	return false
+}
+
+type SetColorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Color         *Color                 `protobuf:"bytes,2,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetColorRequest) Reset() {
+	*x = SetColorRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetColorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetColorRequest) ProtoMessage() {}
+
+func (x *SetColorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetColorRequest.ProtoReflect.Descriptor instead.
+func (*SetColorRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SetColorRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *SetColorRequest) GetColor() *Color {
+	if x != nil {
+		return x.Color
+	}
+	return nil
+}
+
+type SetBrightnessRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Level         uint32                 `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetBrightnessRequest) Reset() {
+	*x = SetBrightnessRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetBrightnessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetBrightnessRequest) ProtoMessage() {}
+
+func (x *SetBrightnessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetBrightnessRequest.ProtoReflect.Descriptor instead.
+func (*SetBrightnessRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetBrightnessRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *SetBrightnessRequest) GetLevel() uint32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+type SetWhiteBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Warm          uint32                 `protobuf:"varint,2,opt,name=warm,proto3" json:"warm,omitempty"`
+	Cold          uint32                 `protobuf:"varint,3,opt,name=cold,proto3" json:"cold,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetWhiteBalanceRequest) Reset() {
+	*x = SetWhiteBalanceRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetWhiteBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetWhiteBalanceRequest) ProtoMessage() {}
+
+func (x *SetWhiteBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetWhiteBalanceRequest.ProtoReflect.Descriptor instead.
+func (*SetWhiteBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SetWhiteBalanceRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *SetWhiteBalanceRequest) GetWarm() uint32 {
+	if x != nil {
+		return x.Warm
+	}
+	return 0
+}
+
+func (x *SetWhiteBalanceRequest) GetCold() uint32 {
+	if x != nil {
+		return x.Cold
+	}
+	return 0
+}
+
+type SetEffectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Effect        uint32                 `protobuf:"varint,2,opt,name=effect,proto3" json:"effect,omitempty"`
+	Speed         uint32                 `protobuf:"varint,3,opt,name=speed,proto3" json:"speed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEffectRequest) Reset() {
+	*x = SetEffectRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEffectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEffectRequest) ProtoMessage() {}
+
+func (x *SetEffectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEffectRequest.ProtoReflect.Descriptor instead.
+func (*SetEffectRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SetEffectRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *SetEffectRequest) GetEffect() uint32 {
+	if x != nil {
+		return x.Effect
+	}
+	return 0
+}
+
+func (x *SetEffectRequest) GetSpeed() uint32 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+type OverrideRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Who asked, shown in the UI. Defaults to "streamer".
+	By            string `protobuf:"bytes,1,opt,name=by,proto3" json:"by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OverrideRequest) Reset() {
+	*x = OverrideRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OverrideRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OverrideRequest) ProtoMessage() {}
+
+func (x *OverrideRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OverrideRequest.ProtoReflect.Descriptor instead.
+func (*OverrideRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *OverrideRequest) GetBy() string {
+	if x != nil {
+		return x.By
+	}
+	return ""
+}
+
+type Override struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Locked        bool                   `protobuf:"varint,1,opt,name=locked,proto3" json:"locked,omitempty"`
+	LockedBy      string                 `protobuf:"bytes,2,opt,name=locked_by,json=lockedBy,proto3" json:"locked_by,omitempty"`
+	LockedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=locked_at,json=lockedAt,proto3" json:"locked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Override) Reset() {
+	*x = Override{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Override) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Override) ProtoMessage() {}
+
+func (x *Override) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Override.ProtoReflect.Descriptor instead.
+func (*Override) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Override) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+func (x *Override) GetLockedBy() string {
+	if x != nil {
+		return x.LockedBy
+	}
+	return ""
+}
+
+func (x *Override) GetLockedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LockedAt
+	}
+	return nil
+}
+
+type Scene struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name   string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Colors []*Color               `protobuf:"bytes,3,rep,name=colors,proto3" json:"colors,omitempty"`
+	// fade, strobe, jump or pulse
+	Pattern       string                 `protobuf:"bytes,4,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Speed         uint32                 `protobuf:"varint,5,opt,name=speed,proto3" json:"speed,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Scene) Reset() {
+	*x = Scene{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Scene) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Scene) ProtoMessage() {}
+
+func (x *Scene) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Scene.ProtoReflect.Descriptor instead.
+func (*Scene) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Scene) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Scene) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Scene) GetColors() []*Color {
+	if x != nil {
+		return x.Colors
+	}
+	return nil
+}
+
+func (x *Scene) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *Scene) GetSpeed() uint32 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+func (x *Scene) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListScenesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScenesRequest) Reset() {
+	*x = ListScenesRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScenesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScenesRequest) ProtoMessage() {}
+
+func (x *ListScenesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScenesRequest.ProtoReflect.Descriptor instead.
+func (*ListScenesRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{17}
+}
+
+type ListScenesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scenes        []*Scene               `protobuf:"bytes,1,rep,name=scenes,proto3" json:"scenes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScenesResponse) Reset() {
+	*x = ListScenesResponse{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScenesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScenesResponse) ProtoMessage() {}
+
+func (x *ListScenesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScenesResponse.ProtoReflect.Descriptor instead.
+func (*ListScenesResponse) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListScenesResponse) GetScenes() []*Scene {
+	if x != nil {
+		return x.Scenes
+	}
+	return nil
+}
+
+type CreateSceneRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Colors        []*Color               `protobuf:"bytes,2,rep,name=colors,proto3" json:"colors,omitempty"`
+	Pattern       string                 `protobuf:"bytes,3,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Speed         uint32                 `protobuf:"varint,4,opt,name=speed,proto3" json:"speed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSceneRequest) Reset() {
+	*x = CreateSceneRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSceneRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSceneRequest) ProtoMessage() {}
+
+func (x *CreateSceneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSceneRequest.ProtoReflect.Descriptor instead.
+func (*CreateSceneRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CreateSceneRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateSceneRequest) GetColors() []*Color {
+	if x != nil {
+		return x.Colors
+	}
+	return nil
+}
+
+func (x *CreateSceneRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *CreateSceneRequest) GetSpeed() uint32 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+type DeleteSceneRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSceneRequest) Reset() {
+	*x = DeleteSceneRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSceneRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSceneRequest) ProtoMessage() {}
+
+func (x *DeleteSceneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSceneRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSceneRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteSceneRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteSceneResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSceneResponse) Reset() {
+	*x = DeleteSceneResponse{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSceneResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSceneResponse) ProtoMessage() {}
+
+func (x *DeleteSceneResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSceneResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSceneResponse) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{21}
+}
+
+type WatchStateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Devices to watch, all devices if empty
+	Addresses     []string `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchStateRequest) Reset() {
+	*x = WatchStateRequest{}
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchStateRequest) ProtoMessage() {}
+
+func (x *WatchStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lampcontrol_v1_lampcontrol_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchStateRequest.ProtoReflect.Descriptor instead.
+func (*WatchStateRequest) Descriptor() ([]byte, []int) {
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *WatchStateRequest) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+var File_lampcontrol_v1_lampcontrol_proto protoreflect.FileDescriptor
+
+const file_lampcontrol_v1_lampcontrol_proto_rawDesc = "" +
+	"\n" +
+	" lampcontrol/v1/lampcontrol.proto\x12\x0elampcontrol.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd4\x01\n" +
+	"\x06Device\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04rssi\x18\x03 \x01(\x05R\x04rssi\x12\x1c\n" +
+	"\tconnected\x18\x04 \x01(\bR\tconnected\x121\n" +
+	"\x05state\x18\x05 \x01(\v2\x1b.lampcontrol.v1.DeviceStateR\x05state\x127\n" +
+	"\tlast_seen\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\"\xfe\x02\n" +
+	"\vDeviceState\x12\x19\n" +
+	"\bpower_on\x18\x01 \x01(\bR\apowerOn\x12\x1e\n" +
+	"\n" +
+	"brightness\x18\x02 \x01(\rR\n" +
+	"brightness\x120\n" +
+	"\x05color\x18\x03 \x01(\v2\x15.lampcontrol.v1.ColorH\x00R\x05color\x88\x01\x01\x12F\n" +
+	"\rwhite_balance\x18\x04 \x01(\v2\x1c.lampcontrol.v1.WhiteBalanceH\x01R\fwhiteBalance\x88\x01\x01\x12\x1b\n" +
+	"\x06effect\x18\x05 \x01(\rH\x02R\x06effect\x88\x01\x01\x12&\n" +
+	"\feffect_speed\x18\x06 \x01(\rH\x03R\veffectSpeed\x88\x01\x01\x12=\n" +
+	"\flast_updated\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vlastUpdatedB\b\n" +
+	"\x06_colorB\x10\n" +
+	"\x0e_white_balanceB\t\n" +
+	"\a_effectB\x0f\n" +
+	"\r_effect_speed\"1\n" +
+	"\x05Color\x12\f\n" +
+	"\x01r\x18\x01 \x01(\rR\x01r\x12\f\n" +
+	"\x01g\x18\x02 \x01(\rR\x01g\x12\f\n" +
+	"\x01b\x18\x03 \x01(\rR\x01b\"6\n" +
+	"\fWhiteBalance\x12\x12\n" +
+	"\x04warm\x18\x01 \x01(\rR\x04warm\x12\x12\n" +
+	"\x04cold\x18\x02 \x01(\rR\x04cold\"\x14\n" +
+	"\x12ListDevicesRequest\"G\n" +
+	"\x13ListDevicesResponse\x120\n" +
+	"\adevices\x18\x01 \x03(\v2\x16.lampcontrol.v1.DeviceR\adevices\"6\n" +
+	"\vScanRequest\x12'\n" +
+	"\x0ftimeout_seconds\x18\x01 \x01(\rR\x0etimeoutSeconds\"@\n" +
+	"\fScanResponse\x120\n" +
+	"\adevices\x18\x01 \x03(\v2\x16.lampcontrol.v1.DeviceR\adevices\"/\n" +
+	"\x13SelectDeviceRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\";\n" +
+	"\x0fSetPowerRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x0e\n" +
+	"\x02on\x18\x02 \x01(\bR\x02on\"X\n" +
+	"\x0fSetColorRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12+\n" +
+	"\x05color\x18\x02 \x01(\v2\x15.lampcontrol.v1.ColorR\x05color\"F\n" +
+	"\x14SetBrightnessRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\rR\x05level\"Z\n" +
+	"\x16SetWhiteBalanceRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x12\n" +
+	"\x04warm\x18\x02 \x01(\rR\x04warm\x12\x12\n" +
+	"\x04cold\x18\x03 \x01(\rR\x04cold\"Z\n" +
+	"\x10SetEffectRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06effect\x18\x02 \x01(\rR\x06effect\x12\x14\n" +
+	"\x05speed\x18\x03 \x01(\rR\x05speed\"!\n" +
+	"\x0fOverrideRequest\x12\x0e\n" +
+	"\x02by\x18\x01 \x01(\tR\x02by\"x\n" +
+	"\bOverride\x12\x16\n" +
+	"\x06locked\x18\x01 \x01(\bR\x06locked\x12\x1b\n" +
+	"\tlocked_by\x18\x02 \x01(\tR\blockedBy\x127\n" +
+	"\tlocked_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\blockedAt\"\xc5\x01\n" +
+	"\x05Scene\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12-\n" +
+	"\x06colors\x18\x03 \x03(\v2\x15.lampcontrol.v1.ColorR\x06colors\x12\x18\n" +
+	"\apattern\x18\x04 \x01(\tR\apattern\x12\x14\n" +
+	"\x05speed\x18\x05 \x01(\rR\x05speed\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x13\n" +
+	"\x11ListScenesRequest\"C\n" +
+	"\x12ListScenesResponse\x12-\n" +
+	"\x06scenes\x18\x01 \x03(\v2\x15.lampcontrol.v1.SceneR\x06scenes\"\x87\x01\n" +
+	"\x12CreateSceneRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12-\n" +
+	"\x06colors\x18\x02 \x03(\v2\x15.lampcontrol.v1.ColorR\x06colors\x12\x18\n" +
+	"\apattern\x18\x03 \x01(\tR\apattern\x12\x14\n" +
+	"\x05speed\x18\x04 \x01(\rR\x05speed\"$\n" +
+	"\x12DeleteSceneRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x15\n" +
+	"\x13DeleteSceneResponse\"1\n" +
+	"\x11WatchStateRequest\x12\x1c\n" +
+	"\taddresses\x18\x01 \x03(\tR\taddresses2\xf6\b\n" +
+	"\vLampControl\x12V\n" +
+	"\vListDevices\x12\".lampcontrol.v1.ListDevicesRequest\x1a#.lampcontrol.v1.ListDevicesResponse\x12A\n" +
+	"\x04Scan\x12\x1b.lampcontrol.v1.ScanRequest\x1a\x1c.lampcontrol.v1.ScanResponse\x12K\n" +
+	"\fSelectDevice\x12#.lampcontrol.v1.SelectDeviceRequest\x1a\x16.lampcontrol.v1.Device\x12C\n" +
+	"\bSetPower\x12\x1f.lampcontrol.v1.SetPowerRequest\x1a\x16.lampcontrol.v1.Device\x12C\n" +
+	"\bSetColor\x12\x1f.lampcontrol.v1.SetColorRequest\x1a\x16.lampcontrol.v1.Device\x12M\n" +
+	"\rSetBrightness\x12$.lampcontrol.v1.SetBrightnessRequest\x1a\x16.lampcontrol.v1.Device\x12Q\n" +
+	"\x0fSetWhiteBalance\x12&.lampcontrol.v1.SetWhiteBalanceRequest\x1a\x16.lampcontrol.v1.Device\x12E\n" +
+	"\tSetEffect\x12 .lampcontrol.v1.SetEffectRequest\x1a\x16.lampcontrol.v1.Device\x12A\n" +
+	"\x04Lock\x12\x1f.lampcontrol.v1.OverrideRequest\x1a\x18.lampcontrol.v1.Override\x12C\n" +
+	"\x06Unlock\x12\x1f.lampcontrol.v1.OverrideRequest\x1a\x18.lampcontrol.v1.Override\x12B\n" +
+	"\x05Panic\x12\x1f.lampcontrol.v1.OverrideRequest\x1a\x18.lampcontrol.v1.Override\x12S\n" +
+	"\n" +
+	"ListScenes\x12!.lampcontrol.v1.ListScenesRequest\x1a\".lampcontrol.v1.ListScenesResponse\x12H\n" +
+	"\vCreateScene\x12\".lampcontrol.v1.CreateSceneRequest\x1a\x15.lampcontrol.v1.Scene\x12V\n" +
+	"\vDeleteScene\x12\".lampcontrol.v1.DeleteSceneRequest\x1a#.lampcontrol.v1.DeleteSceneResponse\x12I\n" +
+	"\n" +
+	"WatchState\x12!.lampcontrol.v1.WatchStateRequest\x1a\x16.lampcontrol.v1.Device0\x01BBZ@github.com/codeneuss/lampcontrol/pkg/lampcontrolv1;lampcontrolv1b\x06proto3"
+
+var (
+	file_lampcontrol_v1_lampcontrol_proto_rawDescOnce sync.Once
+	file_lampcontrol_v1_lampcontrol_proto_rawDescData []byte
+)
+
+func file_lampcontrol_v1_lampcontrol_proto_rawDescGZIP() []byte {
+	file_lampcontrol_v1_lampcontrol_proto_rawDescOnce.Do(func() {
+		file_lampcontrol_v1_lampcontrol_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lampcontrol_v1_lampcontrol_proto_rawDesc), len(file_lampcontrol_v1_lampcontrol_proto_rawDesc)))
+	})
+	return file_lampcontrol_v1_lampcontrol_proto_rawDescData
+}
+
+var file_lampcontrol_v1_lampcontrol_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_lampcontrol_v1_lampcontrol_proto_goTypes = []any{
+	(*Device)(nil),                 // 0: lampcontrol.v1.Device
+	(*DeviceState)(nil),            // 1: lampcontrol.v1.DeviceState
+	(*Color)(nil),                  // 2: lampcontrol.v1.Color
+	(*WhiteBalance)(nil),           // 3: lampcontrol.v1.WhiteBalance
+	(*ListDevicesRequest)(nil),     // 4: lampcontrol.v1.ListDevicesRequest
+	(*ListDevicesResponse)(nil),    // 5: lampcontrol.v1.ListDevicesResponse
+	(*ScanRequest)(nil),            // 6: lampcontrol.v1.ScanRequest
+	(*ScanResponse)(nil),           // 7: lampcontrol.v1.ScanResponse
+	(*SelectDeviceRequest)(nil),    // 8: lampcontrol.v1.SelectDeviceRequest
+	(*SetPowerRequest)(nil),        // 9: lampcontrol.v1.SetPowerRequest
+	(*SetColorRequest)(nil),        // 10: lampcontrol.v1.SetColorRequest
+	(*SetBrightnessRequest)(nil),   // 11: lampcontrol.v1.SetBrightnessRequest
+	(*SetWhiteBalanceRequest)(nil), // 12: lampcontrol.v1.SetWhiteBalanceRequest
+	(*SetEffectRequest)(nil),       // 13: lampcontrol.v1.SetEffectRequest
+	(*OverrideRequest)(nil),        // 14: lampcontrol.v1.OverrideRequest
+	(*Override)(nil),               // 15: lampcontrol.v1.Override
+	(*Scene)(nil),                  // 16: lampcontrol.v1.Scene
+	(*ListScenesRequest)(nil),      // 17: lampcontrol.v1.ListScenesRequest
+	(*ListScenesResponse)(nil),     // 18: lampcontrol.v1.ListScenesResponse
+	(*CreateSceneRequest)(nil),     // 19: lampcontrol.v1.CreateSceneRequest
+	(*DeleteSceneRequest)(nil),     // 20: lampcontrol.v1.DeleteSceneRequest
+	(*DeleteSceneResponse)(nil),    // 21: lampcontrol.v1.DeleteSceneResponse
+	(*WatchStateRequest)(nil),      // 22: lampcontrol.v1.WatchStateRequest
+	(*timestamppb.Timestamp)(nil),  // 23: google.protobuf.Timestamp
+}
+var file_lampcontrol_v1_lampcontrol_proto_depIdxs = []int32{
+	1,  // 0: lampcontrol.v1.Device.state:type_name -> lampcontrol.v1.DeviceState
+	23, // 1: lampcontrol.v1.Device.last_seen:type_name -> google.protobuf.Timestamp
+	2,  // 2: lampcontrol.v1.DeviceState.color:type_name -> lampcontrol.v1.Color
+	3,  // 3: lampcontrol.v1.DeviceState.white_balance:type_name -> lampcontrol.v1.WhiteBalance
+	23, // 4: lampcontrol.v1.DeviceState.last_updated:type_name -> google.protobuf.Timestamp
+	0,  // 5: lampcontrol.v1.ListDevicesResponse.devices:type_name -> lampcontrol.v1.Device
+	0,  // 6: lampcontrol.v1.ScanResponse.devices:type_name -> lampcontrol.v1.Device
+	2,  // 7: lampcontrol.v1.SetColorRequest.color:type_name -> lampcontrol.v1.Color
+	23, // 8: lampcontrol.v1.Override.locked_at:type_name -> google.protobuf.Timestamp
+	2,  // 9: lampcontrol.v1.Scene.colors:type_name -> lampcontrol.v1.Color
+	23, // 10: lampcontrol.v1.Scene.created_at:type_name -> google.protobuf.Timestamp
+	16, // 11: lampcontrol.v1.ListScenesResponse.scenes:type_name -> lampcontrol.v1.Scene
+	2,  // 12: lampcontrol.v1.CreateSceneRequest.colors:type_name -> lampcontrol.v1.Color
+	4,  // 13: lampcontrol.v1.LampControl.ListDevices:input_type -> lampcontrol.v1.ListDevicesRequest
+	6,  // 14: lampcontrol.v1.LampControl.Scan:input_type -> lampcontrol.v1.ScanRequest
+	8,  // 15: lampcontrol.v1.LampControl.SelectDevice:input_type -> lampcontrol.v1.SelectDeviceRequest
+	9,  // 16: lampcontrol.v1.LampControl.SetPower:input_type -> lampcontrol.v1.SetPowerRequest
+	10, // 17: lampcontrol.v1.LampControl.SetColor:input_type -> lampcontrol.v1.SetColorRequest
+	11, // 18: lampcontrol.v1.LampControl.SetBrightness:input_type -> lampcontrol.v1.SetBrightnessRequest
+	12, // 19: lampcontrol.v1.LampControl.SetWhiteBalance:input_type -> lampcontrol.v1.SetWhiteBalanceRequest
+	13, // 20: lampcontrol.v1.LampControl.SetEffect:input_type -> lampcontrol.v1.SetEffectRequest
+	14, // 21: lampcontrol.v1.LampControl.Lock:input_type -> lampcontrol.v1.OverrideRequest
+	14, // 22: lampcontrol.v1.LampControl.Unlock:input_type -> lampcontrol.v1.OverrideRequest
+	14, // 23: lampcontrol.v1.LampControl.Panic:input_type -> lampcontrol.v1.OverrideRequest
+	17, // 24: lampcontrol.v1.LampControl.ListScenes:input_type -> lampcontrol.v1.ListScenesRequest
+	19, // 25: lampcontrol.v1.LampControl.CreateScene:input_type -> lampcontrol.v1.CreateSceneRequest
+	20, // 26: lampcontrol.v1.LampControl.DeleteScene:input_type -> lampcontrol.v1.DeleteSceneRequest
+	22, // 27: lampcontrol.v1.LampControl.WatchState:input_type -> lampcontrol.v1.WatchStateRequest
+	5,  // 28: lampcontrol.v1.LampControl.ListDevices:output_type -> lampcontrol.v1.ListDevicesResponse
+	7,  // 29: lampcontrol.v1.LampControl.Scan:output_type -> lampcontrol.v1.ScanResponse
+	0,  // 30: lampcontrol.v1.LampControl.SelectDevice:output_type -> lampcontrol.v1.Device
+	0,  // 31: lampcontrol.v1.LampControl.SetPower:output_type -> lampcontrol.v1.Device
+	0,  // 32: lampcontrol.v1.LampControl.SetColor:output_type -> lampcontrol.v1.Device
+	0,  // 33: lampcontrol.v1.LampControl.SetBrightness:output_type -> lampcontrol.v1.Device
+	0,  // 34: lampcontrol.v1.LampControl.SetWhiteBalance:output_type -> lampcontrol.v1.Device
+	0,  // 35: lampcontrol.v1.LampControl.SetEffect:output_type -> lampcontrol.v1.Device
+	15, // 36: lampcontrol.v1.LampControl.Lock:output_type -> lampcontrol.v1.Override
+	15, // 37: lampcontrol.v1.LampControl.Unlock:output_type -> lampcontrol.v1.Override
+	15, // 38: lampcontrol.v1.LampControl.Panic:output_type -> lampcontrol.v1.Override
+	18, // 39: lampcontrol.v1.LampControl.ListScenes:output_type -> lampcontrol.v1.ListScenesResponse
+	16, // 40: lampcontrol.v1.LampControl.CreateScene:output_type -> lampcontrol.v1.Scene
+	21, // 41: lampcontrol.v1.LampControl.DeleteScene:output_type -> lampcontrol.v1.DeleteSceneResponse
+	0,  // 42: lampcontrol.v1.LampControl.WatchState:output_type -> lampcontrol.v1.Device
+	28, // [28:43] is the sub-list for method output_type
+	13, // [13:28] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_lampcontrol_v1_lampcontrol_proto_init() }
+func file_lampcontrol_v1_lampcontrol_proto_init() {
+	if File_lampcontrol_v1_lampcontrol_proto != nil {
+		return
+	}
+	file_lampcontrol_v1_lampcontrol_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lampcontrol_v1_lampcontrol_proto_rawDesc), len(file_lampcontrol_v1_lampcontrol_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   23,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lampcontrol_v1_lampcontrol_proto_goTypes,
+		DependencyIndexes: file_lampcontrol_v1_lampcontrol_proto_depIdxs,
+		MessageInfos:      file_lampcontrol_v1_lampcontrol_proto_msgTypes,
+	}.Build()
+	File_lampcontrol_v1_lampcontrol_proto = out.File
+	file_lampcontrol_v1_lampcontrol_proto_goTypes = nil
+	file_lampcontrol_v1_lampcontrol_proto_depIdxs = nil
+}
@@ -0,0 +1,685 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: lampcontrol/v1/lampcontrol.proto
+
+package lampcontrolv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LampControl_ListDevices_FullMethodName     = "/lampcontrol.v1.LampControl/ListDevices"
+	LampControl_Scan_FullMethodName            = "/lampcontrol.v1.LampControl/Scan"
+	LampControl_SelectDevice_FullMethodName    = "/lampcontrol.v1.LampControl/SelectDevice"
+	LampControl_SetPower_FullMethodName        = "/lampcontrol.v1.LampControl/SetPower"
+	LampControl_SetColor_FullMethodName        = "/lampcontrol.v1.LampControl/SetColor"
+	LampControl_SetBrightness_FullMethodName   = "/lampcontrol.v1.LampControl/SetBrightness"
+	LampControl_SetWhiteBalance_FullMethodName = "/lampcontrol.v1.LampControl/SetWhiteBalance"
+	LampControl_SetEffect_FullMethodName       = "/lampcontrol.v1.LampControl/SetEffect"
+	LampControl_Lock_FullMethodName            = "/lampcontrol.v1.LampControl/Lock"
+	LampControl_Unlock_FullMethodName          = "/lampcontrol.v1.LampControl/Unlock"
+	LampControl_Panic_FullMethodName           = "/lampcontrol.v1.LampControl/Panic"
+	LampControl_ListScenes_FullMethodName      = "/lampcontrol.v1.LampControl/ListScenes"
+	LampControl_CreateScene_FullMethodName     = "/lampcontrol.v1.LampControl/CreateScene"
+	LampControl_DeleteScene_FullMethodName     = "/lampcontrol.v1.LampControl/DeleteScene"
+	LampControl_WatchState_FullMethodName      = "/lampcontrol.v1.LampControl/WatchState"
+)
+
+// LampControlClient is the client API for LampControl service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LampControl controls the lamps of a running `lamp web` daemon. Control
+// actions act for the streamer, like the web UI: they pass the safety filter
+// and become the state restored after viewer effects. An empty address picks
+// the device selected in the daemon.
+type LampControlClient interface {
+	// ListDevices returns the devices found so far
+	ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*ListDevicesResponse, error)
+	// Scan scans for devices and returns the ones found
+	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error)
+	// SelectDevice selects the device used when no address is given
+	SelectDevice(ctx context.Context, in *SelectDeviceRequest, opts ...grpc.CallOption) (*Device, error)
+	SetPower(ctx context.Context, in *SetPowerRequest, opts ...grpc.CallOption) (*Device, error)
+	SetColor(ctx context.Context, in *SetColorRequest, opts ...grpc.CallOption) (*Device, error)
+	SetBrightness(ctx context.Context, in *SetBrightnessRequest, opts ...grpc.CallOption) (*Device, error)
+	SetWhiteBalance(ctx context.Context, in *SetWhiteBalanceRequest, opts ...grpc.CallOption) (*Device, error)
+	SetEffect(ctx context.Context, in *SetEffectRequest, opts ...grpc.CallOption) (*Device, error)
+	// Lock stops viewer commands, Unlock allows them again
+	Lock(ctx context.Context, in *OverrideRequest, opts ...grpc.CallOption) (*Override, error)
+	Unlock(ctx context.Context, in *OverrideRequest, opts ...grpc.CallOption) (*Override, error)
+	// Panic cancels viewer effects, alerts and polls, locks and applies the safe scene
+	Panic(ctx context.Context, in *OverrideRequest, opts ...grpc.CallOption) (*Override, error)
+	// Scenes are the saved custom effects of the web UI
+	ListScenes(ctx context.Context, in *ListScenesRequest, opts ...grpc.CallOption) (*ListScenesResponse, error)
+	CreateScene(ctx context.Context, in *CreateSceneRequest, opts ...grpc.CallOption) (*Scene, error)
+	DeleteScene(ctx context.Context, in *DeleteSceneRequest, opts ...grpc.CallOption) (*DeleteSceneResponse, error)
+	// WatchState sends the watched devices, then each device again when its
+	// state or connection changes. The stream ends with UNAVAILABLE if the
+	// client falls behind or the daemon shuts down.
+	WatchState(ctx context.Context, in *WatchStateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Device], error)
+}
+
+type lampControlClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLampControlClient(cc grpc.ClientConnInterface) LampControlClient {
+	return &lampControlClient{cc}
+}
+
+func (c *lampControlClient) ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*ListDevicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDevicesResponse)
+	err := c.cc.Invoke(ctx, LampControl_ListDevices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScanResponse)
+	err := c.cc.Invoke(ctx, LampControl_Scan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) SelectDevice(ctx context.Context, in *SelectDeviceRequest, opts ...grpc.CallOption) (*Device, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Device)
+	err := c.cc.Invoke(ctx, LampControl_SelectDevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) SetPower(ctx context.Context, in *SetPowerRequest, opts ...grpc.CallOption) (*Device, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Device)
+	err := c.cc.Invoke(ctx, LampControl_SetPower_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) SetColor(ctx context.Context, in *SetColorRequest, opts ...grpc.CallOption) (*Device, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Device)
+	err := c.cc.Invoke(ctx, LampControl_SetColor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) SetBrightness(ctx context.Context, in *SetBrightnessRequest, opts ...grpc.CallOption) (*Device, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Device)
+	err := c.cc.Invoke(ctx, LampControl_SetBrightness_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) SetWhiteBalance(ctx context.Context, in *SetWhiteBalanceRequest, opts ...grpc.CallOption) (*Device, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Device)
+	err := c.cc.Invoke(ctx, LampControl_SetWhiteBalance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) SetEffect(ctx context.Context, in *SetEffectRequest, opts ...grpc.CallOption) (*Device, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Device)
+	err := c.cc.Invoke(ctx, LampControl_SetEffect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) Lock(ctx context.Context, in *OverrideRequest, opts ...grpc.CallOption) (*Override, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Override)
+	err := c.cc.Invoke(ctx, LampControl_Lock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) Unlock(ctx context.Context, in *OverrideRequest, opts ...grpc.CallOption) (*Override, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Override)
+	err := c.cc.Invoke(ctx, LampControl_Unlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) Panic(ctx context.Context, in *OverrideRequest, opts ...grpc.CallOption) (*Override, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Override)
+	err := c.cc.Invoke(ctx, LampControl_Panic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) ListScenes(ctx context.Context, in *ListScenesRequest, opts ...grpc.CallOption) (*ListScenesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScenesResponse)
+	err := c.cc.Invoke(ctx, LampControl_ListScenes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) CreateScene(ctx context.Context, in *CreateSceneRequest, opts ...grpc.CallOption) (*Scene, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Scene)
+	err := c.cc.Invoke(ctx, LampControl_CreateScene_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) DeleteScene(ctx context.Context, in *DeleteSceneRequest, opts ...grpc.CallOption) (*DeleteSceneResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSceneResponse)
+	err := c.cc.Invoke(ctx, LampControl_DeleteScene_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lampControlClient) WatchState(ctx context.Context, in *WatchStateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Device], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LampControl_ServiceDesc.Streams[0], LampControl_WatchState_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchStateRequest, Device]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LampControl_WatchStateClient = grpc.ServerStreamingClient[Device]
+
+// LampControlServer is the server API for LampControl service.
+// All implementations must embed UnimplementedLampControlServer
+// for forward compatibility.
+//
+// LampControl controls the lamps of a running `lamp web` daemon. Control
+// actions act for the streamer, like the web UI: they pass the safety filter
+// and become the state restored after viewer effects. An empty address picks
+// the device selected in the daemon.
+type LampControlServer interface {
+	// ListDevices returns the devices found so far
+	ListDevices(context.Context, *ListDevicesRequest) (*ListDevicesResponse, error)
+	// Scan scans for devices and returns the ones found
+	Scan(context.Context, *ScanRequest) (*ScanResponse, error)
+	// SelectDevice selects the device used when no address is given
+	SelectDevice(context.Context, *SelectDeviceRequest) (*Device, error)
+	SetPower(context.Context, *SetPowerRequest) (*Device, error)
+	SetColor(context.Context, *SetColorRequest) (*Device, error)
+	SetBrightness(context.Context, *SetBrightnessRequest) (*Device, error)
+	SetWhiteBalance(context.Context, *SetWhiteBalanceRequest) (*Device, error)
+	SetEffect(context.Context, *SetEffectRequest) (*Device, error)
+	// Lock stops viewer commands, Unlock allows them again
+	Lock(context.Context, *OverrideRequest) (*Override, error)
+	Unlock(context.Context, *OverrideRequest) (*Override, error)
+	// Panic cancels viewer effects, alerts and polls, locks and applies the safe scene
+	Panic(context.Context, *OverrideRequest) (*Override, error)
+	// Scenes are the saved custom effects of the web UI
+	ListScenes(context.Context, *ListScenesRequest) (*ListScenesResponse, error)
+	CreateScene(context.Context, *CreateSceneRequest) (*Scene, error)
+	DeleteScene(context.Context, *DeleteSceneRequest) (*DeleteSceneResponse, error)
+	// WatchState sends the watched devices, then each device again when its
+	// state or connection changes. The stream ends with UNAVAILABLE if the
+	// client falls behind or the daemon shuts down.
+	WatchState(*WatchStateRequest, grpc.ServerStreamingServer[Device]) error
+	mustEmbedUnimplementedLampControlServer()
+}
+
+// UnimplementedLampControlServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLampControlServer struct{}
+
+func (UnimplementedLampControlServer) ListDevices(context.Context, *ListDevicesRequest) (*ListDevicesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListDevices not implemented")
+}
+func (UnimplementedLampControlServer) Scan(context.Context, *ScanRequest) (*ScanResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Scan not implemented")
+}
+func (UnimplementedLampControlServer) SelectDevice(context.Context, *SelectDeviceRequest) (*Device, error) {
+	return nil, status.Error(codes.Unimplemented, "method SelectDevice not implemented")
+}
+func (UnimplementedLampControlServer) SetPower(context.Context, *SetPowerRequest) (*Device, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetPower not implemented")
+}
+func (UnimplementedLampControlServer) SetColor(context.Context, *SetColorRequest) (*Device, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetColor not implemented")
+}
+func (UnimplementedLampControlServer) SetBrightness(context.Context, *SetBrightnessRequest) (*Device, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetBrightness not implemented")
+}
+func (UnimplementedLampControlServer) SetWhiteBalance(context.Context, *SetWhiteBalanceRequest) (*Device, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetWhiteBalance not implemented")
+}
+func (UnimplementedLampControlServer) SetEffect(context.Context, *SetEffectRequest) (*Device, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetEffect not implemented")
+}
+func (UnimplementedLampControlServer) Lock(context.Context, *OverrideRequest) (*Override, error) {
+	return nil, status.Error(codes.Unimplemented, "method Lock not implemented")
+}
+func (UnimplementedLampControlServer) Unlock(context.Context, *OverrideRequest) (*Override, error) {
+	return nil, status.Error(codes.Unimplemented, "method Unlock not implemented")
+}
+func (UnimplementedLampControlServer) Panic(context.Context, *OverrideRequest) (*Override, error) {
+	return nil, status.Error(codes.Unimplemented, "method Panic not implemented")
+}
+func (UnimplementedLampControlServer) ListScenes(context.Context, *ListScenesRequest) (*ListScenesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListScenes not implemented")
+}
+func (UnimplementedLampControlServer) CreateScene(context.Context, *CreateSceneRequest) (*Scene, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateScene not implemented")
+}
+func (UnimplementedLampControlServer) DeleteScene(context.Context, *DeleteSceneRequest) (*DeleteSceneResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteScene not implemented")
+}
+func (UnimplementedLampControlServer) WatchState(*WatchStateRequest, grpc.ServerStreamingServer[Device]) error {
+	return status.Error(codes.Unimplemented, "method WatchState not implemented")
+}
+func (UnimplementedLampControlServer) mustEmbedUnimplementedLampControlServer() {}
+func (UnimplementedLampControlServer) testEmbeddedByValue()                     {}
+
+// UnsafeLampControlServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LampControlServer will
+// result in compilation errors.
+type UnsafeLampControlServer interface {
+	mustEmbedUnimplementedLampControlServer()
+}
+
+func RegisterLampControlServer(s grpc.ServiceRegistrar, srv LampControlServer) {
+	// If the following call panics, it indicates UnimplementedLampControlServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LampControl_ServiceDesc, srv)
+}
+
+func _LampControl_ListDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).ListDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_ListDevices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).ListDevices(ctx, req.(*ListDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_Scan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).Scan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_Scan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).Scan(ctx, req.(*ScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_SelectDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelectDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).SelectDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_SelectDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).SelectDevice(ctx, req.(*SelectDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_SetPower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPowerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).SetPower(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_SetPower_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).SetPower(ctx, req.(*SetPowerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_SetColor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetColorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).SetColor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_SetColor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).SetColor(ctx, req.(*SetColorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_SetBrightness_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBrightnessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).SetBrightness(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_SetBrightness_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).SetBrightness(ctx, req.(*SetBrightnessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_SetWhiteBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetWhiteBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).SetWhiteBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_SetWhiteBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).SetWhiteBalance(ctx, req.(*SetWhiteBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_SetEffect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEffectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).SetEffect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_SetEffect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).SetEffect(ctx, req.(*SetEffectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_Lock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OverrideRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).Lock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_Lock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).Lock(ctx, req.(*OverrideRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_Unlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OverrideRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).Unlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_Unlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).Unlock(ctx, req.(*OverrideRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_Panic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OverrideRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).Panic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_Panic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).Panic(ctx, req.(*OverrideRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_ListScenes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScenesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).ListScenes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_ListScenes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).ListScenes(ctx, req.(*ListScenesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_CreateScene_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSceneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).CreateScene(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_CreateScene_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).CreateScene(ctx, req.(*CreateSceneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_DeleteScene_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSceneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LampControlServer).DeleteScene(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LampControl_DeleteScene_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LampControlServer).DeleteScene(ctx, req.(*DeleteSceneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LampControl_WatchState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchStateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LampControlServer).WatchState(m, &grpc.GenericServerStream[WatchStateRequest, Device]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LampControl_WatchStateServer = grpc.ServerStreamingServer[Device]
+
+// LampControl_ServiceDesc is the grpc.ServiceDesc for LampControl service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LampControl_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "lampcontrol.v1.LampControl",
+	HandlerType: (*LampControlServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListDevices",
+			Handler:    _LampControl_ListDevices_Handler,
+		},
+		{
+			MethodName: "Scan",
+			Handler:    _LampControl_Scan_Handler,
+		},
+		{
+			MethodName: "SelectDevice",
+			Handler:    _LampControl_SelectDevice_Handler,
+		},
+		{
+			MethodName: "SetPower",
+			Handler:    _LampControl_SetPower_Handler,
+		},
+		{
+			MethodName: "SetColor",
+			Handler:    _LampControl_SetColor_Handler,
+		},
+		{
+			MethodName: "SetBrightness",
+			Handler:    _LampControl_SetBrightness_Handler,
+		},
+		{
+			MethodName: "SetWhiteBalance",
+			Handler:    _LampControl_SetWhiteBalance_Handler,
+		},
+		{
+			MethodName: "SetEffect",
+			Handler:    _LampControl_SetEffect_Handler,
+		},
+		{
+			MethodName: "Lock",
+			Handler:    _LampControl_Lock_Handler,
+		},
+		{
+			MethodName: "Unlock",
+			Handler:    _LampControl_Unlock_Handler,
+		},
+		{
+			MethodName: "Panic",
+			Handler:    _LampControl_Panic_Handler,
+		},
+		{
+			MethodName: "ListScenes",
+			Handler:    _LampControl_ListScenes_Handler,
+		},
+		{
+			MethodName: "CreateScene",
+			Handler:    _LampControl_CreateScene_Handler,
+		},
+		{
+			MethodName: "DeleteScene",
+			Handler:    _LampControl_DeleteScene_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchState",
+			Handler:       _LampControl_WatchState_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "lampcontrol/v1/lampcontrol.proto",
+}
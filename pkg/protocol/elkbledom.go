@@ -157,6 +157,30 @@ func (c Command) Bytes() []byte {
 	return c[:]
 }
 
+// Name returns what the command sets, e.g. "rgb" or "effect"
+func (c Command) Name() string {
+	switch c[2] {
+	case CmdPower:
+		return "power"
+	case CmdBrightness:
+		return "brightness"
+	case CmdEffect:
+		return "effect"
+	case CmdCustom:
+		return "custom"
+	case CmdColor:
+		switch c[3] {
+		case ColorModeRGB:
+			return "rgb"
+		case ColorModeWhite:
+			return "white_balance"
+		case ColorModeSingle:
+			return "single_color"
+		}
+	}
+	return "unknown"
+}
+
 // String returns a hex representation of the command for debugging
 func (c Command) String() string {
 	return bytesToHex(c[:])
@@ -240,6 +240,27 @@ func TestCommandString(t *testing.T) {
 	assert.Contains(t, str, "EF")
 }
 
+func TestCommandName(t *testing.T) {
+	tests := []struct {
+		cmd      Command
+		expected string
+	}{
+		{NewPowerCommand(false), "power"},
+		{NewRGBCommand(255, 0, 0), "rgb"},
+		{NewBrightnessCommand(128), "brightness"},
+		{NewWhiteBalanceCommand(128, 128), "white_balance"},
+		{NewSingleColorCommand(3), "single_color"},
+		{NewEffectCommand(1, 50), "effect"},
+		{Command{}, "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.cmd.Name())
+		})
+	}
+}
+
 func TestCommandFrameStructure(t *testing.T) {
 	// Test that all commands have correct frame structure
 	commands := []Command{
@@ -1157,7 +1157,8 @@ class CustomEffectModalController {
                 this.closeModal();
                 await this.effectsController.loadCustomEffects();
             } else {
-                alert('Failed to create effect');
+                const error = await response.json();
+                alert(`Failed to create effect: ${error.message}`);
             }
         } catch (error) {
             console.error('Failed to create effect:', error);
@@ -1324,8 +1325,15 @@ class TwitchController {
         this.vipBypassCheckbox = $('#vip-bypass');
         this.subBypassCheckbox = $('#sub-bypass');
         this.modBypassCheckbox = $('#mod-bypass');
+        this.voteModeCheckbox = $('#vote-mode');
+        this.voteDurationSlider = $('#vote-duration');
+        this.voteDurationValue = $('#vote-duration-value');
         this.saveBtn = $('#save-twitch-config');
         this.getOAuthBtn = $('#get-oauth-btn');
+        this.lockBtn = $('#lock-btn');
+        this.panicBtn = $('#panic-btn');
+        this.overrideStatus = $('#override-status');
+        this.locked = false;
 
         // Status elements
         this.statusIndicator = $('#twitch-connection-status');
@@ -1337,6 +1345,11 @@ class TwitchController {
         this.effectCommand = $('#effect-command');
         this.effectRemaining = $('#effect-remaining');
 
+        // Poll elements
+        this.activeVoteDiv = $('#active-vote');
+        this.voteStatus = $('#vote-status');
+        this.voteTally = $('#vote-tally');
+
         // Available commands
         this.availableColors = $('#available-colors');
         this.availableEffects = $('#available-effects');
@@ -1361,15 +1374,27 @@ class TwitchController {
             this.userCooldownValue.textContent = `${e.target.value}s`;
         });
 
+        this.voteDurationSlider.addEventListener('input', (e) => {
+            this.voteDurationValue.textContent = `${e.target.value}s`;
+        });
+
         // Save button
         this.saveBtn.addEventListener('click', () => this.saveConfig());
 
         // OAuth button
         this.getOAuthBtn.addEventListener('click', () => this.openOAuthURL());
 
+        // Override buttons
+        this.lockBtn.addEventListener('click', () => {
+            this.ws.sendCommand(this.locked ? 'unlock' : 'lock', {});
+        });
+        this.panicBtn.addEventListener('click', () => this.ws.sendCommand('panic', {}));
+
         // WebSocket listeners
         this.ws.on('twitch_status', (message) => this.handleTwitchStatus(message));
         this.ws.on('twitch_command', (message) => this.handleTwitchCommand(message));
+        this.ws.on('twitch_vote', (message) => this.updateVote(message.vote));
+        this.ws.on('override_status', (message) => this.updateOverride(message.override));
     }
 
     async loadConfig() {
@@ -1389,6 +1414,9 @@ class TwitchController {
             this.vipBypassCheckbox.checked = config.vip_bypass_cooldown !== false;
             this.subBypassCheckbox.checked = config.sub_bypass_cooldown !== false;
             this.modBypassCheckbox.checked = config.mod_bypass_cooldown !== false;
+            this.voteModeCheckbox.checked = config.vote_mode || false;
+            this.voteDurationSlider.value = config.vote_duration_sec || 30;
+            this.voteDurationValue.textContent = `${config.vote_duration_sec || 30}s`;
         } catch (error) {
             console.error('Failed to load Twitch config:', error);
         }
@@ -1404,7 +1432,9 @@ class TwitchController {
             user_cooldown_sec: parseInt(this.userCooldownSlider.value),
             vip_bypass_cooldown: this.vipBypassCheckbox.checked,
             sub_bypass_cooldown: this.subBypassCheckbox.checked,
-            mod_bypass_cooldown: this.modBypassCheckbox.checked
+            mod_bypass_cooldown: this.modBypassCheckbox.checked,
+            vote_mode: this.voteModeCheckbox.checked,
+            vote_duration_sec: parseInt(this.voteDurationSlider.value)
         };
 
         // Only include token if it was entered
@@ -1439,8 +1469,12 @@ class TwitchController {
             const response = await fetch(`${API_URL}/twitch/status`);
             const status = await response.json();
 
-            this.updateStatusUI(status.connected);
+            this.updateStatusUI(status);
             this.updateActiveEffect(status.active_effect);
+            if (status.active_vote) {
+                this.updateVote(status.active_vote);
+            }
+            this.updateOverride(status.override);
         } catch (error) {
             console.error('Failed to load Twitch status:', error);
         }
@@ -1501,15 +1535,36 @@ class TwitchController {
         }
     }
 
-    updateStatusUI(connected) {
-        if (connected) {
+    updateStatusUI(status) {
+        if (status.connected) {
             this.statusIndicator.classList.remove('disconnected');
             this.statusIndicator.classList.add('connected');
             this.statusText.textContent = 'Connected';
-        } else {
-            this.statusIndicator.classList.remove('connected');
-            this.statusIndicator.classList.add('disconnected');
-            this.statusText.textContent = 'Disconnected';
+
+            const channels = status.channels || [];
+            if (channels.length > 1) {
+                const joined = channels.filter(c => c.state === 'joined').length;
+                this.statusText.textContent = `Connected (${joined} of ${channels.length} chats)`;
+            }
+            return;
+        }
+
+        this.statusIndicator.classList.remove('connected');
+        this.statusIndicator.classList.add('disconnected');
+
+        switch (status.state) {
+            case 'connecting':
+            case 'connected':
+                this.statusText.textContent = 'Connecting...';
+                break;
+            case 'reconnecting':
+                this.statusText.textContent = `Reconnecting (attempt ${status.reconnect_attempt || 1})...`;
+                break;
+            case 'auth_failed':
+                this.statusText.textContent = 'Authentication failed, check the access token';
+                break;
+            default:
+                this.statusText.textContent = 'Disconnected';
         }
     }
 
@@ -1524,10 +1579,44 @@ class TwitchController {
         }
     }
 
+    updateOverride(override) {
+        if (!override) {
+            return;
+        }
+
+        this.locked = override.locked;
+        this.lockBtn.textContent = override.locked ? 'Unlock Viewers' : 'Lock Viewers';
+        this.overrideStatus.textContent = override.locked
+            ? `Viewer commands are locked by ${override.locked_by}`
+            : 'Viewer commands are enabled';
+    }
+
+    updateVote(vote) {
+        if (!vote) {
+            this.activeVoteDiv.classList.add('hidden');
+            return;
+        }
+
+        this.activeVoteDiv.classList.remove('hidden');
+
+        if (vote.open) {
+            this.voteStatus.textContent = `Open, ${vote.remaining_sec}s left`;
+        } else if (vote.canceled) {
+            this.voteStatus.textContent = 'Canceled';
+        } else {
+            this.voteStatus.textContent = vote.winner ? `Closed, ${vote.winner} wins` : 'Closed';
+        }
+
+        this.voteTally.textContent = vote.tally.length
+            ? vote.tally.map(r => `${r.option} (${r.count})`).join(', ')
+            : 'No votes yet';
+    }
+
     handleTwitchStatus(message) {
         if (message.status) {
-            this.updateStatusUI(message.status.connected);
+            this.updateStatusUI(message.status);
             this.updateActiveEffect(message.status.active_effect);
+            this.updateOverride(message.status.override);
         }
     }
 
@@ -1566,6 +1655,193 @@ class TwitchController {
     }
 }
 
+// ===== Hue Bridge =====
+class HueController {
+    constructor(wsClient) {
+        this.ws = wsClient;
+        this.countdownInterval = null;
+        this.linkRemaining = 0;
+
+        this.enabledCheckbox = $('#hue-enabled');
+        this.portInput = $('#hue-port');
+        this.saveBtn = $('#save-hue-config');
+        this.linkBtn = $('#hue-link-btn');
+        this.linkStatus = $('#hue-link-status');
+        this.usersList = $('#hue-users');
+        this.statusIndicator = $('#hue-bridge-status');
+        this.statusText = $('#hue-bridge-text');
+
+        this.attachEvents();
+        this.loadConfig();
+        this.loadStatus();
+    }
+
+    attachEvents() {
+        this.saveBtn.addEventListener('click', () => this.saveConfig());
+        this.linkBtn.addEventListener('click', () => this.pressLinkButton());
+
+        // Apps pairing and the bridge starting or stopping are pushed over the WebSocket
+        this.ws.on('hue_status', (message) => {
+            this.updateStatus(message.status);
+            this.loadConfig();
+        });
+    }
+
+    async loadConfig() {
+        try {
+            const response = await fetch(`${API_URL}/hue/config`);
+            const config = await response.json();
+
+            this.enabledCheckbox.checked = config.enabled || false;
+            this.portInput.value = config.port || 80;
+            this.renderUsers(config.users || []);
+        } catch (error) {
+            console.error('Failed to load Hue config:', error);
+        }
+    }
+
+    async saveConfig() {
+        const config = {
+            enabled: this.enabledCheckbox.checked,
+            port: parseInt(this.portInput.value) || 80
+        };
+
+        try {
+            const response = await fetch(`${API_URL}/hue/config`, {
+                method: 'PUT',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify(config)
+            });
+
+            if (response.ok) {
+                this.showMessage('Hue configuration saved successfully', 'success');
+            } else {
+                const error = await response.json();
+                this.showMessage(`Failed to save Hue configuration: ${error.message}`, 'error');
+            }
+            await this.loadStatus();
+        } catch (error) {
+            console.error('Failed to save Hue config:', error);
+            this.showMessage('Failed to save Hue configuration', 'error');
+        }
+    }
+
+    async loadStatus() {
+        try {
+            const response = await fetch(`${API_URL}/hue/status`);
+            this.updateStatus(await response.json());
+        } catch (error) {
+            console.error('Failed to load Hue status:', error);
+        }
+    }
+
+    async pressLinkButton() {
+        try {
+            const response = await fetch(`${API_URL}/hue/link`, { method: 'POST' });
+            this.updateStatus(await response.json());
+        } catch (error) {
+            console.error('Failed to press link button:', error);
+            this.showMessage('Failed to press link button', 'error');
+        }
+    }
+
+    async removeUser(username) {
+        try {
+            const response = await fetch(`${API_URL}/hue/users/${encodeURIComponent(username)}`, { method: 'DELETE' });
+            if (!response.ok) {
+                this.showMessage('Failed to remove app', 'error');
+            }
+            await this.loadConfig();
+        } catch (error) {
+            console.error('Failed to remove Hue app:', error);
+            this.showMessage('Failed to remove app', 'error');
+        }
+    }
+
+    updateStatus(status) {
+        if (status.running) {
+            this.statusIndicator.classList.remove('disconnected');
+            this.statusIndicator.classList.add('connected');
+            this.statusText.textContent = status.discovery
+                ? `Running on port ${status.port}`
+                : `Running on port ${status.port} (discovery unavailable)`;
+        } else {
+            this.statusIndicator.classList.remove('connected');
+            this.statusIndicator.classList.add('disconnected');
+            this.statusText.textContent = status.error ? `Stopped: ${status.error}` : 'Stopped';
+        }
+
+        this.linkRemaining = status.link_button ? status.link_remaining : 0;
+        this.updateCountdown();
+    }
+
+    updateCountdown() {
+        clearInterval(this.countdownInterval);
+        this.countdownInterval = null;
+
+        const render = () => {
+            if (this.linkRemaining > 0) {
+                this.linkBtn.disabled = true;
+                this.linkStatus.textContent = `Pairing open for ${this.linkRemaining}s, search for bridges in the app now`;
+            } else {
+                this.linkBtn.disabled = false;
+                this.linkStatus.textContent = 'Press the link button, then search for bridges in the app';
+            }
+        };
+
+        render();
+        if (this.linkRemaining > 0) {
+            this.countdownInterval = setInterval(() => {
+                this.linkRemaining--;
+                render();
+                if (this.linkRemaining <= 0) {
+                    clearInterval(this.countdownInterval);
+                    this.countdownInterval = null;
+                }
+            }, 1000);
+        }
+    }
+
+    renderUsers(users) {
+        this.usersList.innerHTML = '';
+
+        if (users.length === 0) {
+            const empty = document.createElement('li');
+            empty.textContent = 'No apps paired yet';
+            this.usersList.appendChild(empty);
+            return;
+        }
+
+        users.forEach(user => {
+            const item = document.createElement('li');
+
+            const name = document.createElement('span');
+            name.textContent = `${user.device_type} (paired ${new Date(user.created_at).toLocaleDateString()})`;
+
+            const removeBtn = document.createElement('button');
+            removeBtn.type = 'button';
+            removeBtn.className = 'btn btn-secondary';
+            removeBtn.textContent = 'Remove';
+            removeBtn.addEventListener('click', () => this.removeUser(user.username));
+
+            item.appendChild(name);
+            item.appendChild(removeBtn);
+            this.usersList.appendChild(item);
+        });
+    }
+
+    showMessage(message, type) {
+        const errorDisplay = $('#error-display');
+        errorDisplay.textContent = message;
+        errorDisplay.className = `message ${type}`;
+        errorDisplay.classList.remove('hidden');
+
+        setTimeout(() => {
+            errorDisplay.classList.add('hidden');
+        }, 5000);
+    }
+}
+
 // ===== Application Initialization =====
 class App {
     constructor() {
@@ -1582,6 +1858,7 @@ class App {
         this.customEffectModalController = new CustomEffectModalController(this.effectsController);
         this.soundController = new SoundController(this.wsClient, this.stateManager);
         this.twitchController = new TwitchController(this.wsClient);
+        this.hueController = new HueController(this.wsClient);
         this.mainTabsController = new MainTabsController();
         this.modeTabsController = new ModeTabsController();
         this.connectionStatusController = new ConnectionStatusController(this.wsClient);